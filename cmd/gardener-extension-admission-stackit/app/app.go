@@ -99,6 +99,7 @@ func NewAdmissionCommand(ctx context.Context) *cobra.Command {
 
 			admissionConfig := admissionOpts.Completed()
 			admissionConfig.ApplyAllowApplicationLoadBalancerController(&validator.DefaultAddOptions.AllowApplicationLoadBalancerController)
+			admissionConfig.ApplyValidateAPIEndpoints(&validator.DefaultAddOptions.ValidateAPIEndpoints)
 
 			// Operators can enable the source cluster option via SOURCE_CLUSTER environment variable.
 			// In-cluster config will be used if no SOURCE_KUBECONFIG is specified.
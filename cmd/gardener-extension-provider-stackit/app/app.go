@@ -197,13 +197,18 @@ func NewControllerManagerCommand(ctx context.Context) *cobra.Command {
 			bastionCtrlOpts.Completed().Apply(&stackitbastion.DefaultAddOptions.Controller)
 			configFileOpts.Completed().ApplyCustomLabelDomain(&stackitbastion.DefaultAddOptions.CustomLabelDomain)
 			controlPlaneCtrlOpts.Completed().Apply(&stackitcontrolplane.DefaultAddOptions.Controller)
+			configFileOpts.Completed().ApplyControlPlane(&stackitcontrolplane.DefaultAddOptions.Configuration)
 			dnsRecordCtrlOpts.Completed().Apply(&stackitdnsrecord.DefaultAddOptions.Controller)
 			healthCheckCtrlOpts.Completed().Apply(&healthcheck.DefaultAddOptions.Controller)
 			heartbeatCtrlOpts.Completed().Apply(&heartbeat.DefaultAddOptions)
 			configFileOpts.Completed().ApplyCustomLabelDomain(&infrastructure.DefaultAddOptions.CustomLabelDomain)
+			configFileOpts.Completed().ApplyInfrastructure(&infrastructure.DefaultAddOptions.Configuration)
+			configFileOpts.Completed().ApplyClusterLabelValueSource(&infrastructure.DefaultAddOptions.ClusterLabelValueSource)
+			configFileOpts.Completed().ApplyClusterLabelValueSource(&stackitselfhostedshootexposure.DefaultAddOptions.ClusterLabelValueSource)
 			infraCtrlOpts.Completed().Apply(&infrastructure.DefaultAddOptions.Controller)
 			selfHostedShootExposureCtrlOpts.Completed().Apply(&stackitselfhostedshootexposure.DefaultAddOptions.Controller)
 			workerCtrlOpts.Completed().Apply(&stackitworker.DefaultAddOptions.Controller)
+			configFileOpts.Completed().ApplyWorker(&stackitworker.DefaultAddOptions.Configuration)
 
 			reconcileOpts.Completed().Apply(&stackitbastion.DefaultAddOptions.IgnoreOperationAnnotation)
 			reconcileOpts.Completed().Apply(&stackitcontrolplane.DefaultAddOptions.IgnoreOperationAnnotation)
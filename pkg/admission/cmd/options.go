@@ -20,6 +20,9 @@ func GardenWebhookSwitchOptions() *webhookcmd.SwitchOptions {
 type ConfigOptions struct {
 	// AllowApplicationLoadBalancerController configures if the application load balancer controller can be used in the ControlPlaneConfig.
 	AllowApplicationLoadBalancerController bool
+	// ValidateAPIEndpoints configures if shoots are rejected if the STACKIT API endpoints required by their components
+	// cannot be determined.
+	ValidateAPIEndpoints bool
 
 	config *Config
 }
@@ -28,6 +31,9 @@ type ConfigOptions struct {
 type Config struct {
 	// AllowApplicationLoadBalancerController configures if the application load balancer controller can be used in the ControlPlaneConfig.
 	AllowApplicationLoadBalancerController bool
+	// ValidateAPIEndpoints configures if shoots are rejected if the STACKIT API endpoints required by their components
+	// cannot be determined.
+	ValidateAPIEndpoints bool
 }
 
 // AddFlags implements Flagger.AddFlags.
@@ -38,12 +44,19 @@ func (c *ConfigOptions) AddFlags(fs *pflag.FlagSet) {
 		false,
 		"Configures if the application load balancer controller can be used in the ControlPlaneConfig.",
 	)
+	fs.BoolVar(
+		&c.ValidateAPIEndpoints,
+		"validate-api-endpoints",
+		false,
+		"Configures if shoots are rejected if the STACKIT API endpoints required by their components are neither configured in the CloudProfileConfig nor known for their region.",
+	)
 }
 
 // Complete implements RESTCompleter.Complete.
 func (c *ConfigOptions) Complete() error {
 	c.config = &Config{
 		AllowApplicationLoadBalancerController: c.AllowApplicationLoadBalancerController,
+		ValidateAPIEndpoints:                   c.ValidateAPIEndpoints,
 	}
 	return nil
 }
@@ -57,3 +70,8 @@ func (c *ConfigOptions) Completed() *Config {
 func (c *Config) ApplyAllowApplicationLoadBalancerController(cfg *bool) {
 	*cfg = c.AllowApplicationLoadBalancerController
 }
+
+// ApplyValidateAPIEndpoints sets the values of this Config in the given config.ValidateAPIEndpoints.
+func (c *Config) ApplyValidateAPIEndpoints(cfg *bool) {
+	*cfg = c.ValidateAPIEndpoints
+}
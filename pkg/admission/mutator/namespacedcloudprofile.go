@@ -64,6 +64,11 @@ func (p *namespacedCloudProfile) Mutate(_ context.Context, newObj, _ client.Obje
 		statusConfig.APIEndpoints = specConfig.APIEndpoints
 	}
 
+	// Overwrite the default volume type from spec
+	if specConfig.DefaultVolumeType != nil {
+		statusConfig.DefaultVolumeType = specConfig.DefaultVolumeType
+	}
+
 	modifiedStatusConfig, err := json.Marshal(statusConfig)
 	if err != nil {
 		return err
@@ -96,6 +96,23 @@ var _ = Describe("NamespacedCloudProfile Mutator", func() {
 				Expect(mergedConfig.APIEndpoints.LoadBalancer).To(PointTo(Equal("https://custom-lb.example.com")))
 			})
 
+			It("should overwrite the defaultVolumeType from spec", func() {
+				namespacedCloudProfile.Status.CloudProfileSpec.ProviderConfig = &runtime.RawExtension{Raw: []byte(`{
+"apiVersion":"stackit.provider.extensions.gardener.cloud/v1alpha1",
+"kind":"CloudProfileConfig",
+"defaultVolumeType":"storage_premium_perf1"}`)}
+				namespacedCloudProfile.Spec.ProviderConfig = &runtime.RawExtension{Raw: []byte(`{
+"apiVersion":"stackit.provider.extensions.gardener.cloud/v1alpha1",
+"kind":"CloudProfileConfig",
+"defaultVolumeType":"storage_premium_perf4"}`)}
+
+				Expect(namespacedCloudProfileMutator.Mutate(ctx, namespacedCloudProfile, nil)).To(Succeed())
+
+				mergedConfig, err := helper.CloudProfileConfigFromRawExtension(namespacedCloudProfile.Status.CloudProfileSpec.ProviderConfig)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(mergedConfig.DefaultVolumeType).To(PointTo(Equal("storage_premium_perf4")))
+			})
+
 			It("should correctly merge extended machineImages", func() {
 				namespacedCloudProfile.Status.CloudProfileSpec.ProviderConfig = &runtime.RawExtension{Raw: []byte(`{
 "apiVersion":"stackit.provider.extensions.gardener.cloud/v1alpha1",
@@ -42,5 +42,7 @@ func (cp *cloudProfile) Validate(_ context.Context, newObj, _ client.Object) err
 		return err
 	}
 
-	return stackitvalidation.ValidateCloudProfileConfig(cpConfig, cloudProfile.Spec.MachineImages, providerConfigPath).ToAggregate()
+	allErrs := stackitvalidation.ValidateCloudProfileConfig(cpConfig, cloudProfile.Spec.MachineImages, providerConfigPath)
+	allErrs = append(allErrs, stackitvalidation.ValidateDefaultVolumeType(cpConfig, cloudProfile.Spec.VolumeTypes, providerConfigPath)...)
+	return allErrs.ToAggregate()
 }
@@ -74,21 +74,38 @@ func (p *namespacedCloudProfile) validateNamespacedCloudProfileProviderConfig(pr
 			APIVersion: stackitv1alpha1.SchemeGroupVersion.String(),
 			Kind:       "CloudProfileConfig",
 		},
-		MachineImages: providerConfig.MachineImages,
-		APIEndpoints:  providerConfig.APIEndpoints,
+		MachineImages:     providerConfig.MachineImages,
+		APIEndpoints:      providerConfig.APIEndpoints,
+		DefaultVolumeType: providerConfig.DefaultVolumeType,
 	}
 	if !equality.Semantic.DeepEqual(validationProviderConfig, providerConfig) {
 		allErrs = append(allErrs, field.Forbidden(
 			field.NewPath("spec.providerConfig"),
-			"must only set machineImages and stackitAPIEndpoints",
+			"must only set machineImages, stackitAPIEndpoints and defaultVolumeType",
 		))
 	}
 
 	allErrs = append(allErrs, p.validateMachineImages(providerConfig, profileSpec.MachineImages, parentSpec)...)
+	allErrs = append(allErrs, p.validateDefaultVolumeType(providerConfig, profileSpec.VolumeTypes, parentSpec)...)
 
 	return allErrs
 }
 
+// validateDefaultVolumeType validates that the default volume type is one of the volume types of the
+// NamespacedCloudProfile or its parent CloudProfile.
+func (p *namespacedCloudProfile) validateDefaultVolumeType(providerConfig *stackitv1alpha1.CloudProfileConfig, volumeTypes []core.VolumeType, parentSpec gardencorev1beta1.CloudProfileSpec) field.ErrorList {
+	providerConfigPath := field.NewPath("spec.providerConfig")
+	if providerConfig.DefaultVolumeType != nil && len(*providerConfig.DefaultVolumeType) == 0 {
+		return field.ErrorList{field.Required(providerConfigPath.Child("defaultVolumeType"), "must provide a volume type when the key is specified")}
+	}
+
+	allVolumeTypes := slices.Clone(volumeTypes)
+	for _, volumeType := range parentSpec.VolumeTypes {
+		allVolumeTypes = append(allVolumeTypes, core.VolumeType{Name: volumeType.Name})
+	}
+	return validation.ValidateDefaultVolumeType(providerConfig, allVolumeTypes, providerConfigPath)
+}
+
 func (p *namespacedCloudProfile) validateMachineImages(providerConfig *stackitv1alpha1.CloudProfileConfig, machineImages []core.MachineImage, parentSpec gardencorev1beta1.CloudProfileSpec) field.ErrorList {
 	allErrs := field.ErrorList{}
 
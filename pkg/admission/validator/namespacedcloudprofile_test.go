@@ -281,7 +281,7 @@ var _ = Describe("NamespacedCloudProfile Validator", func() {
 			Expect(err).To(ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
 				"Type":   Equal(field.ErrorTypeForbidden),
 				"Field":  Equal("spec.providerConfig"),
-				"Detail": Equal("must only set machineImages and stackitAPIEndpoints"),
+				"Detail": Equal("must only set machineImages, stackitAPIEndpoints and defaultVolumeType"),
 			}))))
 		})
 
@@ -317,5 +317,54 @@ var _ = Describe("NamespacedCloudProfile Validator", func() {
 
 			Expect(namespacedCloudProfileValidator.Validate(ctx, namespacedCloudProfile, nil)).To(Succeed())
 		})
+
+		It("should succeed for NamespacedCloudProfile specifying a default volume type of the parent or its own volume types", func() {
+			cloudProfile.Spec.VolumeTypes = []v1beta1.VolumeType{{Name: "storage_premium_perf1"}}
+			namespacedCloudProfile.Spec.VolumeTypes = []core.VolumeType{{Name: "storage_premium_perf4"}}
+			Expect(fakeClient.Create(ctx, cloudProfile)).To(Succeed())
+
+			for _, volumeType := range []string{"storage_premium_perf1", "storage_premium_perf4"} {
+				namespacedCloudProfile.Spec.ProviderConfig = &runtime.RawExtension{Raw: []byte(`{
+"apiVersion":"stackit.provider.extensions.gardener.cloud/v1alpha1",
+"kind":"CloudProfileConfig",
+"defaultVolumeType":"` + volumeType + `"
+}`)}
+
+				Expect(namespacedCloudProfileValidator.Validate(ctx, namespacedCloudProfile, nil)).To(Succeed())
+			}
+		})
+
+		It("should fail for NamespacedCloudProfile specifying an unknown default volume type", func() {
+			cloudProfile.Spec.VolumeTypes = []v1beta1.VolumeType{{Name: "storage_premium_perf1"}}
+			namespacedCloudProfile.Spec.ProviderConfig = &runtime.RawExtension{Raw: []byte(`{
+"apiVersion":"stackit.provider.extensions.gardener.cloud/v1alpha1",
+"kind":"CloudProfileConfig",
+"defaultVolumeType":"storage_premium_perf6"
+}`)}
+
+			Expect(fakeClient.Create(ctx, cloudProfile)).To(Succeed())
+
+			err := namespacedCloudProfileValidator.Validate(ctx, namespacedCloudProfile, nil)
+			Expect(err).To(ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
+				"Type":  Equal(field.ErrorTypeNotSupported),
+				"Field": Equal("spec.providerConfig.defaultVolumeType"),
+			}))))
+		})
+
+		It("should fail for NamespacedCloudProfile specifying an empty default volume type", func() {
+			namespacedCloudProfile.Spec.ProviderConfig = &runtime.RawExtension{Raw: []byte(`{
+"apiVersion":"stackit.provider.extensions.gardener.cloud/v1alpha1",
+"kind":"CloudProfileConfig",
+"defaultVolumeType":""
+}`)}
+
+			Expect(fakeClient.Create(ctx, cloudProfile)).To(Succeed())
+
+			err := namespacedCloudProfileValidator.Validate(ctx, namespacedCloudProfile, nil)
+			Expect(err).To(ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
+				"Type":  Equal(field.ErrorTypeRequired),
+				"Field": Equal("spec.providerConfig.defaultVolumeType"),
+			}))))
+		})
 	})
 })
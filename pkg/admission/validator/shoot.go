@@ -7,31 +7,43 @@ package validator
 import (
 	"context"
 	"fmt"
+	"slices"
 
 	extensionswebhook "github.com/gardener/gardener/extensions/pkg/webhook"
 	"github.com/gardener/gardener/pkg/apis/core"
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	v1beta1constants "github.com/gardener/gardener/pkg/apis/core/v1beta1/constants"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 
 	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/apis/stackit/helper"
+	stackitv1alpha1 "github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/apis/stackit/v1alpha1"
 	stackitvalidation "github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/apis/stackit/validation"
+	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/feature"
+	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/stackit"
 )
 
 // NewShootValidator returns a new instance of a shoot validator.
-func NewShootValidator(mgr manager.Manager, allowApplicationLoadBalancerController bool) extensionswebhook.Validator {
+func NewShootValidator(mgr manager.Manager, allowApplicationLoadBalancerController, validateAPIEndpoints bool) extensionswebhook.Validator {
 	return &shoot{
+		client:                                 mgr.GetClient(),
 		allowApplicationLoadBalancerController: allowApplicationLoadBalancerController,
+		validateAPIEndpoints:                   validateAPIEndpoints,
 	}
 }
 
 type shoot struct {
+	client                                 client.Client
 	allowApplicationLoadBalancerController bool
+	validateAPIEndpoints                   bool
 }
 
 // Validate validates the given shoot objects.
-func (s *shoot) Validate(_ context.Context, newObj, oldObj client.Object) error {
+func (s *shoot) Validate(ctx context.Context, newObj, oldObj client.Object) error {
 	shoot, ok := newObj.(*core.Shoot)
 	if !ok {
 		return fmt.Errorf("wrong object type %T", newObj)
@@ -53,13 +65,23 @@ func (s *shoot) Validate(_ context.Context, newObj, oldObj client.Object) error
 
 	allErrs = append(allErrs, stackitvalidation.ValidateInfrastructureConfig(infraConfig, ptr.Deref(shoot.Spec.Networking, core.Networking{}).Nodes, field.NewPath("spec").Child("provider").Child("infrastructureConfig"))...)
 
+	var ipFamilies []string
+	for _, ipFamily := range ptr.Deref(shoot.Spec.Networking, core.Networking{}).IPFamilies {
+		ipFamilies = append(ipFamilies, string(ipFamily))
+	}
+	allErrs = append(allErrs, stackitvalidation.ValidateInfrastructureConfigAgainstIPFamilies(infraConfig, ipFamilies, field.NewPath("spec").Child("provider").Child("infrastructureConfig"))...)
+
+	var (
+		oldShoot       *core.Shoot
+		oldInfraConfig *stackitv1alpha1.InfrastructureConfig
+	)
 	if oldObj != nil {
-		oldShoot, ok := oldObj.(*core.Shoot)
+		oldShoot, ok = oldObj.(*core.Shoot)
 		if !ok {
 			return fmt.Errorf("wrong object type %T for old object", oldObj)
 		}
 
-		oldInfraConfig, err := helper.InfrastructureConfigFromRawExtension(oldShoot.Spec.Provider.InfrastructureConfig)
+		oldInfraConfig, err = helper.InfrastructureConfigFromRawExtension(oldShoot.Spec.Provider.InfrastructureConfig)
 		if err != nil {
 			return err
 		}
@@ -73,5 +95,119 @@ func (s *shoot) Validate(_ context.Context, newObj, oldObj client.Object) error
 		allErrs = append(allErrs, stackitvalidation.ValidateControlPlaneConfigUpdate(oldCpConfig, cpConfig, field.NewPath("spec").Child("provider").Child("controlPlaneConfig"))...)
 	}
 
+	// the number of DNS servers is only limited for networks created via the STACKIT API, DNS servers accepted for the
+	// OpenStack API are validated again when switching to the STACKIT API
+	if feature.UseStackitAPIInfrastructureControllerForShoot(shoot.Annotations) {
+		oldDNSServersConfig := oldInfraConfig
+		if oldShoot != nil && !feature.UseStackitAPIInfrastructureControllerForShoot(oldShoot.Annotations) {
+			oldDNSServersConfig = nil
+		}
+		allErrs = append(allErrs, stackitvalidation.ValidateInfrastructureConfigDNSServerLimits(oldDNSServersConfig, infraConfig, field.NewPath("spec").Child("provider").Child("infrastructureConfig"))...)
+	}
+
+	cloudProfileConfig, err := s.getCloudProfileConfig(ctx, shoot)
+	if err != nil {
+		return err
+	}
+
+	var serverGroupPolicies []string
+	if cloudProfileConfig != nil {
+		serverGroupPolicies = cloudProfileConfig.ServerGroupPolicies
+	}
+
+	useStackitMachineControllerManager := feature.UseStackitMachineControllerManagerForShoot(shoot.Annotations)
+	// unchanged worker pools are not validated again, so that updates of other fields are not blocked by worker pools
+	// which were accepted before
+	oldWorkersByName := map[string]core.Worker{}
+	if oldShoot != nil && feature.UseStackitMachineControllerManagerForShoot(oldShoot.Annotations) == useStackitMachineControllerManager {
+		for _, worker := range oldShoot.Spec.Provider.Workers {
+			oldWorkersByName[worker.Name] = worker
+		}
+	}
+
+	workersPath := field.NewPath("spec").Child("provider").Child("workers")
+	for i, worker := range shoot.Spec.Provider.Workers {
+		if oldWorker, ok := oldWorkersByName[worker.Name]; ok &&
+			equality.Semantic.DeepEqual(oldWorker.ProviderConfig, worker.ProviderConfig) &&
+			slices.Equal(oldWorker.Zones, worker.Zones) {
+			continue
+		}
+
+		providerConfigPath := workersPath.Index(i).Child("providerConfig")
+		workerConfig, err := helper.WorkerConfigFromRawExtension(worker.ProviderConfig)
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(providerConfigPath, string(worker.ProviderConfig.Raw), fmt.Sprintf("could not decode worker config: %v", err)))
+			continue
+		}
+		allErrs = append(allErrs, stackitvalidation.ValidateWorkerConfig(workerConfig, worker.Zones, serverGroupPolicies, useStackitMachineControllerManager, providerConfigPath)...)
+	}
+
+	allErrs = append(allErrs, stackitvalidation.ValidateIaaSEndpointAgainstCloudProfile(infraConfig, cloudProfileConfig, field.NewPath("spec").Child("provider").Child("infrastructureConfig").Child("iaasEndpoint"))...)
+
+	if cloudProfileConfig != nil {
+		var oldWorkers []core.Worker
+		if oldShoot != nil {
+			oldWorkers = oldShoot.Spec.Provider.Workers
+		}
+		allErrs = append(allErrs, stackitvalidation.ValidateWorkersAgainstCloudProfile(oldWorkers, shoot.Spec.Provider.Workers, shoot.Spec.Region, cloudProfileConfig, workersPath)...)
+	}
+
+	var apiEndpoints *stackitv1alpha1.APIEndpoints
+	if cloudProfileConfig != nil && cloudProfileConfig.APIEndpoints != nil {
+		apiEndpoints = new(cloudProfileConfig.APIEndpoints.ForRegion(stackit.NormalizeRegion(shoot.Spec.Region)))
+	}
+
+	if s.allowApplicationLoadBalancerController && cpConfig.ApplicationLoadBalancer != nil && cpConfig.ApplicationLoadBalancer.Enabled {
+		allErrs = append(allErrs, stackitvalidation.ValidateApplicationLoadBalancerPrerequisites(cpConfig, apiEndpoints, field.NewPath("spec").Child("provider").Child("controlPlaneConfig"))...)
+	}
+
+	if s.validateAPIEndpoints {
+		allErrs = append(allErrs, stackitvalidation.ValidateRequiredAPIEndpoints(cpConfig, shoot.Spec.Region, apiEndpoints, field.NewPath("spec").Child("provider").Child("controlPlaneConfig"))...)
+	}
+
 	return allErrs.ToAggregate()
 }
+
+// getCloudProfileConfig returns the CloudProfileConfig of the (Namespaced)CloudProfile referenced by the given shoot.
+// It returns nil if the shoot does not reference a cloud profile.
+func (s *shoot) getCloudProfileConfig(ctx context.Context, shoot *core.Shoot) (*stackitv1alpha1.CloudProfileConfig, error) {
+	var (
+		kind = v1beta1constants.CloudProfileReferenceKindCloudProfile
+		name string
+	)
+	if ref := shoot.Spec.CloudProfile; ref != nil {
+		kind, name = ref.Kind, ref.Name
+	} else if shoot.Spec.CloudProfileName != nil { //nolint:staticcheck // SA1019: needed for backwards compatibility
+		name = *shoot.Spec.CloudProfileName //nolint:staticcheck // SA1019: needed for backwards compatibility
+	}
+	if name == "" {
+		return nil, nil
+	}
+
+	var providerConfig *runtime.RawExtension
+	switch kind {
+	case v1beta1constants.CloudProfileReferenceKindCloudProfile:
+		cloudProfile := &gardencorev1beta1.CloudProfile{}
+		if err := s.client.Get(ctx, client.ObjectKey{Name: name}, cloudProfile); err != nil {
+			return nil, fmt.Errorf("could not get CloudProfile %q: %w", name, err)
+		}
+		providerConfig = cloudProfile.Spec.ProviderConfig
+	case v1beta1constants.CloudProfileReferenceKindNamespacedCloudProfile:
+		namespacedCloudProfile := &gardencorev1beta1.NamespacedCloudProfile{}
+		if err := s.client.Get(ctx, client.ObjectKey{Namespace: shoot.Namespace, Name: name}, namespacedCloudProfile); err != nil {
+			return nil, fmt.Errorf("could not get NamespacedCloudProfile %q: %w", client.ObjectKey{Namespace: shoot.Namespace, Name: name}, err)
+		}
+		providerConfig = namespacedCloudProfile.Status.CloudProfileSpec.ProviderConfig
+	default:
+		return nil, fmt.Errorf("unsupported cloud profile kind %q", kind)
+	}
+
+	if providerConfig == nil {
+		return nil, nil
+	}
+	cloudProfileConfig, err := helper.CloudProfileConfigFromRawExtension(providerConfig)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode providerConfig of %s %q: %w", kind, name, err)
+	}
+	return cloudProfileConfig, nil
+}
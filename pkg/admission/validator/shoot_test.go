@@ -25,6 +25,7 @@ import (
 	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/admission/validator"
 	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/apis/stackit/install"
 	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/apis/stackit/v1alpha1"
+	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/feature"
 	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/stackit"
 )
 
@@ -49,7 +50,7 @@ var _ = Describe("NamespacedCloudProfile Validator", func() {
 			Client: fakeClient,
 			Scheme: scheme,
 		}
-		shootValidator = validator.NewShootValidator(fakeManager, true)
+		shootValidator = validator.NewShootValidator(fakeManager, true, false)
 
 		infrastructureConfig = v1alpha1.InfrastructureConfig{
 			FloatingPoolName: "floating-pool",
@@ -96,6 +97,36 @@ var _ = Describe("NamespacedCloudProfile Validator", func() {
 
 			Expect(shootValidator.Validate(ctx, shoot, nil)).To(Not(Succeed()))
 		})
+		It("should allow IPv6 for dual-stack shoots only", func() {
+			infrastructureConfig.Networks.IPv6 = &v1alpha1.IPv6Network{PrefixLength: new(int32(64))}
+			shoot.Spec.Provider.InfrastructureConfig = &runtime.RawExtension{Raw: encode(&infrastructureConfig)}
+
+			Expect(shootValidator.Validate(ctx, shoot, nil)).To(MatchError(ContainSubstring("can only be set for dual-stack shoots")))
+
+			shoot.Spec.Networking.IPFamilies = []core.IPFamily{core.IPFamilyIPv4, core.IPFamilyIPv6}
+			Expect(shootValidator.Validate(ctx, shoot, nil)).To(Succeed())
+		})
+		It("should limit the number of DNS servers only for the STACKIT API", func() {
+			infrastructureConfig.Networks.DNSServers = &[]string{"1.1.1.1", "8.8.8.8", "9.9.9.9", "1.0.0.1"}
+			shoot.Spec.Provider.InfrastructureConfig = &runtime.RawExtension{Raw: encode(&infrastructureConfig)}
+
+			shoot.Annotations = map[string]string{feature.ShootUseSTACKITAPIInfrastructureController: "true"}
+			Expect(shootValidator.Validate(ctx, shoot, nil)).To(MatchError(ContainSubstring("networks.dnsServers: Too many")))
+
+			shoot.Annotations = map[string]string{feature.ShootUseSTACKITAPIInfrastructureController: "false"}
+			Expect(shootValidator.Validate(ctx, shoot, nil)).To(Succeed())
+		})
+		It("should allow unchanged DNS servers exceeding the limit of the STACKIT API", func() {
+			infrastructureConfig.Networks.DNSServers = &[]string{"1.1.1.1", "8.8.8.8", "9.9.9.9", "1.0.0.1"}
+			shoot.Spec.Provider.InfrastructureConfig = &runtime.RawExtension{Raw: encode(&infrastructureConfig)}
+			shoot.Annotations = map[string]string{feature.ShootUseSTACKITAPIInfrastructureController: "true"}
+			oldShoot := shoot.DeepCopy()
+
+			Expect(shootValidator.Validate(ctx, shoot, oldShoot)).To(Succeed())
+
+			oldShoot.Annotations = map[string]string{feature.ShootUseSTACKITAPIInfrastructureController: "false"}
+			Expect(shootValidator.Validate(ctx, shoot, oldShoot)).To(MatchError(ContainSubstring("networks.dnsServers: Too many")))
+		})
 		It("should fail for with invalid ControlPlaneConfig", func() {
 			shoot.Spec.Provider.ControlPlaneConfig = &runtime.RawExtension{Raw: []byte(`{"foo": "bar"}`)}
 
@@ -120,6 +151,176 @@ var _ = Describe("NamespacedCloudProfile Validator", func() {
 			Expect(shootValidator.Validate(ctx, shoot, nil)).To(Not(Succeed()))
 		})
 
+		It("should fail for zone weights of unknown zones", func() {
+			shoot.Spec.Provider.Workers = []core.Worker{{
+				Name:  "worker",
+				Zones: []string{"eu01-1"},
+				ProviderConfig: &runtime.RawExtension{Raw: encode(&v1alpha1.WorkerConfig{
+					ZoneWeights: map[string]int32{"eu01-1": 1, "eu01-2": 1},
+				})},
+			}}
+
+			Expect(shootValidator.Validate(ctx, shoot, nil)).To(MatchError(ContainSubstring("spec.provider.workers[0].providerConfig.zoneWeights[eu01-2]")))
+		})
+
+		It("should return a field error for a worker config which cannot be decoded", func() {
+			shoot.Spec.Provider.Workers = []core.Worker{{
+				Name:           "worker",
+				Zones:          []string{"eu01-1"},
+				ProviderConfig: &runtime.RawExtension{Raw: []byte(`{"foo": "bar"}`)},
+			}}
+
+			Expect(shootValidator.Validate(ctx, shoot, nil)).To(MatchError(ContainSubstring("spec.provider.workers[0].providerConfig: Invalid value")))
+		})
+
+		It("should not validate unchanged worker pools again", func() {
+			shoot.Spec.Provider.Workers = []core.Worker{{
+				Name:  "worker",
+				Zones: []string{"eu01-1"},
+				ProviderConfig: &runtime.RawExtension{Raw: encode(&v1alpha1.WorkerConfig{
+					ZoneWeights: map[string]int32{"eu01-1": 1, "eu01-2": 1},
+				})},
+			}}
+			oldShoot := shoot.DeepCopy()
+			shoot.Spec.Provider.Workers[0].Maximum = 5
+
+			Expect(shootValidator.Validate(ctx, shoot, oldShoot)).To(Succeed())
+
+			shoot.Spec.Provider.Workers[0].Zones = []string{"eu01-1", "eu01-3"}
+			Expect(shootValidator.Validate(ctx, shoot, oldShoot)).To(MatchError(ContainSubstring("spec.provider.workers[0].providerConfig.zoneWeights[eu01-3]")))
+		})
+
+		Context("machine images", func() {
+			BeforeEach(func() {
+				cloudProfileConfig := &v1alpha1.CloudProfileConfig{
+					TypeMeta: metav1.TypeMeta{
+						APIVersion: v1alpha1.SchemeGroupVersion.String(),
+						Kind:       "CloudProfileConfig",
+					},
+					MachineImages: []v1alpha1.MachineImages{{
+						Name: "flatcar",
+						Versions: []v1alpha1.MachineImageVersion{{
+							Version: "1.0.0",
+							Regions: []v1alpha1.RegionIDMapping{{Name: "eu01", ID: "image-id"}},
+						}},
+					}},
+				}
+				Expect(fakeClient.Create(ctx, &v1beta1.CloudProfile{
+					ObjectMeta: metav1.ObjectMeta{Name: "stackit"},
+					Spec: v1beta1.CloudProfileSpec{
+						ProviderConfig: &runtime.RawExtension{Raw: encode(cloudProfileConfig)},
+					},
+				})).To(Succeed())
+
+				shoot.Spec.CloudProfile = &core.CloudProfileReference{Kind: "CloudProfile", Name: "stackit"}
+				shoot.Spec.Region = "eu01"
+				shoot.Spec.Provider.Workers = []core.Worker{{
+					Name: "worker",
+					Machine: core.Machine{
+						Image: &core.ShootMachineImage{Name: "flatcar", Version: new("1.0.0")},
+					},
+				}}
+			})
+
+			It("should succeed when the machine image is mapped for the region", func() {
+				Expect(shootValidator.Validate(ctx, shoot, nil)).To(Succeed())
+			})
+
+			It("should fail when the machine image is not mapped for the region", func() {
+				shoot.Spec.Region = "eu02"
+
+				Expect(shootValidator.Validate(ctx, shoot, nil)).To(MatchError(ContainSubstring(`no machine image mapping found in CloudProfileConfig for name "flatcar", version "1.0.0", architecture "amd64" and region "eu02"`)))
+			})
+
+			It("should fail for server group policies which are not allowed by the CloudProfile", func() {
+				shoot.Spec.Provider.Workers[0].ProviderConfig = &runtime.RawExtension{Raw: encode(&v1alpha1.WorkerConfig{
+					ServerGroup: &v1alpha1.ServerGroupConfig{Policy: "soft-anti-affinity"},
+				})}
+
+				Expect(shootValidator.Validate(ctx, shoot, nil)).To(MatchError(ContainSubstring("spec.provider.workers[0].providerConfig.serverGroup.policy")))
+			})
+
+			It("should fail when the referenced CloudProfile does not exist", func() {
+				shoot.Spec.CloudProfile.Name = "missing"
+
+				Expect(shootValidator.Validate(ctx, shoot, nil)).To(Not(Succeed()))
+			})
+		})
+
+		Context("application load balancer", func() {
+			BeforeEach(func() {
+				shoot.Spec.Provider.ControlPlaneConfig = &runtime.RawExtension{Raw: encode(&v1alpha1.ControlPlaneConfig{
+					ApplicationLoadBalancer: &v1alpha1.ApplicationLoadBalancerConfig{
+						Enabled: true,
+						Ingress: &v1alpha1.ApplicationLoadBalancerConfigIngress{Enabled: true},
+					},
+				})}
+			})
+
+			It("should succeed without reading the credentials of the shoot", func() {
+				Expect(shootValidator.Validate(ctx, shoot, nil)).To(Succeed())
+			})
+
+			It("should fail with an invalid application load balancer API endpoint in the CloudProfile", func() {
+				Expect(fakeClient.Create(ctx, &v1beta1.CloudProfile{
+					ObjectMeta: metav1.ObjectMeta{Name: "stackit"},
+					Spec: v1beta1.CloudProfileSpec{
+						ProviderConfig: &runtime.RawExtension{Raw: encode(&v1alpha1.CloudProfileConfig{
+							TypeMeta: metav1.TypeMeta{
+								APIVersion: v1alpha1.SchemeGroupVersion.String(),
+								Kind:       "CloudProfileConfig",
+							},
+							APIEndpoints: &v1alpha1.APIEndpoints{ApplicationLoadBalancer: new("alb.api.stackit.cloud")},
+						})},
+					},
+				})).To(Succeed())
+				shoot.Spec.CloudProfile = &core.CloudProfileReference{Kind: "CloudProfile", Name: "stackit"}
+
+				Expect(shootValidator.Validate(ctx, shoot, nil)).To(MatchError(ContainSubstring("application load balancer requires a valid application load balancer API endpoint")))
+			})
+		})
+
+		Context("API endpoints", func() {
+			BeforeEach(func() {
+				shootValidator = validator.NewShootValidator(fakeManager, true, true)
+			})
+
+			It("should succeed in a known region", func() {
+				shoot.Spec.Region = "eu01"
+
+				Expect(shootValidator.Validate(ctx, shoot, nil)).To(Succeed())
+			})
+
+			It("should fail if the endpoints cannot be determined for the region", func() {
+				shoot.Spec.Region = "eu99"
+
+				Expect(shootValidator.Validate(ctx, shoot, nil)).To(MatchError(ContainSubstring(`the STACKIT CSI driver requires the IaaS API endpoint, which is neither configured nor known for region "eu99"`)))
+			})
+
+			It("should succeed if the endpoints are configured in the CloudProfileConfig", func() {
+				cloudProfileConfig := &v1alpha1.CloudProfileConfig{
+					TypeMeta: metav1.TypeMeta{
+						APIVersion: v1alpha1.SchemeGroupVersion.String(),
+						Kind:       "CloudProfileConfig",
+					},
+					APIEndpoints: &v1alpha1.APIEndpoints{
+						LoadBalancer: new("https://lb.example.com"),
+						IaaS:         new("https://iaas.example.com"),
+					},
+				}
+				Expect(fakeClient.Create(ctx, &v1beta1.CloudProfile{
+					ObjectMeta: metav1.ObjectMeta{Name: "stackit"},
+					Spec: v1beta1.CloudProfileSpec{
+						ProviderConfig: &runtime.RawExtension{Raw: encode(cloudProfileConfig)},
+					},
+				})).To(Succeed())
+				shoot.Spec.CloudProfile = &core.CloudProfileReference{Kind: "CloudProfile", Name: "stackit"}
+				shoot.Spec.Region = "eu99"
+
+				Expect(shootValidator.Validate(ctx, shoot, nil)).To(Succeed())
+			})
+		})
+
 		It("should fail for immutable field", func() {
 			infrastructureConfig.Networks.Workers = "10.0.1.0/24"
 			newShoot := shoot.DeepCopy()
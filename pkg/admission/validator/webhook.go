@@ -24,6 +24,9 @@ var (
 type AddOptions struct {
 	// AllowApplicationLoadBalancerController configures if the application load balancer controller can be used in the ControlPlaneConfig.
 	AllowApplicationLoadBalancerController bool
+	// ValidateAPIEndpoints configures if shoots are rejected if an endpoint of a STACKIT API used by the enabled components
+	// is neither configured in the CloudProfileConfig nor known for the region of the shoot.
+	ValidateAPIEndpoints bool
 }
 
 var logger = log.Log.WithName("stackit-validator-webhook")
@@ -37,8 +40,8 @@ func New(mgr manager.Manager) (*extensionswebhook.Webhook, error) {
 		Path: "/webhooks/validate",
 		Validators: map[extensionswebhook.Validator][]extensionswebhook.Type{
 			NewCloudProfileValidator(mgr): {{Obj: &core.CloudProfile{}}},
-			NewShootValidator(mgr, DefaultAddOptions.AllowApplicationLoadBalancerController): {{Obj: &core.Shoot{}}},
-			NewNamespacedCloudProfileValidator(mgr):                                          {{Obj: &core.NamespacedCloudProfile{}}},
+			NewShootValidator(mgr, DefaultAddOptions.AllowApplicationLoadBalancerController, DefaultAddOptions.ValidateAPIEndpoints): {{Obj: &core.Shoot{}}},
+			NewNamespacedCloudProfileValidator(mgr): {{Obj: &core.NamespacedCloudProfile{}}},
 		},
 		Target: extensionswebhook.TargetSeed,
 		ObjectSelector: &metav1.LabelSelector{
@@ -6,25 +6,31 @@ package loader
 
 import (
 	"fmt"
+	"net"
 	"os"
 	"regexp"
+	"slices"
+	"strconv"
+	"strings"
 
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/runtime/serializer/json"
 	"k8s.io/apimachinery/pkg/runtime/serializer/versioning"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/apis/config"
 	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/apis/config/install"
+	infrainternal "github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/internal/infrastructure"
 )
 
 var (
 	codec  runtime.Codec
 	scheme *runtime.Scheme
-	// labelKeyRegex validates that domain parts conform to DNS-like naming rules.
-	// Allowed: alphanumeric, hyphens, underscores and dots.
-	// Start and end must be alphanumeric.
-	labelKeyRegex = regexp.MustCompile(`^[a-zA-Z0-9]([-a-zA-Z0-9_.]*[a-zA-Z0-9])?$`)
 )
 
 func init() {
@@ -82,14 +88,212 @@ func applyDefaults(cfg *config.ControllerConfiguration) {
 		// It will lead to orphaned cloud resources without a migration plan.
 		cfg.CustomLabelDomain = "kubernetes.io"
 	}
+	if cfg.Infrastructure.EmptySSHPublicKeyPolicy == "" {
+		cfg.Infrastructure.EmptySSHPublicKeyPolicy = config.EmptySSHPublicKeyPolicySkip
+	}
+	if cfg.Infrastructure.SecurityGroupDescription == "" {
+		cfg.Infrastructure.SecurityGroupDescription = infrainternal.DefaultSecurityGroupDescription
+	}
+	if cfg.Infrastructure.RouterInterfaceTimeout == nil {
+		cfg.Infrastructure.RouterInterfaceTimeout = &metav1.Duration{Duration: infrainternal.DefaultRouterInterfaceTimeout}
+	}
+	if cfg.Infrastructure.AllowMetadataServiceEgress == nil {
+		cfg.Infrastructure.AllowMetadataServiceEgress = new(true)
+	}
+	if cfg.Infrastructure.LoadBalancerRequestTimeout == nil {
+		cfg.Infrastructure.LoadBalancerRequestTimeout = &metav1.Duration{Duration: infrainternal.DefaultLoadBalancerRequestTimeout}
+	}
+	if cfg.Infrastructure.LoadBalancerRequestRetries == nil {
+		cfg.Infrastructure.LoadBalancerRequestRetries = new(int32(infrainternal.DefaultLoadBalancerRequestRetries))
+	}
+	if cfg.ControlPlane.CredentialsRotationHistoryLimit == nil {
+		cfg.ControlPlane.CredentialsRotationHistoryLimit = new(int32(3))
+	}
+	if cfg.ControlPlane.MalformedEmergencyAccessSecretPolicy == "" {
+		cfg.ControlPlane.MalformedEmergencyAccessSecretPolicy = config.MalformedEmergencyAccessSecretPolicyReject
+	}
+	if cfg.ControlPlane.ClusterLabelValueSource == "" {
+		cfg.ControlPlane.ClusterLabelValueSource = config.ClusterLabelValueSourceTechnicalID
+	}
+	if cfg.ControlPlane.CloudControllerManagerMetricsPort == nil {
+		cfg.ControlPlane.CloudControllerManagerMetricsPort = new(int32(defaultMetricsPort))
+	}
+	if cfg.ControlPlane.CSIControllerMetricsPort == nil {
+		cfg.ControlPlane.CSIControllerMetricsPort = new(int32(defaultMetricsPort))
+	}
 }
 
 // validate validates the configuration and all its fields.
 func validate(cfg *config.ControllerConfiguration) error {
-	// Validate customLabelDomain
-	if !labelKeyRegex.MatchString(cfg.CustomLabelDomain) {
-		return fmt.Errorf("invalid customLabelDomain %q: must start and end with alphanumeric characters and may contain hyphens, underscores and dots", cfg.CustomLabelDomain)
+	// the custom label domain is the prefix of label keys, which must be a DNS subdomain
+	if errs := validation.IsDNS1123Subdomain(cfg.CustomLabelDomain); len(errs) > 0 {
+		return fmt.Errorf("invalid customLabelDomain %q: %s", cfg.CustomLabelDomain, strings.Join(errs, ", "))
+	}
+
+	switch cfg.Infrastructure.EmptySSHPublicKeyPolicy {
+	case config.EmptySSHPublicKeyPolicySkip, config.EmptySSHPublicKeyPolicyReject:
+	default:
+		return fmt.Errorf("invalid infrastructure.emptySSHPublicKeyPolicy %q: must be one of %q, %q", cfg.Infrastructure.EmptySSHPublicKeyPolicy, config.EmptySSHPublicKeyPolicySkip, config.EmptySSHPublicKeyPolicyReject)
+	}
+
+	// technical IDs are restricted by the length limits of the project and shoot names, so a DNS label of maximum length
+	// is sufficient as sample to detect descriptions exceeding the length limit
+	if _, err := infrainternal.RenderSecurityGroupDescription(cfg.Infrastructure.SecurityGroupDescription, sampleTechnicalID); err != nil {
+		return fmt.Errorf("invalid infrastructure.securityGroupDescription: %w", err)
+	}
+
+	if cfg.Infrastructure.RouterInterfaceTimeout.Duration <= 0 {
+		return fmt.Errorf("invalid infrastructure.routerInterfaceTimeout %q: must be positive", cfg.Infrastructure.RouterInterfaceTimeout.Duration)
+	}
+	if cfg.Infrastructure.LoadBalancerRequestTimeout.Duration <= 0 {
+		return fmt.Errorf("invalid infrastructure.loadBalancerRequestTimeout %q: must be positive", cfg.Infrastructure.LoadBalancerRequestTimeout.Duration)
+	}
+	if *cfg.Infrastructure.LoadBalancerRequestRetries < 0 {
+		return fmt.Errorf("invalid infrastructure.loadBalancerRequestRetries %d: must not be negative", *cfg.Infrastructure.LoadBalancerRequestRetries)
+	}
+
+	if name := cfg.Worker.MachineCredentialsSecretName; name != "" {
+		if errs := validation.IsDNS1123Subdomain(name); len(errs) > 0 {
+			return fmt.Errorf("invalid worker.machineCredentialsSecretName %q: %s", name, strings.Join(errs, ", "))
+		}
+	}
+
+	return validateControlPlane(&cfg.ControlPlane, field.NewPath("controlPlane")).ToAggregate()
+}
+
+const (
+	// defaultMetricsPort is the default port of the metrics endpoints of the control plane components.
+	defaultMetricsPort = 9090
+	// cloudControllerManagerPort is the secure port of the STACKIT cloud-controller-manager.
+	cloudControllerManagerPort = 10258
+)
+
+// csiControllerReservedPorts are the ports used by the sidecars of the STACKIT CSI driver controller.
+var csiControllerReservedPorts = []int32{8080, 8081, 9808}
+
+// sampleTechnicalID is used to validate the security group description template.
+var sampleTechnicalID = "shoot--" + strings.Repeat("x", validation.DNS1123LabelMaxLength-len("shoot--"))
+
+var (
+	validTolerationOperators                    = []corev1.TolerationOperator{"", corev1.TolerationOpExists, corev1.TolerationOpEqual}
+	validTaintEffects                           = []corev1.TaintEffect{"", corev1.TaintEffectNoSchedule, corev1.TaintEffectPreferNoSchedule, corev1.TaintEffectNoExecute}
+	validImagePullPolicies                      = []corev1.PullPolicy{"", corev1.PullAlways, corev1.PullIfNotPresent, corev1.PullNever}
+	validMalformedEmergencyAccessSecretPolicies = []config.MalformedEmergencyAccessSecretPolicy{config.MalformedEmergencyAccessSecretPolicyReject, config.MalformedEmergencyAccessSecretPolicyIgnore}
+	validClusterLabelValueSources               = []config.ClusterLabelValueSource{config.ClusterLabelValueSourceTechnicalID, config.ClusterLabelValueSourceShootUID}
+
+	// registryPathComponentRegex matches a path component of an image repository.
+	registryPathComponentRegex = regexp.MustCompile(`^[a-z0-9]+([._-][a-z0-9]+)*$`)
+)
+
+// validateControlPlane validates the scheduling configuration of the control plane components.
+func validateControlPlane(controlPlane *config.ControlPlaneControllerConfiguration, fldPath *field.Path) field.ErrorList {
+	allErrs := metav1validation.ValidateLabels(controlPlane.NodeSelector, fldPath.Child("nodeSelector"))
+
+	for i, toleration := range controlPlane.Tolerations {
+		idxPath := fldPath.Child("tolerations").Index(i)
+
+		if toleration.Key != "" {
+			for _, msg := range validation.IsQualifiedName(toleration.Key) {
+				allErrs = append(allErrs, field.Invalid(idxPath.Child("key"), toleration.Key, msg))
+			}
+		} else if toleration.Operator != corev1.TolerationOpExists {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("operator"), toleration.Operator, "operator must be Exists when `key` is empty"))
+		}
+
+		if !slices.Contains(validTolerationOperators, toleration.Operator) {
+			allErrs = append(allErrs, field.NotSupported(idxPath.Child("operator"), toleration.Operator, validTolerationOperators))
+		} else if toleration.Operator == corev1.TolerationOpExists && toleration.Value != "" {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("value"), toleration.Value, "value must be empty when `operator` is Exists"))
+		}
+
+		if !slices.Contains(validTaintEffects, toleration.Effect) {
+			allErrs = append(allErrs, field.NotSupported(idxPath.Child("effect"), toleration.Effect, validTaintEffects))
+		}
+		if toleration.TolerationSeconds != nil && toleration.Effect != corev1.TaintEffectNoExecute {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("effect"), toleration.Effect, "effect must be NoExecute when `tolerationSeconds` is set"))
+		}
+	}
+
+	if limit := controlPlane.CredentialsRotationHistoryLimit; limit != nil && *limit < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("credentialsRotationHistoryLimit"), *limit, "must not be negative"))
+	}
+
+	if !slices.Contains(validImagePullPolicies, controlPlane.ImagePullPolicy) {
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("imagePullPolicy"), controlPlane.ImagePullPolicy, validImagePullPolicies))
+	}
+
+	if controlPlane.ImageRegistryMirror != "" {
+		allErrs = append(allErrs, validateImageRegistryMirror(controlPlane.ImageRegistryMirror, fldPath.Child("imageRegistryMirror"))...)
+	}
+
+	if !slices.Contains(validMalformedEmergencyAccessSecretPolicies, controlPlane.MalformedEmergencyAccessSecretPolicy) {
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("malformedEmergencyAccessSecretPolicy"), controlPlane.MalformedEmergencyAccessSecretPolicy, validMalformedEmergencyAccessSecretPolicies))
+	}
+
+	if !slices.Contains(validClusterLabelValueSources, controlPlane.ClusterLabelValueSource) {
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("clusterLabelValueSource"), controlPlane.ClusterLabelValueSource, validClusterLabelValueSources))
+	}
+
+	if controlPlane.MetricsBindAddress != "" {
+		if ip := net.ParseIP(controlPlane.MetricsBindAddress); ip == nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("metricsBindAddress"), controlPlane.MetricsBindAddress, "must be a valid IP address"))
+		} else if !ip.IsUnspecified() {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("metricsBindAddress"), controlPlane.MetricsBindAddress, "must be an unspecified address (0.0.0.0 or ::) as the IPs of the pods are not known in advance"))
+		}
+	}
+	if port := controlPlane.CloudControllerManagerMetricsPort; port != nil {
+		allErrs = append(allErrs, validateMetricsPort(*port, []int32{cloudControllerManagerPort}, fldPath.Child("cloudControllerManagerMetricsPort"))...)
+	}
+	if port := controlPlane.CSIControllerMetricsPort; port != nil {
+		allErrs = append(allErrs, validateMetricsPort(*port, csiControllerReservedPorts, fldPath.Child("csiControllerMetricsPort"))...)
+	}
+
+	return allErrs
+}
+
+// validateMetricsPort validates that the metrics port is a valid port which does not collide with other ports of the
+// component.
+func validateMetricsPort(port int32, reservedPorts []int32, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	for _, msg := range validation.IsValidPortNum(int(port)) {
+		allErrs = append(allErrs, field.Invalid(fldPath, port, msg))
+	}
+	if slices.Contains(reservedPorts, port) {
+		allErrs = append(allErrs, field.Invalid(fldPath, port, fmt.Sprintf("must not be one of the ports used by the component: %v", reservedPorts)))
+	}
+
+	return allErrs
+}
+
+// validateImageRegistryMirror validates that the mirror is a registry host with an optional port and path prefix.
+func validateImageRegistryMirror(mirror string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	hostPort, path, hasPath := strings.Cut(mirror, "/")
+	host, port, hasPort := strings.Cut(hostPort, ":")
+	if net.ParseIP(host) == nil {
+		for _, msg := range validation.IsDNS1123Subdomain(host) {
+			allErrs = append(allErrs, field.Invalid(fldPath, mirror, "invalid registry host: "+msg))
+		}
+	}
+	if hasPort {
+		portNum, err := strconv.Atoi(port)
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath, mirror, "invalid registry port: must be a number"))
+		} else {
+			for _, msg := range validation.IsValidPortNum(portNum) {
+				allErrs = append(allErrs, field.Invalid(fldPath, mirror, "invalid registry port: "+msg))
+			}
+		}
+	}
+	if hasPath {
+		for component := range strings.SplitSeq(path, "/") {
+			if !registryPathComponentRegex.MatchString(component) {
+				allErrs = append(allErrs, field.Invalid(fldPath, mirror, fmt.Sprintf("invalid path component %q", component)))
+			}
+		}
 	}
 
-	return nil
+	return allErrs
 }
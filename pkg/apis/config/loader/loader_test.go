@@ -6,11 +6,17 @@ package loader_test
 
 import (
 	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/types"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/apis/config"
 	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/apis/config/loader"
 )
 
@@ -32,6 +38,7 @@ customLabelDomain: %s
 			cfg, err := loader.Load([]byte{})
 			Expect(err).NotTo(HaveOccurred())
 			Expect(cfg.CustomLabelDomain).To(Equal("kubernetes.io"))
+			Expect(cfg.Infrastructure.EmptySSHPublicKeyPolicy).To(Equal(config.EmptySSHPublicKeyPolicySkip))
 		})
 
 		DescribeTable("should accept valid customLabelDomain values",
@@ -44,9 +51,7 @@ customLabelDomain: %s
 			Entry("custom ske.stackit.cloud", "ske.stackit.cloud"),
 			Entry("custom example.com", "example.com"),
 			Entry("single character", "a"),
-			Entry("mixed case", "MyDomain.Com"),
 			Entry("with hyphens", "example-domain.io"),
-			Entry("with underscores", "example_domain.com"),
 			Entry("alphanumeric", "a1b2c3"),
 		)
 
@@ -64,7 +69,271 @@ customLabelDomain: %s
 			Entry("contains at sign", "invalid@domain.com"),
 			Entry("contains slash", "example.com/part"),
 			Entry("only special characters", "---"),
+			Entry("mixed case", "MyDomain.Com"),
+			Entry("with underscores", "example_domain.com"),
+			Entry("too long", strings.Repeat("a.", 127)+"a"),
+		)
+	})
+
+	Describe("#Load infrastructure", func() {
+		buildConfigYAML := func(policy string) []byte {
+			return fmt.Appendf(nil, `apiVersion: stackit.provider.extensions.config.stackit.cloud/v1alpha1
+kind: ControllerConfiguration
+infrastructure:
+  emptySSHPublicKeyPolicy: %s
+`, policy)
+		}
+
+		DescribeTable("should accept valid emptySSHPublicKeyPolicy values",
+			func(policy string, expected config.EmptySSHPublicKeyPolicy) {
+				cfg, err := loader.Load(buildConfigYAML(policy))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cfg.Infrastructure.EmptySSHPublicKeyPolicy).To(Equal(expected))
+			},
+			Entry("default", `""`, config.EmptySSHPublicKeyPolicySkip),
+			Entry("skip", "Skip", config.EmptySSHPublicKeyPolicySkip),
+			Entry("reject", "Reject", config.EmptySSHPublicKeyPolicyReject),
+		)
+
+		It("should reject an unknown emptySSHPublicKeyPolicy", func() {
+			_, err := loader.Load(buildConfigYAML("Ignore"))
+			Expect(err).To(MatchError(ContainSubstring("invalid infrastructure.emptySSHPublicKeyPolicy")))
+		})
+
+		It("should default the securityGroupDescription", func() {
+			cfg, err := loader.Load(buildConfigYAML("Skip"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfg.Infrastructure.SecurityGroupDescription).To(Equal("Cluster Nodes"))
+		})
+
+		DescribeTable("should validate the securityGroupDescription",
+			func(description string, matcher types.GomegaMatcher) {
+				_, err := loader.Load(fmt.Appendf(nil, `apiVersion: stackit.provider.extensions.config.stackit.cloud/v1alpha1
+kind: ControllerConfiguration
+infrastructure:
+  securityGroupDescription: %q
+`, description))
+				Expect(err).To(matcher)
+			},
+			Entry("static", "Nodes", Not(HaveOccurred())),
+			Entry("with technical ID", "Nodes of {{ .TechnicalID }}", Not(HaveOccurred())),
+			Entry("invalid template", "Nodes of {{ .TechnicalID", MatchError(ContainSubstring("invalid infrastructure.securityGroupDescription"))),
+			Entry("unknown field", "Nodes of {{ .Shoot }}", MatchError(ContainSubstring("invalid infrastructure.securityGroupDescription"))),
+			Entry("too long", strings.Repeat("x", 200)+"{{ .TechnicalID }}", MatchError(ContainSubstring("must not be longer than 255 characters"))),
+		)
+
+		It("should default the routerInterfaceTimeout", func() {
+			cfg, err := loader.Load(buildConfigYAML("Skip"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfg.Infrastructure.RouterInterfaceTimeout).To(Equal(&metav1.Duration{Duration: 5 * time.Minute}))
+		})
+
+		DescribeTable("should validate the routerInterfaceTimeout",
+			func(timeout string, matcher types.GomegaMatcher) {
+				_, err := loader.Load(fmt.Appendf(nil, `apiVersion: stackit.provider.extensions.config.stackit.cloud/v1alpha1
+kind: ControllerConfiguration
+infrastructure:
+  routerInterfaceTimeout: %s
+`, timeout))
+				Expect(err).To(matcher)
+			},
+			Entry("positive", "10m", Not(HaveOccurred())),
+			Entry("zero", "0s", MatchError(ContainSubstring("invalid infrastructure.routerInterfaceTimeout"))),
+			Entry("negative", "-1m", MatchError(ContainSubstring("invalid infrastructure.routerInterfaceTimeout"))),
+		)
+
+		It("should default the load balancer request options", func() {
+			cfg, err := loader.Load(buildConfigYAML("Skip"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfg.Infrastructure.LoadBalancerRequestTimeout).To(Equal(&metav1.Duration{Duration: 15 * time.Second}))
+			Expect(cfg.Infrastructure.LoadBalancerRequestRetries).To(HaveValue(BeEquivalentTo(3)))
+		})
+
+		DescribeTable("should validate the load balancer request options",
+			func(options string, matcher types.GomegaMatcher) {
+				_, err := loader.Load([]byte(`apiVersion: stackit.provider.extensions.config.stackit.cloud/v1alpha1
+kind: ControllerConfiguration
+infrastructure:
+` + options))
+				Expect(err).To(matcher)
+			},
+			Entry("valid", "  loadBalancerRequestTimeout: 30s\n  loadBalancerRequestRetries: 0\n", Not(HaveOccurred())),
+			Entry("zero timeout", "  loadBalancerRequestTimeout: 0s\n", MatchError(ContainSubstring("invalid infrastructure.loadBalancerRequestTimeout"))),
+			Entry("negative retries", "  loadBalancerRequestRetries: -1\n", MatchError(ContainSubstring("invalid infrastructure.loadBalancerRequestRetries"))),
+		)
+
+		It("should allow the metadata service egress by default", func() {
+			cfg, err := loader.Load(buildConfigYAML("Skip"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfg.Infrastructure.AllowMetadataServiceEgress).To(HaveValue(BeTrue()))
+		})
+
+		It("should keep a disabled metadata service egress", func() {
+			cfg, err := loader.Load([]byte(`apiVersion: stackit.provider.extensions.config.stackit.cloud/v1alpha1
+kind: ControllerConfiguration
+infrastructure:
+  allowMetadataServiceEgress: false
+`))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfg.Infrastructure.AllowMetadataServiceEgress).To(HaveValue(BeFalse()))
+		})
+	})
+
+	Describe("#Load controlPlane", func() {
+		buildConfigYAML := func(controlPlane string) []byte {
+			return fmt.Appendf(nil, `apiVersion: stackit.provider.extensions.config.stackit.cloud/v1alpha1
+kind: ControllerConfiguration
+controlPlane:
+%s`, controlPlane)
+		}
+
+		It("should load nodeSelector and tolerations", func() {
+			cfg, err := loader.Load(buildConfigYAML(`  nodeSelector:
+    worker.gardener.cloud/pool: control-plane
+  tolerations:
+  - key: dedicated
+    operator: Equal
+    value: control-plane
+    effect: NoSchedule
+`))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfg.ControlPlane.NodeSelector).To(Equal(map[string]string{"worker.gardener.cloud/pool": "control-plane"}))
+			Expect(cfg.ControlPlane.Tolerations).To(Equal([]corev1.Toleration{{
+				Key:      "dedicated",
+				Operator: corev1.TolerationOpEqual,
+				Value:    "control-plane",
+				Effect:   corev1.TaintEffectNoSchedule,
+			}}))
+		})
+
+		DescribeTable("should reject invalid scheduling configuration",
+			func(controlPlane, field string) {
+				_, err := loader.Load(buildConfigYAML(controlPlane))
+				Expect(err).To(MatchError(ContainSubstring(field)))
+			},
+			Entry("invalid nodeSelector key", "  nodeSelector:\n    -invalid: foo\n", "controlPlane.nodeSelector"),
+			Entry("invalid toleration key", "  tolerations:\n  - key: -invalid\n    operator: Exists\n", "controlPlane.tolerations[0].key"),
+			Entry("empty key without Exists", "  tolerations:\n  - operator: Equal\n", "controlPlane.tolerations[0].operator"),
+			Entry("unknown operator", "  tolerations:\n  - key: foo\n    operator: In\n", "controlPlane.tolerations[0].operator"),
+			Entry("value with Exists", "  tolerations:\n  - key: foo\n    operator: Exists\n    value: bar\n", "controlPlane.tolerations[0].value"),
+			Entry("unknown effect", "  tolerations:\n  - key: foo\n    effect: NoRun\n", "controlPlane.tolerations[0].effect"),
+			Entry("tolerationSeconds without NoExecute", "  tolerations:\n  - key: foo\n    effect: NoSchedule\n    tolerationSeconds: 30\n", "controlPlane.tolerations[0].effect"),
+			Entry("negative credentialsRotationHistoryLimit", "  credentialsRotationHistoryLimit: -1\n", "controlPlane.credentialsRotationHistoryLimit"),
+			Entry("unknown imagePullPolicy", "  imagePullPolicy: Sometimes\n", "controlPlane.imagePullPolicy"),
+			Entry("imageRegistryMirror with scheme", "  imageRegistryMirror: https://mirror.example.com\n", "controlPlane.imageRegistryMirror"),
+			Entry("imageRegistryMirror with invalid host", "  imageRegistryMirror: Mirror_Example\n", "controlPlane.imageRegistryMirror"),
+			Entry("imageRegistryMirror with invalid port", "  imageRegistryMirror: mirror.example.com:99999\n", "controlPlane.imageRegistryMirror"),
+			Entry("imageRegistryMirror with trailing slash", "  imageRegistryMirror: mirror.example.com/\n", "controlPlane.imageRegistryMirror"),
+			Entry("unknown malformedEmergencyAccessSecretPolicy", "  malformedEmergencyAccessSecretPolicy: Warn\n", "controlPlane.malformedEmergencyAccessSecretPolicy"),
+			Entry("unknown clusterLabelValueSource", "  clusterLabelValueSource: ShootName\n", "controlPlane.clusterLabelValueSource"),
+			Entry("invalid metricsBindAddress", "  metricsBindAddress: localhost\n", "controlPlane.metricsBindAddress"),
+			Entry("loopback metricsBindAddress", "  metricsBindAddress: 127.0.0.1\n", "controlPlane.metricsBindAddress"),
+			Entry("fixed metricsBindAddress", "  metricsBindAddress: 10.0.0.5\n", "controlPlane.metricsBindAddress"),
+			Entry("cloudControllerManagerMetricsPort out of range", "  cloudControllerManagerMetricsPort: 70000\n", "controlPlane.cloudControllerManagerMetricsPort"),
+			Entry("cloudControllerManagerMetricsPort of the secure port", "  cloudControllerManagerMetricsPort: 10258\n", "controlPlane.cloudControllerManagerMetricsPort"),
+			Entry("csiControllerMetricsPort out of range", "  csiControllerMetricsPort: 0\n", "controlPlane.csiControllerMetricsPort"),
+			Entry("csiControllerMetricsPort of a sidecar", "  csiControllerMetricsPort: 8080\n", "controlPlane.csiControllerMetricsPort"),
+		)
+
+		It("should default the malformedEmergencyAccessSecretPolicy", func() {
+			cfg, err := loader.Load(buildConfigYAML("  nodeSelector: {}\n"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfg.ControlPlane.MalformedEmergencyAccessSecretPolicy).To(Equal(config.MalformedEmergencyAccessSecretPolicyReject))
+		})
+
+		It("should load the Ignore malformedEmergencyAccessSecretPolicy", func() {
+			cfg, err := loader.Load(buildConfigYAML("  malformedEmergencyAccessSecretPolicy: Ignore\n"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfg.ControlPlane.MalformedEmergencyAccessSecretPolicy).To(Equal(config.MalformedEmergencyAccessSecretPolicyIgnore))
+		})
+
+		DescribeTable("should load the clusterLabelValueSource",
+			func(controlPlane string, expected config.ClusterLabelValueSource) {
+				cfg, err := loader.Load(buildConfigYAML(controlPlane))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cfg.ControlPlane.ClusterLabelValueSource).To(Equal(expected))
+			},
+			Entry("default", "  nodeSelector: {}\n", config.ClusterLabelValueSourceTechnicalID),
+			Entry("TechnicalID", "  clusterLabelValueSource: TechnicalID\n", config.ClusterLabelValueSourceTechnicalID),
+			Entry("ShootUID", "  clusterLabelValueSource: ShootUID\n", config.ClusterLabelValueSourceShootUID),
+		)
+
+		It("should not default the imagePullPolicy", func() {
+			cfg, err := loader.Load(buildConfigYAML("  nodeSelector: {}\n"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfg.ControlPlane.ImagePullPolicy).To(BeEmpty())
+			Expect(cfg.ControlPlane.ImageRegistryMirror).To(BeEmpty())
+		})
+
+		DescribeTable("should accept valid imageRegistryMirror values",
+			func(mirror string) {
+				cfg, err := loader.Load(buildConfigYAML("  imageRegistryMirror: " + mirror + "\n"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cfg.ControlPlane.ImageRegistryMirror).To(Equal(mirror))
+			},
+			Entry("host", "mirror.example.com"),
+			Entry("host with port", "mirror.example.com:5000"),
+			Entry("host with path", "mirror.example.com/proxy/ske"),
+			Entry("IP with port", "10.0.0.1:5000"),
+			Entry("localhost", "localhost:5000"),
 		)
+
+		It("should default the metrics configuration", func() {
+			cfg, err := loader.Load(buildConfigYAML("  nodeSelector: {}\n"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfg.ControlPlane.MetricsBindAddress).To(BeEmpty())
+			Expect(cfg.ControlPlane.CloudControllerManagerMetricsPort).To(HaveValue(BeEquivalentTo(9090)))
+			Expect(cfg.ControlPlane.CSIControllerMetricsPort).To(HaveValue(BeEquivalentTo(9090)))
+			Expect(cfg.ControlPlane.ServiceMonitors).To(BeFalse())
+		})
+
+		It("should load the metrics configuration", func() {
+			cfg, err := loader.Load(buildConfigYAML("  metricsBindAddress: 0.0.0.0\n  cloudControllerManagerMetricsPort: 9091\n  csiControllerMetricsPort: 9092\n  serviceMonitors: true\n"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfg.ControlPlane.MetricsBindAddress).To(Equal("0.0.0.0"))
+			Expect(cfg.ControlPlane.CloudControllerManagerMetricsPort).To(HaveValue(BeEquivalentTo(9091)))
+			Expect(cfg.ControlPlane.CSIControllerMetricsPort).To(HaveValue(BeEquivalentTo(9092)))
+			Expect(cfg.ControlPlane.ServiceMonitors).To(BeTrue())
+		})
+
+		It("should accept the IPv6 unspecified address as metricsBindAddress", func() {
+			cfg, err := loader.Load(buildConfigYAML("  metricsBindAddress: \"::\"\n"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfg.ControlPlane.MetricsBindAddress).To(Equal("::"))
+		})
+
+		It("should default credentialsRotationHistoryLimit", func() {
+			cfg, err := loader.Load(buildConfigYAML("  nodeSelector: {}\n"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfg.ControlPlane.CredentialsRotationHistoryLimit).To(HaveValue(BeEquivalentTo(3)))
+		})
+
+		It("should allow disabling the credentials rotation history", func() {
+			cfg, err := loader.Load(buildConfigYAML("  credentialsRotationHistoryLimit: 0\n"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfg.ControlPlane.CredentialsRotationHistoryLimit).To(HaveValue(BeEquivalentTo(0)))
+		})
+	})
+
+	Describe("#Load worker", func() {
+		buildConfigYAML := func(name string) []byte {
+			return fmt.Appendf(nil, `apiVersion: stackit.provider.extensions.config.stackit.cloud/v1alpha1
+kind: ControllerConfiguration
+worker:
+  machineCredentialsSecretName: %q
+`, name)
+		}
+
+		It("should accept a valid machineCredentialsSecretName", func() {
+			cfg, err := loader.Load(buildConfigYAML("machine-credentials"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfg.Worker.MachineCredentialsSecretName).To(Equal("machine-credentials"))
+		})
+
+		It("should reject an invalid machineCredentialsSecretName", func() {
+			_, err := loader.Load(buildConfigYAML("Machine_Credentials"))
+			Expect(err).To(MatchError(ContainSubstring("invalid worker.machineCredentialsSecretName")))
+		})
 	})
 
 	Describe("#LoadFromFile", func() {
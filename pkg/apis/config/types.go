@@ -6,6 +6,7 @@ package config
 
 import (
 	healthcheckconfig "github.com/gardener/gardener/extensions/pkg/apis/config/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	componentbaseconfig "k8s.io/component-base/config/v1alpha1"
@@ -32,12 +33,119 @@ type ControllerConfiguration struct {
 	RegistryCaches []RegistryCacheConfiguration
 
 	// CustomLabelDomain is the domain prefix for custom labels applied to STACKIT infrastructure resources.
-	// For example, cluster labels will use "<domain>/cluster" (default: "kubernetes.io").
+	// For example, cluster labels will use "<domain>/cluster" (default: "kubernetes.io"). It must be a valid DNS
+	// subdomain.
 	// NOTE: Only change this if you know what you are doing!!
 	// Changing without a migration plan could lead to orphaned STACKIT resources.
 	CustomLabelDomain string
+
+	// ControlPlane is the configuration for the control plane controller.
+	ControlPlane ControlPlaneControllerConfiguration
+
+	// Infrastructure is the configuration for the infrastructure controller.
+	Infrastructure InfrastructureControllerConfiguration
+
+	// Worker is the configuration for the worker controller.
+	Worker WorkerControllerConfiguration
+}
+
+// ControlPlaneControllerConfiguration is the configuration for the control plane controller.
+type ControlPlaneControllerConfiguration struct {
+	// NodeSelector is added to the control plane components deployed into the seed, i.e. the cloud-controller-manager,
+	// the CSI driver controllers, the application load balancer controller and the pod identity webhook.
+	NodeSelector map[string]string
+	// Tolerations are added to the control plane components deployed into the seed.
+	Tolerations []corev1.Toleration
+	// CredentialsRotationHistoryLimit is the number of observed cloudprovider secret checksums with their rotation
+	// timestamps which are recorded in an annotation on the ControlPlane. A value of 0 disables the recording.
+	CredentialsRotationHistoryLimit *int32
+	// ImagePullPolicy is the pull policy of the images of the control plane components deployed into the seed.
+	ImagePullPolicy corev1.PullPolicy
+	// ImageRegistryMirror is a registry host with an optional path prefix which replaces the registry of all images
+	// deployed by the control plane controller.
+	ImageRegistryMirror string
+	// MalformedEmergencyAccessSecretPolicy defines how the control plane controller handles a malformed load balancer
+	// emergency access secret.
+	MalformedEmergencyAccessSecretPolicy MalformedEmergencyAccessSecretPolicy
+	// ClusterLabelValueSource defines which identifier of the shoot is used as value of the cluster label of STACKIT
+	// resources created by the control plane components.
+	ClusterLabelValueSource ClusterLabelValueSource
+	// MetricsBindAddress is the IP address the metrics endpoints of the STACKIT cloud-controller-manager and the STACKIT
+	// CSI driver controller bind to. It must be an unspecified address, i.e. 0.0.0.0 or ::, as the IPs of the pods are not
+	// known in advance and Prometheus must be able to reach the endpoints. If empty, they bind to all interfaces.
+	MetricsBindAddress string
+	// CloudControllerManagerMetricsPort is the port of the metrics endpoint of the STACKIT cloud-controller-manager.
+	CloudControllerManagerMetricsPort *int32
+	// CSIControllerMetricsPort is the port of the metrics endpoint of the STACKIT CSI driver controller.
+	CSIControllerMetricsPort *int32
+	// ServiceMonitors deploys ServiceMonitors for the metrics endpoints of the STACKIT cloud-controller-manager and the
+	// STACKIT CSI driver controller if the ServiceMonitor CRD of the Prometheus operator exists in the seed.
+	ServiceMonitors bool
+}
+
+// ClusterLabelValueSource defines which identifier of the shoot is used as cluster label value.
+type ClusterLabelValueSource string
+
+const (
+	// ClusterLabelValueSourceTechnicalID uses the technical ID of the shoot.
+	ClusterLabelValueSourceTechnicalID ClusterLabelValueSource = "TechnicalID"
+	// ClusterLabelValueSourceShootUID uses the UID of the shoot.
+	ClusterLabelValueSourceShootUID ClusterLabelValueSource = "ShootUID"
+)
+
+// MalformedEmergencyAccessSecretPolicy defines how a malformed load balancer emergency access secret is handled.
+type MalformedEmergencyAccessSecretPolicy string
+
+const (
+	// MalformedEmergencyAccessSecretPolicyReject fails the reconciliation of the control plane.
+	MalformedEmergencyAccessSecretPolicyReject MalformedEmergencyAccessSecretPolicy = "Reject"
+	// MalformedEmergencyAccessSecretPolicyIgnore ignores the secret and reports it in a condition of the ControlPlane.
+	// The regular load balancer API access is used instead.
+	MalformedEmergencyAccessSecretPolicyIgnore MalformedEmergencyAccessSecretPolicy = "Ignore"
+)
+
+// InfrastructureControllerConfiguration is the configuration for the infrastructure controller.
+type InfrastructureControllerConfiguration struct {
+	// EmptySSHPublicKeyPolicy defines how the infrastructure controller handles an Infrastructure without SSH public key.
+	EmptySSHPublicKeyPolicy EmptySSHPublicKeyPolicy
+	// AggregateEgressCIDRs merges contiguous egress IPs into the smallest covering CIDRs in the Infrastructure status
+	// instead of reporting one host CIDR per egress IP.
+	AggregateEgressCIDRs bool
+	// SecurityGroupDescription is the text/template of the description of the security group of the nodes.
+	SecurityGroupDescription string
+	// RouterInterfaceTimeout is the maximum duration to wait for a new router interface to become active.
+	RouterInterfaceTimeout *metav1.Duration
+	// DeleteDuplicateSecurityGroupRules deletes existing security group rules which are exact duplicates of another
+	// desired rule of the security group of the nodes.
+	DeleteDuplicateSecurityGroupRules bool
+	// AllowMetadataServiceEgress adds an explicit egress rule for the instance metadata service to the security group
+	// of the nodes.
+	AllowMetadataServiceEgress *bool
+	// LoadBalancerRequestTimeout is the timeout of a single request to the STACKIT load balancer APIs when deleting
+	// the infrastructure.
+	LoadBalancerRequestTimeout *metav1.Duration
+	// LoadBalancerRequestRetries is the number of retries of requests to the STACKIT load balancer APIs failing with a
+	// transient error when deleting the infrastructure.
+	LoadBalancerRequestRetries *int32
 }
 
+// WorkerControllerConfiguration is the configuration for the worker controller.
+type WorkerControllerConfiguration struct {
+	// MachineCredentialsSecretName is the name of a secret in the namespace of the Worker which is referenced as
+	// credentials of the machine classes instead of the cloudprovider secret.
+	MachineCredentialsSecretName string
+}
+
+// EmptySSHPublicKeyPolicy defines how an empty SSH public key is handled.
+type EmptySSHPublicKeyPolicy string
+
+const (
+	// EmptySSHPublicKeyPolicySkip skips the creation of the SSH key pair. Nodes will not be accessible via SSH.
+	EmptySSHPublicKeyPolicySkip EmptySSHPublicKeyPolicy = "Skip"
+	// EmptySSHPublicKeyPolicyReject fails the reconciliation with a configuration error.
+	EmptySSHPublicKeyPolicyReject EmptySSHPublicKeyPolicy = "Reject"
+)
+
 // ETCD is an etcd configuration.
 type ETCD struct {
 	// ETCDStorage is the etcd storage configuration.
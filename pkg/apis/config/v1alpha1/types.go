@@ -6,6 +6,7 @@ package v1alpha1
 
 import (
 	healthcheckconfigv1alpha1 "github.com/gardener/gardener/extensions/pkg/apis/config/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	componentbaseconfigv1alpha1 "k8s.io/component-base/config/v1alpha1"
@@ -34,11 +35,165 @@ type ControllerConfiguration struct {
 	RegistryCaches []RegistryCacheConfiguration `json:"registryCaches,omitempty"`
 
 	// CustomLabelDomain is the domain prefix for custom labels applied to STACKIT infrastructure resources.
-	// For example, cluster labels will use "<domain>/cluster" (default: "kubernetes.io").
+	// For example, cluster labels will use "<domain>/cluster" (default: "kubernetes.io"). It must be a valid DNS
+	// subdomain.
 	// +optional
 	CustomLabelDomain string `json:"customLabelDomain,omitempty"`
+
+	// ControlPlane is the configuration for the control plane controller.
+	// +optional
+	ControlPlane ControlPlaneControllerConfiguration `json:"controlPlane"`
+
+	// Infrastructure is the configuration for the infrastructure controller.
+	// +optional
+	Infrastructure InfrastructureControllerConfiguration `json:"infrastructure,omitempty"`
+
+	// Worker is the configuration for the worker controller.
+	// +optional
+	Worker WorkerControllerConfiguration `json:"worker,omitempty"`
 }
 
+// ControlPlaneControllerConfiguration is the configuration for the control plane controller.
+type ControlPlaneControllerConfiguration struct {
+	// NodeSelector is added to the control plane components deployed into the seed, i.e. the cloud-controller-manager,
+	// the CSI driver controllers, the application load balancer controller and the pod identity webhook.
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// Tolerations are added to the control plane components deployed into the seed.
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+	// CredentialsRotationHistoryLimit is the number of observed cloudprovider secret checksums with their rotation
+	// timestamps which are recorded in an annotation on the ControlPlane. A value of 0 disables the recording.
+	// Defaults to 3.
+	// +optional
+	CredentialsRotationHistoryLimit *int32 `json:"credentialsRotationHistoryLimit,omitempty"`
+	// ImagePullPolicy is the pull policy of the images of the control plane components deployed into the seed.
+	// If not set, the pull policies of the charts are kept.
+	// +optional
+	ImagePullPolicy corev1.PullPolicy `json:"imagePullPolicy,omitempty"`
+	// ImageRegistryMirror is a registry host with an optional path prefix which replaces the registry of all images
+	// deployed by the control plane controller, e.g. `mirror.example.com:5000/proxy`.
+	// +optional
+	ImageRegistryMirror string `json:"imageRegistryMirror,omitempty"`
+	// MalformedEmergencyAccessSecretPolicy defines how the control plane controller handles a malformed load balancer
+	// emergency access secret.
+	// "Reject" (default) fails the reconciliation of the control plane.
+	// "Ignore" uses the regular load balancer API access and reports the malformed secret in a condition of the
+	// ControlPlane.
+	// +optional
+	MalformedEmergencyAccessSecretPolicy MalformedEmergencyAccessSecretPolicy `json:"malformedEmergencyAccessSecretPolicy,omitempty"`
+	// ClusterLabelValueSource defines which identifier of the shoot is used as value of the cluster label of STACKIT
+	// resources created by the control plane components.
+	// "TechnicalID" (default) uses the technical ID of the shoot.
+	// "ShootUID" uses the UID of the shoot.
+	// +optional
+	ClusterLabelValueSource ClusterLabelValueSource `json:"clusterLabelValueSource,omitempty"`
+	// MetricsBindAddress is the IP address the metrics endpoints of the STACKIT cloud-controller-manager and the STACKIT
+	// CSI driver controller bind to. It must be an unspecified address, i.e. 0.0.0.0 or ::, as the IPs of the pods are not
+	// known in advance and Prometheus must be able to reach the endpoints. If empty, they bind to all interfaces.
+	// +optional
+	MetricsBindAddress string `json:"metricsBindAddress,omitempty"`
+	// CloudControllerManagerMetricsPort is the port of the metrics endpoint of the STACKIT cloud-controller-manager.
+	// Defaults to 9090.
+	// +optional
+	CloudControllerManagerMetricsPort *int32 `json:"cloudControllerManagerMetricsPort,omitempty"`
+	// CSIControllerMetricsPort is the port of the metrics endpoint of the STACKIT CSI driver controller.
+	// Defaults to 9090.
+	// +optional
+	CSIControllerMetricsPort *int32 `json:"csiControllerMetricsPort,omitempty"`
+	// ServiceMonitors deploys ServiceMonitors for the metrics endpoints of the STACKIT cloud-controller-manager and the
+	// STACKIT CSI driver controller if the ServiceMonitor CRD of the Prometheus operator exists in the seed.
+	// +optional
+	ServiceMonitors bool `json:"serviceMonitors,omitempty"`
+}
+
+// ClusterLabelValueSource defines which identifier of the shoot is used as cluster label value.
+type ClusterLabelValueSource string
+
+const (
+	// ClusterLabelValueSourceTechnicalID uses the technical ID of the shoot.
+	ClusterLabelValueSourceTechnicalID ClusterLabelValueSource = "TechnicalID"
+	// ClusterLabelValueSourceShootUID uses the UID of the shoot.
+	ClusterLabelValueSourceShootUID ClusterLabelValueSource = "ShootUID"
+)
+
+// MalformedEmergencyAccessSecretPolicy defines how a malformed load balancer emergency access secret is handled.
+type MalformedEmergencyAccessSecretPolicy string
+
+const (
+	// MalformedEmergencyAccessSecretPolicyReject fails the reconciliation of the control plane.
+	MalformedEmergencyAccessSecretPolicyReject MalformedEmergencyAccessSecretPolicy = "Reject"
+	// MalformedEmergencyAccessSecretPolicyIgnore ignores the secret and reports it in a condition of the ControlPlane.
+	// The regular load balancer API access is used instead.
+	MalformedEmergencyAccessSecretPolicyIgnore MalformedEmergencyAccessSecretPolicy = "Ignore"
+)
+
+// InfrastructureControllerConfiguration is the configuration for the infrastructure controller.
+type InfrastructureControllerConfiguration struct {
+	// EmptySSHPublicKeyPolicy defines how the infrastructure controller handles an Infrastructure without SSH public key.
+	// "Skip" (default) does not create an SSH key pair, so nodes will not be accessible via SSH.
+	// "Reject" fails the reconciliation with a configuration error.
+	// +optional
+	EmptySSHPublicKeyPolicy EmptySSHPublicKeyPolicy `json:"emptySSHPublicKeyPolicy,omitempty"`
+	// AggregateEgressCIDRs merges contiguous egress IPs into the smallest covering CIDRs in the Infrastructure status
+	// instead of reporting one host CIDR per egress IP (default).
+	// +optional
+	AggregateEgressCIDRs bool `json:"aggregateEgressCIDRs,omitempty"`
+	// SecurityGroupDescription is the text/template of the description of the security group of the nodes, e.g.
+	// `Nodes of {{ .TechnicalID }}`. The technical ID of the shoot is available as `.TechnicalID`. The rendered
+	// description must not be longer than 255 characters. Defaults to "Cluster Nodes".
+	// Only newly created security groups get the description.
+	// +optional
+	SecurityGroupDescription string `json:"securityGroupDescription,omitempty"`
+	// RouterInterfaceTimeout is the maximum duration to wait for a new router interface to become active. It is
+	// independent of the timeout of the other infrastructure tasks, as attaching the interface can take longer in some
+	// regions. If the interface is not active in time, the reconciliation fails with a retryable error and continues
+	// in the next reconciliation. Defaults to 5m.
+	// +optional
+	RouterInterfaceTimeout *metav1.Duration `json:"routerInterfaceTimeout,omitempty"`
+	// DeleteDuplicateSecurityGroupRules deletes existing security group rules which are exact duplicates of another
+	// desired rule of the security group of the nodes. Duplicates are kept by default (false), as unknown rules of the
+	// security group are not deleted either.
+	// +optional
+	DeleteDuplicateSecurityGroupRules bool `json:"deleteDuplicateSecurityGroupRules,omitempty"`
+	// AllowMetadataServiceEgress adds an explicit egress rule for the instance metadata service (169.254.169.254) to
+	// the security group of the nodes, so that cloud-init and the kubelet keep access to it if the outgoing traffic
+	// of the nodes is restricted. Defaults to true.
+	// +optional
+	AllowMetadataServiceEgress *bool `json:"allowMetadataServiceEgress,omitempty"`
+	// LoadBalancerRequestTimeout is the timeout of a single request to the STACKIT load balancer and application load
+	// balancer APIs when deleting the infrastructure. Defaults to 15s.
+	// +optional
+	LoadBalancerRequestTimeout *metav1.Duration `json:"loadBalancerRequestTimeout,omitempty"`
+	// LoadBalancerRequestRetries is the number of retries of requests to the STACKIT load balancer and application load
+	// balancer APIs failing with a transient error (timeouts, rate limiting and server errors) when deleting the
+	// infrastructure. The retries are done with an exponential backoff. Defaults to 3.
+	// +optional
+	LoadBalancerRequestRetries *int32 `json:"loadBalancerRequestRetries,omitempty"`
+}
+
+// WorkerControllerConfiguration is the configuration for the worker controller.
+type WorkerControllerConfiguration struct {
+	// MachineCredentialsSecretName is the name of a secret in the namespace of the Worker which is referenced as
+	// credentials of the machine classes instead of the cloudprovider secret, e.g. to give the
+	// machine-controller-manager a more restricted service account. Only the name is configured globally, the secret
+	// is read from the namespace of each shoot. It must have the same format as the cloudprovider secret, contain the
+	// credentials for the STACKIT project of the shoot and is provisioned by the operator. Defaults to the cloudprovider
+	// secret.
+	// +optional
+	MachineCredentialsSecretName string `json:"machineCredentialsSecretName,omitempty"`
+}
+
+// EmptySSHPublicKeyPolicy defines how an empty SSH public key is handled.
+type EmptySSHPublicKeyPolicy string
+
+const (
+	// EmptySSHPublicKeyPolicySkip skips the creation of the SSH key pair. Nodes will not be accessible via SSH.
+	EmptySSHPublicKeyPolicySkip EmptySSHPublicKeyPolicy = "Skip"
+	// EmptySSHPublicKeyPolicyReject fails the reconciliation with a configuration error.
+	EmptySSHPublicKeyPolicyReject EmptySSHPublicKeyPolicy = "Reject"
+)
+
 // ETCD is an etcd configuration.
 type ETCD struct {
 	// ETCDStorage is the etcd storage configuration.
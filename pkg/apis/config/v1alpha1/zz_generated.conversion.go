@@ -12,7 +12,9 @@ import (
 
 	apisconfigv1alpha1 "github.com/gardener/gardener/extensions/pkg/apis/config/v1alpha1"
 	config "github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/apis/config"
+	v1 "k8s.io/api/core/v1"
 	resource "k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	conversion "k8s.io/apimachinery/pkg/conversion"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 	configv1alpha1 "k8s.io/component-base/config/v1alpha1"
@@ -25,6 +27,16 @@ func init() {
 // RegisterConversions adds conversion functions to the given scheme.
 // Public to allow building arbitrary schemes.
 func RegisterConversions(s *runtime.Scheme) error {
+	if err := s.AddGeneratedConversionFunc((*ControlPlaneControllerConfiguration)(nil), (*config.ControlPlaneControllerConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_ControlPlaneControllerConfiguration_To_config_ControlPlaneControllerConfiguration(a.(*ControlPlaneControllerConfiguration), b.(*config.ControlPlaneControllerConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.ControlPlaneControllerConfiguration)(nil), (*ControlPlaneControllerConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_ControlPlaneControllerConfiguration_To_v1alpha1_ControlPlaneControllerConfiguration(a.(*config.ControlPlaneControllerConfiguration), b.(*ControlPlaneControllerConfiguration), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*ControllerConfiguration)(nil), (*config.ControllerConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_ControllerConfiguration_To_config_ControllerConfiguration(a.(*ControllerConfiguration), b.(*config.ControllerConfiguration), scope)
 	}); err != nil {
@@ -65,6 +77,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*InfrastructureControllerConfiguration)(nil), (*config.InfrastructureControllerConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_InfrastructureControllerConfiguration_To_config_InfrastructureControllerConfiguration(a.(*InfrastructureControllerConfiguration), b.(*config.InfrastructureControllerConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.InfrastructureControllerConfiguration)(nil), (*InfrastructureControllerConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_InfrastructureControllerConfiguration_To_v1alpha1_InfrastructureControllerConfiguration(a.(*config.InfrastructureControllerConfiguration), b.(*InfrastructureControllerConfiguration), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*RegistryCacheConfiguration)(nil), (*config.RegistryCacheConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_RegistryCacheConfiguration_To_config_RegistryCacheConfiguration(a.(*RegistryCacheConfiguration), b.(*config.RegistryCacheConfiguration), scope)
 	}); err != nil {
@@ -75,9 +97,59 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*WorkerControllerConfiguration)(nil), (*config.WorkerControllerConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_WorkerControllerConfiguration_To_config_WorkerControllerConfiguration(a.(*WorkerControllerConfiguration), b.(*config.WorkerControllerConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.WorkerControllerConfiguration)(nil), (*WorkerControllerConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_WorkerControllerConfiguration_To_v1alpha1_WorkerControllerConfiguration(a.(*config.WorkerControllerConfiguration), b.(*WorkerControllerConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	return nil
+}
+
+func autoConvert_v1alpha1_ControlPlaneControllerConfiguration_To_config_ControlPlaneControllerConfiguration(in *ControlPlaneControllerConfiguration, out *config.ControlPlaneControllerConfiguration, s conversion.Scope) error {
+	out.NodeSelector = *(*map[string]string)(unsafe.Pointer(&in.NodeSelector))
+	out.Tolerations = *(*[]v1.Toleration)(unsafe.Pointer(&in.Tolerations))
+	out.CredentialsRotationHistoryLimit = (*int32)(unsafe.Pointer(in.CredentialsRotationHistoryLimit))
+	out.ImagePullPolicy = v1.PullPolicy(in.ImagePullPolicy)
+	out.ImageRegistryMirror = in.ImageRegistryMirror
+	out.MalformedEmergencyAccessSecretPolicy = config.MalformedEmergencyAccessSecretPolicy(in.MalformedEmergencyAccessSecretPolicy)
+	out.ClusterLabelValueSource = config.ClusterLabelValueSource(in.ClusterLabelValueSource)
+	out.MetricsBindAddress = in.MetricsBindAddress
+	out.CloudControllerManagerMetricsPort = (*int32)(unsafe.Pointer(in.CloudControllerManagerMetricsPort))
+	out.CSIControllerMetricsPort = (*int32)(unsafe.Pointer(in.CSIControllerMetricsPort))
+	out.ServiceMonitors = in.ServiceMonitors
 	return nil
 }
 
+// Convert_v1alpha1_ControlPlaneControllerConfiguration_To_config_ControlPlaneControllerConfiguration is an autogenerated conversion function.
+func Convert_v1alpha1_ControlPlaneControllerConfiguration_To_config_ControlPlaneControllerConfiguration(in *ControlPlaneControllerConfiguration, out *config.ControlPlaneControllerConfiguration, s conversion.Scope) error {
+	return autoConvert_v1alpha1_ControlPlaneControllerConfiguration_To_config_ControlPlaneControllerConfiguration(in, out, s)
+}
+
+func autoConvert_config_ControlPlaneControllerConfiguration_To_v1alpha1_ControlPlaneControllerConfiguration(in *config.ControlPlaneControllerConfiguration, out *ControlPlaneControllerConfiguration, s conversion.Scope) error {
+	out.NodeSelector = *(*map[string]string)(unsafe.Pointer(&in.NodeSelector))
+	out.Tolerations = *(*[]v1.Toleration)(unsafe.Pointer(&in.Tolerations))
+	out.CredentialsRotationHistoryLimit = (*int32)(unsafe.Pointer(in.CredentialsRotationHistoryLimit))
+	out.ImagePullPolicy = v1.PullPolicy(in.ImagePullPolicy)
+	out.ImageRegistryMirror = in.ImageRegistryMirror
+	out.MalformedEmergencyAccessSecretPolicy = MalformedEmergencyAccessSecretPolicy(in.MalformedEmergencyAccessSecretPolicy)
+	out.ClusterLabelValueSource = ClusterLabelValueSource(in.ClusterLabelValueSource)
+	out.MetricsBindAddress = in.MetricsBindAddress
+	out.CloudControllerManagerMetricsPort = (*int32)(unsafe.Pointer(in.CloudControllerManagerMetricsPort))
+	out.CSIControllerMetricsPort = (*int32)(unsafe.Pointer(in.CSIControllerMetricsPort))
+	out.ServiceMonitors = in.ServiceMonitors
+	return nil
+}
+
+// Convert_config_ControlPlaneControllerConfiguration_To_v1alpha1_ControlPlaneControllerConfiguration is an autogenerated conversion function.
+func Convert_config_ControlPlaneControllerConfiguration_To_v1alpha1_ControlPlaneControllerConfiguration(in *config.ControlPlaneControllerConfiguration, out *ControlPlaneControllerConfiguration, s conversion.Scope) error {
+	return autoConvert_config_ControlPlaneControllerConfiguration_To_v1alpha1_ControlPlaneControllerConfiguration(in, out, s)
+}
+
 func autoConvert_v1alpha1_ControllerConfiguration_To_config_ControllerConfiguration(in *ControllerConfiguration, out *config.ControllerConfiguration, s conversion.Scope) error {
 	out.ClientConnection = (*configv1alpha1.ClientConnectionConfiguration)(unsafe.Pointer(in.ClientConnection))
 	if err := Convert_v1alpha1_ETCD_To_config_ETCD(&in.ETCD, &out.ETCD, s); err != nil {
@@ -86,6 +158,15 @@ func autoConvert_v1alpha1_ControllerConfiguration_To_config_ControllerConfigurat
 	out.HealthCheckConfig = (*apisconfigv1alpha1.HealthCheckConfig)(unsafe.Pointer(in.HealthCheckConfig))
 	out.RegistryCaches = *(*[]config.RegistryCacheConfiguration)(unsafe.Pointer(&in.RegistryCaches))
 	out.CustomLabelDomain = in.CustomLabelDomain
+	if err := Convert_v1alpha1_ControlPlaneControllerConfiguration_To_config_ControlPlaneControllerConfiguration(&in.ControlPlane, &out.ControlPlane, s); err != nil {
+		return err
+	}
+	if err := Convert_v1alpha1_InfrastructureControllerConfiguration_To_config_InfrastructureControllerConfiguration(&in.Infrastructure, &out.Infrastructure, s); err != nil {
+		return err
+	}
+	if err := Convert_v1alpha1_WorkerControllerConfiguration_To_config_WorkerControllerConfiguration(&in.Worker, &out.Worker, s); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -102,6 +183,15 @@ func autoConvert_config_ControllerConfiguration_To_v1alpha1_ControllerConfigurat
 	out.HealthCheckConfig = (*apisconfigv1alpha1.HealthCheckConfig)(unsafe.Pointer(in.HealthCheckConfig))
 	out.RegistryCaches = *(*[]RegistryCacheConfiguration)(unsafe.Pointer(&in.RegistryCaches))
 	out.CustomLabelDomain = in.CustomLabelDomain
+	if err := Convert_config_ControlPlaneControllerConfiguration_To_v1alpha1_ControlPlaneControllerConfiguration(&in.ControlPlane, &out.ControlPlane, s); err != nil {
+		return err
+	}
+	if err := Convert_config_InfrastructureControllerConfiguration_To_v1alpha1_InfrastructureControllerConfiguration(&in.Infrastructure, &out.Infrastructure, s); err != nil {
+		return err
+	}
+	if err := Convert_config_WorkerControllerConfiguration_To_v1alpha1_WorkerControllerConfiguration(&in.Worker, &out.Worker, s); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -182,6 +272,40 @@ func Convert_config_ETCDStorage_To_v1alpha1_ETCDStorage(in *config.ETCDStorage,
 	return autoConvert_config_ETCDStorage_To_v1alpha1_ETCDStorage(in, out, s)
 }
 
+func autoConvert_v1alpha1_InfrastructureControllerConfiguration_To_config_InfrastructureControllerConfiguration(in *InfrastructureControllerConfiguration, out *config.InfrastructureControllerConfiguration, s conversion.Scope) error {
+	out.EmptySSHPublicKeyPolicy = config.EmptySSHPublicKeyPolicy(in.EmptySSHPublicKeyPolicy)
+	out.AggregateEgressCIDRs = in.AggregateEgressCIDRs
+	out.SecurityGroupDescription = in.SecurityGroupDescription
+	out.RouterInterfaceTimeout = (*metav1.Duration)(unsafe.Pointer(in.RouterInterfaceTimeout))
+	out.DeleteDuplicateSecurityGroupRules = in.DeleteDuplicateSecurityGroupRules
+	out.AllowMetadataServiceEgress = (*bool)(unsafe.Pointer(in.AllowMetadataServiceEgress))
+	out.LoadBalancerRequestTimeout = (*metav1.Duration)(unsafe.Pointer(in.LoadBalancerRequestTimeout))
+	out.LoadBalancerRequestRetries = (*int32)(unsafe.Pointer(in.LoadBalancerRequestRetries))
+	return nil
+}
+
+// Convert_v1alpha1_InfrastructureControllerConfiguration_To_config_InfrastructureControllerConfiguration is an autogenerated conversion function.
+func Convert_v1alpha1_InfrastructureControllerConfiguration_To_config_InfrastructureControllerConfiguration(in *InfrastructureControllerConfiguration, out *config.InfrastructureControllerConfiguration, s conversion.Scope) error {
+	return autoConvert_v1alpha1_InfrastructureControllerConfiguration_To_config_InfrastructureControllerConfiguration(in, out, s)
+}
+
+func autoConvert_config_InfrastructureControllerConfiguration_To_v1alpha1_InfrastructureControllerConfiguration(in *config.InfrastructureControllerConfiguration, out *InfrastructureControllerConfiguration, s conversion.Scope) error {
+	out.EmptySSHPublicKeyPolicy = EmptySSHPublicKeyPolicy(in.EmptySSHPublicKeyPolicy)
+	out.AggregateEgressCIDRs = in.AggregateEgressCIDRs
+	out.SecurityGroupDescription = in.SecurityGroupDescription
+	out.RouterInterfaceTimeout = (*metav1.Duration)(unsafe.Pointer(in.RouterInterfaceTimeout))
+	out.DeleteDuplicateSecurityGroupRules = in.DeleteDuplicateSecurityGroupRules
+	out.AllowMetadataServiceEgress = (*bool)(unsafe.Pointer(in.AllowMetadataServiceEgress))
+	out.LoadBalancerRequestTimeout = (*metav1.Duration)(unsafe.Pointer(in.LoadBalancerRequestTimeout))
+	out.LoadBalancerRequestRetries = (*int32)(unsafe.Pointer(in.LoadBalancerRequestRetries))
+	return nil
+}
+
+// Convert_config_InfrastructureControllerConfiguration_To_v1alpha1_InfrastructureControllerConfiguration is an autogenerated conversion function.
+func Convert_config_InfrastructureControllerConfiguration_To_v1alpha1_InfrastructureControllerConfiguration(in *config.InfrastructureControllerConfiguration, out *InfrastructureControllerConfiguration, s conversion.Scope) error {
+	return autoConvert_config_InfrastructureControllerConfiguration_To_v1alpha1_InfrastructureControllerConfiguration(in, out, s)
+}
+
 func autoConvert_v1alpha1_RegistryCacheConfiguration_To_config_RegistryCacheConfiguration(in *RegistryCacheConfiguration, out *config.RegistryCacheConfiguration, s conversion.Scope) error {
 	out.Server = in.Server
 	out.Cache = in.Cache
@@ -207,3 +331,23 @@ func autoConvert_config_RegistryCacheConfiguration_To_v1alpha1_RegistryCacheConf
 func Convert_config_RegistryCacheConfiguration_To_v1alpha1_RegistryCacheConfiguration(in *config.RegistryCacheConfiguration, out *RegistryCacheConfiguration, s conversion.Scope) error {
 	return autoConvert_config_RegistryCacheConfiguration_To_v1alpha1_RegistryCacheConfiguration(in, out, s)
 }
+
+func autoConvert_v1alpha1_WorkerControllerConfiguration_To_config_WorkerControllerConfiguration(in *WorkerControllerConfiguration, out *config.WorkerControllerConfiguration, s conversion.Scope) error {
+	out.MachineCredentialsSecretName = in.MachineCredentialsSecretName
+	return nil
+}
+
+// Convert_v1alpha1_WorkerControllerConfiguration_To_config_WorkerControllerConfiguration is an autogenerated conversion function.
+func Convert_v1alpha1_WorkerControllerConfiguration_To_config_WorkerControllerConfiguration(in *WorkerControllerConfiguration, out *config.WorkerControllerConfiguration, s conversion.Scope) error {
+	return autoConvert_v1alpha1_WorkerControllerConfiguration_To_config_WorkerControllerConfiguration(in, out, s)
+}
+
+func autoConvert_config_WorkerControllerConfiguration_To_v1alpha1_WorkerControllerConfiguration(in *config.WorkerControllerConfiguration, out *WorkerControllerConfiguration, s conversion.Scope) error {
+	out.MachineCredentialsSecretName = in.MachineCredentialsSecretName
+	return nil
+}
+
+// Convert_config_WorkerControllerConfiguration_To_v1alpha1_WorkerControllerConfiguration is an autogenerated conversion function.
+func Convert_config_WorkerControllerConfiguration_To_v1alpha1_WorkerControllerConfiguration(in *config.WorkerControllerConfiguration, out *WorkerControllerConfiguration, s conversion.Scope) error {
+	return autoConvert_config_WorkerControllerConfiguration_To_v1alpha1_WorkerControllerConfiguration(in, out, s)
+}
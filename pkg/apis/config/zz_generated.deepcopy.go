@@ -9,10 +9,57 @@ package config
 
 import (
 	configv1alpha1 "github.com/gardener/gardener/extensions/pkg/apis/config/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 	v1alpha1 "k8s.io/component-base/config/v1alpha1"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ControlPlaneControllerConfiguration) DeepCopyInto(out *ControlPlaneControllerConfiguration) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]v1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.CredentialsRotationHistoryLimit != nil {
+		in, out := &in.CredentialsRotationHistoryLimit, &out.CredentialsRotationHistoryLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.CloudControllerManagerMetricsPort != nil {
+		in, out := &in.CloudControllerManagerMetricsPort, &out.CloudControllerManagerMetricsPort
+		*out = new(int32)
+		**out = **in
+	}
+	if in.CSIControllerMetricsPort != nil {
+		in, out := &in.CSIControllerMetricsPort, &out.CSIControllerMetricsPort
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ControlPlaneControllerConfiguration.
+func (in *ControlPlaneControllerConfiguration) DeepCopy() *ControlPlaneControllerConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(ControlPlaneControllerConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ControllerConfiguration) DeepCopyInto(out *ControllerConfiguration) {
 	*out = *in
@@ -35,6 +82,9 @@ func (in *ControllerConfiguration) DeepCopyInto(out *ControllerConfiguration) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	in.ControlPlane.DeepCopyInto(&out.ControlPlane)
+	in.Infrastructure.DeepCopyInto(&out.Infrastructure)
+	out.Worker = in.Worker
 	return
 }
 
@@ -121,6 +171,42 @@ func (in *ETCDStorage) DeepCopy() *ETCDStorage {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InfrastructureControllerConfiguration) DeepCopyInto(out *InfrastructureControllerConfiguration) {
+	*out = *in
+	if in.RouterInterfaceTimeout != nil {
+		in, out := &in.RouterInterfaceTimeout, &out.RouterInterfaceTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.AllowMetadataServiceEgress != nil {
+		in, out := &in.AllowMetadataServiceEgress, &out.AllowMetadataServiceEgress
+		*out = new(bool)
+		**out = **in
+	}
+	if in.LoadBalancerRequestTimeout != nil {
+		in, out := &in.LoadBalancerRequestTimeout, &out.LoadBalancerRequestTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.LoadBalancerRequestRetries != nil {
+		in, out := &in.LoadBalancerRequestRetries, &out.LoadBalancerRequestRetries
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InfrastructureControllerConfiguration.
+func (in *InfrastructureControllerConfiguration) DeepCopy() *InfrastructureControllerConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(InfrastructureControllerConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RegistryCacheConfiguration) DeepCopyInto(out *RegistryCacheConfiguration) {
 	*out = *in
@@ -146,3 +232,19 @@ func (in *RegistryCacheConfiguration) DeepCopy() *RegistryCacheConfiguration {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkerControllerConfiguration) DeepCopyInto(out *WorkerControllerConfiguration) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkerControllerConfiguration.
+func (in *WorkerControllerConfiguration) DeepCopy() *WorkerControllerConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkerControllerConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
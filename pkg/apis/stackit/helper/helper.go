@@ -72,6 +72,7 @@ func FindImageFromCloudProfile(cloudProfileConfig *stackitv1alpha1.CloudProfileC
 							Version:      imageVersion,
 							Architecture: &architecture,
 							ID:           region.ID,
+							Checksum:     region.Checksum,
 						}, nil
 					}
 				}
@@ -127,6 +128,15 @@ func FindKeyStoneCACert(keyStoneURLs []stackitv1alpha1.KeyStoneURL, keystoneCABu
 	return keystoneCABundle
 }
 
+// CustomLabelDomain returns the domain prefix of the labels applied to the STACKIT resources of the cluster. The
+// customLabelDomain of the ControlPlaneConfig takes precedence over the given default of the controller configuration.
+func CustomLabelDomain(cpConfig *stackitv1alpha1.ControlPlaneConfig, defaultDomain string) string {
+	if cpConfig == nil || ptr.Deref(cpConfig.CustomLabelDomain, "") == "" {
+		return defaultDomain
+	}
+	return *cpConfig.CustomLabelDomain
+}
+
 // FindFloatingPool receives a list of floating pools and tries to find the best
 // match for a given `floatingPoolNamePattern` considering constraints like
 // `region` and `domain`. If no matching floating pool was found then an error will be returned.
@@ -271,6 +271,17 @@ var _ = Describe("Helper", func() {
 		Entry("no default URL", []stackitv1alpha1.KeyStoneURL{{URL: "bar", Region: "europe"}}, "", "asia", "", true),
 	)
 
+	DescribeTable("#CustomLabelDomain",
+		func(cpConfig *stackitv1alpha1.ControlPlaneConfig, expected string) {
+			Expect(CustomLabelDomain(cpConfig, "kubernetes.io")).To(Equal(expected))
+		},
+
+		Entry("config is nil", nil, "kubernetes.io"),
+		Entry("domain is not set", &stackitv1alpha1.ControlPlaneConfig{}, "kubernetes.io"),
+		Entry("domain is empty", &stackitv1alpha1.ControlPlaneConfig{CustomLabelDomain: new("")}, "kubernetes.io"),
+		Entry("domain is set", &stackitv1alpha1.ControlPlaneConfig{CustomLabelDomain: new("customer.example.com")}, "customer.example.com"),
+	)
+
 	DescribeTable("#FindFloatingPool",
 		func(floatingPools []stackitv1alpha1.FloatingPool, floatingPoolNamePattern, region string, domain, expectedFloatingPoolName *string) {
 			result, err := FindFloatingPool(floatingPools, floatingPoolNamePattern, region, domain)
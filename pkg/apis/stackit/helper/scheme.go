@@ -6,6 +6,7 @@ package helper
 
 import (
 	"fmt"
+	"slices"
 
 	"github.com/gardener/gardener/extensions/pkg/controller"
 	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
@@ -141,6 +142,43 @@ func ControlPlaneConfigFromCluster(cluster *controller.Cluster) (*stackitv1alpha
 	return cpConfig, nil
 }
 
+// CustomLabelDomainFromCluster returns the custom label domain of the given cluster, i.e. the customLabelDomain of its
+// ControlPlaneConfig or the given default of the controller configuration.
+func CustomLabelDomainFromCluster(cluster *controller.Cluster, defaultDomain string) (string, error) {
+	cpConfig, err := ControlPlaneConfigFromCluster(cluster)
+	if err != nil {
+		return "", fmt.Errorf("could not decode the ControlPlaneConfig of the cluster: %w", err)
+	}
+	return CustomLabelDomain(cpConfig, defaultDomain), nil
+}
+
+// APIEndpointsFromCluster returns the API endpoints of the given CloudProfileConfig for the given region and cluster.
+// The IaaS endpoint of the InfrastructureConfig of the shoot takes precedence, but only if it is one of the allowed IaaS
+// endpoints of the CloudProfileConfig, as the credentials of the shoot are sent to it.
+func APIEndpointsFromCluster(cluster *controller.Cluster, cloudProfileConfig *stackitv1alpha1.CloudProfileConfig, region string) stackitv1alpha1.APIEndpoints {
+	if cloudProfileConfig == nil {
+		return stackitv1alpha1.APIEndpoints{}
+	}
+
+	apiEndpoints := cloudProfileConfig.APIEndpoints.ForRegion(region)
+	if iaasEndpoint := infrastructureIaaSEndpoint(cluster); iaasEndpoint != nil && slices.Contains(cloudProfileConfig.AllowedIaaSEndpoints, *iaasEndpoint) {
+		apiEndpoints.IaaS = iaasEndpoint
+	}
+	return apiEndpoints
+}
+
+// infrastructureIaaSEndpoint returns the IaaS endpoint of the InfrastructureConfig of the given cluster, if any.
+func infrastructureIaaSEndpoint(cluster *controller.Cluster) *string {
+	if cluster == nil || cluster.Shoot == nil || cluster.Shoot.Spec.Provider.InfrastructureConfig == nil {
+		return nil
+	}
+	infraConfig, err := InfrastructureConfigFromRawExtension(cluster.Shoot.Spec.Provider.InfrastructureConfig)
+	if err != nil {
+		return nil
+	}
+	return infraConfig.IaaSEndpoint
+}
+
 type objectWithGVK interface {
 	runtime.Object
 	SetGroupVersionKind(gvk schema.GroupVersionKind)
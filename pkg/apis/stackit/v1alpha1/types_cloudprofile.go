@@ -5,6 +5,8 @@
 package v1alpha1
 
 import (
+	"cmp"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -31,6 +33,10 @@ type CloudProfileConfig struct {
 	// APIEndpoints contains API endpoints for various services (e.g., "LoadBalancer", "IaaS").
 	// +optional
 	APIEndpoints *APIEndpoints `json:"apiEndpoints,omitempty"`
+	// AllowedIaaSEndpoints are the https endpoints of the STACKIT IaaS API shoots may select with the iaasEndpoint of
+	// their InfrastructureConfig. Shoots cannot override the IaaS endpoint if the list is empty.
+	// +optional
+	AllowedIaaSEndpoints []string `json:"allowedIaaSEndpoints,omitempty"`
 	// CABundle is the CA certificate bundle for API endpoints.
 	// This field is currently ignored and reserved for future use.
 	//
@@ -42,6 +48,20 @@ type CloudProfileConfig struct {
 	// the bastion server.
 	// +optional
 	Bastion *Bastion `json:"bastion,omitempty"`
+	// WorkerUpdateLimits caps the maxSurge and maxUnavailable of all worker pools. Values of worker pools exceeding
+	// the limits are clamped by the worker controller.
+	// +optional
+	WorkerUpdateLimits *WorkerUpdateLimits `json:"workerUpdateLimits,omitempty"`
+	// DefaultVolumeType is the STACKIT volume type (performance class) used for storage classes without a `type`
+	// parameter and for worker volumes which don't specify a type. It must be one of the volume types of the
+	// CloudProfile.
+	// +optional
+	DefaultVolumeType *string `json:"defaultVolumeType,omitempty"`
+	// PreservedMachineLabelKeyPrefixes are prefixes of worker pool and machine label keys which are passed to the
+	// machine classes of the OpenStack machine controller manager as they are. Label keys are normalized by replacing
+	// characters which are not allowed in OpenStack metadata keys (e.g. `/`) with `-` by default.
+	// +optional
+	PreservedMachineLabelKeyPrefixes []string `json:"preservedMachineLabelKeyPrefixes,omitempty"`
 	// Constraints is an object containing constraints for certain values in the control plane config.
 	//
 	// Deprecated: OpenStack-only; not used for STACKIT.
@@ -72,9 +92,8 @@ type CloudProfileConfig struct {
 	// Deprecated: OpenStack-only; not used for STACKIT.
 	// +optional
 	KeyStoneURLs []KeyStoneURL `json:"keystoneURLs,omitempty"`
-	// RequestTimeout specifies the HTTP timeout against the OpenStack API.
-	//
-	// Deprecated: OpenStack-only; not used for STACKIT.
+	// RequestTimeout specifies the HTTP timeout against the OpenStack and STACKIT APIs. Requests against the STACKIT APIs
+	// time out after 30s by default.
 	// +optional
 	RequestTimeout *metav1.Duration `json:"requestTimeout,omitempty"`
 	// IgnoreVolumeAZ specifies whether the volumes AZ should be ignored when scheduling to nodes,
@@ -99,8 +118,6 @@ type CloudProfileConfig struct {
 	// +optional
 	UseSNAT *bool `json:"useSNAT,omitempty"`
 	// ServerGroupPolicies specify the allowed server group policies for worker groups.
-	//
-	// Deprecated: OpenStack-only; not used for STACKIT.
 	// +optional
 	ServerGroupPolicies []string `json:"serverGroupPolicies,omitempty"`
 	// ResolvConfOptions specifies options to be added to /etc/resolv.conf on workers
@@ -115,6 +132,17 @@ type Bastion struct {
 	RootDiskSize *int64 `json:"rootDiskSize,omitempty"`
 }
 
+// WorkerUpdateLimits contains the limits of the update configuration of worker pools.
+type WorkerUpdateLimits struct {
+	// MaxSurge is the maximum number of machines a worker pool may create above its desired number of machines
+	// during an update.
+	// +optional
+	MaxSurge *int32 `json:"maxSurge,omitempty"`
+	// MaxUnavailable is the maximum number of machines of a worker pool which may be unavailable during an update.
+	// +optional
+	MaxUnavailable *int32 `json:"maxUnavailable,omitempty"`
+}
+
 // Constraints is an object containing constraints for the shoots.
 type Constraints struct {
 	// FloatingPools contains constraints regarding allowed values of the 'floatingPoolName' block in the control plane config.
@@ -176,6 +204,18 @@ type RegionIDMapping struct {
 	// Architecture is the CPU architecture of the machine image
 	// +optional
 	Architecture *string `json:"architecture,omitempty"`
+	// Checksum is the expected checksum of the machine image. If it is set, the worker controller verifies it against
+	// the checksum of the image in the STACKIT API before generating the machine classes.
+	// +optional
+	Checksum *ImageChecksum `json:"checksum,omitempty"`
+}
+
+// ImageChecksum is the checksum of a machine image.
+type ImageChecksum struct {
+	// Algorithm is the algorithm of the checksum. Supported values are "sha256" and "sha512".
+	Algorithm string `json:"algorithm"`
+	// Digest is the hex encoded digest of the machine image.
+	Digest string `json:"digest"`
 }
 
 // StorageClassDefinition is a definition of a storageClass
@@ -225,4 +265,33 @@ type APIEndpoints struct {
 	// TokenEndpoint is the token endpoint URL.
 	// +optional
 	TokenEndpoint *string `json:"tokenEndpoint,omitempty"`
+	// RegionalOverrides contains endpoints for single STACKIT regions (e.g. "eu02"), which take precedence over the
+	// endpoints above for shoots in the region. Endpoints which are not set for the region fall back to the ones above.
+	// Overrides must not contain further regional overrides.
+	// +optional
+	RegionalOverrides map[string]APIEndpoints `json:"regionalOverrides,omitempty"`
+}
+
+// ForRegion returns the endpoints for the given STACKIT region, i.e. the endpoints merged with the regional override of
+// the region. The returned endpoints do not contain regional overrides.
+func (e *APIEndpoints) ForRegion(region string) APIEndpoints {
+	if e == nil {
+		return APIEndpoints{}
+	}
+
+	endpoints := *e.DeepCopy()
+	endpoints.RegionalOverrides = nil
+
+	override, ok := e.RegionalOverrides[region]
+	if !ok {
+		return endpoints
+	}
+	override = *override.DeepCopy()
+	endpoints.DNS = cmp.Or(override.DNS, endpoints.DNS)
+	endpoints.LoadBalancer = cmp.Or(override.LoadBalancer, endpoints.LoadBalancer)
+	endpoints.IaaS = cmp.Or(override.IaaS, endpoints.IaaS)
+	endpoints.ApplicationLoadBalancer = cmp.Or(override.ApplicationLoadBalancer, endpoints.ApplicationLoadBalancer)
+	endpoints.ApplicationLoadBalancerCertificate = cmp.Or(override.ApplicationLoadBalancerCertificate, endpoints.ApplicationLoadBalancerCertificate)
+	endpoints.TokenEndpoint = cmp.Or(override.TokenEndpoint, endpoints.TokenEndpoint)
+	return endpoints
 }
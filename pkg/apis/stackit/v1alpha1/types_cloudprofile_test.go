@@ -0,0 +1,63 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/apis/stackit/v1alpha1"
+)
+
+var _ = Describe("APIEndpoints", func() {
+	Describe("#ForRegion", func() {
+		var endpoints *APIEndpoints
+
+		BeforeEach(func() {
+			endpoints = &APIEndpoints{
+				IaaS:          new("https://iaas.example.com"),
+				LoadBalancer:  new("https://lb.example.com"),
+				TokenEndpoint: new("https://token.example.com"),
+				RegionalOverrides: map[string]APIEndpoints{
+					"eu02": {
+						IaaS: new("https://iaas.eu02.example.com"),
+						DNS:  new("https://dns.eu02.example.com"),
+					},
+				},
+			}
+		})
+
+		It("should return empty endpoints for nil endpoints", func() {
+			var nilEndpoints *APIEndpoints
+			Expect(nilEndpoints.ForRegion("eu01")).To(Equal(APIEndpoints{}))
+		})
+
+		It("should return the base endpoints for a region without override", func() {
+			Expect(endpoints.ForRegion("eu01")).To(Equal(APIEndpoints{
+				IaaS:          new("https://iaas.example.com"),
+				LoadBalancer:  new("https://lb.example.com"),
+				TokenEndpoint: new("https://token.example.com"),
+			}))
+		})
+
+		It("should prefer the endpoints of the override and fall back to the base endpoints", func() {
+			Expect(endpoints.ForRegion("eu02")).To(Equal(APIEndpoints{
+				DNS:           new("https://dns.eu02.example.com"),
+				IaaS:          new("https://iaas.eu02.example.com"),
+				LoadBalancer:  new("https://lb.example.com"),
+				TokenEndpoint: new("https://token.example.com"),
+			}))
+		})
+
+		It("should not share the endpoints with the original", func() {
+			resolved := endpoints.ForRegion("eu02")
+			*resolved.IaaS = "https://changed.example.com"
+			*resolved.LoadBalancer = "https://changed.example.com"
+
+			Expect(endpoints.RegionalOverrides["eu02"].IaaS).To(HaveValue(Equal("https://iaas.eu02.example.com")))
+			Expect(endpoints.LoadBalancer).To(HaveValue(Equal("https://lb.example.com")))
+		})
+	})
+})
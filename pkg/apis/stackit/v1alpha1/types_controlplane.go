@@ -31,6 +31,12 @@ type ControlPlaneConfig struct {
 	// ApplicationLoadBalancer holds the configuration for the ApplicationLoadBalancer controller
 	// +optional
 	ApplicationLoadBalancer *ApplicationLoadBalancerConfig `json:"applicationLoadBalancer,omitempty"`
+
+	// CustomLabelDomain overrides the domain prefix of the labels the extension applies to the network, the bastion
+	// resources and the machines of the cluster. Defaults to the customLabelDomain of the controller configuration.
+	// It cannot be changed after the creation of the cluster.
+	// +optional
+	CustomLabelDomain *string `json:"customLabelDomain,omitempty"`
 }
 
 // ApplicationLoadBalancerConfig defines the configuration for the
@@ -59,6 +65,20 @@ type CloudControllerManagerConfig struct {
 	// Name contains the information of which ccm to deploy
 	// +optional
 	Name string `json:"name,omitempty"`
+	// ExtraArgs are additional command line flags (e.g. "--concurrent-service-syncs=5") passed to the
+	// cloud-controller-manager. They are appended to the flags set by the extension, so they take precedence.
+	// Flags managed by the extension are rejected. Unsupported flags are used at your own risk.
+	// +optional
+	ExtraArgs []string `json:"extraArgs,omitempty"`
+	// ServiceLoadBalancerNetworkID is the ID of the STACKIT network the STACKIT cloud-controller-manager places the load
+	// balancers of all Services in, both internal and public ones. Defaults to the network of the nodes. The nodes must
+	// be reachable from the network.
+	// +optional
+	ServiceLoadBalancerNetworkID *string `json:"serviceLoadBalancerNetworkId,omitempty"`
+	// RouteController forces the route controller of the cloud-controller-manager on or off. By default, it is
+	// enabled if the overlay network is disabled and the network is not routed via BGP.
+	// +optional
+	RouteController *bool `json:"routeController,omitempty"`
 }
 
 // Storage contains configuration for storage in the cluster.
@@ -69,6 +89,10 @@ type Storage struct {
 	// CSI holds the name of the CSI to use (either stackit or openstack)
 	// +optional
 	CSI *CSI `json:"csi,omitempty"`
+	// DisableSnapshotController disables the CSI snapshot controller and removes the VolumeSnapshot CRDs from the shoot.
+	// It can only be set when creating the cluster, as removing the CRDs deletes all existing VolumeSnapshots.
+	// +optional
+	DisableSnapshotController *bool `json:"disableSnapshotController,omitempty"`
 }
 
 type CSI struct {
@@ -82,6 +106,10 @@ type CSI struct {
 	// - "compatblock" enables the cinder compatibility layer like "compat", but does not allow to create new cinder volumes
 	// +optional
 	CompatibilityMode string `json:"compatibilityMode,omitempty"`
+	// MaxEntries is the maximum number of volume attachments the OpenStack CSI attacher lists per page. It is only used
+	// with the "openstack" CSI driver. If not set, it is computed from the maximum number of nodes of the cluster.
+	// +optional
+	MaxEntries *int32 `json:"maxEntries,omitempty"`
 }
 
 // CSIManila contains configuration for CSI Manila driver (support for NFS volumes)
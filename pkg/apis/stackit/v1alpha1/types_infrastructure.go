@@ -15,13 +15,87 @@ import (
 type InfrastructureConfig struct {
 	metav1.TypeMeta `json:",inline"`
 	// FloatingPoolName contains the FloatingPoolName name in which LoadBalancer FIPs should be created.
+	// It is ignored if the infrastructure is reconciled via the STACKIT API without OpenStack credentials, as the
+	// worker network is an isolated network without router to an external network in this case.
 	FloatingPoolName string `json:"floatingPoolName"`
 	// FloatingPoolSubnetName contains the fixed name of subnet or matching name pattern for subnet
 	// in the Floating IP Pool where the router should be attached to.
 	// +optional
 	FloatingPoolSubnetName *string `json:"floatingPoolSubnetName,omitempty"`
+	// FloatingPoolID is the ID of the external network of the FloatingPoolName. It is only needed if multiple external
+	// networks have the FloatingPoolName as name, and selects the one which is used.
+	// +optional
+	FloatingPoolID *string `json:"floatingPoolId,omitempty"`
 	// Networks is the OpenStack specific network configuration
 	Networks Networks `json:"networks"`
+	// IntraNodeTraffic restricts the traffic allowed between the nodes of the cluster. If not set, all traffic between
+	// the nodes is allowed. It is only respected if the infrastructure is reconciled via the STACKIT API.
+	// +optional
+	IntraNodeTraffic *IntraNodeTraffic `json:"intraNodeTraffic,omitempty"`
+	// IaaSEndpoint overrides the endpoint of the STACKIT IaaS API of the CloudProfileConfig for this shoot, e.g. to test
+	// against a staging endpoint. It must be one of the allowedIaaSEndpoints of the CloudProfileConfig. It is used by the
+	// extension and by the components of the shoot, and it cannot be changed after the creation of the shoot.
+	// +optional
+	IaaSEndpoint *string `json:"iaasEndpoint,omitempty"`
+	// AdditionalSecurityGroupRules are added to the rules of the security group of the nodes, e.g. to open the port of
+	// a VPN. Rules removed from the list are deleted from the security group.
+	// +optional
+	AdditionalSecurityGroupRules []SecurityGroupRuleConfig `json:"additionalSecurityGroupRules,omitempty"`
+}
+
+// IntraNodeTraffic holds the configuration of the traffic allowed between the nodes of the cluster.
+type IntraNodeTraffic struct {
+	// Ports is the list of ports which are allowed between the nodes. If it is empty, all traffic between the nodes is
+	// allowed. Otherwise, the rule allowing all traffic between the nodes is replaced by rules for the given ports only.
+	// Note that the CNI, the kubelet and node-local components require certain ports to be open between the nodes.
+	// +optional
+	Ports []IntraNodePort `json:"ports,omitempty"`
+}
+
+// IntraNodePort is a port or port range which is allowed between the nodes of the cluster.
+type IntraNodePort struct {
+	// Protocol is the protocol of the traffic. Supported values are "tcp", "udp", "sctp", "dccp", "udplite", "icmp"
+	// and "ipip".
+	Protocol string `json:"protocol"`
+	// Min is the (first) port of the range. It must only be set for the protocols "tcp", "udp", "sctp", "dccp"
+	// and "udplite".
+	// +optional
+	Min *int32 `json:"min,omitempty"`
+	// Max is the last port of the range. If not set, only the port Min is allowed.
+	// +optional
+	Max *int32 `json:"max,omitempty"`
+}
+
+// SecurityGroupRuleConfig is an additional rule of the security group of the nodes.
+type SecurityGroupRuleConfig struct {
+	// Direction is the direction of the traffic, either "ingress" or "egress".
+	Direction string `json:"direction"`
+	// EtherType is either "IPv4" or "IPv6". Defaults to "IPv4".
+	// +optional
+	EtherType *string `json:"etherType,omitempty"`
+	// Protocol is the protocol of the traffic. Supported values are "tcp", "udp", "sctp", "dccp", "udplite", "icmp"
+	// and "ipip". If not set, the rule applies to all protocols.
+	// +optional
+	Protocol *string `json:"protocol,omitempty"`
+	// PortRangeMin is the (first) port of the range. It must only be set for the protocols "tcp", "udp", "sctp",
+	// "dccp" and "udplite".
+	// +optional
+	PortRangeMin *int32 `json:"portRangeMin,omitempty"`
+	// PortRangeMax is the last port of the range. If not set, only the port PortRangeMin is allowed.
+	// +optional
+	PortRangeMax *int32 `json:"portRangeMax,omitempty"`
+	// RemoteIPPrefix is the CIDR of the remote side of the traffic. If neither RemoteIPPrefix nor
+	// RemoteSecurityGroupID is set, the rule applies to all addresses.
+	// +optional
+	RemoteIPPrefix *string `json:"remoteIPPrefix,omitempty"`
+	// RemoteSecurityGroupID is the ID of a security group on the remote side of the traffic. It must not be set
+	// together with RemoteIPPrefix.
+	// +optional
+	RemoteSecurityGroupID *string `json:"remoteSecurityGroupID,omitempty"`
+	// Description is the description of the rule. A description is generated if it is not set. It is prefixed with
+	// "gardener-additional: " to identify the rule and must not exceed 106 characters.
+	// +optional
+	Description *string `json:"description,omitempty"`
 }
 
 // Networks holds information about the Kubernetes and infrastructure networks.
@@ -44,9 +118,36 @@ type Networks struct {
 	// ShareNetwork holds information about the share network (used for shared file systems like NFS)
 	// +optional
 	ShareNetwork *ShareNetwork `json:"shareNetwork,omitempty"`
-	// DNSServers overrides the default dns configuration from cloud profile
+	// DNSServers overrides the default dns configuration from cloud profile.
+	// If neither is set, the network of the STACKIT infrastructure inherits the default nameservers of STACKIT.
 	// +optional
 	DNSServers *[]string `json:"dnsServers,omitempty"`
+	// AllowMissingEgressIP lets the STACKIT infrastructure proceed if the worker network has no public IP, e.g. if a
+	// configured network provides egress by other means. The egress CIDRs of the Infrastructure are empty in this case.
+	// It is only respected if the infrastructure is reconciled via the STACKIT API. Defaults to false.
+	// +optional
+	AllowMissingEgressIP bool `json:"allowMissingEgressIP,omitempty"`
+	// DHCP enables DHCP in the isolated network created by the STACKIT infrastructure. If it is disabled, the node
+	// addresses have to be assigned by other means and the DNS servers must be configured. Defaults to true.
+	// +optional
+	DHCP *bool `json:"dhcp,omitempty"`
+	// IPv6 adds an IPv6 prefix to the isolated network created by the STACKIT infrastructure. It requires a dual-stack
+	// shoot and cannot be used with an existing network.
+	// +optional
+	IPv6 *IPv6Network `json:"ipv6,omitempty"`
+}
+
+// IPv6Network contains the IPv6 configuration of the worker network.
+type IPv6Network struct {
+	// Prefix is the IPv6 prefix of the network. Exactly one of Prefix and PrefixLength must be set.
+	// +optional
+	Prefix *string `json:"prefix,omitempty"`
+	// PrefixLength is the length of the IPv6 prefix which is assigned to the network by STACKIT.
+	// +optional
+	PrefixLength *int32 `json:"prefixLength,omitempty"`
+	// DNSServers are the IPv6 nameservers of the network.
+	// +optional
+	DNSServers []string `json:"dnsServers,omitempty"`
 }
 
 // Router indicates whether to use an existing router or create a new one.
@@ -42,9 +42,12 @@ type MachineImage struct {
 	// Architecture is the CPU architecture of the machine image
 	// +optional
 	Architecture *string `json:"architecture,omitempty"`
+	// Checksum is the expected checksum of the machine image.
+	// +optional
+	Checksum *ImageChecksum `json:"checksum,omitempty"`
 }
 
-// ServerGroupDependency is a reference to an external machine dependency of OpenStack server groups.
+// ServerGroupDependency is a reference to the server group of a worker pool.
 type ServerGroupDependency struct {
 	// PoolName identifies the worker pool that this dependency belongs
 	PoolName string `json:"poolName"`
@@ -66,6 +69,33 @@ type WorkerConfig struct {
 
 	// MachineLabels define key value pairs to add to machines.
 	MachineLabels []MachineLabel `json:"machineLabels,omitempty"`
+
+	// DisablePortSecurity disables the source/destination check of the network interfaces of the machines, so that they
+	// can send and receive traffic of arbitrary addresses, e.g. when running a router or NAT on the nodes.
+	// This is only supported with the STACKIT machine controller manager. Defaults to false.
+	DisablePortSecurity bool `json:"disablePortSecurity,omitempty"`
+
+	// RescanBlockStorageOnResize overrides the RescanBlockStorageOnResize of the CloudProfileConfig for the nodes of the
+	// worker pool, i.e. whether the CSI driver rescans block devices after resizing volumes.
+	// +optional
+	RescanBlockStorageOnResize *bool `json:"rescanBlockStorageOnResize,omitempty"`
+
+	// ZoneWeights maps the zones of the worker pool to weights, which distribute the minimum and maximum of the pool
+	// proportionally over the zones instead of evenly. If set, every zone of the pool needs a positive weight.
+	// +optional
+	ZoneWeights map[string]int32 `json:"zoneWeights,omitempty"`
+
+	// ServerGroup configures a server group for the machines of the worker pool, e.g. to spread them over different
+	// hosts. This is only supported with the STACKIT machine controller manager.
+	// +optional
+	ServerGroup *ServerGroupConfig `json:"serverGroup,omitempty"`
+}
+
+// ServerGroupConfig contains the configuration of the server group of a worker pool.
+type ServerGroupConfig struct {
+	// Policy is the policy of the server group, e.g. soft-anti-affinity. It must be one of the ServerGroupPolicies of
+	// the CloudProfileConfig. Changing the policy rolls the nodes of the worker pool.
+	Policy string `json:"policy"`
 }
 
 // MachineLabel define key value pair to label machines.
@@ -46,6 +46,13 @@ func (in *APIEndpoints) DeepCopyInto(out *APIEndpoints) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.RegionalOverrides != nil {
+		in, out := &in.RegionalOverrides, &out.RegionalOverrides
+		*out = make(map[string]APIEndpoints, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
 	return
 }
 
@@ -141,6 +148,11 @@ func (in *Bastion) DeepCopy() *Bastion {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CSI) DeepCopyInto(out *CSI) {
 	*out = *in
+	if in.MaxEntries != nil {
+		in, out := &in.MaxEntries, &out.MaxEntries
+		*out = new(int32)
+		**out = **in
+	}
 	return
 }
 
@@ -180,6 +192,21 @@ func (in *CloudControllerManagerConfig) DeepCopyInto(out *CloudControllerManager
 			(*out)[key] = val
 		}
 	}
+	if in.ExtraArgs != nil {
+		in, out := &in.ExtraArgs, &out.ExtraArgs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ServiceLoadBalancerNetworkID != nil {
+		in, out := &in.ServiceLoadBalancerNetworkID, &out.ServiceLoadBalancerNetworkID
+		*out = new(string)
+		**out = **in
+	}
+	if in.RouteController != nil {
+		in, out := &in.RouteController, &out.RouteController
+		*out = new(bool)
+		**out = **in
+	}
 	return
 }
 
@@ -226,6 +253,11 @@ func (in *CloudProfileConfig) DeepCopyInto(out *CloudProfileConfig) {
 		*out = new(APIEndpoints)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.AllowedIaaSEndpoints != nil {
+		in, out := &in.AllowedIaaSEndpoints, &out.AllowedIaaSEndpoints
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.CABundle != nil {
 		in, out := &in.CABundle, &out.CABundle
 		*out = new(string)
@@ -236,6 +268,21 @@ func (in *CloudProfileConfig) DeepCopyInto(out *CloudProfileConfig) {
 		*out = new(Bastion)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.WorkerUpdateLimits != nil {
+		in, out := &in.WorkerUpdateLimits, &out.WorkerUpdateLimits
+		*out = new(WorkerUpdateLimits)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DefaultVolumeType != nil {
+		in, out := &in.DefaultVolumeType, &out.DefaultVolumeType
+		*out = new(string)
+		**out = **in
+	}
+	if in.PreservedMachineLabelKeyPrefixes != nil {
+		in, out := &in.PreservedMachineLabelKeyPrefixes, &out.PreservedMachineLabelKeyPrefixes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	in.Constraints.DeepCopyInto(&out.Constraints)
 	if in.DHCPDomain != nil {
 		in, out := &in.DHCPDomain, &out.DHCPDomain
@@ -357,6 +404,11 @@ func (in *ControlPlaneConfig) DeepCopyInto(out *ControlPlaneConfig) {
 		*out = new(ApplicationLoadBalancerConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.CustomLabelDomain != nil {
+		in, out := &in.CustomLabelDomain, &out.CustomLabelDomain
+		*out = new(string)
+		**out = **in
+	}
 	return
 }
 
@@ -430,6 +482,53 @@ func (in *FloatingPoolStatus) DeepCopy() *FloatingPoolStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IPv6Network) DeepCopyInto(out *IPv6Network) {
+	*out = *in
+	if in.Prefix != nil {
+		in, out := &in.Prefix, &out.Prefix
+		*out = new(string)
+		**out = **in
+	}
+	if in.PrefixLength != nil {
+		in, out := &in.PrefixLength, &out.PrefixLength
+		*out = new(int32)
+		**out = **in
+	}
+	if in.DNSServers != nil {
+		in, out := &in.DNSServers, &out.DNSServers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IPv6Network.
+func (in *IPv6Network) DeepCopy() *IPv6Network {
+	if in == nil {
+		return nil
+	}
+	out := new(IPv6Network)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageChecksum) DeepCopyInto(out *ImageChecksum) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageChecksum.
+func (in *ImageChecksum) DeepCopy() *ImageChecksum {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageChecksum)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *InfrastructureConfig) DeepCopyInto(out *InfrastructureConfig) {
 	*out = *in
@@ -439,7 +538,29 @@ func (in *InfrastructureConfig) DeepCopyInto(out *InfrastructureConfig) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.FloatingPoolID != nil {
+		in, out := &in.FloatingPoolID, &out.FloatingPoolID
+		*out = new(string)
+		**out = **in
+	}
 	in.Networks.DeepCopyInto(&out.Networks)
+	if in.IntraNodeTraffic != nil {
+		in, out := &in.IntraNodeTraffic, &out.IntraNodeTraffic
+		*out = new(IntraNodeTraffic)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.IaaSEndpoint != nil {
+		in, out := &in.IaaSEndpoint, &out.IaaSEndpoint
+		*out = new(string)
+		**out = **in
+	}
+	if in.AdditionalSecurityGroupRules != nil {
+		in, out := &in.AdditionalSecurityGroupRules, &out.AdditionalSecurityGroupRules
+		*out = make([]SecurityGroupRuleConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -525,6 +646,55 @@ func (in *InfrastructureStatus) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IntraNodePort) DeepCopyInto(out *IntraNodePort) {
+	*out = *in
+	if in.Min != nil {
+		in, out := &in.Min, &out.Min
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Max != nil {
+		in, out := &in.Max, &out.Max
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IntraNodePort.
+func (in *IntraNodePort) DeepCopy() *IntraNodePort {
+	if in == nil {
+		return nil
+	}
+	out := new(IntraNodePort)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IntraNodeTraffic) DeepCopyInto(out *IntraNodeTraffic) {
+	*out = *in
+	if in.Ports != nil {
+		in, out := &in.Ports, &out.Ports
+		*out = make([]IntraNodePort, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IntraNodeTraffic.
+func (in *IntraNodeTraffic) DeepCopy() *IntraNodeTraffic {
+	if in == nil {
+		return nil
+	}
+	out := new(IntraNodeTraffic)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KeyStoneURL) DeepCopyInto(out *KeyStoneURL) {
 	*out = *in
@@ -580,6 +750,11 @@ func (in *MachineImage) DeepCopyInto(out *MachineImage) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.Checksum != nil {
+		in, out := &in.Checksum, &out.Checksum
+		*out = new(ImageChecksum)
+		**out = **in
+	}
 	return
 }
 
@@ -717,6 +892,16 @@ func (in *Networks) DeepCopyInto(out *Networks) {
 			copy(*out, *in)
 		}
 	}
+	if in.DHCP != nil {
+		in, out := &in.DHCP, &out.DHCP
+		*out = new(bool)
+		**out = **in
+	}
+	if in.IPv6 != nil {
+		in, out := &in.IPv6, &out.IPv6
+		*out = new(IPv6Network)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -754,6 +939,11 @@ func (in *RegionIDMapping) DeepCopyInto(out *RegionIDMapping) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.Checksum != nil {
+		in, out := &in.Checksum, &out.Checksum
+		*out = new(ImageChecksum)
+		**out = **in
+	}
 	return
 }
 
@@ -820,6 +1010,57 @@ func (in *SecurityGroup) DeepCopy() *SecurityGroup {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecurityGroupRuleConfig) DeepCopyInto(out *SecurityGroupRuleConfig) {
+	*out = *in
+	if in.EtherType != nil {
+		in, out := &in.EtherType, &out.EtherType
+		*out = new(string)
+		**out = **in
+	}
+	if in.Protocol != nil {
+		in, out := &in.Protocol, &out.Protocol
+		*out = new(string)
+		**out = **in
+	}
+	if in.PortRangeMin != nil {
+		in, out := &in.PortRangeMin, &out.PortRangeMin
+		*out = new(int32)
+		**out = **in
+	}
+	if in.PortRangeMax != nil {
+		in, out := &in.PortRangeMax, &out.PortRangeMax
+		*out = new(int32)
+		**out = **in
+	}
+	if in.RemoteIPPrefix != nil {
+		in, out := &in.RemoteIPPrefix, &out.RemoteIPPrefix
+		*out = new(string)
+		**out = **in
+	}
+	if in.RemoteSecurityGroupID != nil {
+		in, out := &in.RemoteSecurityGroupID, &out.RemoteSecurityGroupID
+		*out = new(string)
+		**out = **in
+	}
+	if in.Description != nil {
+		in, out := &in.Description, &out.Description
+		*out = new(string)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecurityGroupRuleConfig.
+func (in *SecurityGroupRuleConfig) DeepCopy() *SecurityGroupRuleConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(SecurityGroupRuleConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SelfHostedShootExposureConfig) DeepCopyInto(out *SelfHostedShootExposureConfig) {
 	*out = *in
@@ -850,6 +1091,22 @@ func (in *SelfHostedShootExposureConfig) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServerGroupConfig) DeepCopyInto(out *ServerGroupConfig) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServerGroupConfig.
+func (in *ServerGroupConfig) DeepCopy() *ServerGroupConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ServerGroupConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ServerGroupDependency) DeepCopyInto(out *ServerGroupDependency) {
 	*out = *in
@@ -909,6 +1166,11 @@ func (in *Storage) DeepCopyInto(out *Storage) {
 	if in.CSI != nil {
 		in, out := &in.CSI, &out.CSI
 		*out = new(CSI)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DisableSnapshotController != nil {
+		in, out := &in.DisableSnapshotController, &out.DisableSnapshotController
+		*out = new(bool)
 		**out = **in
 	}
 	return
@@ -1020,6 +1282,23 @@ func (in *WorkerConfig) DeepCopyInto(out *WorkerConfig) {
 		*out = make([]MachineLabel, len(*in))
 		copy(*out, *in)
 	}
+	if in.RescanBlockStorageOnResize != nil {
+		in, out := &in.RescanBlockStorageOnResize, &out.RescanBlockStorageOnResize
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ZoneWeights != nil {
+		in, out := &in.ZoneWeights, &out.ZoneWeights
+		*out = make(map[string]int32, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ServerGroup != nil {
+		in, out := &in.ServerGroup, &out.ServerGroup
+		*out = new(ServerGroupConfig)
+		**out = **in
+	}
 	return
 }
 
@@ -1077,3 +1356,29 @@ func (in *WorkerStatus) DeepCopyObject() runtime.Object {
 	}
 	return nil
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkerUpdateLimits) DeepCopyInto(out *WorkerUpdateLimits) {
+	*out = *in
+	if in.MaxSurge != nil {
+		in, out := &in.MaxSurge, &out.MaxSurge
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkerUpdateLimits.
+func (in *WorkerUpdateLimits) DeepCopy() *WorkerUpdateLimits {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkerUpdateLimits)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -5,6 +5,7 @@
 package validation
 
 import (
+	"encoding/hex"
 	"fmt"
 	"maps"
 	"net"
@@ -101,6 +102,7 @@ func ValidateCloudProfileConfig(cloudProfile *stackitv1alpha1.CloudProfileConfig
 		regionsFound.Insert(val.Region)
 	}
 
+	// The number of DNS servers is only limited for STACKIT networks, which is validated with the InfrastructureConfig.
 	for i, ip := range cloudProfile.DNSServers {
 		if net.ParseIP(ip) == nil {
 			allErrs = append(allErrs, field.Invalid(fldPath.Child("dnsServers").Index(i), ip, "must provide a valid IP"))
@@ -112,8 +114,52 @@ func ValidateCloudProfileConfig(cloudProfile *stackitv1alpha1.CloudProfileConfig
 		allErrs = append(allErrs, field.Required(fldPath.Child("dhcpDomain"), "must provide a dhcp domain when the key is specified"))
 	}
 
+	if limits := cloudProfile.WorkerUpdateLimits; limits != nil {
+		limitsPath := fldPath.Child("workerUpdateLimits")
+		if limits.MaxSurge != nil && *limits.MaxSurge < 0 {
+			allErrs = append(allErrs, field.Invalid(limitsPath.Child("maxSurge"), *limits.MaxSurge, "must be greater than or equal to 0"))
+		}
+		if limits.MaxUnavailable != nil && *limits.MaxUnavailable < 0 {
+			allErrs = append(allErrs, field.Invalid(limitsPath.Child("maxUnavailable"), *limits.MaxUnavailable, "must be greater than or equal to 0"))
+		}
+		if ptr.Deref(limits.MaxSurge, -1) == 0 && ptr.Deref(limits.MaxUnavailable, -1) == 0 {
+			allErrs = append(allErrs, field.Invalid(limitsPath, limits, "maxSurge and maxUnavailable must not both be 0"))
+		}
+	}
+
+	if timeout := cloudProfile.RequestTimeout; timeout != nil && timeout.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("requestTimeout"), timeout.Duration.String(), "must be positive"))
+	}
+
+	if cloudProfile.DefaultVolumeType != nil && len(*cloudProfile.DefaultVolumeType) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("defaultVolumeType"), "must provide a volume type when the key is specified"))
+	}
+
+	for i, prefix := range cloudProfile.PreservedMachineLabelKeyPrefixes {
+		if len(prefix) == 0 {
+			allErrs = append(allErrs, field.Required(fldPath.Child("preservedMachineLabelKeyPrefixes").Index(i), "prefix cannot be empty"))
+		}
+	}
+
+	for i, endpoint := range cloudProfile.AllowedIaaSEndpoints {
+		if !isHTTPSURL(endpoint) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("allowedIaaSEndpoints").Index(i), endpoint, "must be a valid https URL"))
+		}
+	}
+
+	if endpoints := cloudProfile.APIEndpoints; endpoints != nil {
+		overridesPath := fldPath.Child("apiEndpoints", "regionalOverrides")
+		for _, region := range slices.Sorted(maps.Keys(endpoints.RegionalOverrides)) {
+			if len(region) == 0 {
+				allErrs = append(allErrs, field.Required(overridesPath, "region of a regional override cannot be empty"))
+			}
+			if len(endpoints.RegionalOverrides[region].RegionalOverrides) > 0 {
+				allErrs = append(allErrs, field.Forbidden(overridesPath.Key(region).Child("regionalOverrides"), "regional overrides cannot be nested"))
+			}
+		}
+	}
+
 	serverGroupPath := fldPath.Child("serverGroupPolicies")
-	//nolint:staticcheck // SA1019: needed for migration purposes
 	for i, policy := range cloudProfile.ServerGroupPolicies {
 		idxPath := serverGroupPath.Index(i)
 
@@ -125,6 +171,26 @@ func ValidateCloudProfileConfig(cloudProfile *stackitv1alpha1.CloudProfileConfig
 	return allErrs
 }
 
+// ValidateDefaultVolumeType validates that the default volume type of the CloudProfileConfig is one of the given volume
+// types of the CloudProfile.
+func ValidateDefaultVolumeType(cloudProfile *stackitv1alpha1.CloudProfileConfig, volumeTypes []core.VolumeType, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if cloudProfile.DefaultVolumeType == nil || len(*cloudProfile.DefaultVolumeType) == 0 {
+		return allErrs
+	}
+
+	names := make([]string, 0, len(volumeTypes))
+	for _, volumeType := range volumeTypes {
+		names = append(names, volumeType.Name)
+	}
+	if !slices.Contains(names, *cloudProfile.DefaultVolumeType) {
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("defaultVolumeType"), *cloudProfile.DefaultVolumeType, names))
+	}
+
+	return allErrs
+}
+
 // ValidateProviderMachineImage validates a CloudProfileConfig MachineImages entry.
 func ValidateProviderMachineImage(validationPath *field.Path, machineImage stackitv1alpha1.MachineImages) field.ErrorList {
 	allErrs := field.ErrorList{}
@@ -155,12 +221,36 @@ func ValidateProviderMachineImage(validationPath *field.Path, machineImage stack
 			if !slices.Contains(v1beta1constants.ValidArchitectures, ptr.Deref(region.Architecture, v1beta1constants.ArchitectureAMD64)) {
 				allErrs = append(allErrs, field.NotSupported(kdxPath.Child("architecture"), *region.Architecture, v1beta1constants.ValidArchitectures))
 			}
+			if region.Checksum != nil {
+				allErrs = append(allErrs, validateImageChecksum(region.Checksum, kdxPath.Child("checksum"))...)
+			}
 		}
 	}
 
 	return allErrs
 }
 
+// imageChecksumDigestLengths are the lengths of the hex encoded digests of the supported checksum algorithms.
+var imageChecksumDigestLengths = map[string]int{
+	"sha256": 64,
+	"sha512": 128,
+}
+
+func validateImageChecksum(checksum *stackitv1alpha1.ImageChecksum, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	digestLength, ok := imageChecksumDigestLengths[checksum.Algorithm]
+	if !ok {
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("algorithm"), checksum.Algorithm, slices.Sorted(maps.Keys(imageChecksumDigestLengths))))
+		return allErrs
+	}
+	if _, err := hex.DecodeString(checksum.Digest); err != nil || len(checksum.Digest) != digestLength {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("digest"), checksum.Digest, fmt.Sprintf("must be a hex encoded %s digest with %d characters", checksum.Algorithm, digestLength)))
+	}
+
+	return allErrs
+}
+
 // NewProviderImagesContext creates a new ImagesContext for provider images.
 func NewProviderImagesContext(providerImages []stackitv1alpha1.MachineImages) *gardener.ImagesContext[stackitv1alpha1.MachineImages, stackitv1alpha1.MachineImageVersion] {
 	return gardener.NewImagesContext(
@@ -5,11 +5,15 @@
 package validation_test
 
 import (
+	"strings"
+	"time"
+
 	"github.com/gardener/gardener/pkg/apis/core"
 	v1beta1constants "github.com/gardener/gardener/pkg/apis/core/v1beta1/constants"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	. "github.com/onsi/gomega/gstruct"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	stackitv1alpha1 "github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/apis/stackit/v1alpha1"
@@ -212,6 +216,88 @@ var _ = Describe("CloudProfileConfig validation", func() {
 					"Field": Equal("root.dnsServers[0]"),
 				}))))
 			})
+
+			It("should allow more dns servers than supported by STACKIT networks", func() {
+				cloudProfileConfig.DNSServers = []string{"1.1.1.1", "8.8.8.8", "9.9.9.9", "1.0.0.1"}
+
+				errorList := ValidateCloudProfileConfig(cloudProfileConfig, machineImages, fldPath)
+
+				Expect(errorList).To(BeEmpty())
+			})
+		})
+
+		Context("default volume type validation", func() {
+			It("should forbid an empty default volume type when the key is present", func() {
+				cloudProfileConfig.DefaultVolumeType = new("")
+
+				errorList := ValidateCloudProfileConfig(cloudProfileConfig, machineImages, fldPath)
+
+				Expect(errorList).To(ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeRequired),
+					"Field": Equal("root.defaultVolumeType"),
+				}))))
+			})
+		})
+
+		Context("preserved machine label key prefixes validation", func() {
+			It("should allow prefixes", func() {
+				cloudProfileConfig.PreservedMachineLabelKeyPrefixes = []string{"example.com/", "node.kubernetes.io/"}
+
+				Expect(ValidateCloudProfileConfig(cloudProfileConfig, machineImages, fldPath)).To(BeEmpty())
+			})
+
+			It("should forbid empty prefixes", func() {
+				cloudProfileConfig.PreservedMachineLabelKeyPrefixes = []string{"example.com/", ""}
+
+				errorList := ValidateCloudProfileConfig(cloudProfileConfig, machineImages, fldPath)
+
+				Expect(errorList).To(ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeRequired),
+					"Field": Equal("root.preservedMachineLabelKeyPrefixes[1]"),
+				}))))
+			})
+		})
+
+		Context("worker update limits validation", func() {
+			It("should allow non-negative limits", func() {
+				cloudProfileConfig.WorkerUpdateLimits = &stackitv1alpha1.WorkerUpdateLimits{
+					MaxSurge:       new(int32(0)),
+					MaxUnavailable: new(int32(2)),
+				}
+
+				Expect(ValidateCloudProfileConfig(cloudProfileConfig, machineImages, fldPath)).To(BeEmpty())
+			})
+
+			It("should forbid negative limits", func() {
+				cloudProfileConfig.WorkerUpdateLimits = &stackitv1alpha1.WorkerUpdateLimits{
+					MaxSurge:       new(int32(-1)),
+					MaxUnavailable: new(int32(-1)),
+				}
+
+				errorList := ValidateCloudProfileConfig(cloudProfileConfig, machineImages, fldPath)
+
+				Expect(errorList).To(ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeInvalid),
+					"Field": Equal("root.workerUpdateLimits.maxSurge"),
+				})), PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeInvalid),
+					"Field": Equal("root.workerUpdateLimits.maxUnavailable"),
+				}))))
+			})
+
+			It("should forbid limiting maxSurge and maxUnavailable to 0", func() {
+				cloudProfileConfig.WorkerUpdateLimits = &stackitv1alpha1.WorkerUpdateLimits{
+					MaxSurge:       new(int32(0)),
+					MaxUnavailable: new(int32(0)),
+				}
+
+				errorList := ValidateCloudProfileConfig(cloudProfileConfig, machineImages, fldPath)
+
+				Expect(errorList).To(ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeInvalid),
+					"Field": Equal("root.workerUpdateLimits"),
+				}))))
+			})
 		})
 
 		Context("dhcp domain validation", func() {
@@ -228,6 +314,93 @@ var _ = Describe("CloudProfileConfig validation", func() {
 			})
 		})
 
+		Context("request timeout validation", func() {
+			It("should allow a positive timeout", func() {
+				cloudProfileConfig.RequestTimeout = &metav1.Duration{Duration: 10 * time.Second}
+
+				Expect(ValidateCloudProfileConfig(cloudProfileConfig, machineImages, fldPath)).To(BeEmpty())
+			})
+
+			DescribeTable("should forbid a timeout which is not positive",
+				func(timeout time.Duration) {
+					cloudProfileConfig.RequestTimeout = &metav1.Duration{Duration: timeout}
+
+					Expect(ValidateCloudProfileConfig(cloudProfileConfig, machineImages, fldPath)).To(ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
+						"Type":  Equal(field.ErrorTypeInvalid),
+						"Field": Equal("root.requestTimeout"),
+					}))))
+				},
+				Entry("zero", time.Duration(0)),
+				Entry("negative", -time.Second),
+			)
+		})
+
+		Context("api endpoints validation", func() {
+			It("should allow regional overrides", func() {
+				cloudProfileConfig.APIEndpoints = &stackitv1alpha1.APIEndpoints{
+					IaaS: new("https://iaas.example.com"),
+					RegionalOverrides: map[string]stackitv1alpha1.APIEndpoints{
+						"eu02": {IaaS: new("https://iaas.eu02.example.com")},
+					},
+				}
+
+				errorList := ValidateCloudProfileConfig(cloudProfileConfig, machineImages, fldPath)
+
+				Expect(errorList).To(BeEmpty())
+			})
+
+			It("should forbid empty regions and nested regional overrides", func() {
+				cloudProfileConfig.APIEndpoints = &stackitv1alpha1.APIEndpoints{
+					RegionalOverrides: map[string]stackitv1alpha1.APIEndpoints{
+						"": {IaaS: new("https://iaas.example.com")},
+						"eu02": {
+							RegionalOverrides: map[string]stackitv1alpha1.APIEndpoints{
+								"eu01": {IaaS: new("https://iaas.eu01.example.com")},
+							},
+						},
+					},
+				}
+
+				errorList := ValidateCloudProfileConfig(cloudProfileConfig, machineImages, fldPath)
+
+				Expect(errorList).To(ConsistOf(
+					PointTo(MatchFields(IgnoreExtras, Fields{
+						"Type":  Equal(field.ErrorTypeRequired),
+						"Field": Equal("root.apiEndpoints.regionalOverrides"),
+					})),
+					PointTo(MatchFields(IgnoreExtras, Fields{
+						"Type":  Equal(field.ErrorTypeForbidden),
+						"Field": Equal("root.apiEndpoints.regionalOverrides[eu02].regionalOverrides"),
+					})),
+				))
+			})
+		})
+
+		Context("allowed IaaS endpoints validation", func() {
+			It("should allow https endpoints", func() {
+				cloudProfileConfig.AllowedIaaSEndpoints = []string{"https://iaas.staging.example.com"}
+
+				Expect(ValidateCloudProfileConfig(cloudProfileConfig, machineImages, fldPath)).To(BeEmpty())
+			})
+
+			It("should forbid invalid and insecure endpoints", func() {
+				cloudProfileConfig.AllowedIaaSEndpoints = []string{"http://iaas.staging.example.com", "iaas.staging.example.com"}
+
+				errorList := ValidateCloudProfileConfig(cloudProfileConfig, machineImages, fldPath)
+
+				Expect(errorList).To(ConsistOf(
+					PointTo(MatchFields(IgnoreExtras, Fields{
+						"Type":  Equal(field.ErrorTypeInvalid),
+						"Field": Equal("root.allowedIaaSEndpoints[0]"),
+					})),
+					PointTo(MatchFields(IgnoreExtras, Fields{
+						"Type":  Equal(field.ErrorTypeInvalid),
+						"Field": Equal("root.allowedIaaSEndpoints[1]"),
+					})),
+				))
+			})
+		})
+
 		Context("machine image validation", func() {
 			It("should pass validation", func() {
 				errorList := ValidateCloudProfileConfig(cloudProfileConfig, machineImages, fldPath)
@@ -240,6 +413,48 @@ var _ = Describe("CloudProfileConfig validation", func() {
 				Expect(errorList).To(BeEmpty())
 			})
 
+			It("should allow a valid image checksum", func() {
+				cloudProfileConfig.MachineImages[0].Versions[0].Regions[0].Checksum = &stackitv1alpha1.ImageChecksum{
+					Algorithm: "sha256",
+					Digest:    strings.Repeat("ab", 32),
+				}
+
+				errorList := ValidateCloudProfileConfig(cloudProfileConfig, machineImages, fldPath)
+				Expect(errorList).To(BeEmpty())
+			})
+
+			It("should forbid invalid image checksums", func() {
+				cloudProfileConfig.MachineImages[0].Versions[0].Regions = append(cloudProfileConfig.MachineImages[0].Versions[0].Regions,
+					*cloudProfileConfig.MachineImages[0].Versions[0].Regions[0].DeepCopy(),
+					*cloudProfileConfig.MachineImages[0].Versions[0].Regions[0].DeepCopy(),
+				)
+				cloudProfileConfig.MachineImages[0].Versions[0].Regions[0].Checksum = &stackitv1alpha1.ImageChecksum{
+					Algorithm: "md5",
+					Digest:    strings.Repeat("ab", 16),
+				}
+				cloudProfileConfig.MachineImages[0].Versions[0].Regions[1].Checksum = &stackitv1alpha1.ImageChecksum{
+					Algorithm: "sha512",
+					Digest:    strings.Repeat("ab", 32),
+				}
+				cloudProfileConfig.MachineImages[0].Versions[0].Regions[2].Checksum = &stackitv1alpha1.ImageChecksum{
+					Algorithm: "sha256",
+					Digest:    strings.Repeat("xy", 32),
+				}
+
+				errorList := ValidateCloudProfileConfig(cloudProfileConfig, machineImages, fldPath)
+
+				Expect(errorList).To(ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeNotSupported),
+					"Field": Equal("root.machineImages[0].versions[0].regions[0].checksum.algorithm"),
+				})), PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeInvalid),
+					"Field": Equal("root.machineImages[0].versions[0].regions[1].checksum.digest"),
+				})), PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeInvalid),
+					"Field": Equal("root.machineImages[0].versions[0].regions[2].checksum.digest"),
+				}))))
+			})
+
 			It("should enforce that at least one machine image has been defined", func() {
 				cloudProfileConfig.MachineImages = []stackitv1alpha1.MachineImages{}
 
@@ -414,4 +629,53 @@ var _ = Describe("CloudProfileConfig validation", func() {
 			})
 		})
 	})
+
+	Describe("#ValidateDefaultVolumeType", func() {
+		var (
+			cloudProfileConfig *stackitv1alpha1.CloudProfileConfig
+			volumeTypes        []core.VolumeType
+			fldPath            *field.Path
+		)
+
+		BeforeEach(func() {
+			cloudProfileConfig = &stackitv1alpha1.CloudProfileConfig{}
+			volumeTypes = []core.VolumeType{
+				{Name: "storage_premium_perf1"},
+				{Name: "storage_premium_perf4"},
+			}
+			fldPath = field.NewPath("root")
+		})
+
+		It("should allow not specifying a default volume type", func() {
+			Expect(ValidateDefaultVolumeType(cloudProfileConfig, volumeTypes, fldPath)).To(BeEmpty())
+		})
+
+		It("should allow a volume type of the CloudProfile", func() {
+			cloudProfileConfig.DefaultVolumeType = new("storage_premium_perf4")
+
+			Expect(ValidateDefaultVolumeType(cloudProfileConfig, volumeTypes, fldPath)).To(BeEmpty())
+		})
+
+		It("should forbid unknown volume types", func() {
+			cloudProfileConfig.DefaultVolumeType = new("storage_premium_perf6")
+
+			errorList := ValidateDefaultVolumeType(cloudProfileConfig, volumeTypes, fldPath)
+
+			Expect(errorList).To(ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
+				"Type":  Equal(field.ErrorTypeNotSupported),
+				"Field": Equal("root.defaultVolumeType"),
+			}))))
+		})
+
+		It("should forbid a default volume type if the CloudProfile has no volume types", func() {
+			cloudProfileConfig.DefaultVolumeType = new("storage_premium_perf4")
+
+			errorList := ValidateDefaultVolumeType(cloudProfileConfig, nil, fldPath)
+
+			Expect(errorList).To(ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
+				"Type":  Equal(field.ErrorTypeNotSupported),
+				"Field": Equal("root.defaultVolumeType"),
+			}))))
+		})
+	})
 })
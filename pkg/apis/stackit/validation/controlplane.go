@@ -5,15 +5,52 @@
 package validation
 
 import (
+	"fmt"
+	"net/url"
+	"regexp"
 	"slices"
+	"strings"
 
 	featurevalidation "github.com/gardener/gardener/pkg/utils/validation/features"
+	"github.com/google/uuid"
+	apivalidation "k8s.io/apimachinery/pkg/api/validation"
+	utilvalidation "k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
 
 	stackitv1alpha1 "github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/apis/stackit/v1alpha1"
 )
 
 var (
+	// deniedCCMExtraArgs are cloud-controller-manager flags which are managed by the extension or would weaken the
+	// security of the deployment and therefore must not be passed as extra args.
+	deniedCCMExtraArgs = []string{
+		"allocate-node-cidrs",
+		"authentication-kubeconfig",
+		"authorization-always-allow-paths",
+		"authorization-kubeconfig",
+		"bind-address",
+		"cloud-config",
+		"cloud-provider",
+		"cluster-cidr",
+		"cluster-name",
+		"configure-cloud-routes",
+		"controllers",
+		"feature-gates",
+		"kubeconfig",
+		"leader-elect",
+		"leader-elect-resource-name",
+		"master",
+		"metrics-address",
+		"secure-port",
+		"tls-cert-file",
+		"tls-cipher-suites",
+		"tls-private-key-file",
+		"use-service-account-credentials",
+		"webhook-secure-port",
+	}
+	extraArgRegex = regexp.MustCompile(`^--[a-z0-9][a-z0-9-]*(=.*)?$`)
+
 	validControllers           = []stackitv1alpha1.ControllerName{stackitv1alpha1.STACKIT, stackitv1alpha1.OPENSTACK}
 	validCSICompatibilityModes = []stackitv1alpha1.CSICompatibilityMode{
 		stackitv1alpha1.DEFAULT, stackitv1alpha1.COMPAT, stackitv1alpha1.COMPATBLOCK,
@@ -30,13 +67,33 @@ func ValidateControlPlaneConfig(controlPlaneConfig *stackitv1alpha1.ControlPlane
 
 	allErrs = append(allErrs, validateStorage(controlPlaneConfig.Storage, fldPath.Child("storage"))...)
 
+	if customLabelDomain := controlPlaneConfig.CustomLabelDomain; customLabelDomain != nil {
+		if len(*customLabelDomain) == 0 {
+			allErrs = append(allErrs, field.Required(fldPath.Child("customLabelDomain"), "must provide a label domain when the key is specified"))
+		} else {
+			for _, msg := range utilvalidation.IsDNS1123Subdomain(*customLabelDomain) {
+				allErrs = append(allErrs, field.Invalid(fldPath.Child("customLabelDomain"), *customLabelDomain, msg))
+			}
+		}
+	}
+
 	return allErrs
 }
 
 // ValidateControlPlaneConfigUpdate validates a ControlPlaneConfig object.
-func ValidateControlPlaneConfigUpdate(_, _ *stackitv1alpha1.ControlPlaneConfig, _ *field.Path) field.ErrorList {
+func ValidateControlPlaneConfigUpdate(oldConfig, newConfig *stackitv1alpha1.ControlPlaneConfig, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 
+	// Disabling the snapshot controller removes the VolumeSnapshot CRDs and with them all existing snapshots of the
+	// cluster, hence it can only be disabled when creating the cluster.
+	if !isSnapshotControllerDisabled(oldConfig) && isSnapshotControllerDisabled(newConfig) {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("storage", "disableSnapshotController"),
+			"snapshot controller cannot be disabled for an existing cluster as all existing VolumeSnapshots would be deleted"))
+	}
+
+	// Resources are found by the labels of the custom label domain, hence changing it would orphan the existing ones.
+	allErrs = append(allErrs, apivalidation.ValidateImmutableField(newConfig.CustomLabelDomain, oldConfig.CustomLabelDomain, fldPath.Child("customLabelDomain"))...)
+
 	return allErrs
 }
 
@@ -56,7 +113,28 @@ func validateCloudController(cloudcontroller *stackitv1alpha1.CloudControllerMan
 		allErrs = append(allErrs, field.Invalid(fldPath.Child("name"), cloudcontroller.Name, "not supported ccm driver"))
 	}
 	allErrs = append(allErrs, featurevalidation.ValidateFeatureGates(cloudcontroller.FeatureGates, version, fldPath.Child("featureGates"))...)
+	allErrs = append(allErrs, validateCCMExtraArgs(cloudcontroller.ExtraArgs, fldPath.Child("extraArgs"))...)
+	if cloudcontroller.ServiceLoadBalancerNetworkID != nil {
+		if _, err := uuid.Parse(*cloudcontroller.ServiceLoadBalancerNetworkID); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("serviceLoadBalancerNetworkId"), *cloudcontroller.ServiceLoadBalancerNetworkID, "must be a valid STACKIT network ID"))
+		}
+	}
+
+	return allErrs
+}
 
+func validateCCMExtraArgs(extraArgs []string, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	for i, arg := range extraArgs {
+		if !extraArgRegex.MatchString(arg) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i), arg, "must be a flag in the form --<name> or --<name>=<value>"))
+			continue
+		}
+		name, _, _ := strings.Cut(strings.TrimPrefix(arg, "--"), "=")
+		if slices.Contains(deniedCCMExtraArgs, name) {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Index(i), "flag --"+name+" is managed by the extension and must not be set"))
+		}
+	}
 	return allErrs
 }
 
@@ -86,6 +164,66 @@ func validateApplicationLoadBalancer(applicationLoadBalancerConfig *stackitv1alp
 	return allErrs
 }
 
+func isSnapshotControllerDisabled(config *stackitv1alpha1.ControlPlaneConfig) bool {
+	return config != nil && config.Storage != nil && ptr.Deref(config.Storage.DisableSnapshotController, false)
+}
+
+// ValidateApplicationLoadBalancerPrerequisites validates that the endpoint of the application load balancer API is valid
+// if the application load balancer controller is enabled.
+func ValidateApplicationLoadBalancerPrerequisites(controlPlaneConfig *stackitv1alpha1.ControlPlaneConfig, apiEndpoints *stackitv1alpha1.APIEndpoints, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if controlPlaneConfig.ApplicationLoadBalancer == nil || !controlPlaneConfig.ApplicationLoadBalancer.Enabled {
+		return allErrs
+	}
+
+	enabledPath := fldPath.Child("applicationLoadBalancer", "enabled")
+	if apiEndpoints != nil && apiEndpoints.ApplicationLoadBalancer != nil {
+		endpoint := *apiEndpoints.ApplicationLoadBalancer
+		if u, err := url.Parse(endpoint); err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			allErrs = append(allErrs, field.Invalid(enabledPath, true, fmt.Sprintf("application load balancer requires a valid application load balancer API endpoint, got %q", endpoint)))
+		}
+	}
+
+	return allErrs
+}
+
+// ValidateRequiredAPIEndpoints validates that the endpoints of the STACKIT APIs used by the enabled components are
+// available. An endpoint is available if it is configured in the given APIEndpoints, or if the STACKIT SDK can derive
+// it from the region, which is only the case for the known regions.
+func ValidateRequiredAPIEndpoints(controlPlaneConfig *stackitv1alpha1.ControlPlaneConfig, region string, apiEndpoints *stackitv1alpha1.APIEndpoints, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	endpoints := ptr.Deref(apiEndpoints, stackitv1alpha1.APIEndpoints{})
+
+	requireEndpoint := func(fldPath *field.Path, value any, component, api string, endpoint *string) {
+		if endpoint == nil && !slices.Contains(stackit.KnownRegions, stackit.NormalizeRegion(region)) {
+			allErrs = append(allErrs, field.Invalid(fldPath, value, fmt.Sprintf("%s requires the %s API endpoint, which is neither configured nor known for region %q", component, api, region)))
+		}
+	}
+
+	ccm := ptr.Deref(controlPlaneConfig.CloudControllerManager, stackitv1alpha1.CloudControllerManagerConfig{})
+	if ccm.Name == "" || stackitv1alpha1.ControllerName(ccm.Name) == stackitv1alpha1.STACKIT {
+		ccmPath := fldPath.Child("cloudControllerManager", "name")
+		requireEndpoint(ccmPath, string(stackitv1alpha1.STACKIT), "the STACKIT cloud-controller-manager", "load balancer", endpoints.LoadBalancer)
+		requireEndpoint(ccmPath, string(stackitv1alpha1.STACKIT), "the STACKIT cloud-controller-manager", "IaaS", endpoints.IaaS)
+	}
+
+	csiName := ""
+	if controlPlaneConfig.Storage != nil && controlPlaneConfig.Storage.CSI != nil {
+		csiName = controlPlaneConfig.Storage.CSI.Name
+	}
+	if csiName == "" || stackitv1alpha1.ControllerName(csiName) == stackitv1alpha1.STACKIT {
+		requireEndpoint(fldPath.Child("storage", "csi", "name"), string(stackitv1alpha1.STACKIT), "the STACKIT CSI driver", "IaaS", endpoints.IaaS)
+	}
+
+	if controlPlaneConfig.ApplicationLoadBalancer != nil && controlPlaneConfig.ApplicationLoadBalancer.Enabled {
+		albPath := fldPath.Child("applicationLoadBalancer", "enabled")
+		requireEndpoint(albPath, true, "the application load balancer", "application load balancer", endpoints.ApplicationLoadBalancer)
+		requireEndpoint(albPath, true, "the application load balancer", "application load balancer certificate", endpoints.ApplicationLoadBalancerCertificate)
+	}
+
+	return allErrs
+}
+
 func validateStorage(storage *stackitv1alpha1.Storage, fldPath *field.Path) field.ErrorList {
 	var allErrs field.ErrorList
 	if storage == nil || storage.CSI == nil {
@@ -112,5 +250,14 @@ func validateStorage(storage *stackitv1alpha1.Storage, fldPath *field.Path) fiel
 			))
 		}
 	}
+	if storage.CSI.MaxEntries != nil {
+		maxEntriesPath := fldPath.Child("csi", "maxEntries")
+		if *storage.CSI.MaxEntries <= 0 {
+			allErrs = append(allErrs, field.Invalid(maxEntriesPath, *storage.CSI.MaxEntries, "must be greater than 0"))
+		}
+		if stackitv1alpha1.ControllerName(storage.CSI.Name) != stackitv1alpha1.OPENSTACK {
+			allErrs = append(allErrs, field.Invalid(maxEntriesPath, *storage.CSI.MaxEntries, "can only be set when CSI driver openstack is in use"))
+		}
+	}
 	return allErrs
 }
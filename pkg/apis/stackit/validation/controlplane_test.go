@@ -119,6 +119,16 @@ var _ = Describe("ControlPlaneConfig validation", func() {
 			))
 		})
 
+		It("should succeed with a disabled application load balancer and allow application load balancer controller on false", func() {
+			controlPlane.ApplicationLoadBalancer = &stackitv1alpha1.ApplicationLoadBalancerConfig{
+				Enabled: false,
+				Ingress: &stackitv1alpha1.ApplicationLoadBalancerConfigIngress{
+					Enabled: true,
+				},
+			}
+			Expect(ValidateControlPlaneConfig(controlPlane, "", false, nilPath)).To(BeEmpty())
+		})
+
 		It("should fail with application load balancer and allow application load balancer controller on false", func() {
 			controlPlane.ApplicationLoadBalancer = &stackitv1alpha1.ApplicationLoadBalancerConfig{
 				Enabled: true,
@@ -146,6 +156,30 @@ var _ = Describe("ControlPlaneConfig validation", func() {
 			))
 		})
 
+		It("should allow maxEntries with openstack CSI", func() {
+			controlPlane.Storage = &stackitv1alpha1.Storage{
+				CSI: &stackitv1alpha1.CSI{Name: string(stackitv1alpha1.OPENSTACK), MaxEntries: new(int32(2000))},
+			}
+			Expect(ValidateControlPlaneConfig(controlPlane, "", false, nilPath)).To(BeEmpty())
+		})
+
+		DescribeTable("should fail with an invalid maxEntries",
+			func(csiName stackitv1alpha1.ControllerName, maxEntries int32) {
+				controlPlane.Storage = &stackitv1alpha1.Storage{
+					CSI: &stackitv1alpha1.CSI{Name: string(csiName), MaxEntries: &maxEntries},
+				}
+				Expect(ValidateControlPlaneConfig(controlPlane, "", false, nilPath)).To(ConsistOf(
+					PointTo(MatchFields(IgnoreExtras, Fields{
+						"Type":  Equal(field.ErrorTypeInvalid),
+						"Field": Equal("storage.csi.maxEntries"),
+					})),
+				))
+			},
+			Entry("zero", stackitv1alpha1.OPENSTACK, int32(0)),
+			Entry("negative", stackitv1alpha1.OPENSTACK, int32(-1)),
+			Entry("stackit CSI", stackitv1alpha1.STACKIT, int32(2000)),
+		)
+
 		It("should fail with an unsupported CSI driver", func() {
 			controlPlane.Storage = &stackitv1alpha1.Storage{
 				CSI: &stackitv1alpha1.CSI{Name: "foobar"},
@@ -170,11 +204,194 @@ var _ = Describe("ControlPlaneConfig validation", func() {
 			))
 		})
 
+		It("should succeed with allowed CCM extra args", func() {
+			controlPlane.CloudControllerManager = &stackitv1alpha1.CloudControllerManagerConfig{
+				ExtraArgs: []string{"--concurrent-service-syncs=5", "--v=4", "--some-bool-flag"},
+			}
+			Expect(ValidateControlPlaneConfig(controlPlane, "", false, nilPath)).To(BeEmpty())
+		})
+
+		It("should fail with malformed or denied CCM extra args", func() {
+			controlPlane.CloudControllerManager = &stackitv1alpha1.CloudControllerManagerConfig{
+				ExtraArgs: []string{"--v=4", "concurrent-service-syncs=5", "--cloud-provider=openstack", "--kubeconfig", "-v=2"},
+			}
+			Expect(ValidateControlPlaneConfig(controlPlane, "", false, nilPath)).To(ConsistOf(
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeInvalid),
+					"Field": Equal("cloudControllerManager.extraArgs[1]"),
+				})),
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeForbidden),
+					"Field": Equal("cloudControllerManager.extraArgs[2]"),
+				})),
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeForbidden),
+					"Field": Equal("cloudControllerManager.extraArgs[3]"),
+				})),
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeInvalid),
+					"Field": Equal("cloudControllerManager.extraArgs[4]"),
+				})),
+			))
+		})
+
+		It("should succeed with a valid load balancer network ID", func() {
+			controlPlane.CloudControllerManager = &stackitv1alpha1.CloudControllerManagerConfig{
+				ServiceLoadBalancerNetworkID: new("4b8e6d9a-4c0f-4f4b-9a4e-7f1c2b3d4e5f"),
+			}
+			Expect(ValidateControlPlaneConfig(controlPlane, "", false, nilPath)).To(BeEmpty())
+		})
+
+		It("should fail with an invalid load balancer network ID", func() {
+			controlPlane.CloudControllerManager = &stackitv1alpha1.CloudControllerManagerConfig{
+				ServiceLoadBalancerNetworkID: new("my-network"),
+			}
+			Expect(ValidateControlPlaneConfig(controlPlane, "", false, nilPath)).To(ConsistOf(
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeInvalid),
+					"Field": Equal("cloudControllerManager.serviceLoadBalancerNetworkId"),
+				})),
+			))
+		})
+
+		It("should succeed with a custom label domain", func() {
+			controlPlane.CustomLabelDomain = new("customer.example.com")
+			Expect(ValidateControlPlaneConfig(controlPlane, "", false, nilPath)).To(BeEmpty())
+		})
+
+		DescribeTable("should fail with an invalid custom label domain",
+			func(customLabelDomain string, errorType field.ErrorType) {
+				controlPlane.CustomLabelDomain = &customLabelDomain
+				Expect(ValidateControlPlaneConfig(controlPlane, "", false, nilPath)).To(ConsistOf(
+					PointTo(MatchFields(IgnoreExtras, Fields{
+						"Type":  Equal(errorType),
+						"Field": Equal("customLabelDomain"),
+					})),
+				))
+			},
+			Entry("empty domain", "", field.ErrorTypeRequired),
+			Entry("uppercase domain", "Example.com", field.ErrorTypeInvalid),
+			Entry("domain with a slash", "example.com/foo", field.ErrorTypeInvalid),
+		)
 	})
 
+	Describe("#ValidateApplicationLoadBalancerPrerequisites", func() {
+		BeforeEach(func() {
+			controlPlane.ApplicationLoadBalancer = &stackitv1alpha1.ApplicationLoadBalancerConfig{Enabled: true}
+		})
+
+		It("should return no errors if the prerequisites are met", func() {
+			apiEndpoints := &stackitv1alpha1.APIEndpoints{ApplicationLoadBalancer: new("https://alb.api.stackit.cloud")}
+
+			Expect(ValidateApplicationLoadBalancerPrerequisites(controlPlane, apiEndpoints, nilPath)).To(BeEmpty())
+			Expect(ValidateApplicationLoadBalancerPrerequisites(controlPlane, nil, nilPath)).To(BeEmpty())
+		})
+
+		It("should return no errors if the application load balancer is disabled", func() {
+			controlPlane.ApplicationLoadBalancer.Enabled = false
+			apiEndpoints := &stackitv1alpha1.APIEndpoints{ApplicationLoadBalancer: new("alb.api.stackit.cloud")}
+
+			Expect(ValidateApplicationLoadBalancerPrerequisites(controlPlane, apiEndpoints, nilPath)).To(BeEmpty())
+		})
+
+		It("should fail with an invalid application load balancer API endpoint", func() {
+			apiEndpoints := &stackitv1alpha1.APIEndpoints{ApplicationLoadBalancer: new("alb.api.stackit.cloud")}
+
+			Expect(ValidateApplicationLoadBalancerPrerequisites(controlPlane, apiEndpoints, nilPath)).To(ConsistOf(
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":   Equal(field.ErrorTypeInvalid),
+					"Field":  Equal("applicationLoadBalancer.enabled"),
+					"Detail": ContainSubstring("API endpoint"),
+				})),
+			))
+		})
+	})
+
+	DescribeTable("#ValidateRequiredAPIEndpoints",
+		func(controlPlane *stackitv1alpha1.ControlPlaneConfig, region string, apiEndpoints *stackitv1alpha1.APIEndpoints, expectedFields []string) {
+			var fields []string
+			for _, err := range ValidateRequiredAPIEndpoints(controlPlane, region, apiEndpoints, nilPath) {
+				Expect(err.Type).To(Equal(field.ErrorTypeInvalid))
+				fields = append(fields, err.Field)
+			}
+			Expect(fields).To(Equal(expectedFields))
+		},
+		Entry("STACKIT components in a known region", &stackitv1alpha1.ControlPlaneConfig{}, "eu01", nil, nil),
+		Entry("STACKIT components in the legacy region", &stackitv1alpha1.ControlPlaneConfig{}, "RegionOne", nil, nil),
+		Entry("STACKIT components in an unknown region without endpoints", &stackitv1alpha1.ControlPlaneConfig{}, "eu99", nil,
+			[]string{"cloudControllerManager.name", "cloudControllerManager.name", "storage.csi.name"}),
+		Entry("STACKIT components in an unknown region with endpoints", &stackitv1alpha1.ControlPlaneConfig{}, "eu99",
+			&stackitv1alpha1.APIEndpoints{LoadBalancer: new("https://lb.example.com"), IaaS: new("https://iaas.example.com")}, nil),
+		Entry("OpenStack components in an unknown region", &stackitv1alpha1.ControlPlaneConfig{
+			CloudControllerManager: &stackitv1alpha1.CloudControllerManagerConfig{Name: "openstack"},
+			Storage:                &stackitv1alpha1.Storage{CSI: &stackitv1alpha1.CSI{Name: "openstack"}},
+		}, "eu99", nil, nil),
+		Entry("application load balancer in a known region", &stackitv1alpha1.ControlPlaneConfig{
+			ApplicationLoadBalancer: &stackitv1alpha1.ApplicationLoadBalancerConfig{Enabled: true},
+		}, "eu02", nil, nil),
+		Entry("application load balancer in an unknown region without endpoints", &stackitv1alpha1.ControlPlaneConfig{
+			ApplicationLoadBalancer: &stackitv1alpha1.ApplicationLoadBalancerConfig{Enabled: true},
+		}, "eu99", &stackitv1alpha1.APIEndpoints{LoadBalancer: new("https://lb.example.com"), IaaS: new("https://iaas.example.com")},
+			[]string{"applicationLoadBalancer.enabled", "applicationLoadBalancer.enabled"}),
+		Entry("application load balancer in an unknown region with endpoints", &stackitv1alpha1.ControlPlaneConfig{
+			ApplicationLoadBalancer: &stackitv1alpha1.ApplicationLoadBalancerConfig{Enabled: true},
+		}, "eu99", &stackitv1alpha1.APIEndpoints{
+			LoadBalancer:                       new("https://lb.example.com"),
+			IaaS:                               new("https://iaas.example.com"),
+			ApplicationLoadBalancer:            new("https://alb.example.com"),
+			ApplicationLoadBalancerCertificate: new("https://certificates.example.com"),
+		}, nil),
+	)
+
 	Describe("#ValidateControlPlaneConfigUpdate", func() {
 		It("should return no errors for an unchanged config", func() {
 			Expect(ValidateControlPlaneConfigUpdate(controlPlane, controlPlane, nilPath)).To(BeEmpty())
 		})
+
+		It("should forbid disabling the snapshot controller of an existing cluster", func() {
+			newControlPlane := controlPlane.DeepCopy()
+			newControlPlane.Storage = &stackitv1alpha1.Storage{DisableSnapshotController: new(true)}
+
+			Expect(ValidateControlPlaneConfigUpdate(controlPlane, newControlPlane, nilPath)).To(ConsistOf(
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeForbidden),
+					"Field": Equal("storage.disableSnapshotController"),
+				})),
+			))
+		})
+
+		It("should allow keeping or re-enabling a disabled snapshot controller", func() {
+			oldControlPlane := controlPlane.DeepCopy()
+			oldControlPlane.Storage = &stackitv1alpha1.Storage{DisableSnapshotController: new(true)}
+
+			Expect(ValidateControlPlaneConfigUpdate(oldControlPlane, oldControlPlane, nilPath)).To(BeEmpty())
+			Expect(ValidateControlPlaneConfigUpdate(oldControlPlane, controlPlane, nilPath)).To(BeEmpty())
+		})
+
+		It("should allow keeping the custom label domain", func() {
+			controlPlane.CustomLabelDomain = new("customer.example.com")
+
+			Expect(ValidateControlPlaneConfigUpdate(controlPlane, controlPlane.DeepCopy(), nilPath)).To(BeEmpty())
+		})
+
+		It("should forbid adding, changing and removing the custom label domain", func() {
+			newControlPlane := controlPlane.DeepCopy()
+			newControlPlane.CustomLabelDomain = new("customer.example.com")
+			changedControlPlane := controlPlane.DeepCopy()
+			changedControlPlane.CustomLabelDomain = new("other.example.com")
+
+			for _, configs := range [][2]*stackitv1alpha1.ControlPlaneConfig{
+				{controlPlane, newControlPlane},
+				{newControlPlane, changedControlPlane},
+				{newControlPlane, controlPlane},
+			} {
+				Expect(ValidateControlPlaneConfigUpdate(configs[0], configs[1], nilPath)).To(ConsistOf(
+					PointTo(MatchFields(IgnoreExtras, Fields{
+						"Type":  Equal(field.ErrorTypeInvalid),
+						"Field": Equal("customLabelDomain"),
+					})),
+				))
+			}
+		})
 	})
 })
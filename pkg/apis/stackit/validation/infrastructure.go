@@ -5,14 +5,21 @@
 package validation
 
 import (
+	"fmt"
+	"net"
+	"net/url"
 	"slices"
 
 	cidrvalidation "github.com/gardener/gardener/pkg/utils/validation/cidr"
 	"github.com/google/uuid"
 	apivalidation "k8s.io/apimachinery/pkg/api/validation"
+	"k8s.io/apimachinery/pkg/util/sets"
+	utilvalidation "k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
 
 	stackitv1alpha1 "github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/apis/stackit/v1alpha1"
+	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/stackit"
 )
 
 // ValidateInfrastructureConfig validates a InfrastructureConfig object.
@@ -75,14 +82,247 @@ func ValidateInfrastructureConfig(infra *stackitv1alpha1.InfrastructureConfig, n
 		}
 	}
 
+	if infra.Networks.DNSServers != nil {
+		allErrs = append(allErrs, validateDNSServers(*infra.Networks.DNSServers, networksPath.Child("dnsServers"))...)
+	}
+
+	if infra.Networks.IPv6 != nil {
+		allErrs = append(allErrs, validateIPv6Network(infra.Networks.IPv6, networksPath.Child("ipv6"))...)
+		if infra.Networks.ID != nil {
+			allErrs = append(allErrs, field.Forbidden(networksPath.Child("ipv6"), "cannot be set if a network id is provided"))
+		}
+	}
+
+	// without DHCP, the nodes do not receive the nameservers of the network
+	if infra.Networks.DHCP != nil && !*infra.Networks.DHCP && (infra.Networks.DNSServers == nil || len(*infra.Networks.DNSServers) == 0) {
+		allErrs = append(allErrs, field.Required(networksPath.Child("dnsServers"), "must provide DNS servers if DHCP is disabled"))
+	}
+
 	if infra.Networks.Router != nil && len(infra.Networks.Router.ID) == 0 {
 		allErrs = append(allErrs, field.Invalid(networksPath.Child("router", "id"), infra.Networks.Router.ID, "router id must not be empty when router key is provided"))
 	}
 
+	if infra.FloatingPoolID != nil && len(*infra.FloatingPoolID) == 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("floatingPoolId"), *infra.FloatingPoolID, "floating pool id must not be empty when the key is provided"))
+	}
+
 	if infra.FloatingPoolSubnetName != nil && infra.Networks.Router != nil && len(infra.Networks.Router.ID) > 0 {
 		allErrs = append(allErrs, field.Invalid(fldPath.Child("floatingPoolSubnetName"), infra.FloatingPoolSubnetName, "router id must be empty when a floating subnet name is provided"))
 	}
 
+	if infra.IntraNodeTraffic != nil {
+		allErrs = append(allErrs, validateIntraNodePorts(infra.IntraNodeTraffic.Ports, fldPath.Child("intraNodeTraffic", "ports"))...)
+	}
+
+	allErrs = append(allErrs, validateAdditionalSecurityGroupRules(infra.AdditionalSecurityGroupRules, fldPath.Child("additionalSecurityGroupRules"))...)
+
+	if infra.IaaSEndpoint != nil {
+		if !isHTTPSURL(*infra.IaaSEndpoint) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("iaasEndpoint"), *infra.IaaSEndpoint, "must be a valid https URL"))
+		}
+	}
+
+	return allErrs
+}
+
+var (
+	supportedSecurityGroupRuleProtocols = []string{"tcp", "udp", "sctp", "dccp", "udplite", "icmp", "ipip"}
+	supportedDirections                 = []string{stackit.DirectionIngress, stackit.DirectionEgress}
+	supportedEtherTypes                 = []string{stackit.EtherTypeIPv4, stackit.EtherTypeIPv6}
+)
+
+func validateIntraNodePorts(ports []stackitv1alpha1.IntraNodePort, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	for i, port := range ports {
+		idxPath := fldPath.Index(i)
+		if !slices.Contains(supportedSecurityGroupRuleProtocols, port.Protocol) {
+			allErrs = append(allErrs, field.NotSupported(idxPath.Child("protocol"), port.Protocol, supportedSecurityGroupRuleProtocols))
+			continue
+		}
+
+		allErrs = append(allErrs, validatePortRange(port.Protocol, port.Min, port.Max, idxPath, "min", "max")...)
+	}
+
+	return allErrs
+}
+
+func validateAdditionalSecurityGroupRules(rules []stackitv1alpha1.SecurityGroupRuleConfig, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	for i, rule := range rules {
+		idxPath := fldPath.Index(i)
+		if !slices.Contains(supportedDirections, rule.Direction) {
+			allErrs = append(allErrs, field.NotSupported(idxPath.Child("direction"), rule.Direction, supportedDirections))
+		}
+
+		etherType := ptr.Deref(rule.EtherType, stackit.EtherTypeIPv4)
+		if !slices.Contains(supportedEtherTypes, etherType) {
+			allErrs = append(allErrs, field.NotSupported(idxPath.Child("etherType"), etherType, supportedEtherTypes))
+		}
+
+		if rule.Protocol == nil {
+			if rule.PortRangeMin != nil || rule.PortRangeMax != nil {
+				allErrs = append(allErrs, field.Required(idxPath.Child("protocol"), "must be set for port ranges"))
+			}
+		} else if !slices.Contains(supportedSecurityGroupRuleProtocols, *rule.Protocol) {
+			allErrs = append(allErrs, field.NotSupported(idxPath.Child("protocol"), *rule.Protocol, supportedSecurityGroupRuleProtocols))
+		} else if rule.PortRangeMin != nil || rule.PortRangeMax != nil || !stackit.ProtocolsWithPortRange.Has(*rule.Protocol) {
+			// rules for protocols with ports may also apply to all ports
+			allErrs = append(allErrs, validatePortRange(*rule.Protocol, rule.PortRangeMin, rule.PortRangeMax, idxPath, "portRangeMin", "portRangeMax")...)
+		}
+
+		if rule.RemoteIPPrefix != nil && rule.RemoteSecurityGroupID != nil {
+			allErrs = append(allErrs, field.Forbidden(idxPath.Child("remoteSecurityGroupID"), "must not be set together with remoteIPPrefix"))
+		}
+		if rule.RemoteIPPrefix != nil {
+			if _, ipNet, err := net.ParseCIDR(*rule.RemoteIPPrefix); err != nil {
+				allErrs = append(allErrs, field.Invalid(idxPath.Child("remoteIPPrefix"), *rule.RemoteIPPrefix, "must be a valid CIDR"))
+			} else if isIPv4 := ipNet.IP.To4() != nil; isIPv4 != (etherType == stackit.EtherTypeIPv4) {
+				allErrs = append(allErrs, field.Invalid(idxPath.Child("remoteIPPrefix"), *rule.RemoteIPPrefix, fmt.Sprintf("must be a CIDR of ether type %s", etherType)))
+			}
+		}
+		if rule.RemoteSecurityGroupID != nil && len(*rule.RemoteSecurityGroupID) == 0 {
+			allErrs = append(allErrs, field.Required(idxPath.Child("remoteSecurityGroupID"), "must not be empty when the key is provided"))
+		}
+		// the description is prefixed to identify the rules of the extension
+		if maxLength := stackit.MaxSecurityGroupRuleDescriptionLength - len(stackit.AdditionalSecurityGroupRuleDescriptionPrefix); rule.Description != nil && len(*rule.Description) > maxLength {
+			allErrs = append(allErrs, field.TooLong(idxPath.Child("description"), *rule.Description, maxLength))
+		}
+	}
+
+	return allErrs
+}
+
+// validatePortRange validates the given port range of a rule with the given protocol, whose ports are the fields
+// minName and maxName of fldPath. Port ranges are required for protocols supporting them and forbidden for other
+// protocols.
+func validatePortRange(protocol string, minPort, maxPort *int32, fldPath *field.Path, minName, maxName string) field.ErrorList {
+	allErrs := field.ErrorList{}
+	minPath, maxPath := fldPath.Child(minName), fldPath.Child(maxName)
+
+	if !stackit.ProtocolsWithPortRange.Has(protocol) {
+		if minPort != nil {
+			allErrs = append(allErrs, field.Forbidden(minPath, fmt.Sprintf("must not be set for protocol %q", protocol)))
+		}
+		if maxPort != nil {
+			allErrs = append(allErrs, field.Forbidden(maxPath, fmt.Sprintf("must not be set for protocol %q", protocol)))
+		}
+		return allErrs
+	}
+
+	if minPort == nil {
+		return append(allErrs, field.Required(minPath, fmt.Sprintf("must be set for protocol %q", protocol)))
+	}
+	for _, msg := range utilvalidation.IsValidPortNum(int(*minPort)) {
+		allErrs = append(allErrs, field.Invalid(minPath, *minPort, msg))
+	}
+	if maxPort != nil {
+		for _, msg := range utilvalidation.IsValidPortNum(int(*maxPort)) {
+			allErrs = append(allErrs, field.Invalid(maxPath, *maxPort, msg))
+		}
+		if *maxPort < *minPort {
+			allErrs = append(allErrs, field.Invalid(maxPath, *maxPort, "must not be less than "+minName))
+		}
+	}
+
+	return allErrs
+}
+
+// validateDNSServers validates that the DNS servers are IPs.
+func validateDNSServers(dnsServers []string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	for i, ip := range dnsServers {
+		if net.ParseIP(ip) == nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i), ip, "must provide a valid IP"))
+		}
+	}
+
+	return allErrs
+}
+
+// ValidateInfrastructureConfigDNSServerLimits validates that the networks of the given InfrastructureConfig can be
+// created with their DNS servers via the STACKIT IaaS API, which accepts at most stackit.MaxDNSServers nameservers per
+// network. Duplicates are removed before creating the network, so they do not count towards the limit. Only DNS
+// servers which were added or changed compared to the old InfrastructureConfig are validated, so that updates of other
+// fields are not blocked by DNS servers which were accepted before. The limit only applies if the infrastructure is
+// reconciled via the STACKIT API.
+func ValidateInfrastructureConfigDNSServerLimits(oldInfra, infra *stackitv1alpha1.InfrastructureConfig, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	networksPath := fldPath.Child("networks")
+
+	var oldDNSServers, oldIPv6DNSServers []string
+	if oldInfra != nil {
+		oldDNSServers = ptr.Deref(oldInfra.Networks.DNSServers, nil)
+		if oldInfra.Networks.IPv6 != nil {
+			oldIPv6DNSServers = oldInfra.Networks.IPv6.DNSServers
+		}
+	}
+
+	allErrs = append(allErrs, validateDNSServerLimit(oldDNSServers, ptr.Deref(infra.Networks.DNSServers, nil), networksPath.Child("dnsServers"))...)
+	if infra.Networks.IPv6 != nil {
+		allErrs = append(allErrs, validateDNSServerLimit(oldIPv6DNSServers, infra.Networks.IPv6.DNSServers, networksPath.Child("ipv6", "dnsServers"))...)
+	}
+
+	return allErrs
+}
+
+func validateDNSServerLimit(oldDNSServers, dnsServers []string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if sets.New(dnsServers...).Equal(sets.New(oldDNSServers...)) {
+		return allErrs
+	}
+	if count := sets.New(dnsServers...).Len(); count > stackit.MaxDNSServers {
+		allErrs = append(allErrs, field.TooMany(fldPath, count, stackit.MaxDNSServers))
+	}
+	return allErrs
+}
+
+// validateIPv6Network validates that exactly one of the IPv6 prefix and prefix length is set, and that the DNS servers
+// are IPv6 addresses.
+func validateIPv6Network(ipv6 *stackitv1alpha1.IPv6Network, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	switch {
+	case ipv6.Prefix == nil && ipv6.PrefixLength == nil:
+		allErrs = append(allErrs, field.Required(fldPath.Child("prefixLength"), "must provide either an IPv6 prefix or a prefix length"))
+	case ipv6.Prefix != nil && ipv6.PrefixLength != nil:
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("prefixLength"), "cannot be set together with a prefix"))
+	}
+	if ipv6.Prefix != nil {
+		prefixPath := fldPath.Child("prefix")
+		if ip, _, err := net.ParseCIDR(*ipv6.Prefix); err != nil || ip.To4() != nil {
+			allErrs = append(allErrs, field.Invalid(prefixPath, *ipv6.Prefix, "must provide a valid IPv6 CIDR"))
+		} else {
+			allErrs = append(allErrs, cidrvalidation.ValidateCIDRIsCanonical(prefixPath, *ipv6.Prefix)...)
+		}
+	}
+	if ipv6.PrefixLength != nil && (*ipv6.PrefixLength < 1 || *ipv6.PrefixLength > 128) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("prefixLength"), *ipv6.PrefixLength, "must be between 1 and 128"))
+	}
+
+	dnsServersPath := fldPath.Child("dnsServers")
+	allErrs = append(allErrs, validateDNSServers(ipv6.DNSServers, dnsServersPath)...)
+	for i, dnsServer := range ipv6.DNSServers {
+		if ip := net.ParseIP(dnsServer); ip != nil && ip.To4() != nil {
+			allErrs = append(allErrs, field.Invalid(dnsServersPath.Index(i), dnsServer, "must provide an IPv6 address"))
+		}
+	}
+
+	return allErrs
+}
+
+// ValidateInfrastructureConfigAgainstIPFamilies validates that IPv6 is only configured for the worker network of
+// dual-stack shoots.
+func ValidateInfrastructureConfigAgainstIPFamilies(infra *stackitv1alpha1.InfrastructureConfig, ipFamilies []string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	dualStack := slices.Contains(ipFamilies, "IPv4") && slices.Contains(ipFamilies, "IPv6")
+	if infra.Networks.IPv6 != nil && !dualStack {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("networks", "ipv6"), "can only be set for dual-stack shoots"))
+	}
+
 	return allErrs
 }
 
@@ -96,9 +336,20 @@ func ValidateInfrastructureConfigUpdate(oldConfig, newConfig *stackitv1alpha1.In
 	newNetworks := newConfig.DeepCopy().Networks
 	oldNetworks := oldConfig.DeepCopy().Networks
 
+	// the IPv6 prefix of a network cannot be added, changed or removed after its creation
+	allErrs = append(allErrs, apivalidation.ValidateImmutableField(newNetworks.IPv6, oldNetworks.IPv6, fldPath.Child("networks", "ipv6"))...)
+	newNetworks.IPv6, oldNetworks.IPv6 = nil, nil
+	// DHCP is also applied to existing networks
+	newNetworks.DHCP, oldNetworks.DHCP = nil, nil
+
 	allErrs = append(allErrs, apivalidation.ValidateImmutableField(newNetworks, oldNetworks, fldPath.Child("networks"))...)
 	allErrs = append(allErrs, apivalidation.ValidateImmutableField(newConfig.FloatingPoolName, oldConfig.FloatingPoolName, fldPath.Child("floatingPoolName"))...)
 	allErrs = append(allErrs, apivalidation.ValidateImmutableField(newConfig.FloatingPoolSubnetName, oldConfig.FloatingPoolSubnetName, fldPath.Child("floatingPoolSubnetName"))...)
+	// the floating pool ID can be added to existing shoots whose floating pool name has become ambiguous
+	if oldConfig.FloatingPoolID != nil {
+		allErrs = append(allErrs, apivalidation.ValidateImmutableField(newConfig.FloatingPoolID, oldConfig.FloatingPoolID, fldPath.Child("floatingPoolId"))...)
+	}
+	allErrs = append(allErrs, apivalidation.ValidateImmutableField(newConfig.IaaSEndpoint, oldConfig.IaaSEndpoint, fldPath.Child("iaasEndpoint"))...)
 
 	return allErrs
 }
@@ -115,6 +366,32 @@ func ValidateInfrastructureConfigAgainstCloudProfile(oldInfra, infra *stackitv1a
 	return allErrs
 }
 
+// ValidateIaaSEndpointAgainstCloudProfile validates that the IaaS endpoint of the given InfrastructureConfig is one of
+// the allowed IaaS endpoints of the given CloudProfileConfig, so that shoot owners cannot make the extension send its
+// credentials to arbitrary hosts.
+func ValidateIaaSEndpointAgainstCloudProfile(infra *stackitv1alpha1.InfrastructureConfig, cloudProfileConfig *stackitv1alpha1.CloudProfileConfig, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if infra.IaaSEndpoint == nil {
+		return allErrs
+	}
+
+	var allowedEndpoints []string
+	if cloudProfileConfig != nil {
+		allowedEndpoints = cloudProfileConfig.AllowedIaaSEndpoints
+	}
+	if !slices.Contains(allowedEndpoints, *infra.IaaSEndpoint) {
+		allErrs = append(allErrs, field.NotSupported(fldPath, *infra.IaaSEndpoint, allowedEndpoints))
+	}
+
+	return allErrs
+}
+
+// isHTTPSURL returns true if the given endpoint is an https URL with a host.
+func isHTTPSURL(endpoint string) bool {
+	u, err := url.Parse(endpoint)
+	return err == nil && u.Scheme == "https" && u.Host != ""
+}
+
 func validateFloatingPoolNameConstraints(fps []stackitv1alpha1.FloatingPool, name string, fldPath *field.Path) *field.Error {
 	availablePoolNames := make([]string, 0, len(fps))
 	for _, fp := range fps {
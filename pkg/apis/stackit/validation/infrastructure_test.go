@@ -5,11 +5,14 @@
 package validation_test
 
 import (
+	"strings"
+
 	. "github.com/gardener/gardener/pkg/utils/test/matchers"
 	"github.com/google/uuid"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	. "github.com/onsi/gomega/gstruct"
+	"github.com/onsi/gomega/types"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	stackitv1alpha1 "github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/apis/stackit/v1alpha1"
@@ -60,6 +63,17 @@ var _ = Describe("InfrastructureConfig validation", func() {
 			}))
 		})
 
+		It("should forbid an empty floating pool id", func() {
+			infrastructureConfig.FloatingPoolID = new("")
+
+			errorList := ValidateInfrastructureConfig(infrastructureConfig, &nodes, nilPath)
+
+			Expect(errorList).To(ConsistOfFields(Fields{
+				"Type":  Equal(field.ErrorTypeInvalid),
+				"Field": Equal("floatingPoolId"),
+			}))
+		})
+
 		It("should forbid floating ip subnet when router is specified", func() {
 			infrastructureConfig.Networks.Router = &stackitv1alpha1.Router{ID: "sample-router-id"}
 			infrastructureConfig.FloatingPoolSubnetName = new("sample-floating-pool-subnet-id")
@@ -107,6 +121,324 @@ var _ = Describe("InfrastructureConfig validation", func() {
 
 			Expect(errorList).To(BeEmpty())
 		})
+
+		It("should allow valid DNS servers", func() {
+			infrastructureConfig.Networks.DNSServers = &[]string{"1.1.1.1", "2001:db8::1"}
+
+			Expect(ValidateInfrastructureConfig(infrastructureConfig, &nodes, nilPath)).To(BeEmpty())
+		})
+
+		It("should not limit the number of DNS servers", func() {
+			infrastructureConfig.Networks.DNSServers = &[]string{"1.1.1.1", "8.8.8.8", "9.9.9.9", "1.0.0.1"}
+
+			Expect(ValidateInfrastructureConfig(infrastructureConfig, &nodes, nilPath)).To(BeEmpty())
+		})
+
+		It("should forbid invalid DNS servers", func() {
+			infrastructureConfig.Networks.DNSServers = &[]string{"1.1.1.1", "dns.example.com"}
+
+			Expect(ValidateInfrastructureConfig(infrastructureConfig, &nodes, nilPath)).To(ConsistOfFields(Fields{
+				"Type":  Equal(field.ErrorTypeInvalid),
+				"Field": Equal("networks.dnsServers[1]"),
+			}))
+		})
+
+		It("should allow disabling DHCP with DNS servers", func() {
+			infrastructureConfig.Networks.DHCP = new(false)
+			infrastructureConfig.Networks.DNSServers = &[]string{"1.1.1.1"}
+
+			Expect(ValidateInfrastructureConfig(infrastructureConfig, &nodes, nilPath)).To(BeEmpty())
+		})
+
+		It("should forbid disabling DHCP without DNS servers", func() {
+			infrastructureConfig.Networks.DHCP = new(false)
+			infrastructureConfig.Networks.DNSServers = &[]string{}
+
+			Expect(ValidateInfrastructureConfig(infrastructureConfig, &nodes, nilPath)).To(ConsistOfFields(Fields{
+				"Type":  Equal(field.ErrorTypeRequired),
+				"Field": Equal("networks.dnsServers"),
+			}))
+		})
+
+		Context("IPv6", func() {
+			It("should allow an IPv6 prefix length", func() {
+				infrastructureConfig.Networks.IPv6 = &stackitv1alpha1.IPv6Network{
+					PrefixLength: new(int32(64)),
+					DNSServers:   []string{"2001:4860:4860::8888"},
+				}
+
+				Expect(ValidateInfrastructureConfig(infrastructureConfig, &nodes, nilPath)).To(BeEmpty())
+			})
+
+			It("should allow an IPv6 prefix", func() {
+				infrastructureConfig.Networks.IPv6 = &stackitv1alpha1.IPv6Network{Prefix: new("2001:db8::/64")}
+
+				Expect(ValidateInfrastructureConfig(infrastructureConfig, &nodes, nilPath)).To(BeEmpty())
+			})
+
+			It("should require either a prefix or a prefix length", func() {
+				infrastructureConfig.Networks.IPv6 = &stackitv1alpha1.IPv6Network{}
+
+				Expect(ValidateInfrastructureConfig(infrastructureConfig, &nodes, nilPath)).To(ConsistOfFields(Fields{
+					"Type":  Equal(field.ErrorTypeRequired),
+					"Field": Equal("networks.ipv6.prefixLength"),
+				}))
+			})
+
+			It("should forbid a prefix together with a prefix length", func() {
+				infrastructureConfig.Networks.IPv6 = &stackitv1alpha1.IPv6Network{
+					Prefix:       new("2001:db8::/64"),
+					PrefixLength: new(int32(64)),
+				}
+
+				Expect(ValidateInfrastructureConfig(infrastructureConfig, &nodes, nilPath)).To(ConsistOfFields(Fields{
+					"Type":  Equal(field.ErrorTypeForbidden),
+					"Field": Equal("networks.ipv6.prefixLength"),
+				}))
+			})
+
+			It("should forbid IPv4 prefixes and nameservers", func() {
+				infrastructureConfig.Networks.IPv6 = &stackitv1alpha1.IPv6Network{
+					Prefix:     new("10.0.0.0/24"),
+					DNSServers: []string{"1.1.1.1"},
+				}
+
+				Expect(ValidateInfrastructureConfig(infrastructureConfig, &nodes, nilPath)).To(ConsistOfFields(Fields{
+					"Type":  Equal(field.ErrorTypeInvalid),
+					"Field": Equal("networks.ipv6.prefix"),
+				}, Fields{
+					"Type":  Equal(field.ErrorTypeInvalid),
+					"Field": Equal("networks.ipv6.dnsServers[0]"),
+				}))
+			})
+
+			It("should forbid an invalid prefix length", func() {
+				infrastructureConfig.Networks.IPv6 = &stackitv1alpha1.IPv6Network{PrefixLength: new(int32(129))}
+
+				Expect(ValidateInfrastructureConfig(infrastructureConfig, &nodes, nilPath)).To(ConsistOfFields(Fields{
+					"Type":  Equal(field.ErrorTypeInvalid),
+					"Field": Equal("networks.ipv6.prefixLength"),
+				}))
+			})
+
+			It("should forbid IPv6 for an existing network", func() {
+				infrastructureConfig.Networks.Workers = ""
+				infrastructureConfig.Networks.ID = new(uuid.NewString())
+				infrastructureConfig.Networks.IPv6 = &stackitv1alpha1.IPv6Network{PrefixLength: new(int32(64))}
+
+				Expect(ValidateInfrastructureConfig(infrastructureConfig, &nodes, nilPath)).To(ConsistOfFields(Fields{
+					"Type":  Equal(field.ErrorTypeForbidden),
+					"Field": Equal("networks.ipv6"),
+				}))
+			})
+		})
+
+		Context("IaaS endpoint", func() {
+			It("should allow a valid URL", func() {
+				infrastructureConfig.IaaSEndpoint = new("https://iaas.staging.example.com")
+
+				Expect(ValidateInfrastructureConfig(infrastructureConfig, &nodes, nilPath)).To(BeEmpty())
+			})
+
+			DescribeTable("should forbid invalid URLs",
+				func(endpoint string) {
+					infrastructureConfig.IaaSEndpoint = &endpoint
+
+					Expect(ValidateInfrastructureConfig(infrastructureConfig, &nodes, nilPath)).To(ConsistOfFields(Fields{
+						"Type":  Equal(field.ErrorTypeInvalid),
+						"Field": Equal("iaasEndpoint"),
+					}))
+				},
+				Entry("empty", ""),
+				Entry("without scheme", "iaas.staging.example.com"),
+				Entry("unsupported scheme", "ftp://iaas.staging.example.com"),
+				Entry("insecure scheme", "http://iaas.staging.example.com"),
+				Entry("unparsable", "https://iaas staging"),
+			)
+		})
+
+		Context("intra node traffic", func() {
+			It("should allow valid ports", func() {
+				infrastructureConfig.IntraNodeTraffic = &stackitv1alpha1.IntraNodeTraffic{
+					Ports: []stackitv1alpha1.IntraNodePort{
+						{Protocol: "tcp", Min: new(int32(10250))},
+						{Protocol: "udp", Min: new(int32(4789)), Max: new(int32(4789))},
+						{Protocol: "icmp"},
+						{Protocol: "ipip"},
+					},
+				}
+
+				Expect(ValidateInfrastructureConfig(infrastructureConfig, &nodes, nilPath)).To(BeEmpty())
+			})
+
+			DescribeTable("should allow port ranges for protocols supporting them",
+				func(protocol string) {
+					infrastructureConfig.IntraNodeTraffic = &stackitv1alpha1.IntraNodeTraffic{
+						Ports: []stackitv1alpha1.IntraNodePort{{Protocol: protocol, Min: new(int32(1000)), Max: new(int32(2000))}},
+					}
+
+					Expect(ValidateInfrastructureConfig(infrastructureConfig, &nodes, nilPath)).To(BeEmpty())
+				},
+				Entry("tcp", "tcp"),
+				Entry("udp", "udp"),
+				Entry("sctp", "sctp"),
+				Entry("dccp", "dccp"),
+				Entry("udplite", "udplite"),
+			)
+
+			DescribeTable("should forbid port ranges for protocols not supporting them",
+				func(protocol string) {
+					infrastructureConfig.IntraNodeTraffic = &stackitv1alpha1.IntraNodeTraffic{
+						Ports: []stackitv1alpha1.IntraNodePort{{Protocol: protocol, Min: new(int32(1000)), Max: new(int32(2000))}},
+					}
+
+					Expect(ValidateInfrastructureConfig(infrastructureConfig, &nodes, nilPath)).To(ConsistOfFields(Fields{
+						"Type":  Equal(field.ErrorTypeForbidden),
+						"Field": Equal("intraNodeTraffic.ports[0].min"),
+					}, Fields{
+						"Type":  Equal(field.ErrorTypeForbidden),
+						"Field": Equal("intraNodeTraffic.ports[0].max"),
+					}))
+				},
+				Entry("icmp", "icmp"),
+				Entry("ipip", "ipip"),
+			)
+
+			It("should forbid unsupported protocols", func() {
+				infrastructureConfig.IntraNodeTraffic = &stackitv1alpha1.IntraNodeTraffic{
+					Ports: []stackitv1alpha1.IntraNodePort{{Protocol: "gre"}},
+				}
+
+				Expect(ValidateInfrastructureConfig(infrastructureConfig, &nodes, nilPath)).To(ConsistOfFields(Fields{
+					"Type":  Equal(field.ErrorTypeNotSupported),
+					"Field": Equal("intraNodeTraffic.ports[0].protocol"),
+				}))
+			})
+
+			It("should forbid invalid port ranges", func() {
+				infrastructureConfig.IntraNodeTraffic = &stackitv1alpha1.IntraNodeTraffic{
+					Ports: []stackitv1alpha1.IntraNodePort{
+						{Protocol: "tcp"},
+						{Protocol: "tcp", Min: new(int32(0))},
+						{Protocol: "udp", Min: new(int32(100)), Max: new(int32(65536))},
+						{Protocol: "udp", Min: new(int32(200)), Max: new(int32(100))},
+						{Protocol: "icmp", Min: new(int32(8))},
+					},
+				}
+
+				Expect(ValidateInfrastructureConfig(infrastructureConfig, &nodes, nilPath)).To(ConsistOfFields(Fields{
+					"Type":  Equal(field.ErrorTypeRequired),
+					"Field": Equal("intraNodeTraffic.ports[0].min"),
+				}, Fields{
+					"Type":  Equal(field.ErrorTypeInvalid),
+					"Field": Equal("intraNodeTraffic.ports[1].min"),
+				}, Fields{
+					"Type":  Equal(field.ErrorTypeInvalid),
+					"Field": Equal("intraNodeTraffic.ports[2].max"),
+				}, Fields{
+					"Type":   Equal(field.ErrorTypeInvalid),
+					"Field":  Equal("intraNodeTraffic.ports[3].max"),
+					"Detail": Equal("must not be less than min"),
+				}, Fields{
+					"Type":  Equal(field.ErrorTypeForbidden),
+					"Field": Equal("intraNodeTraffic.ports[4].min"),
+				}))
+			})
+		})
+
+		Context("additional security group rules", func() {
+			It("should allow valid rules", func() {
+				infrastructureConfig.AdditionalSecurityGroupRules = []stackitv1alpha1.SecurityGroupRuleConfig{
+					{Direction: "ingress", Protocol: new("udp"), PortRangeMin: new(int32(1194)), RemoteIPPrefix: new("192.168.0.0/24")},
+					{Direction: "ingress", Protocol: new("tcp"), PortRangeMin: new(int32(8000)), PortRangeMax: new(int32(8100)), RemoteSecurityGroupID: new("peer-group")},
+					{Direction: "egress", EtherType: new("IPv6"), Protocol: new("tcp"), RemoteIPPrefix: new("2001:db8::/32")},
+					{Direction: "ingress", Protocol: new("icmp")},
+				}
+
+				Expect(ValidateInfrastructureConfig(infrastructureConfig, &nodes, nilPath)).To(BeEmpty())
+			})
+
+			It("should forbid unknown directions, ether types and protocols", func() {
+				infrastructureConfig.AdditionalSecurityGroupRules = []stackitv1alpha1.SecurityGroupRuleConfig{
+					{Direction: "inbound", EtherType: new("IPv5"), Protocol: new("gre")},
+				}
+
+				Expect(ValidateInfrastructureConfig(infrastructureConfig, &nodes, nilPath)).To(ConsistOfFields(Fields{
+					"Type":  Equal(field.ErrorTypeNotSupported),
+					"Field": Equal("additionalSecurityGroupRules[0].direction"),
+				}, Fields{
+					"Type":  Equal(field.ErrorTypeNotSupported),
+					"Field": Equal("additionalSecurityGroupRules[0].etherType"),
+				}, Fields{
+					"Type":  Equal(field.ErrorTypeNotSupported),
+					"Field": Equal("additionalSecurityGroupRules[0].protocol"),
+				}))
+			})
+
+			It("should forbid invalid port ranges", func() {
+				infrastructureConfig.AdditionalSecurityGroupRules = []stackitv1alpha1.SecurityGroupRuleConfig{
+					{Direction: "ingress", PortRangeMin: new(int32(80))},
+					{Direction: "ingress", Protocol: new("tcp"), PortRangeMin: new(int32(0))},
+					{Direction: "ingress", Protocol: new("udp"), PortRangeMin: new(int32(200)), PortRangeMax: new(int32(100))},
+					{Direction: "ingress", Protocol: new("icmp"), PortRangeMin: new(int32(8))},
+					{Direction: "ingress", Protocol: new("tcp"), PortRangeMax: new(int32(80))},
+				}
+
+				Expect(ValidateInfrastructureConfig(infrastructureConfig, &nodes, nilPath)).To(ConsistOfFields(Fields{
+					"Type":  Equal(field.ErrorTypeRequired),
+					"Field": Equal("additionalSecurityGroupRules[0].protocol"),
+				}, Fields{
+					"Type":  Equal(field.ErrorTypeInvalid),
+					"Field": Equal("additionalSecurityGroupRules[1].portRangeMin"),
+				}, Fields{
+					"Type":   Equal(field.ErrorTypeInvalid),
+					"Field":  Equal("additionalSecurityGroupRules[2].portRangeMax"),
+					"Detail": Equal("must not be less than portRangeMin"),
+				}, Fields{
+					"Type":  Equal(field.ErrorTypeForbidden),
+					"Field": Equal("additionalSecurityGroupRules[3].portRangeMin"),
+				}, Fields{
+					"Type":  Equal(field.ErrorTypeRequired),
+					"Field": Equal("additionalSecurityGroupRules[4].portRangeMin"),
+				}))
+			})
+
+			It("should forbid invalid remotes", func() {
+				infrastructureConfig.AdditionalSecurityGroupRules = []stackitv1alpha1.SecurityGroupRuleConfig{
+					{Direction: "ingress", RemoteIPPrefix: new("10.0.0.0/8"), RemoteSecurityGroupID: new("peer-group")},
+					{Direction: "ingress", RemoteIPPrefix: new("10.0.0.0")},
+					{Direction: "ingress", RemoteIPPrefix: new("2001:db8::/32")},
+					{Direction: "ingress", RemoteSecurityGroupID: new("")},
+				}
+
+				Expect(ValidateInfrastructureConfig(infrastructureConfig, &nodes, nilPath)).To(ConsistOfFields(Fields{
+					"Type":  Equal(field.ErrorTypeForbidden),
+					"Field": Equal("additionalSecurityGroupRules[0].remoteSecurityGroupID"),
+				}, Fields{
+					"Type":  Equal(field.ErrorTypeInvalid),
+					"Field": Equal("additionalSecurityGroupRules[1].remoteIPPrefix"),
+				}, Fields{
+					"Type":   Equal(field.ErrorTypeInvalid),
+					"Field":  Equal("additionalSecurityGroupRules[2].remoteIPPrefix"),
+					"Detail": Equal("must be a CIDR of ether type IPv4"),
+				}, Fields{
+					"Type":  Equal(field.ErrorTypeRequired),
+					"Field": Equal("additionalSecurityGroupRules[3].remoteSecurityGroupID"),
+				}))
+			})
+
+			It("should forbid too long descriptions", func() {
+				infrastructureConfig.AdditionalSecurityGroupRules = []stackitv1alpha1.SecurityGroupRuleConfig{
+					{Direction: "ingress", Description: new(strings.Repeat("a", 106))},
+					{Direction: "ingress", Description: new(strings.Repeat("a", 107))},
+				}
+
+				Expect(ValidateInfrastructureConfig(infrastructureConfig, &nodes, nilPath)).To(ConsistOfFields(Fields{
+					"Type":  Equal(field.ErrorTypeTooLong),
+					"Field": Equal("additionalSecurityGroupRules[1].description"),
+				}))
+			})
+		})
 	})
 
 	Context("CIDR", func() {
@@ -199,6 +531,24 @@ var _ = Describe("InfrastructureConfig validation", func() {
 		})
 	})
 
+	DescribeTable("#ValidateInfrastructureConfigAgainstIPFamilies",
+		func(ipv6 *stackitv1alpha1.IPv6Network, ipFamilies []string, matcher types.GomegaMatcher) {
+			infrastructureConfig.Networks.IPv6 = ipv6
+
+			Expect(ValidateInfrastructureConfigAgainstIPFamilies(infrastructureConfig, ipFamilies, nilPath)).To(matcher)
+		},
+		Entry("IPv4 shoot without IPv6", nil, []string{"IPv4"}, BeEmpty()),
+		Entry("dual-stack shoot with IPv6", &stackitv1alpha1.IPv6Network{PrefixLength: new(int32(64))}, []string{"IPv4", "IPv6"}, BeEmpty()),
+		Entry("IPv4 shoot with IPv6", &stackitv1alpha1.IPv6Network{PrefixLength: new(int32(64))}, []string{"IPv4"}, ConsistOfFields(Fields{
+			"Type":  Equal(field.ErrorTypeForbidden),
+			"Field": Equal("networks.ipv6"),
+		})),
+		Entry("shoot without IP families with IPv6", &stackitv1alpha1.IPv6Network{PrefixLength: new(int32(64))}, nil, ConsistOfFields(Fields{
+			"Type":  Equal(field.ErrorTypeForbidden),
+			"Field": Equal("networks.ipv6"),
+		})),
+	)
+
 	Describe("#ValidateInfrastructureConfigUpdate", func() {
 		It("should return no errors for an unchanged config", func() {
 			Expect(ValidateInfrastructureConfigUpdate(infrastructureConfig, infrastructureConfig, nilPath)).To(BeEmpty())
@@ -216,6 +566,36 @@ var _ = Describe("InfrastructureConfig validation", func() {
 			}))))
 		})
 
+		It("should forbid adding, changing and removing the IPv6 network", func() {
+			newInfrastructureConfig := infrastructureConfig.DeepCopy()
+			newInfrastructureConfig.Networks.IPv6 = &stackitv1alpha1.IPv6Network{PrefixLength: new(int32(64))}
+
+			errorList := ValidateInfrastructureConfigUpdate(infrastructureConfig, newInfrastructureConfig, nilPath)
+			Expect(errorList).To(ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
+				"Type":  Equal(field.ErrorTypeInvalid),
+				"Field": Equal("networks.ipv6"),
+			}))))
+
+			changedInfrastructureConfig := newInfrastructureConfig.DeepCopy()
+			changedInfrastructureConfig.Networks.IPv6.PrefixLength = new(int32(56))
+			Expect(ValidateInfrastructureConfigUpdate(newInfrastructureConfig, changedInfrastructureConfig, nilPath)).To(ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
+				"Field": Equal("networks.ipv6"),
+			}))))
+
+			Expect(ValidateInfrastructureConfigUpdate(newInfrastructureConfig, infrastructureConfig, nilPath)).To(ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
+				"Field": Equal("networks.ipv6"),
+			}))))
+		})
+
+		It("should allow enabling and disabling DHCP", func() {
+			infrastructureConfig.Networks.DNSServers = &[]string{"1.1.1.1"}
+			newInfrastructureConfig := infrastructureConfig.DeepCopy()
+			newInfrastructureConfig.Networks.DHCP = new(false)
+
+			Expect(ValidateInfrastructureConfigUpdate(infrastructureConfig, newInfrastructureConfig, nilPath)).To(BeEmpty())
+			Expect(ValidateInfrastructureConfigUpdate(newInfrastructureConfig, infrastructureConfig, nilPath)).To(BeEmpty())
+		})
+
 		It("should forbid changing the floating pool", func() {
 			newInfrastructureConfig := infrastructureConfig.DeepCopy()
 			newInfrastructureConfig.FloatingPoolName = "test"
@@ -239,6 +619,89 @@ var _ = Describe("InfrastructureConfig validation", func() {
 				"Field": Equal("floatingPoolSubnetName"),
 			}))))
 		})
+
+		It("should allow adding the floating pool id", func() {
+			newInfrastructureConfig := infrastructureConfig.DeepCopy()
+			newInfrastructureConfig.FloatingPoolID = new("test")
+
+			Expect(ValidateInfrastructureConfigUpdate(infrastructureConfig, newInfrastructureConfig, nilPath)).To(BeEmpty())
+		})
+
+		It("should forbid changing and removing the floating pool id", func() {
+			infrastructureConfig.FloatingPoolID = new("test")
+			changedInfrastructureConfig := infrastructureConfig.DeepCopy()
+			changedInfrastructureConfig.FloatingPoolID = new("other")
+			removedInfrastructureConfig := infrastructureConfig.DeepCopy()
+			removedInfrastructureConfig.FloatingPoolID = nil
+
+			for _, newInfrastructureConfig := range []*stackitv1alpha1.InfrastructureConfig{changedInfrastructureConfig, removedInfrastructureConfig} {
+				Expect(ValidateInfrastructureConfigUpdate(infrastructureConfig, newInfrastructureConfig, nilPath)).To(ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeInvalid),
+					"Field": Equal("floatingPoolId"),
+				}))))
+			}
+		})
+
+		It("should forbid adding, changing and removing the IaaS endpoint", func() {
+			newInfrastructureConfig := infrastructureConfig.DeepCopy()
+			newInfrastructureConfig.IaaSEndpoint = new("https://iaas.staging.example.com")
+			changedInfrastructureConfig := infrastructureConfig.DeepCopy()
+			changedInfrastructureConfig.IaaSEndpoint = new("https://iaas.qa.example.com")
+
+			for _, configs := range [][2]*stackitv1alpha1.InfrastructureConfig{
+				{infrastructureConfig, newInfrastructureConfig},
+				{newInfrastructureConfig, changedInfrastructureConfig},
+				{newInfrastructureConfig, infrastructureConfig},
+			} {
+				Expect(ValidateInfrastructureConfigUpdate(configs[0], configs[1], nilPath)).To(ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeInvalid),
+					"Field": Equal("iaasEndpoint"),
+				}))))
+			}
+		})
+	})
+
+	Describe("#ValidateInfrastructureConfigDNSServerLimits", func() {
+		It("should allow duplicate DNS servers within the limit", func() {
+			infrastructureConfig.Networks.DNSServers = &[]string{"1.1.1.1", "8.8.8.8", "9.9.9.9", "1.1.1.1"}
+
+			Expect(ValidateInfrastructureConfigDNSServerLimits(nil, infrastructureConfig, nilPath)).To(BeEmpty())
+		})
+
+		It("should forbid more DNS servers than supported by STACKIT", func() {
+			infrastructureConfig.Networks.DNSServers = &[]string{"1.1.1.1", "8.8.8.8", "9.9.9.9", "1.0.0.1"}
+			infrastructureConfig.Networks.IPv6 = &stackitv1alpha1.IPv6Network{
+				PrefixLength: new(int32(64)),
+				DNSServers:   []string{"2001:db8::1", "2001:db8::2", "2001:db8::3", "2001:db8::4"},
+			}
+
+			Expect(ValidateInfrastructureConfigDNSServerLimits(nil, infrastructureConfig, nilPath)).To(ConsistOfFields(Fields{
+				"Type":  Equal(field.ErrorTypeTooMany),
+				"Field": Equal("networks.dnsServers"),
+			}, Fields{
+				"Type":  Equal(field.ErrorTypeTooMany),
+				"Field": Equal("networks.ipv6.dnsServers"),
+			}))
+		})
+
+		It("should allow unchanged DNS servers exceeding the limit", func() {
+			infrastructureConfig.Networks.DNSServers = &[]string{"1.1.1.1", "8.8.8.8", "9.9.9.9", "1.0.0.1"}
+			newInfrastructureConfig := infrastructureConfig.DeepCopy()
+			newInfrastructureConfig.Networks.DNSServers = &[]string{"1.0.0.1", "9.9.9.9", "8.8.8.8", "1.1.1.1"}
+
+			Expect(ValidateInfrastructureConfigDNSServerLimits(infrastructureConfig, newInfrastructureConfig, nilPath)).To(BeEmpty())
+		})
+
+		It("should forbid changed DNS servers exceeding the limit", func() {
+			infrastructureConfig.Networks.DNSServers = &[]string{"1.1.1.1", "8.8.8.8"}
+			newInfrastructureConfig := infrastructureConfig.DeepCopy()
+			newInfrastructureConfig.Networks.DNSServers = &[]string{"1.1.1.1", "8.8.8.8", "9.9.9.9", "1.0.0.1"}
+
+			Expect(ValidateInfrastructureConfigDNSServerLimits(infrastructureConfig, newInfrastructureConfig, nilPath)).To(ConsistOfFields(Fields{
+				"Type":  Equal(field.ErrorTypeTooMany),
+				"Field": Equal("networks.dnsServers"),
+			}))
+		})
 	})
 
 	Describe("#ValidateInfrastructureConfigAgainstCloudProfile", func() {
@@ -277,4 +740,33 @@ var _ = Describe("InfrastructureConfig validation", func() {
 		})
 
 	})
+
+	Describe("#ValidateIaaSEndpointAgainstCloudProfile", func() {
+		fldPath := field.NewPath("iaasEndpoint")
+
+		It("should allow shoots without IaaS endpoint", func() {
+			Expect(ValidateIaaSEndpointAgainstCloudProfile(infrastructureConfig, nil, fldPath)).To(BeEmpty())
+		})
+
+		It("should allow endpoints of the allow-list", func() {
+			infrastructureConfig.IaaSEndpoint = new("https://iaas.staging.example.com")
+			cloudProfileConfig := &stackitv1alpha1.CloudProfileConfig{AllowedIaaSEndpoints: []string{"https://iaas.staging.example.com"}}
+
+			Expect(ValidateIaaSEndpointAgainstCloudProfile(infrastructureConfig, cloudProfileConfig, fldPath)).To(BeEmpty())
+		})
+
+		DescribeTable("should forbid endpoints which are not allowed",
+			func(cloudProfileConfig *stackitv1alpha1.CloudProfileConfig) {
+				infrastructureConfig.IaaSEndpoint = new("https://attacker.example.com")
+
+				Expect(ValidateIaaSEndpointAgainstCloudProfile(infrastructureConfig, cloudProfileConfig, fldPath)).To(ConsistOfFields(Fields{
+					"Type":  Equal(field.ErrorTypeNotSupported),
+					"Field": Equal("iaasEndpoint"),
+				}))
+			},
+			Entry("without cloud profile config", nil),
+			Entry("without allow-list", &stackitv1alpha1.CloudProfileConfig{}),
+			Entry("not in allow-list", &stackitv1alpha1.CloudProfileConfig{AllowedIaaSEndpoints: []string{"https://iaas.staging.example.com"}}),
+		)
+	})
 })
@@ -0,0 +1,99 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package validation
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/gardener/gardener/pkg/apis/core"
+	v1beta1constants "github.com/gardener/gardener/pkg/apis/core/v1beta1/constants"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+
+	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/apis/stackit/helper"
+	stackitv1alpha1 "github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/apis/stackit/v1alpha1"
+)
+
+// ValidateWorkersAgainstCloudProfile validates that the machine images referenced by the given workers have a mapping
+// for the given region in the CloudProfileConfig. Workers whose machine image did not change compared to the old
+// workers are skipped, so that existing shoots are not blocked by images removed from the CloudProfile.
+func ValidateWorkersAgainstCloudProfile(oldWorkers, workers []core.Worker, region string, cloudProfileConfig *stackitv1alpha1.CloudProfileConfig, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	oldWorkersByName := make(map[string]core.Worker, len(oldWorkers))
+	for _, worker := range oldWorkers {
+		oldWorkersByName[worker.Name] = worker
+	}
+
+	for i, worker := range workers {
+		if worker.Machine.Image == nil || worker.Machine.Image.Version == nil {
+			continue
+		}
+
+		architecture := ptr.Deref(worker.Machine.Architecture, v1beta1constants.ArchitectureAMD64)
+		if oldWorker, ok := oldWorkersByName[worker.Name]; ok &&
+			equality.Semantic.DeepEqual(oldWorker.Machine.Image, worker.Machine.Image) &&
+			ptr.Deref(oldWorker.Machine.Architecture, v1beta1constants.ArchitectureAMD64) == architecture {
+			continue
+		}
+
+		image := worker.Machine.Image
+		if _, err := helper.FindImageFromCloudProfile(cloudProfileConfig, image.Name, *image.Version, region, architecture); err != nil {
+			allErrs = append(allErrs, field.Invalid(
+				fldPath.Index(i).Child("machine", "image"),
+				fmt.Sprintf("%s/%s", image.Name, *image.Version),
+				fmt.Sprintf("no machine image mapping found in CloudProfileConfig for name %q, version %q, architecture %q and region %q", image.Name, *image.Version, architecture, region),
+			))
+		}
+	}
+
+	return allErrs
+}
+
+// ValidateWorkerConfig validates the given WorkerConfig of a worker pool with the given zones. The policy of the server
+// group must be one of the given server group policies of the CloudProfileConfig, so server groups are forbidden if the
+// CloudProfileConfig defines none. The port security can only be disabled
+// if the machines are managed by the STACKIT machine controller manager.
+func ValidateWorkerConfig(workerConfig *stackitv1alpha1.WorkerConfig, zones, serverGroupPolicies []string, useStackitMachineControllerManager bool, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if len(workerConfig.ZoneWeights) > 0 {
+		zoneWeightsPath := fldPath.Child("zoneWeights")
+		for _, zone := range slices.Sorted(maps.Keys(workerConfig.ZoneWeights)) {
+			if !slices.Contains(zones, zone) {
+				allErrs = append(allErrs, field.NotSupported(zoneWeightsPath.Key(zone), zone, zones))
+				continue
+			}
+			if weight := workerConfig.ZoneWeights[zone]; weight <= 0 {
+				allErrs = append(allErrs, field.Invalid(zoneWeightsPath.Key(zone), weight, "zone weight must be positive"))
+			}
+		}
+		for _, zone := range zones {
+			if _, ok := workerConfig.ZoneWeights[zone]; !ok {
+				allErrs = append(allErrs, field.Required(zoneWeightsPath.Key(zone), "every zone of the worker pool needs a zone weight"))
+			}
+		}
+	}
+
+	if workerConfig.ServerGroup != nil {
+		policyPath := fldPath.Child("serverGroup", "policy")
+		if len(workerConfig.ServerGroup.Policy) == 0 {
+			allErrs = append(allErrs, field.Required(policyPath, "policy cannot be empty"))
+		} else if len(serverGroupPolicies) == 0 {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("serverGroup"), "server groups are not supported as the CloudProfileConfig does not define any serverGroupPolicies"))
+		} else if !slices.Contains(serverGroupPolicies, workerConfig.ServerGroup.Policy) {
+			allErrs = append(allErrs, field.NotSupported(policyPath, workerConfig.ServerGroup.Policy, serverGroupPolicies))
+		}
+	}
+
+	if workerConfig.DisablePortSecurity && !useStackitMachineControllerManager {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("disablePortSecurity"), "disabling the port security is only supported with the STACKIT machine controller manager"))
+	}
+
+	return allErrs
+}
@@ -0,0 +1,221 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package validation_test
+
+import (
+	"github.com/gardener/gardener/pkg/apis/core"
+	. "github.com/gardener/gardener/pkg/utils/test/matchers"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	stackitv1alpha1 "github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/apis/stackit/v1alpha1"
+	. "github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/apis/stackit/validation"
+)
+
+var _ = Describe("Worker validation", func() {
+	var (
+		fldPath = field.NewPath("workers")
+		region  = "eu01"
+
+		cloudProfileConfig *stackitv1alpha1.CloudProfileConfig
+		workers            []core.Worker
+	)
+
+	BeforeEach(func() {
+		cloudProfileConfig = &stackitv1alpha1.CloudProfileConfig{
+			MachineImages: []stackitv1alpha1.MachineImages{
+				{
+					Name: "flatcar",
+					Versions: []stackitv1alpha1.MachineImageVersion{
+						{
+							Version: "1.0.0",
+							Regions: []stackitv1alpha1.RegionIDMapping{
+								{Name: region, ID: "image-amd64"},
+								{Name: region, ID: "image-arm64", Architecture: new("arm64")},
+							},
+						},
+					},
+				},
+			},
+		}
+
+		workers = []core.Worker{
+			{
+				Name: "worker-1",
+				Machine: core.Machine{
+					Image: &core.ShootMachineImage{Name: "flatcar", Version: new("1.0.0")},
+				},
+			},
+		}
+	})
+
+	Describe("#ValidateWorkersAgainstCloudProfile", func() {
+		It("should allow images with a mapping in the region", func() {
+			workers[0].Machine.Architecture = new("arm64")
+
+			Expect(ValidateWorkersAgainstCloudProfile(nil, workers, region, cloudProfileConfig, fldPath)).To(BeEmpty())
+		})
+
+		It("should skip workers without image version", func() {
+			workers[0].Machine.Image.Version = nil
+
+			Expect(ValidateWorkersAgainstCloudProfile(nil, workers, region, cloudProfileConfig, fldPath)).To(BeEmpty())
+		})
+
+		It("should forbid unknown image versions", func() {
+			workers[0].Machine.Image.Version = new("2.0.0")
+
+			Expect(ValidateWorkersAgainstCloudProfile(nil, workers, region, cloudProfileConfig, fldPath)).To(ConsistOfFields(Fields{
+				"Type":   Equal(field.ErrorTypeInvalid),
+				"Field":  Equal("workers[0].machine.image"),
+				"Detail": ContainSubstring(`version "2.0.0"`),
+			}))
+		})
+
+		It("should forbid images without mapping for the region", func() {
+			Expect(ValidateWorkersAgainstCloudProfile(nil, workers, "eu02", cloudProfileConfig, fldPath)).To(ConsistOfFields(Fields{
+				"Type":   Equal(field.ErrorTypeInvalid),
+				"Field":  Equal("workers[0].machine.image"),
+				"Detail": ContainSubstring(`region "eu02"`),
+			}))
+		})
+
+		It("should forbid images without mapping for the architecture", func() {
+			cloudProfileConfig.MachineImages[0].Versions[0].Regions = cloudProfileConfig.MachineImages[0].Versions[0].Regions[:1]
+			workers[0].Machine.Architecture = new("arm64")
+
+			Expect(ValidateWorkersAgainstCloudProfile(nil, workers, region, cloudProfileConfig, fldPath)).To(ConsistOfFields(Fields{
+				"Type":   Equal(field.ErrorTypeInvalid),
+				"Field":  Equal("workers[0].machine.image"),
+				"Detail": ContainSubstring(`architecture "arm64"`),
+			}))
+		})
+
+		It("should allow unchanged images which are no longer in the cloud profile", func() {
+			oldWorkers := []core.Worker{*workers[0].DeepCopy()}
+			cloudProfileConfig.MachineImages = nil
+
+			Expect(ValidateWorkersAgainstCloudProfile(oldWorkers, workers, region, cloudProfileConfig, fldPath)).To(BeEmpty())
+		})
+
+		It("should forbid changed images without mapping on update", func() {
+			oldWorkers := []core.Worker{*workers[0].DeepCopy()}
+			workers[0].Machine.Image.Version = new("2.0.0")
+
+			Expect(ValidateWorkersAgainstCloudProfile(oldWorkers, workers, region, cloudProfileConfig, fldPath)).To(ConsistOfFields(Fields{
+				"Type":  Equal(field.ErrorTypeInvalid),
+				"Field": Equal("workers[0].machine.image"),
+			}))
+		})
+	})
+
+	Describe("#ValidateWorkerConfig", func() {
+		var (
+			workerConfig *stackitv1alpha1.WorkerConfig
+			zones        = []string{"eu01-1", "eu01-2"}
+		)
+
+		BeforeEach(func() {
+			workerConfig = &stackitv1alpha1.WorkerConfig{}
+		})
+
+		It("should allow a config without zone weights", func() {
+			Expect(ValidateWorkerConfig(workerConfig, zones, nil, true, fldPath)).To(BeEmpty())
+		})
+
+		It("should allow positive weights for all zones", func() {
+			workerConfig.ZoneWeights = map[string]int32{"eu01-1": 3, "eu01-2": 1}
+
+			Expect(ValidateWorkerConfig(workerConfig, zones, nil, true, fldPath)).To(BeEmpty())
+		})
+
+		It("should forbid weights which are not positive", func() {
+			workerConfig.ZoneWeights = map[string]int32{"eu01-1": 0, "eu01-2": -1}
+
+			Expect(ValidateWorkerConfig(workerConfig, zones, nil, true, fldPath)).To(ConsistOf(
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeInvalid),
+					"Field": Equal("workers.zoneWeights[eu01-1]"),
+				})),
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeInvalid),
+					"Field": Equal("workers.zoneWeights[eu01-2]"),
+				})),
+			))
+		})
+
+		It("should forbid weights of unknown zones and missing weights", func() {
+			workerConfig.ZoneWeights = map[string]int32{"eu01-1": 1, "eu01-3": 1}
+
+			Expect(ValidateWorkerConfig(workerConfig, zones, nil, true, fldPath)).To(ConsistOf(
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeNotSupported),
+					"Field": Equal("workers.zoneWeights[eu01-3]"),
+				})),
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeRequired),
+					"Field": Equal("workers.zoneWeights[eu01-2]"),
+				})),
+			))
+		})
+
+		It("should allow server group policies of the cloud profile", func() {
+			workerConfig.ServerGroup = &stackitv1alpha1.ServerGroupConfig{Policy: "soft-anti-affinity"}
+
+			Expect(ValidateWorkerConfig(workerConfig, zones, []string{"soft-anti-affinity"}, true, fldPath)).To(BeEmpty())
+		})
+
+		It("should forbid empty and unknown server group policies", func() {
+			workerConfig.ServerGroup = &stackitv1alpha1.ServerGroupConfig{}
+
+			Expect(ValidateWorkerConfig(workerConfig, zones, []string{"soft-anti-affinity"}, true, fldPath)).To(ConsistOf(
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeRequired),
+					"Field": Equal("workers.serverGroup.policy"),
+				})),
+			))
+
+			workerConfig.ServerGroup.Policy = "hard-anti-affinity"
+
+			Expect(ValidateWorkerConfig(workerConfig, zones, []string{"soft-anti-affinity"}, true, fldPath)).To(ConsistOf(
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":   Equal(field.ErrorTypeNotSupported),
+					"Field":  Equal("workers.serverGroup.policy"),
+					"Detail": ContainSubstring(`"soft-anti-affinity"`),
+				})),
+			))
+		})
+
+		It("should forbid server groups if the cloud profile has no server group policies", func() {
+			workerConfig.ServerGroup = &stackitv1alpha1.ServerGroupConfig{Policy: "soft-anti-affinity"}
+
+			Expect(ValidateWorkerConfig(workerConfig, zones, nil, true, fldPath)).To(ConsistOf(
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeForbidden),
+					"Field": Equal("workers.serverGroup"),
+				})),
+			))
+		})
+
+		It("should allow disabling the port security with the STACKIT machine controller manager", func() {
+			workerConfig.DisablePortSecurity = true
+
+			Expect(ValidateWorkerConfig(workerConfig, zones, nil, true, fldPath)).To(BeEmpty())
+		})
+
+		It("should forbid disabling the port security with the OpenStack machine controller manager", func() {
+			workerConfig.DisablePortSecurity = true
+
+			Expect(ValidateWorkerConfig(workerConfig, zones, nil, false, fldPath)).To(ConsistOf(
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeForbidden),
+					"Field": Equal("workers.disablePortSecurity"),
+				})),
+			))
+		})
+	})
+})
@@ -82,6 +82,26 @@ func (c *Config) ApplyCustomLabelDomain(customLabelDomain *string) {
 	*customLabelDomain = c.Config.CustomLabelDomain
 }
 
+// ApplyClusterLabelValueSource sets the source of the cluster label value of STACKIT resources.
+func (c *Config) ApplyClusterLabelValueSource(source *config.ClusterLabelValueSource) {
+	*source = c.Config.ControlPlane.ClusterLabelValueSource
+}
+
+// ApplyControlPlane sets the control plane controller configuration.
+func (c *Config) ApplyControlPlane(controlPlane *config.ControlPlaneControllerConfiguration) {
+	*controlPlane = c.Config.ControlPlane
+}
+
+// ApplyInfrastructure sets the infrastructure controller configuration.
+func (c *Config) ApplyInfrastructure(infrastructure *config.InfrastructureControllerConfiguration) {
+	*infrastructure = c.Config.Infrastructure
+}
+
+// ApplyWorker sets the worker controller configuration.
+func (c *Config) ApplyWorker(worker *config.WorkerControllerConfiguration) {
+	*worker = c.Config.Worker
+}
+
 // Options initializes empty config.ControllerConfiguration, applies the set values and returns it.
 func (c *Config) Options() config.ControllerConfiguration {
 	var cfg config.ControllerConfiguration
@@ -51,18 +51,22 @@ const (
 )
 
 func (a *Actuator) DetermineOptions(ctx context.Context, bastion *extensionsv1alpha1.Bastion, cluster *extensionscontroller.Cluster, projectID string) (*Options, error) {
+	customLabelDomain, err := helper.CustomLabelDomainFromCluster(cluster, a.CustomLabelDomain)
+	if err != nil {
+		return nil, err
+	}
+
 	opts := &Options{
 		Bastion:      bastion,
 		ProjectID:    projectID,
 		ResourceName: stackitclient.BuildResourceName(cluster.Shoot.Status.TechnicalID, "-bastion-", bastion.Name),
 		Labels: map[string]string{
-			utils.ClusterLabelKey(a.CustomLabelDomain):          cluster.Shoot.Status.TechnicalID,
-			utils.BuildLabelKey(a.CustomLabelDomain, "bastion"): bastion.Name,
+			utils.ClusterLabelKey(customLabelDomain):          cluster.Shoot.Status.TechnicalID,
+			utils.BuildLabelKey(customLabelDomain, "bastion"): bastion.Name,
 		},
 		Region: stackit.DetermineRegion(cluster),
 	}
 
-	var err error
 	opts.AvailabilityZone, err = determineAvailabilityZone(cluster)
 	if err != nil {
 		return nil, fmt.Errorf("error determining availability zone: %w", err)
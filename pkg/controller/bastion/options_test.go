@@ -316,4 +316,17 @@ var _ = Describe("Options", func() {
 			"example.com/bastion",
 		),
 	)
+
+	It("should use the customLabelDomain of the ControlPlaneConfig", func() {
+		cpConfigBytes, err := runtime.Encode(encoder, &stackitv1alpha1.ControlPlaneConfig{CustomLabelDomain: new("customer.example.com")})
+		Expect(err).NotTo(HaveOccurred())
+		shoot.Spec.Provider.ControlPlaneConfig = &runtime.RawExtension{Raw: cpConfigBytes}
+
+		options, err := a.DetermineOptions(ctx, bastion, cluster, projectID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(options.Labels).To(Equal(map[string]string{
+			"customer.example.com/cluster": "shoot--garden--hops",
+			"customer.example.com/bastion": "foo",
+		}))
+	})
 })
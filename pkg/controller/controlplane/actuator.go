@@ -0,0 +1,66 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controlplane
+
+import (
+	"context"
+	"fmt"
+
+	extensionscontroller "github.com/gardener/gardener/extensions/pkg/controller"
+	"github.com/gardener/gardener/extensions/pkg/controller/controlplane"
+	v1beta1constants "github.com/gardener/gardener/pkg/apis/core/v1beta1/constants"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"github.com/gardener/gardener/pkg/utils"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/clock"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/apis/config"
+)
+
+// actuator wraps the generic control plane actuator and records the state of the ControlPlane which is not part of the
+// charts after a successful reconciliation. All other operations are handed to the wrapped actuator.
+type actuator struct {
+	controlplane.Actuator
+
+	client        k8sclient.Client
+	clock         clock.PassiveClock
+	configuration config.ControlPlaneControllerConfiguration
+}
+
+// Reconcile reconciles the given ControlPlane with the wrapped actuator and records the rotation of the cloudprovider
+// secret as well as the state of the load balancer emergency access secret afterwards.
+func (a *actuator) Reconcile(ctx context.Context, log logr.Logger, cp *extensionsv1alpha1.ControlPlane, cluster *extensionscontroller.Cluster) (bool, error) {
+	requeue, err := a.Actuator.Reconcile(ctx, log, cp, cluster)
+	if err != nil {
+		return requeue, err
+	}
+
+	checksum, err := a.cloudProviderSecretChecksum(ctx, cp.Namespace)
+	if err != nil {
+		return requeue, err
+	}
+	if err := a.recordCredentialsRotation(ctx, cp, checksum); err != nil {
+		return requeue, fmt.Errorf("recording credentials rotation: %w", err)
+	}
+	if err := a.updateEmergencyAccessCondition(ctx, cp); err != nil {
+		return requeue, fmt.Errorf("updating emergency access condition: %w", err)
+	}
+	return requeue, nil
+}
+
+// cloudProviderSecretChecksum returns the checksum of the cloudprovider secret in the given namespace as computed by the
+// generic actuator, or an empty string if the secret does not exist.
+func (a *actuator) cloudProviderSecretChecksum(ctx context.Context, namespace string) (string, error) {
+	secret := &corev1.Secret{}
+	if err := a.client.Get(ctx, k8sclient.ObjectKey{Namespace: namespace, Name: v1beta1constants.SecretNameCloudProvider}, secret); err != nil {
+		if k8sclient.IgnoreNotFound(err) == nil {
+			return "", nil
+		}
+		return "", fmt.Errorf("could not get secret '%s/%s': %w", namespace, v1beta1constants.SecretNameCloudProvider, err)
+	}
+	return utils.ComputeChecksum(secret.Data), nil
+}
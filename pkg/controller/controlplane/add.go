@@ -12,10 +12,11 @@ import (
 	"github.com/gardener/gardener/extensions/pkg/controller/controlplane/genericactuator"
 	"github.com/gardener/gardener/extensions/pkg/util"
 	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"k8s.io/utils/clock"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 
-	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/imagevector"
+	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/apis/config"
 	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/stackit"
 )
 
@@ -36,27 +37,35 @@ type AddOptions struct {
 	ExtensionClasses []extensionsv1alpha1.ExtensionClass
 	// CustomLabelDomain is the domain prefix for custom labels applied to STACKIT infrastructure resources.
 	CustomLabelDomain string
+	// Configuration is the control plane controller configuration.
+	Configuration config.ControlPlaneControllerConfiguration
 }
 
 // AddToManagerWithOptions adds a controller with the given Options to the given manager.
 // The opts.Reconciler is being set with a newly instantiated actuator.
 func AddToManagerWithOptions(ctx context.Context, mgr manager.Manager, opts AddOptions) error {
-	csiCompatibilityHandler, err := NewCompatCSICompatibilityHandler(mgr.GetClient(), mgr.GetConfig())
+	imageVector := controlPlaneImageVector(opts.Configuration)
+	csiCompatibilityHandler, err := NewCompatCSICompatibilityHandler(mgr.GetClient(), mgr.GetConfig(), imageVector)
 	if err != nil {
 		return err
 	}
 	genericActuator, err := genericactuator.NewActuator(mgr, stackit.Name,
 		secretConfigsFunc, shootAccessSecretsFunc,
 		configChart, controlPlaneChart, controlPlaneShootChart, controlPlaneShootCRDsChart, storageClassChart,
-		NewValuesProvider(mgr, opts.CustomLabelDomain, csiCompatibilityHandler),
+		NewValuesProvider(mgr, opts.CustomLabelDomain, opts.Configuration, csiCompatibilityHandler),
 		extensionscontroller.ChartRendererFactoryFunc(util.NewChartRendererForShoot),
-		imagevector.ImageVector(), "", nil, opts.WebhookServerNamespace)
+		imageVector, "", nil, opts.WebhookServerNamespace)
 	if err != nil {
 		return err
 	}
 
 	return controlplane.Add(mgr, controlplane.AddArgs{
-		Actuator:          genericActuator,
+		Actuator: &actuator{
+			Actuator:      genericActuator,
+			client:        mgr.GetClient(),
+			clock:         clock.RealClock{},
+			configuration: opts.Configuration,
+		},
 		ControllerOptions: opts.Controller,
 		Predicates:        controlplane.DefaultPredicates(ctx, mgr, opts.IgnoreOperationAnnotation),
 		Type:              stackit.Type,
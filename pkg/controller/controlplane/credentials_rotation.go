@@ -0,0 +1,60 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controlplane
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/stackit"
+)
+
+// credentialsRotation is an entry of the credentials rotation history recorded on the ControlPlane.
+type credentialsRotation struct {
+	// Checksum is the checksum of the cloudprovider secret.
+	Checksum string `json:"checksum"`
+	// ObservedAt is the time the checksum was first observed.
+	ObservedAt metav1.Time `json:"observedAt"`
+}
+
+// recordCredentialsRotation records the given checksum of the cloudprovider secret in the credentials rotation history
+// annotation of the ControlPlane if it differs from the last recorded one. Only the configured number of entries is kept.
+func (a *actuator) recordCredentialsRotation(ctx context.Context, cp *extensionsv1alpha1.ControlPlane, checksum string) error {
+	limit := int(ptr.Deref(a.configuration.CredentialsRotationHistoryLimit, 0))
+	if limit <= 0 || checksum == "" {
+		return nil
+	}
+
+	// an unparsable annotation is replaced by a new history
+	var history []credentialsRotation
+	if value, ok := cp.Annotations[stackit.AnnotationCredentialsRotationHistory]; ok {
+		if err := json.Unmarshal([]byte(value), &history); err != nil {
+			history = nil
+		}
+	}
+	if len(history) == 0 || history[len(history)-1].Checksum != checksum {
+		history = append(history, credentialsRotation{Checksum: checksum, ObservedAt: metav1.NewTime(a.clock.Now().UTC())})
+	} else if len(history) <= limit {
+		return nil
+	}
+	if len(history) > limit {
+		history = history[len(history)-limit:]
+	}
+
+	data, err := json.Marshal(history)
+	if err != nil {
+		return fmt.Errorf("could not marshal credentials rotation history: %w", err)
+	}
+
+	patch := k8sclient.MergeFrom(cp.DeepCopy())
+	metav1.SetMetaDataAnnotation(&cp.ObjectMeta, stackit.AnnotationCredentialsRotationHistory, string(data))
+	return a.client.Patch(ctx, cp, patch)
+}
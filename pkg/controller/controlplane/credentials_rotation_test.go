@@ -0,0 +1,154 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controlplane
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	extensionscontroller "github.com/gardener/gardener/extensions/pkg/controller"
+	mockcontrolplane "github.com/gardener/gardener/extensions/pkg/controller/controlplane/mock"
+	v1beta1constants "github.com/gardener/gardener/pkg/apis/core/v1beta1/constants"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"github.com/gardener/gardener/pkg/utils"
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	testclock "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/apis/config"
+	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/stackit"
+)
+
+var _ = Describe("#recordCredentialsRotation", func() {
+	var (
+		ctx       context.Context
+		c         client.Client
+		a         *actuator
+		fakeClock *testclock.FakePassiveClock
+		cp        *extensionsv1alpha1.ControlPlane
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme := newTestScheme()
+		cp = &extensionsv1alpha1.ControlPlane{
+			ObjectMeta: metav1.ObjectMeta{Name: "control-plane", Namespace: namespace},
+		}
+		c = fake.NewClientBuilder().WithScheme(scheme).WithObjects(cp).Build()
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(cp), cp)).To(Succeed())
+
+		fakeClock = testclock.NewFakePassiveClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+		a = &actuator{
+			client:        c,
+			clock:         fakeClock,
+			configuration: config.ControlPlaneControllerConfiguration{CredentialsRotationHistoryLimit: new(int32(2))},
+		}
+	})
+
+	history := func() []credentialsRotation {
+		GinkgoHelper()
+		actual := &extensionsv1alpha1.ControlPlane{}
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(cp), actual)).To(Succeed())
+		var result []credentialsRotation
+		Expect(json.Unmarshal([]byte(actual.Annotations[stackit.AnnotationCredentialsRotationHistory]), &result)).To(Succeed())
+		return result
+	}
+
+	It("should record the first observed checksum", func() {
+		Expect(a.recordCredentialsRotation(ctx, cp, "checksum-1")).To(Succeed())
+
+		Expect(history()).To(ConsistOf(And(
+			HaveField("Checksum", "checksum-1"),
+			HaveField("ObservedAt.Time", BeTemporally("==", fakeClock.Now())),
+		)))
+	})
+
+	It("should not change the history if the checksum did not change", func() {
+		Expect(a.recordCredentialsRotation(ctx, cp, "checksum-1")).To(Succeed())
+		fakeClock.SetTime(fakeClock.Now().Add(time.Hour))
+		Expect(a.recordCredentialsRotation(ctx, cp, "checksum-1")).To(Succeed())
+
+		Expect(history()).To(HaveLen(1))
+		Expect(history()[0].ObservedAt.Time).To(BeTemporally("==", fakeClock.Now().Add(-time.Hour)))
+	})
+
+	It("should only keep the configured number of entries", func() {
+		for _, checksum := range []string{"checksum-1", "checksum-2", "checksum-3"} {
+			Expect(a.recordCredentialsRotation(ctx, cp, checksum)).To(Succeed())
+			fakeClock.SetTime(fakeClock.Now().Add(time.Hour))
+		}
+
+		Expect(history()).To(HaveExactElements(
+			HaveField("Checksum", "checksum-2"),
+			HaveField("Checksum", "checksum-3"),
+		))
+	})
+
+	It("should replace an invalid annotation", func() {
+		metav1.SetMetaDataAnnotation(&cp.ObjectMeta, stackit.AnnotationCredentialsRotationHistory, "invalid")
+		Expect(c.Update(ctx, cp)).To(Succeed())
+
+		Expect(a.recordCredentialsRotation(ctx, cp, "checksum-1")).To(Succeed())
+
+		Expect(history()).To(ConsistOf(HaveField("Checksum", "checksum-1")))
+	})
+
+	It("should not record anything if disabled", func() {
+		a.configuration.CredentialsRotationHistoryLimit = new(int32(0))
+
+		Expect(a.recordCredentialsRotation(ctx, cp, "checksum-1")).To(Succeed())
+
+		actual := &extensionsv1alpha1.ControlPlane{}
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(cp), actual)).To(Succeed())
+		Expect(actual.Annotations).NotTo(HaveKey(stackit.AnnotationCredentialsRotationHistory))
+	})
+
+	Describe("#Reconcile", func() {
+		var (
+			ctrl            *gomock.Controller
+			genericActuator *mockcontrolplane.MockActuator
+			cluster         *extensionscontroller.Cluster
+		)
+
+		BeforeEach(func() {
+			ctrl = gomock.NewController(GinkgoT())
+			genericActuator = mockcontrolplane.NewMockActuator(ctrl)
+			a.Actuator = genericActuator
+			cluster = &extensionscontroller.Cluster{}
+		})
+
+		It("should record the checksum of the cloudprovider secret after a successful reconciliation", func() {
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: v1beta1constants.SecretNameCloudProvider, Namespace: namespace},
+				Data:       map[string][]byte{"serviceaccount.json": []byte("{}")},
+			}
+			Expect(c.Create(ctx, secret)).To(Succeed())
+			genericActuator.EXPECT().Reconcile(ctx, gomock.Any(), cp, cluster).Return(false, nil)
+
+			Expect(a.Reconcile(ctx, logr.Discard(), cp, cluster)).To(BeFalse())
+
+			Expect(history()).To(ConsistOf(HaveField("Checksum", utils.ComputeChecksum(secret.Data))))
+		})
+
+		It("should not record anything if the reconciliation failed", func() {
+			genericActuator.EXPECT().Reconcile(ctx, gomock.Any(), cp, cluster).Return(false, errors.New("boom"))
+
+			_, err := a.Reconcile(ctx, logr.Discard(), cp, cluster)
+			Expect(err).To(MatchError("boom"))
+
+			actual := &extensionsv1alpha1.ControlPlane{}
+			Expect(c.Get(ctx, client.ObjectKeyFromObject(cp), actual)).To(Succeed())
+			Expect(actual.Annotations).NotTo(HaveKey(stackit.AnnotationCredentialsRotationHistory))
+		})
+	})
+})
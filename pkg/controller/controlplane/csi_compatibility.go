@@ -13,7 +13,6 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/charts"
-	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/imagevector"
 	stackitv1alpha1 "github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/apis/stackit/v1alpha1"
 	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/openstack"
 )
@@ -25,20 +24,22 @@ const (
 	csiCompatShootChartName = csiCompatibilityPrefix + "-shoot-chart"
 )
 
-func NewCompatCSICompatibilityHandler(client client.Client, config *rest.Config) (*CompatCSICompatibilityHandler, error) {
+func NewCompatCSICompatibilityHandler(client client.Client, config *rest.Config, imageVector imagevectorutils.ImageVector) (*CompatCSICompatibilityHandler, error) {
 	renderer, err := chartrenderer.NewForConfig(config)
 	if err != nil {
 		return nil, err
 	}
 	return &CompatCSICompatibilityHandler{
-		client:   client,
-		renderer: renderer,
+		client:      client,
+		renderer:    renderer,
+		imageVector: imageVector,
 	}, nil
 }
 
 type CompatCSICompatibilityHandler struct {
-	client   client.Client
-	renderer chartrenderer.Interface
+	client      client.Client
+	renderer    chartrenderer.Interface
+	imageVector imagevectorutils.ImageVector
 }
 
 func (ch *CompatCSICompatibilityHandler) HandleSeedCSICompatibility(ctx context.Context, namespace string, version string, cpConfig *stackitv1alpha1.ControlPlaneConfig, controlPlaneValues map[string]any) error {
@@ -71,7 +72,7 @@ func (ch *CompatCSICompatibilityHandler) renderSeedCSICompatibilityMode(values m
 	// Override chart values
 	chartValues["prefix"] = csiCompatibilityPrefix
 
-	imageMap, err := findImages(version, "csi-driver-stackit", "csi-provisioner", "csi-attacher", "csi-snapshotter", "csi-resizer", "csi-liveness-probe", "csi-snapshot-controller")
+	imageMap, err := findImages(ch.imageVector, version, "csi-driver-stackit", "csi-provisioner", "csi-attacher", "csi-snapshotter", "csi-resizer", "csi-liveness-probe", "csi-snapshot-controller")
 	if err != nil {
 		return nil, err
 	}
@@ -132,7 +133,7 @@ func (ch *CompatCSICompatibilityHandler) renderShootCSICompatibilityMode(values
 	// Override chart values
 	chartValues["prefix"] = csiCompatibilityPrefix
 
-	imageMap, err := findImages(version, "csi-driver-stackit", "csi-node-driver-registrar", "csi-liveness-probe")
+	imageMap, err := findImages(ch.imageVector, version, "csi-driver-stackit", "csi-node-driver-registrar", "csi-liveness-probe")
 	if err != nil {
 		return nil, err
 	}
@@ -176,8 +177,7 @@ func composeCompatibilityChartValues(values map[string]any, csiSTACKITChartName
 	return gardenerutils.MergeMaps(values, csiStackitValues)
 }
 
-func findImages(version string, imagesToFind ...string) (map[string]any, error) {
-	images := imagevector.ImageVector()
+func findImages(images imagevectorutils.ImageVector, version string, imagesToFind ...string) (map[string]any, error) {
 	result := make(map[string]any)
 	for _, image := range imagesToFind {
 		foundImage, err := images.FindImage(image, imagevectorutils.TargetVersion(version))
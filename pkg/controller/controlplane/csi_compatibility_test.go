@@ -26,6 +26,7 @@ import (
 	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/yaml"
 
+	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/imagevector"
 	stackitv1alpha1 "github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/apis/stackit/v1alpha1"
 	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/openstack"
 )
@@ -77,7 +78,7 @@ var _ = Describe("CompatCSICompatibilityHandler", func() {
 			Transport: &mockRoundTripper{},
 		}
 
-		handler, _ = NewCompatCSICompatibilityHandler(fakeClient, config)
+		handler, _ = NewCompatCSICompatibilityHandler(fakeClient, config, imagevector.ImageVector())
 
 		controlPlaneValues = map[string]any{
 			"global": map[string]any{
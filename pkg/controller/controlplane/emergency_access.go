@@ -0,0 +1,60 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controlplane
+
+import (
+	"context"
+	"fmt"
+
+	v1beta1helper "github.com/gardener/gardener/pkg/api/core/v1beta1/helper"
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// ConditionTypeLoadBalancerEmergencyAccessValid is the condition type of the ControlPlane reporting a malformed
+	// [LoadBalancerEmergencyAccessSecretName] secret which was ignored.
+	ConditionTypeLoadBalancerEmergencyAccessValid gardencorev1beta1.ConditionType = "LoadBalancerEmergencyAccessValid"
+
+	reasonEmergencyAccessSecretMalformed = "SecretMalformed"
+	reasonEmergencyAccessSecretValid     = "SecretValidOrAbsent"
+)
+
+// updateEmergencyAccessCondition reports a malformed [LoadBalancerEmergencyAccessSecretName] secret in the
+// [ConditionTypeLoadBalancerEmergencyAccessValid] condition of the ControlPlane. A malformed secret only passes the
+// reconciliation with the [config.MalformedEmergencyAccessSecretPolicyIgnore] policy. For a valid or absent secret, the
+// condition is only updated if it reports a malformed secret, so the condition is not added to ControlPlanes which
+// never had one.
+func (a *actuator) updateEmergencyAccessCondition(ctx context.Context, cp *extensionsv1alpha1.ControlPlane) error {
+	var malformedErr error
+	secret := &corev1.Secret{}
+	if err := a.client.Get(ctx, k8sclient.ObjectKey{Namespace: cp.Namespace, Name: LoadBalancerEmergencyAccessSecretName}, secret); err != nil {
+		if k8sclient.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("could not get secret %s: %w", LoadBalancerEmergencyAccessSecretName, err)
+		}
+	} else if _, _, _, err := decodeLoadBalancerAPIEmergencySecret(secret); err != nil {
+		malformedErr = fmt.Errorf("malformed secret %s: %w", LoadBalancerEmergencyAccessSecretName, err)
+	}
+
+	existing := v1beta1helper.GetCondition(cp.Status.Conditions, ConditionTypeLoadBalancerEmergencyAccessValid)
+	if malformedErr == nil && (existing == nil || existing.Status == gardencorev1beta1.ConditionTrue) {
+		return nil
+	}
+
+	condition := v1beta1helper.GetOrInitConditionWithClock(a.clock, cp.Status.Conditions, ConditionTypeLoadBalancerEmergencyAccessValid)
+	if malformedErr != nil {
+		condition = v1beta1helper.UpdatedConditionWithClock(a.clock, condition, gardencorev1beta1.ConditionFalse, reasonEmergencyAccessSecretMalformed,
+			fmt.Sprintf("Ignoring %s, the regular load balancer API access is used: %v", LoadBalancerEmergencyAccessSecretName, malformedErr))
+	} else {
+		condition = v1beta1helper.UpdatedConditionWithClock(a.clock, condition, gardencorev1beta1.ConditionTrue, reasonEmergencyAccessSecretValid,
+			fmt.Sprintf("The secret %s is valid or absent.", LoadBalancerEmergencyAccessSecretName))
+	}
+
+	patch := k8sclient.MergeFrom(cp.DeepCopy())
+	cp.Status.Conditions = v1beta1helper.MergeConditions(cp.Status.Conditions, condition)
+	return a.client.Status().Patch(ctx, cp, patch)
+}
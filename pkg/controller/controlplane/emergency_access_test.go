@@ -0,0 +1,74 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controlplane
+
+import (
+	"context"
+	"time"
+
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+	testclock "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("#updateEmergencyAccessCondition", func() {
+	var (
+		ctx context.Context
+		c   client.Client
+		a   *actuator
+		cp  *extensionsv1alpha1.ControlPlane
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		cp = baseControlPlane()
+		c = fake.NewClientBuilder().WithScheme(newTestScheme()).WithStatusSubresource(cp).Build()
+		createObjects(ctx, c, cp)
+		a = &actuator{client: c, clock: testclock.NewFakePassiveClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))}
+	})
+
+	conditions := func() []gardencorev1beta1.Condition {
+		GinkgoHelper()
+		persisted := &extensionsv1alpha1.ControlPlane{}
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(cp), persisted)).To(Succeed())
+		return persisted.Status.Conditions
+	}
+
+	It("should not add the condition for an absent or valid secret", func() {
+		Expect(a.updateEmergencyAccessCondition(ctx, cp)).To(Succeed())
+		createObjects(ctx, c, emergencyLBSecret())
+		Expect(a.updateEmergencyAccessCondition(ctx, cp)).To(Succeed())
+
+		Expect(conditions()).To(BeEmpty())
+	})
+
+	It("should report a malformed secret and reset the condition once the secret is fixed", func() {
+		secret := emergencyLBSecret()
+		delete(secret.Data, LoadBalancerEmergencyAccessAPITokenKey)
+		createObjects(ctx, c, secret)
+
+		Expect(a.updateEmergencyAccessCondition(ctx, cp)).To(Succeed())
+		Expect(conditions()).To(ConsistOf(MatchFields(IgnoreExtras, Fields{
+			"Type":    Equal(ConditionTypeLoadBalancerEmergencyAccessValid),
+			"Status":  Equal(gardencorev1beta1.ConditionFalse),
+			"Reason":  Equal("SecretMalformed"),
+			"Message": ContainSubstring("missing or empty secret key " + LoadBalancerEmergencyAccessAPITokenKey),
+		})))
+
+		Expect(c.Delete(ctx, secret)).To(Succeed())
+		createObjects(ctx, c, emergencyLBSecret())
+
+		Expect(a.updateEmergencyAccessCondition(ctx, cp)).To(Succeed())
+		Expect(conditions()).To(ConsistOf(MatchFields(IgnoreExtras, Fields{
+			"Type":   Equal(ConditionTypeLoadBalancerEmergencyAccessValid),
+			"Status": Equal(gardencorev1beta1.ConditionTrue),
+		})))
+	})
+})
@@ -0,0 +1,52 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controlplane
+
+import (
+	"strings"
+
+	imagevectorutils "github.com/gardener/gardener/pkg/utils/imagevector"
+
+	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/imagevector"
+	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/apis/config"
+)
+
+// controlPlaneImageVector returns the image vector of the control plane components. If a registry mirror is configured,
+// the registry of all images is replaced by the mirror.
+func controlPlaneImageVector(configuration config.ControlPlaneControllerConfiguration) imagevectorutils.ImageVector {
+	images := imagevector.ImageVector()
+	if configuration.ImageRegistryMirror == "" {
+		return images
+	}
+	return withRegistryMirror(images, configuration.ImageRegistryMirror)
+}
+
+// withRegistryMirror returns a copy of the given image vector in which the registry of all image references is replaced
+// by the given mirror.
+func withRegistryMirror(images imagevectorutils.ImageVector, mirror string) imagevectorutils.ImageVector {
+	mirrored := make(imagevectorutils.ImageVector, 0, len(images))
+	for _, image := range images {
+		mirroredImage := *image
+		if image.Repository != nil {
+			mirroredImage.Repository = new(mirrorImageReference(*image.Repository, mirror))
+		}
+		if image.Ref != nil {
+			mirroredImage.Ref = new(mirrorImageReference(*image.Ref, mirror))
+		}
+		mirrored = append(mirrored, &mirroredImage)
+	}
+	return mirrored
+}
+
+// mirrorImageReference replaces the registry of the given image reference by the mirror. References without an explicit
+// registry are prefixed with the mirror.
+func mirrorImageReference(reference, mirror string) string {
+	// the first path component is a registry if it contains a dot or a port or is localhost, see
+	// https://github.com/distribution/reference/blob/main/normalize.go
+	if registry, name, ok := strings.Cut(reference, "/"); ok && (strings.ContainsAny(registry, ".:") || registry == "localhost") {
+		reference = name
+	}
+	return mirror + "/" + reference
+}
@@ -0,0 +1,60 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controlplane
+
+import (
+	imagevectorutils "github.com/gardener/gardener/pkg/utils/imagevector"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/imagevector"
+	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/apis/config"
+)
+
+var _ = Describe("Images", func() {
+	Describe("#controlPlaneImageVector", func() {
+		It("should return the image vector unchanged without a mirror", func() {
+			Expect(controlPlaneImageVector(config.ControlPlaneControllerConfiguration{})).To(Equal(imagevector.ImageVector()))
+		})
+
+		It("should rewrite all images to the mirror", func() {
+			images := controlPlaneImageVector(config.ControlPlaneControllerConfiguration{ImageRegistryMirror: "mirror.example.com/proxy"})
+
+			image, err := images.FindImage(imagevector.ImageNameCsiProvisioner)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(image.String()).To(HavePrefix("mirror.example.com/proxy/sig-storage/csi-provisioner:"))
+
+			original, err := imagevector.ImageVector().FindImage(imagevector.ImageNameCsiProvisioner)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(original.String()).To(HavePrefix("registry.k8s.io/sig-storage/csi-provisioner:"))
+		})
+	})
+
+	Describe("#withRegistryMirror", func() {
+		It("should rewrite repositories and refs", func() {
+			images := imagevectorutils.ImageVector{
+				{Name: "repository", Repository: new("registry.k8s.io/sig-storage/csi-attacher"), Tag: new("v1.0.0")},
+				{Name: "ref", Ref: new("ghcr.io/stackitcloud/foo@sha256:abc")},
+			}
+
+			Expect(withRegistryMirror(images, "mirror.example.com:5000")).To(HaveExactElements(
+				HaveField("Repository", HaveValue(Equal("mirror.example.com:5000/sig-storage/csi-attacher"))),
+				HaveField("Ref", HaveValue(Equal("mirror.example.com:5000/stackitcloud/foo@sha256:abc"))),
+			))
+			Expect(*images[0].Repository).To(Equal("registry.k8s.io/sig-storage/csi-attacher"))
+		})
+	})
+
+	DescribeTable("#mirrorImageReference",
+		func(reference, expected string) {
+			Expect(mirrorImageReference(reference, "mirror.example.com")).To(Equal(expected))
+		},
+		Entry("registry with domain", "registry.k8s.io/sig-storage/csi-attacher", "mirror.example.com/sig-storage/csi-attacher"),
+		Entry("registry with port", "registry:5000/foo/bar:v1", "mirror.example.com/foo/bar:v1"),
+		Entry("localhost registry", "localhost/foo", "mirror.example.com/foo"),
+		Entry("implicit registry", "library/nginx", "mirror.example.com/library/nginx"),
+		Entry("implicit registry without path", "nginx:latest", "mirror.example.com/nginx:latest"),
+	)
+})
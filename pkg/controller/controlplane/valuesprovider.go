@@ -6,6 +6,7 @@ package controlplane
 
 import (
 	"context"
+	"crypto/x509"
 	"encoding/json"
 	"fmt"
 	"maps"
@@ -18,6 +19,7 @@ import (
 	extensionscontroller "github.com/gardener/gardener/extensions/pkg/controller"
 	"github.com/gardener/gardener/extensions/pkg/controller/controlplane/genericactuator"
 	extensionssecretmanager "github.com/gardener/gardener/extensions/pkg/util/secret/manager"
+	gardenv1beta1helper "github.com/gardener/gardener/pkg/api/core/v1beta1/helper"
 	"github.com/gardener/gardener/pkg/apis/core/v1beta1"
 	v1beta1constants "github.com/gardener/gardener/pkg/apis/core/v1beta1/constants"
 	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
@@ -27,6 +29,7 @@ import (
 	kutil "github.com/gardener/gardener/pkg/utils/kubernetes"
 	secretutils "github.com/gardener/gardener/pkg/utils/secrets"
 	secretsmanager "github.com/gardener/gardener/pkg/utils/secrets/manager"
+	"github.com/go-logr/logr"
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	appsv1 "k8s.io/api/apps/v1"
@@ -36,6 +39,7 @@ import (
 	storagev1 "k8s.io/api/storage/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
 	"k8s.io/apimachinery/pkg/types"
@@ -48,11 +52,13 @@ import (
 
 	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/charts"
 	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/imagevector"
+	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/apis/config"
 	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/apis/stackit/helper"
 	stackitv1alpha1 "github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/apis/stackit/v1alpha1"
 	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/feature"
 	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/openstack"
 	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/stackit"
+	stackitutils "github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/utils"
 )
 
 const (
@@ -62,11 +68,22 @@ const (
 
 	CSIStackitPrefix = "stackit-blockstorage"
 
+	// storageClassParameterType is the storage class parameter of the volume type of both the STACKIT and the
+	// OpenStack CSI driver.
+	storageClassParameterType = "type"
+
+	// csiMaxEntriesPerNode is the number of volume attachments per node the default maxEntries of the OpenStack CSI
+	// attacher is computed with. The result is clamped to [minCSIMaxEntries, maxCSIMaxEntries].
+	csiMaxEntriesPerNode = 10
+	minCSIMaxEntries     = 1000
+	maxCSIMaxEntries     = 10000
+
 	// LoadBalancerEmergencyAccessSecretName defines the name of the secret which, when deployed,
 	// will reconfigure the CCM and bypass the LoadBalancer API Gateway.
-	LoadBalancerEmergencyAccessSecretName  = "lb-api-emergency-access"
-	LoadBalancerEmergencyAccessAPIURLKey   = "lbApiUrl"
-	LoadBalancerEmergencyAccessAPITokenKey = "lbApiToken"
+	LoadBalancerEmergencyAccessSecretName   = "lb-api-emergency-access"
+	LoadBalancerEmergencyAccessAPIURLKey    = "lbApiUrl"
+	LoadBalancerEmergencyAccessAPITokenKey  = "lbApiToken"
+	LoadBalancerEmergencyAccessAPICACertKey = "lbApiCaCert"
 
 	STACKITCCMServiceLoadbalancerController = "service-lb-controller"
 	// TODO: migrate to utils.BuildLabelKey
@@ -160,6 +177,7 @@ var (
 					{Type: &appsv1.Deployment{}, Name: openstack.STACKITCloudControllerManagerName},
 					{Type: &corev1.ConfigMap{}, Name: openstack.STACKITCloudControllerManagerName},
 					{Type: &vpaautoscalingv1.VerticalPodAutoscaler{}, Name: openstack.STACKITCloudControllerManagerImageName + "-vpa"},
+					{Type: &monitoringv1.ServiceMonitor{}, Name: openstack.STACKITCloudControllerManagerName},
 				},
 			},
 			{
@@ -198,6 +216,7 @@ var (
 					{Type: &appsv1.Deployment{}, Name: CSIStackitPrefix + "-csi-driver-controller"},
 					{Type: &vpaautoscalingv1.VerticalPodAutoscaler{}, Name: CSIStackitPrefix + "-csi-driver-vpa"},
 					{Type: &corev1.Secret{}, Name: CSIStackitPrefix + "-cloud-provider-config"},
+					{Type: &monitoringv1.ServiceMonitor{}, Name: CSIStackitPrefix + "-csi-driver-controller"},
 					// csi-snapshot-controller
 					{Type: &appsv1.Deployment{}, Name: CSIStackitPrefix + "-csi-snapshot-controller"},
 					{Type: &vpaautoscalingv1.VerticalPodAutoscaler{}, Name: CSIStackitPrefix + "-csi-snapshot-controller-vpa"},
@@ -364,11 +383,12 @@ type CSICompatibilityHandler interface {
 }
 
 // NewValuesProvider creates a new ValuesProvider for the generic actuator.
-func NewValuesProvider(mgr manager.Manager, customLabelDomain string, csiCompatibilityHandler CSICompatibilityHandler) genericactuator.ValuesProvider {
+func NewValuesProvider(mgr manager.Manager, customLabelDomain string, configuration config.ControlPlaneControllerConfiguration, csiCompatibilityHandler CSICompatibilityHandler) genericactuator.ValuesProvider {
 	return &valuesProvider{
 		client:                  mgr.GetClient(),
 		decoder:                 serializer.NewCodecFactory(mgr.GetScheme(), serializer.EnableStrict).UniversalDecoder(),
 		customLabelDomain:       customLabelDomain,
+		configuration:           configuration,
 		csiCompatibilityHandler: csiCompatibilityHandler,
 	}
 }
@@ -380,6 +400,7 @@ type valuesProvider struct {
 	config                  *rest.Config
 	decoder                 runtime.Decoder
 	customLabelDomain       string
+	configuration           config.ControlPlaneControllerConfiguration
 	csiCompatibilityHandler CSICompatibilityHandler
 }
 
@@ -389,6 +410,10 @@ func (vp *valuesProvider) GetConfigChartValues(
 	cp *extensionsv1alpha1.ControlPlane,
 	cluster *extensionscontroller.Cluster,
 ) (map[string]any, error) {
+	if err := checkCluster(cluster); err != nil {
+		return nil, err
+	}
+
 	controlPlaneConfig := &stackitv1alpha1.ControlPlaneConfig{}
 	if cp.Spec.ProviderConfig != nil {
 		if _, _, err := vp.decoder.Decode(cp.Spec.ProviderConfig.Raw, nil, controlPlaneConfig); err != nil {
@@ -412,6 +437,11 @@ func (vp *valuesProvider) GetConfigChartValues(
 		if err != nil {
 			return nil, fmt.Errorf("could not get service account from secret '%s/%s': %w", cp.Spec.SecretRef.Namespace, cp.Spec.SecretRef.Name, err)
 		}
+	} else if secret, err := extensionscontroller.GetSecretByReference(ctx, vp.client, &cp.Spec.SecretRef); err == nil {
+		// the OpenStack credentials are not passed to the control plane components, warn operators relying on them
+		if keys := openstack.CredentialKeys(secret); len(keys) > 0 {
+			logr.FromContextOrDiscard(ctx).Info("Ignoring OpenStack credentials of the cloudprovider secret for the cloud provider config, as only STACKIT components are used", "keys", keys)
+		}
 	}
 
 	// We ONLY enable the cloud-controller-manager's route-controller when overlay: false AND
@@ -426,10 +456,29 @@ func (vp *valuesProvider) GetConfigChartValues(
 		return nil, fmt.Errorf("could not determine if BGP is enabled: %v", err)
 	}
 	useRouteController := !overlayEnabled && !BGPEnabled
+	if ccm := controlPlaneConfig.CloudControllerManager; ccm != nil && ccm.RouteController != nil && *ccm.RouteController != useRouteController {
+		logr.FromContextOrDiscard(ctx).Info("Overriding the route controller of the cloud-controller-manager",
+			"routeController", *ccm.RouteController, "overlayEnabled", overlayEnabled, "bgpEnabled", BGPEnabled)
+		useRouteController = *ccm.RouteController
+	}
 
 	return getConfigChartValues(infraStatus, cloudProfileConfig, controlPlaneConfig, cluster, cp, osCredentials, useRouteController)
 }
 
+// checkCluster returns an error if the given cluster lacks the Shoot or the CloudProfile the chart values are computed
+// from, so that the value builders can rely on both.
+func checkCluster(cluster *extensionscontroller.Cluster) error {
+	switch {
+	case cluster == nil:
+		return fmt.Errorf("cluster is missing")
+	case cluster.Shoot == nil:
+		return fmt.Errorf("shoot of cluster %s is missing", cluster.ObjectMeta.Name)
+	case cluster.CloudProfile == nil:
+		return fmt.Errorf("cloud profile of cluster %s is missing", cluster.ObjectMeta.Name)
+	}
+	return nil
+}
+
 func (vp *valuesProvider) getInfrastructureStatus(cp *extensionsv1alpha1.ControlPlane) (*stackitv1alpha1.InfrastructureStatus, error) {
 	infraStatus := &stackitv1alpha1.InfrastructureStatus{}
 	if _, _, err := vp.decoder.Decode(cp.Spec.InfrastructureProviderStatus.Raw, nil, infraStatus); err != nil {
@@ -450,6 +499,10 @@ func (vp *valuesProvider) GetControlPlaneChartValues(
 	map[string]any,
 	error,
 ) {
+	if err := checkCluster(cluster); err != nil {
+		return nil, err
+	}
+
 	// Decode providerConfig
 	cpConfig := &stackitv1alpha1.ControlPlaneConfig{}
 	if cp.Spec.ProviderConfig != nil {
@@ -458,20 +511,32 @@ func (vp *valuesProvider) GetControlPlaneChartValues(
 		}
 	}
 
-	cpConfigSecret := &corev1.Secret{}
+	// the config chart does not render the cloud provider config secrets for STACKIT-only clusters
+	if !isSTACKITOnly(cluster, cpConfig) {
+		cpConfigSecret := &corev1.Secret{}
+		if err := vp.client.Get(ctx, k8sclient.ObjectKey{Namespace: cp.Namespace, Name: openstack.CloudProviderConfigName}, cpConfigSecret); err != nil {
+			return nil, err
+		}
+		checksums[openstack.CloudProviderConfigName] = gardenerutils.ComputeChecksum(cpConfigSecret.Data)
 
-	if err := vp.client.Get(ctx, k8sclient.ObjectKey{Namespace: cp.Namespace, Name: openstack.CloudProviderConfigName}, cpConfigSecret); err != nil {
-		return nil, err
+		cpDiskConfigSecret := &corev1.Secret{}
+		if err := vp.client.Get(ctx, k8sclient.ObjectKey{Namespace: cp.Namespace, Name: openstack.CloudProviderCSIDiskConfigName}, cpDiskConfigSecret); err != nil {
+			return nil, err
+		}
+		checksums[openstack.CloudProviderCSIDiskConfigName] = gardenerutils.ComputeChecksum(cpDiskConfigSecret.Data)
 	}
-	checksums[openstack.CloudProviderConfigName] = gardenerutils.ComputeChecksum(cpConfigSecret.Data)
 
 	var userAgentHeaders []string
-	cpDiskConfigSecret := &corev1.Secret{}
-	if err := vp.client.Get(ctx, k8sclient.ObjectKey{Namespace: cp.Namespace, Name: openstack.CloudProviderCSIDiskConfigName}, cpDiskConfigSecret); err != nil {
-		return nil, err
+	credentials, err := vp.getCredentials(ctx, cp)
+	if err != nil {
+		// Missing credentials are only logged here and ignored silently for the shoot chart values, so that the message
+		// is emitted once per reconciliation.
+		if getCSIDriver(cpConfig) == stackitv1alpha1.OPENSTACK || getCCMController(cpConfig) == stackitv1alpha1.OPENSTACK {
+			logr.FromContextOrDiscard(ctx).Info("OpenStack credentials are missing in the cloudprovider secret, user agent headers do not contain the domain and project",
+				"csi", getCSIDriver(cpConfig), "cloudControllerManager", getCCMController(cpConfig), "reason", err.Error())
+		}
+		credentials = nil
 	}
-	checksums[openstack.CloudProviderCSIDiskConfigName] = gardenerutils.ComputeChecksum(cpDiskConfigSecret.Data)
-	credentials, _ := vp.getCredentials(ctx, cp) // ignore missing credentials
 
 	stackitCredentials, err := vp.getSTACKITCredentials(ctx, cp)
 	if err != nil {
@@ -489,7 +554,9 @@ func (vp *valuesProvider) GetControlPlaneChartValues(
 		return nil, err
 	}
 
-	return vp.getControlPlaneChartValues(ctx, cpConfig, cp, cluster, infra, secretsReader, userAgentHeaders, checksums, scaledDown, stackitCredentials, cloudProfileConfig.APIEndpoints)
+	// the API endpoints are resolved for the region of the shoot, including the IaaS endpoint of its InfrastructureConfig
+	apiEndpoints := helper.APIEndpointsFromCluster(cluster, cloudProfileConfig, stackit.DetermineRegion(cluster))
+	return vp.getControlPlaneChartValues(ctx, cpConfig, cp, cluster, infra, secretsReader, userAgentHeaders, checksums, scaledDown, stackitCredentials, &apiEndpoints)
 }
 
 // GetControlPlaneShootChartValues returns the values for the control plane shoot chart applied by the generic actuator.
@@ -500,6 +567,10 @@ func (vp *valuesProvider) GetControlPlaneShootChartValues(
 	secretsReader secretsmanager.Reader,
 	_ map[string]string,
 ) (map[string]any, error) {
+	if err := checkCluster(cluster); err != nil {
+		return nil, err
+	}
+
 	// Decode providerConfig
 	cpConfig := &stackitv1alpha1.ControlPlaneConfig{}
 	if cp.Spec.ProviderConfig != nil {
@@ -515,12 +586,37 @@ func (vp *valuesProvider) GetControlPlaneShootChartValues(
 	return vp.getControlPlaneShootChartValues(ctx, cpConfig, cp, cloudProfileConfig, cluster, secretsReader)
 }
 
+// GetControlPlaneShootCRDsChartValues returns the values for the control plane shoot CRDs chart applied by the generic actuator.
+func (vp *valuesProvider) GetControlPlaneShootCRDsChartValues(
+	_ context.Context,
+	cp *extensionsv1alpha1.ControlPlane,
+	_ *extensionscontroller.Cluster,
+) (map[string]any, error) {
+	// Decode providerConfig
+	cpConfig := &stackitv1alpha1.ControlPlaneConfig{}
+	if cp.Spec.ProviderConfig != nil {
+		if _, _, err := vp.decoder.Decode(cp.Spec.ProviderConfig.Raw, nil, cpConfig); err != nil {
+			return nil, fmt.Errorf("could not decode providerConfig of controlplane '%s': %w", k8sclient.ObjectKeyFromObject(cp), err)
+		}
+	}
+
+	return map[string]any{
+		"volumesnapshots": map[string]any{
+			"enabled": !isSnapshotControllerDisabled(cpConfig),
+		},
+	}, nil
+}
+
 // GetStorageClassesChartValues returns the values for the shoot storageclasses chart applied by the generic actuator.
 func (vp *valuesProvider) GetStorageClassesChartValues(
 	_ context.Context,
 	controlPlane *extensionsv1alpha1.ControlPlane,
 	cluster *extensionscontroller.Cluster,
 ) (map[string]any, error) {
+	if err := checkCluster(cluster); err != nil {
+		return nil, err
+	}
+
 	providerConfig := stackitv1alpha1.CloudProfileConfig{}
 	if cluster.CloudProfile.Spec.ProviderConfig != nil {
 		if _, _, err := vp.decoder.Decode(cluster.CloudProfile.Spec.ProviderConfig.Raw, nil, &providerConfig); err != nil {
@@ -553,8 +649,8 @@ func (vp *valuesProvider) GetStorageClassesChartValues(
 			if len(sc.Labels) != 0 {
 				storageClassValues["labels"] = sc.Labels
 			}
-			if len(sc.Parameters) != 0 {
-				storageClassValues["parameters"] = sc.Parameters
+			if parameters := storageClassParameters(sc.Parameters, providerConfig.DefaultVolumeType); len(parameters) != 0 {
+				storageClassValues["parameters"] = parameters
 			}
 
 			csiDriverInUse := getCSIDriver(cpConfig)
@@ -595,11 +691,35 @@ func (vp *valuesProvider) GetStorageClassesChartValues(
 		},
 	}
 
+	if parameters := storageClassParameters(nil, providerConfig.DefaultVolumeType); len(parameters) != 0 {
+		for _, storageClassValues := range storageclasses {
+			storageClassValues["parameters"] = parameters
+		}
+	}
+
 	values["storageclasses"] = storageclasses
 
 	return values, nil
 }
 
+// storageClassParameters returns the parameters of a storage class. The default volume type is added as `type`
+// parameter if the parameters don't specify a volume type.
+func storageClassParameters(parameters map[string]string, defaultVolumeType *string) map[string]string {
+	if defaultVolumeType == nil {
+		return parameters
+	}
+	if _, ok := parameters[storageClassParameterType]; ok {
+		return parameters
+	}
+
+	result := maps.Clone(parameters)
+	if result == nil {
+		result = map[string]string{}
+	}
+	result[storageClassParameterType] = *defaultVolumeType
+	return result
+}
+
 func (vp *valuesProvider) getCredentials(ctx context.Context, cp *extensionsv1alpha1.ControlPlane) (*openstack.Credentials, error) {
 	return openstack.GetCredentials(ctx, vp.client, cp.Spec.SecretRef, false)
 }
@@ -648,7 +768,7 @@ func getConfigChartValues(
 
 	values := map[string]any{}
 	if isSTACKITOnly(cluster, controlPlaneConfig) {
-		values["stackitonly"] = true
+		values["stackitOnly"] = true
 	} else {
 		values["domainName"] = osCredentials.DomainName
 		values["tenantName"] = osCredentials.TenantName
@@ -660,7 +780,6 @@ func getConfigChartValues(
 		values["applicationCredentialName"] = osCredentials.ApplicationCredentialName
 		values["applicationCredentialSecret"] = osCredentials.ApplicationCredentialSecret
 		values["region"] = cp.Spec.Region
-		//nolint:staticcheck // SA1019: needed for migration purposes
 		values["requestTimeout"] = cloudProfileConfig.RequestTimeout
 		//nolint:staticcheck // SA1019: needed for migration purposes
 		values["ignoreVolumeAZ"] = cloudProfileConfig.IgnoreVolumeAZ != nil && *cloudProfileConfig.IgnoreVolumeAZ
@@ -669,6 +788,15 @@ func getConfigChartValues(
 		values["internalNetworkName"] = infraStatus.Networks.Name
 
 		if addRouterID {
+			// The infrastructure status contains exactly the router the node subnet is attached to, which carries the
+			// routes of the route controller. A configured network without such a router is a configuration problem, which
+			// the shoot owner can resolve by disabling the route controller in the ControlPlaneConfig.
+			if infraStatus.Networks.Router.ID == "" {
+				return nil, gardenv1beta1helper.NewErrorWithCodes(
+					fmt.Errorf("the route controller requires the router of the node network, but the infrastructure status of controlplane '%s' does not contain a router ID, disable it with cloudControllerManager.routeController=false in the ControlPlaneConfig if the network has no router", k8sclient.ObjectKeyFromObject(cp)),
+					v1beta1.ErrorConfigurationProblem,
+				)
+			}
 			values["routerID"] = infraStatus.Networks.Router.ID
 		}
 
@@ -702,31 +830,34 @@ func (vp *valuesProvider) getControlPlaneChartValues(ctx context.Context, cpConf
 	// in the shoot controlplane namespace, the CCM must be reconfigured to bypass the LB API gateway and
 	// hit the API on the URL and with the token which are both specified by the secret.
 	// See ADR: https://developers.stackit.schwarz/domains/runtime/ske/architecture/adrs/loadbalancer-emergency-access/
-	lbAPIURL, lbAPIToken, err := vp.checkEmergencyLoadBalancerAccess(ctx, types.NamespacedName{
-		Name:      LoadBalancerEmergencyAccessSecretName,
-		Namespace: cp.Namespace,
-	})
+	lbAPIURL, lbAPIToken, lbAPICACert, err := vp.checkEmergencyLoadBalancerAccess(ctx, cp)
 	if err != nil {
 		return nil, err
 	}
 
 	stackitCredentialsConfig := stackitCredentials
 
-	// Copy API endpoints to avoid mutating the original from CloudProfileConfig
-	var ccmAPIEndpoints stackitv1alpha1.APIEndpoints
-	if apiEndpoints != nil {
-		ccmAPIEndpoints = *apiEndpoints
-	}
+	// Resolve the API endpoints of the region before the emergency LB API access is applied, so that it also takes
+	// precedence over a regional override. ForRegion returns a copy, hence the CloudProfileConfig is not mutated.
+	ccmAPIEndpoints := apiEndpoints.ForRegion(stackit.DetermineRegion(cluster))
 
 	// Override with emergency LB API access if configured
 	if lbAPIURL != "" && lbAPIToken != "" {
 		ccmAPIEndpoints.LoadBalancer = &lbAPIURL
 		ccmAPIEndpoints.TokenEndpoint = nil
 		stackitCredentialsConfig.LoadBalancerAPIEmergencyToken = lbAPIToken
+		stackitCredentialsConfig.LoadBalancerAPIEmergencyCACert = lbAPICACert
+	}
+
+	clusterLabel, err := ClusterLabelValue(cluster, vp.configuration.ClusterLabelValueSource)
+	if err != nil {
+		return nil, err
 	}
 
+	customLabelDomain := helper.CustomLabelDomain(cpConfig, vp.customLabelDomain)
+
 	stackitRegion := stackit.DetermineRegion(cluster)
-	stackitccm, err := getSTACKITCCMChartValues(cpConfig, cp, cluster, infra, stackitCredentialsConfig, stackitRegion, &ccmAPIEndpoints, checksums, scaledDown, vp.customLabelDomain)
+	stackitccm, err := getSTACKITCCMChartValues(cpConfig, cp, cluster, infra, stackitCredentialsConfig, stackitRegion, &ccmAPIEndpoints, checksums, scaledDown, customLabelDomain, clusterLabel)
 	if err != nil {
 		return nil, err
 	}
@@ -742,25 +873,31 @@ func (vp *valuesProvider) getControlPlaneChartValues(ctx context.Context, cpConf
 		return nil, err
 	}
 
+	global := map[string]any{
+		"genericTokenKubeconfigSecretName": extensionscontroller.GenericTokenKubeconfigSecretNameFromCluster(cluster),
+	}
+	if vp.configuration.ImagePullPolicy != "" {
+		global["imagePullPolicy"] = vp.configuration.ImagePullPolicy
+	}
+
 	maps.Copy(controlPlaneValues, map[string]any{
-		"global": map[string]any{
-			"genericTokenKubeconfigSecretName": extensionscontroller.GenericTokenKubeconfigSecretNameFromCluster(cluster),
-		},
-		openstack.CloudControllerManagerName:        ccm,
+		"global":                             global,
+		openstack.CloudControllerManagerName: ccm,
 		openstack.STACKITCloudControllerManagerName: stackitccm,
 		stackit.PodIdentityWebhookName:              podIdentityWebhook,
 	})
 
 	storageCSIDriver := getCSIDriver(cpConfig)
+	snapshotControllerEnabled := !isSnapshotControllerDisabled(cpConfig)
 	switch storageCSIDriver {
 	case stackitv1alpha1.OPENSTACK:
-		csiCinder := getCSIControllerChartValues(cluster, userAgentHeaders, checksums, scaledDown)
+		csiCinder := getCSIControllerChartValues(cpConfig, cluster, userAgentHeaders, checksums, scaledDown, snapshotControllerEnabled)
 		controlPlaneValues[openstack.CSIControllerName] = csiCinder
 		controlPlaneValues[openstack.CSISTACKITControllerName] = map[string]any{
 			"enabled": false,
 		}
 	case stackitv1alpha1.STACKIT:
-		csiSTACKIT := getCSISTACKITControllerChartValues(cluster, stackitCredentialsConfig, userAgentHeaders, checksums, scaledDown, apiEndpoints, vp.customLabelDomain)
+		csiSTACKIT := getCSISTACKITControllerChartValues(cluster, stackitCredentialsConfig, userAgentHeaders, checksums, scaledDown, snapshotControllerEnabled, apiEndpoints, customLabelDomain, clusterLabel)
 		controlPlaneValues[openstack.CSISTACKITControllerName] = csiSTACKIT
 		controlPlaneValues[openstack.CSIControllerName] = map[string]any{
 			"enabled": false,
@@ -776,7 +913,7 @@ func (vp *valuesProvider) getControlPlaneChartValues(ctx context.Context, cpConf
 	if DeploySTACKITApplicationLoadBalancer(cpConfig) {
 		// Currently only the ingress source is allowed and the validation does not allow to enable the ALB controller of no source is enabled.
 		// When adding support for GatewayAPI it is required to adopt the configuration of the ALB controller here.
-		albcm, err := getSTACKITApplicationLoadBalancerCMChartValues(cpConfig, cluster, infra, stackitCredentialsConfig, apiEndpoints, checksums, scaledDown, stackitRegion)
+		albcm, err := getSTACKITApplicationLoadBalancerCMChartValues(cpConfig, cluster, infra, stackitCredentialsConfig, new(apiEndpoints.ForRegion(stackitRegion)), checksums, scaledDown, stackitRegion, clusterLabel)
 		if err != nil {
 			return nil, err
 		}
@@ -789,9 +926,106 @@ func (vp *valuesProvider) getControlPlaneChartValues(ctx context.Context, cpConf
 		}
 	}
 
+	vp.injectSchedulingValues(controlPlaneValues)
+
+	if err := vp.injectMetricsValues(controlPlaneValues); err != nil {
+		return nil, err
+	}
+
 	return controlPlaneValues, nil
 }
 
+// injectSchedulingValues adds the configured node selector and tolerations to the values of all enabled control plane
+// components. Components keep the scheduling defaults of their charts if nothing is configured.
+func (vp *valuesProvider) injectSchedulingValues(controlPlaneValues map[string]any) {
+	if len(vp.configuration.NodeSelector) == 0 && len(vp.configuration.Tolerations) == 0 {
+		return
+	}
+
+	for _, name := range []string{
+		openstack.CloudControllerManagerName,
+		openstack.STACKITCloudControllerManagerName,
+		openstack.CSIControllerName,
+		openstack.CSISTACKITControllerName,
+		openstack.STACKITApplicationLoadBalancerControllerName,
+		stackit.PodIdentityWebhookName,
+	} {
+		values, ok := controlPlaneValues[name].(map[string]any)
+		if !ok || values == nil {
+			continue
+		}
+		if len(vp.configuration.NodeSelector) > 0 {
+			values["nodeSelector"] = vp.configuration.NodeSelector
+		}
+		if len(vp.configuration.Tolerations) > 0 {
+			values["tolerations"] = vp.configuration.Tolerations
+		}
+	}
+}
+
+// injectMetricsValues adds the configured metrics endpoint to the values of the STACKIT cloud-controller-manager and
+// the STACKIT CSI driver controller. If enabled, their ServiceMonitors are deployed as long as the ServiceMonitor CRD
+// exists in the seed. ServiceMonitors which are no longer rendered are removed by the ManagedResource of the control
+// plane chart.
+func (vp *valuesProvider) injectMetricsValues(controlPlaneValues map[string]any) error {
+	serviceMonitorsAvailable, err := vp.serviceMonitorsAvailable()
+	if err != nil {
+		return err
+	}
+
+	for _, component := range []struct {
+		chartName   string
+		metricsPort *int32
+	}{
+		{openstack.STACKITCloudControllerManagerName, vp.configuration.CloudControllerManagerMetricsPort},
+		{openstack.CSISTACKITControllerName, vp.configuration.CSIControllerMetricsPort},
+	} {
+		values, ok := controlPlaneValues[component.chartName].(map[string]any)
+		if !ok || values == nil || values["enabled"] != true {
+			continue
+		}
+
+		chartConfig, ok := values["config"].(map[string]any)
+		if !ok || chartConfig == nil {
+			chartConfig = map[string]any{}
+			values["config"] = chartConfig
+		}
+		if vp.configuration.MetricsBindAddress != "" {
+			chartConfig["metricsBindAddress"] = metricsBindHost(vp.configuration.MetricsBindAddress)
+		}
+		if component.metricsPort != nil {
+			chartConfig["metricsPort"] = *component.metricsPort
+		}
+
+		if vp.configuration.ServiceMonitors && serviceMonitorsAvailable {
+			values["serviceMonitor"] = map[string]any{"enabled": true}
+		}
+	}
+
+	return nil
+}
+
+// serviceMonitorsAvailable returns whether the ServiceMonitor CRD of the Prometheus operator exists in the seed.
+func (vp *valuesProvider) serviceMonitorsAvailable() (bool, error) {
+	_, err := vp.client.RESTMapper().RESTMapping(monitoringv1.SchemeGroupVersion.WithKind(monitoringv1.ServiceMonitorsKind).GroupKind(), monitoringv1.SchemeGroupVersion.Version)
+	if meta.IsNoMatchError(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to determine whether ServiceMonitors are available: %w", err)
+	}
+	return true, nil
+}
+
+// metricsBindHost returns the host part of a metrics address for the given IP, i.e. IPv6 addresses are enclosed in
+// brackets.
+func metricsBindHost(ip string) string {
+	if strings.Contains(ip, ":") {
+		return "[" + ip + "]"
+	}
+	return ip
+}
+
 func (vp *valuesProvider) cleanupControlPlaneFromUnusedCSIDriverComponents(ctx context.Context, namespace string, csiDriver stackitv1alpha1.ControllerName) error {
 	switch csiDriver {
 	case stackitv1alpha1.STACKIT:
@@ -845,6 +1079,25 @@ func getCCMControllersForSTACKIT(cpConfig *stackitv1alpha1.ControlPlaneConfig) [
 	return []string{STACKITCCMServiceLoadbalancerController}
 }
 
+// ClusterLabelValue returns the identifier of the shoot which is used as value of the cluster label of STACKIT resources.
+// All components creating or looking up STACKIT resources by the cluster label must use it with the same source.
+func ClusterLabelValue(cluster *extensionscontroller.Cluster, source config.ClusterLabelValueSource) (string, error) {
+	switch source {
+	case config.ClusterLabelValueSourceShootUID:
+		if cluster.Shoot.UID == "" {
+			return "", fmt.Errorf("cluster label value source %s is not available: shoot %s has no UID", source, cluster.Shoot.Name)
+		}
+		return string(cluster.Shoot.UID), nil
+	case config.ClusterLabelValueSourceTechnicalID, "":
+		if cluster.Shoot.Status.TechnicalID == "" {
+			return "", fmt.Errorf("cluster label value source %s is not available: shoot %s has no technical ID", config.ClusterLabelValueSourceTechnicalID, cluster.Shoot.Name)
+		}
+		return cluster.Shoot.Status.TechnicalID, nil
+	default:
+		return "", fmt.Errorf("unsupported cluster label value source: %s", source)
+	}
+}
+
 // getSTACKITCCMChartValues collects and returns the CCM chart values.
 func getSTACKITCCMChartValues(
 	cpConfig *stackitv1alpha1.ControlPlaneConfig,
@@ -857,21 +1110,27 @@ func getSTACKITCCMChartValues(
 	checksums map[string]string,
 	scaledDown bool,
 	customLabelDomain string,
+	clusterLabel string,
 ) (map[string]any, error) {
 	if credentials == nil {
 		return nil, fmt.Errorf("no STACKIT credentials are provided in cluster %s", cluster.Shoot.Name)
 	}
 
+	serviceLoadBalancerNetworkID := infra.Networks.ID
+	if cpConfig.CloudControllerManager != nil && cpConfig.CloudControllerManager.ServiceLoadBalancerNetworkID != nil {
+		serviceLoadBalancerNetworkID = *cpConfig.CloudControllerManager.ServiceLoadBalancerNetworkID
+	}
+
 	ccmConfig := map[string]any{
-		"stackitNetworkID": infra.Networks.ID,
+		"stackitNetworkID": serviceLoadBalancerNetworkID,
 		"stackitRegion":    stackitRegion,
 		"stackitProjectID": credentials.ProjectID,
 		"extraLabels": map[string]string{
 			// TODO: migrate away from the old key
-			STACKITLBClusterLabelKey: cluster.Shoot.Status.TechnicalID,
+			STACKITLBClusterLabelKey: clusterLabel,
 			// Disabled as the load balancer API is currently not accepting `/` in the label
 			// TODO: enable this as soon as the load balancer API supports this
-			// utils.ClusterLabelKey(customLabelDomain): cluster.Shoot.Status.TechnicalID,
+			// utils.ClusterLabelKey(customLabelDomain): clusterLabel,
 		},
 		"customLabelDomain": customLabelDomain,
 	}
@@ -879,17 +1138,19 @@ func getSTACKITCCMChartValues(
 	if credentials.LoadBalancerAPIEmergencyToken != "" {
 		ccmConfig["loadBalancerEmergencyToken"] = credentials.LoadBalancerAPIEmergencyToken
 	}
+	if credentials.LoadBalancerAPIEmergencyCACert != "" {
+		ccmConfig["loadBalancerApiCaCert"] = credentials.LoadBalancerAPIEmergencyCACert
+	}
 
-	if apiEndpoints != nil {
-		if apiEndpoints.LoadBalancer != nil {
-			ccmConfig["loadBalancerApiUrl"] = *apiEndpoints.LoadBalancer
-		}
-		if apiEndpoints.IaaS != nil {
-			ccmConfig["iaasApiUrl"] = *apiEndpoints.IaaS
-		}
-		if apiEndpoints.TokenEndpoint != nil {
-			ccmConfig["tokenUrl"] = *apiEndpoints.TokenEndpoint
-		}
+	endpoints := apiEndpoints.ForRegion(stackit.DetermineRegion(cluster))
+	if endpoints.LoadBalancer != nil {
+		ccmConfig["loadBalancerApiUrl"] = *endpoints.LoadBalancer
+	}
+	if endpoints.IaaS != nil {
+		ccmConfig["iaasApiUrl"] = *endpoints.IaaS
+	}
+	if endpoints.TokenEndpoint != nil {
+		ccmConfig["tokenUrl"] = *endpoints.TokenEndpoint
 	}
 
 	values := map[string]any{
@@ -909,6 +1170,9 @@ func getSTACKITCCMChartValues(
 
 	if cpConfig.CloudControllerManager != nil {
 		values["featureGates"] = cpConfig.CloudControllerManager.FeatureGates
+		if len(cpConfig.CloudControllerManager.ExtraArgs) > 0 {
+			values["extraArgs"] = cpConfig.CloudControllerManager.ExtraArgs
+		}
 	}
 
 	if cluster.CloudProfile != nil && cluster.CloudProfile.Spec.CABundle != nil {
@@ -938,12 +1202,18 @@ func getCCMChartValues(
 
 	enabled := getCCMController(cpConfig) == stackitv1alpha1.OPENSTACK
 
+	// the CCM expects the IPv4 CIDR first in dual-stack clusters
+	podNetwork, err := stackitutils.PodNetworkCIDRs(cluster)
+	if err != nil {
+		return nil, err
+	}
+
 	values := map[string]any{
 		"enabled":           enabled,
 		"replicas":          extensionscontroller.GetControlPlaneReplicas(cluster, scaledDown, 1),
 		"technicalID":       cluster.Shoot.Status.TechnicalID,
 		"kubernetesVersion": cluster.Shoot.Spec.Kubernetes.Version,
-		"podNetwork":        strings.Join(extensionscontroller.GetPodNetwork(cluster), ","),
+		"podNetwork":        strings.Join(podNetwork, ","),
 		"podAnnotations": map[string]any{
 			"checksum/secret-" + v1beta1constants.SecretNameCloudProvider: checksums[v1beta1constants.SecretNameCloudProvider],
 			"checksum/secret-" + openstack.CloudProviderConfigName:        checksums[openstack.CloudProviderConfigName],
@@ -963,22 +1233,24 @@ func getCCMChartValues(
 
 	if cpConfig.CloudControllerManager != nil {
 		values["featureGates"] = cpConfig.CloudControllerManager.FeatureGates
+		if len(cpConfig.CloudControllerManager.ExtraArgs) > 0 {
+			values["extraArgs"] = cpConfig.CloudControllerManager.ExtraArgs
+		}
 	}
 
 	return values, nil
 }
 
-func getCSISTACKITControllerChartValues(cluster *extensionscontroller.Cluster, credentials *stackit.Credentials, userAgentHeaders []string, checksums map[string]string, scaledDown bool, apiEndpoints *stackitv1alpha1.APIEndpoints, customLabelDomain string) map[string]any {
+func getCSISTACKITControllerChartValues(cluster *extensionscontroller.Cluster, credentials *stackit.Credentials, userAgentHeaders []string, checksums map[string]string, scaledDown, snapshotControllerEnabled bool, apiEndpoints *stackitv1alpha1.APIEndpoints, customLabelDomain, clusterLabel string) map[string]any {
 	region := stackit.DetermineRegion(cluster)
 
 	endpointConfig := map[string]string{}
-	if apiEndpoints != nil {
-		if apiEndpoints.TokenEndpoint != nil {
-			endpointConfig["tokenUrl"] = *apiEndpoints.TokenEndpoint
-		}
-		if apiEndpoints.IaaS != nil {
-			endpointConfig["iaasUrl"] = *apiEndpoints.IaaS
-		}
+	endpoints := apiEndpoints.ForRegion(region)
+	if endpoints.TokenEndpoint != nil {
+		endpointConfig["tokenUrl"] = *endpoints.TokenEndpoint
+	}
+	if endpoints.IaaS != nil {
+		endpointConfig["iaasUrl"] = *endpoints.IaaS
 	}
 
 	values := map[string]any{
@@ -987,14 +1259,19 @@ func getCSISTACKITControllerChartValues(cluster *extensionscontroller.Cluster, c
 		"region":    region,
 		"replicas":  extensionscontroller.GetControlPlaneReplicas(cluster, scaledDown, 1),
 		"podAnnotations": map[string]any{
-			"checksum/secret-" + openstack.CloudProviderCSIDiskConfigName: checksums[openstack.CloudProviderCSIDiskConfigName],
 			"checksum/secret-" + v1beta1constants.SecretNameCloudProvider: checksums[v1beta1constants.SecretNameCloudProvider],
 		},
 		"csiSnapshotController": map[string]any{
+			"enabled":  snapshotControllerEnabled,
 			"replicas": extensionscontroller.GetControlPlaneReplicas(cluster, scaledDown, 1),
 		},
 		"stackitEndpoints":  endpointConfig,
 		"customLabelDomain": customLabelDomain,
+		"clusterLabelValue": clusterLabel,
+	}
+	// the disk config secret does not exist in STACKIT-only clusters
+	if checksum, ok := checksums[openstack.CloudProviderCSIDiskConfigName]; ok {
+		values["podAnnotations"].(map[string]any)["checksum/secret-"+openstack.CloudProviderCSIDiskConfigName] = checksum
 	}
 	if userAgentHeaders != nil {
 		values["userAgentHeaders"] = userAgentHeaders
@@ -1009,7 +1286,7 @@ func getCSISTACKITControllerChartValues(cluster *extensionscontroller.Cluster, c
 }
 
 // getCSIControllerChartValues collects and returns the CSIController chart values.
-func getCSIControllerChartValues(cluster *extensionscontroller.Cluster, userAgentHeaders []string, checksums map[string]string, scaledDown bool) map[string]any {
+func getCSIControllerChartValues(cpConfig *stackitv1alpha1.ControlPlaneConfig, cluster *extensionscontroller.Cluster, userAgentHeaders []string, checksums map[string]string, scaledDown, snapshotControllerEnabled bool) map[string]any {
 	values := map[string]any{
 		"kubernetesVersion": cluster.Shoot.Spec.Kubernetes.Version,
 		"enabled":           true,
@@ -1018,9 +1295,10 @@ func getCSIControllerChartValues(cluster *extensionscontroller.Cluster, userAgen
 			"checksum/secret-" + openstack.CloudProviderCSIDiskConfigName: checksums[openstack.CloudProviderCSIDiskConfigName],
 		},
 		"csiSnapshotController": map[string]any{
+			"enabled":  snapshotControllerEnabled,
 			"replicas": extensionscontroller.GetControlPlaneReplicas(cluster, scaledDown, 1),
 		},
-		"maxEntries": 1000,
+		"maxEntries": getCSIMaxEntries(cpConfig, cluster),
 	}
 	if userAgentHeaders != nil {
 		values["userAgentHeaders"] = userAgentHeaders
@@ -1037,6 +1315,7 @@ func getSTACKITApplicationLoadBalancerCMChartValues(
 	checksums map[string]string,
 	scaledDown bool,
 	stackitRegion string,
+	clusterLabel string,
 ) (map[string]any, error) {
 	if credentials == nil {
 		return nil, fmt.Errorf("no STACKIT credentials are provided in cluster %s", cluster.Shoot.Name)
@@ -1071,10 +1350,10 @@ func getSTACKITApplicationLoadBalancerCMChartValues(
 			"networkId": infra.Networks.ID,
 			"extraLabels": map[string]string{
 				// TODO: migrate away from the old key
-				STACKITALBClusterLabelKey: cluster.Shoot.Status.TechnicalID,
+				STACKITALBClusterLabelKey: clusterLabel,
 				// Disabled as the application load balancer API is currently not accepting `/` in the label
 				// TODO: enable this as soon as the load balancer API supports this
-				// utils.ClusterLabelKey(customLabelDomain): cluster.Shoot.Status.TechnicalID,
+				// utils.ClusterLabelKey(customLabelDomain): clusterLabel,
 			},
 		},
 	}
@@ -1096,6 +1375,9 @@ func getSTACKITApplicationLoadBalancerCMChartValues(
 	return values, nil
 }
 
+// DeploySTACKITApplicationLoadBalancer returns whether the application load balancer controller is enabled in the given
+// ControlPlaneConfig. The per-shoot configuration is authoritative, whether it may be enabled at all is enforced by the
+// admission webhook.
 func DeploySTACKITApplicationLoadBalancer(cpConfig *stackitv1alpha1.ControlPlaneConfig) bool {
 	return ptr.Deref(cpConfig.ApplicationLoadBalancer, stackitv1alpha1.ApplicationLoadBalancerConfig{}).Enabled
 }
@@ -1106,10 +1388,15 @@ func (vp *valuesProvider) getControlPlaneShootChartValues(ctx context.Context, c
 
 	values := make(map[string]any)
 
+	rescanPools, err := rescanBlockStorageOnResizePools(cluster, cloudProfileConfig)
+	if err != nil {
+		return nil, err
+	}
+
 	// OpenStack CSI
-	csiNodeDriverValues = vp.getControlPlaneShootChartCSIValues(ctx, cpConfig, cp, cluster, cloudProfileConfig)
+	csiNodeDriverValues = vp.getControlPlaneShootChartCSIValues(ctx, cpConfig, cp, cluster, cloudProfileConfig, rescanPools)
 	// STACKIT CSI
-	csiDriverSTACKITValues := vp.getControlPlaneShootChartCSISTACKITValues(ctx, cpConfig, cp, cluster, cloudProfileConfig)
+	csiDriverSTACKITValues := vp.getControlPlaneShootChartCSISTACKITValues(ctx, cpConfig, cp, cluster, cloudProfileConfig, rescanPools)
 
 	csiDriverInUse := getCSIDriver(cpConfig)
 	switch csiDriverInUse {
@@ -1201,33 +1488,43 @@ func (vp *valuesProvider) isBGPEnabled(network *v1beta1.Networking) (bool, error
 }
 
 // checkEmergencyLoadBalancerAccess checks for the existence of the [LoadBalancerEmergencyAccessSecretName] secret.
-// If the secret exists and is decodeable, the 'apiURL' and 'apiToken' are returned non-empty.
-// If the secret doesn't exist, 'apiUrl', 'apiToken' and 'err' will be nil
-// On any other cases, 'apiUrl' and 'apiToken' are empty and an error is returned.
-func (vp *valuesProvider) checkEmergencyLoadBalancerAccess(ctx context.Context, secretConfKey types.NamespacedName) (apiURL, apiToken string, err error) {
+// If the secret exists and is decodeable, the 'apiURL' and 'apiToken' are returned non-empty, 'caCert' only if the
+// secret contains a CA certificate.
+// If the secret doesn't exist, 'apiUrl', 'apiToken', 'caCert' and 'err' will be nil
+// If the secret is malformed and the [config.MalformedEmergencyAccessSecretPolicyIgnore] policy is configured,
+// 'apiUrl', 'apiToken', 'caCert' and 'err' will be nil as well. The actuator reports the malformed secret in a condition
+// of the ControlPlane.
+// On any other cases, 'apiUrl', 'apiToken' and 'caCert' are empty and an error is returned.
+func (vp *valuesProvider) checkEmergencyLoadBalancerAccess(ctx context.Context, cp *extensionsv1alpha1.ControlPlane) (apiURL, apiToken, caCert string, err error) {
 	secret := &corev1.Secret{}
-	err = vp.client.Get(ctx, secretConfKey, secret)
+	err = vp.client.Get(ctx, types.NamespacedName{Name: LoadBalancerEmergencyAccessSecretName, Namespace: cp.Namespace}, secret)
 	if err != nil {
 		// secret not found -> keep doing business as usual
 		if errors.IsNotFound(err) {
-			return "", "", nil
+			return "", "", "", nil
 		}
-		return "", "", err
+		return "", "", "", err
 	}
 
-	apiURL, apiToken, err = decodeLoadBalancerAPIEmergencySecret(secret)
+	apiURL, apiToken, caCert, err = decodeLoadBalancerAPIEmergencySecret(secret)
 	if err != nil {
-		return "", "", fmt.Errorf("malformed secret %s: %w", LoadBalancerEmergencyAccessSecretName, err)
+		err = fmt.Errorf("malformed secret %s: %w", LoadBalancerEmergencyAccessSecretName, err)
+		if vp.configuration.MalformedEmergencyAccessSecretPolicy != config.MalformedEmergencyAccessSecretPolicyIgnore {
+			return "", "", "", err
+		}
+		// a bad emergency secret must not make things worse, fall back to the regular load balancer API access
+		return "", "", "", nil
 	}
 
-	return apiURL, apiToken, nil
+	return apiURL, apiToken, caCert, nil
 }
 
 // decodeLoadBalancerAPIEmergencySecret decodes a [corev1.Secret] for emergency loadbalancer access and
-// returns the apiURL and apiToken to use or an error.
+// returns the apiURL, apiToken and the optional caCert to use or an error.
 // The apiURL and apiToken are only set if both values exist inside the secret and are not empty.
-// In case the secret is malformed (wrong key names, empty values) an error is returned.
-func decodeLoadBalancerAPIEmergencySecret(secret *corev1.Secret) (apiURL string, apiToken string, err error) {
+// In case the secret is malformed (wrong key names, empty values, a CA certificate which is not PEM encoded) an error
+// is returned.
+func decodeLoadBalancerAPIEmergencySecret(secret *corev1.Secret) (apiURL, apiToken, caCert string, err error) {
 	existsNotEmpty := func(key string) (string, error) {
 		value, ok := secret.Data[key]
 		if !ok || len(value) == 0 {
@@ -1238,14 +1535,21 @@ func decodeLoadBalancerAPIEmergencySecret(secret *corev1.Secret) (apiURL string,
 
 	apiURL, err = existsNotEmpty(LoadBalancerEmergencyAccessAPIURLKey)
 	if err != nil {
-		return "", "", err
+		return "", "", "", err
 	}
 	apiToken, err = existsNotEmpty(LoadBalancerEmergencyAccessAPITokenKey)
 	if err != nil {
-		return "", "", err
+		return "", "", "", err
 	}
 
-	return apiURL, apiToken, nil
+	if value, ok := secret.Data[LoadBalancerEmergencyAccessAPICACertKey]; ok {
+		if !x509.NewCertPool().AppendCertsFromPEM(value) {
+			return "", "", "", fmt.Errorf("secret key %s does not contain a PEM encoded certificate", LoadBalancerEmergencyAccessAPICACertKey)
+		}
+		caCert = string(value)
+	}
+
+	return apiURL, apiToken, caCert, nil
 }
 
 func marshallNetworkProviderConfig(network *v1beta1.Networking) ([]byte, error) {
@@ -1263,6 +1567,24 @@ func getCSIDriver(cpConfig *stackitv1alpha1.ControlPlaneConfig) stackitv1alpha1.
 	return stackitv1alpha1.ControllerName(cpConfig.Storage.CSI.Name)
 }
 
+func isSnapshotControllerDisabled(cpConfig *stackitv1alpha1.ControlPlaneConfig) bool {
+	return cpConfig.Storage != nil && ptr.Deref(cpConfig.Storage.DisableSnapshotController, false)
+}
+
+// getCSIMaxEntries returns the configured maxEntries of the OpenStack CSI attacher. If it is not configured, it is
+// computed from the sum of the maxima of all worker pools, assuming csiMaxEntriesPerNode volume attachments per node.
+func getCSIMaxEntries(cpConfig *stackitv1alpha1.ControlPlaneConfig, cluster *extensionscontroller.Cluster) int32 {
+	if maxEntries := cpConfig.Storage.CSI.MaxEntries; maxEntries != nil {
+		return *maxEntries
+	}
+
+	var nodes int64
+	for _, pool := range cluster.Shoot.Spec.Provider.Workers {
+		nodes += int64(pool.Maximum)
+	}
+	return int32(min(max(nodes*csiMaxEntriesPerNode, minCSIMaxEntries), maxCSIMaxEntries))
+}
+
 func getCSICompatibilityMode(cpConfig *stackitv1alpha1.ControlPlaneConfig) stackitv1alpha1.CSICompatibilityMode {
 	return stackitv1alpha1.CSICompatibilityMode(cpConfig.Storage.CSI.CompatibilityMode)
 }
@@ -1278,8 +1600,25 @@ func isSTACKITOnly(cluster *extensionscontroller.Cluster, cpConfig *stackitv1alp
 		getCCMController(cpConfig) == stackitv1alpha1.STACKIT
 }
 
-func (vp *valuesProvider) getControlPlaneShootChartCSIValues(ctx context.Context, cpConfig *stackitv1alpha1.ControlPlaneConfig, cp *extensionsv1alpha1.ControlPlane, cluster *extensionscontroller.Cluster, cloudProfileConfig *stackitv1alpha1.CloudProfileConfig) map[string]any {
-	credentials, _ := vp.getCredentials(ctx, cp) // ignore missing credentials
+// rescanBlockStorageOnResizePools returns the names of the worker pools which override the RescanBlockStorageOnResize
+// of the CloudProfileConfig with the opposite value. The CSI node driver is deployed separately for the nodes of these
+// pools.
+func rescanBlockStorageOnResizePools(cluster *extensionscontroller.Cluster, cloudProfileConfig *stackitv1alpha1.CloudProfileConfig) ([]string, error) {
+	var pools []string
+	for _, pool := range cluster.Shoot.Spec.Provider.Workers {
+		workerConfig, err := helper.WorkerConfigFromRawExtension(pool.ProviderConfig)
+		if err != nil {
+			return nil, fmt.Errorf("could not decode the WorkerConfig of worker pool %s: %w", pool.Name, err)
+		}
+		if workerConfig.RescanBlockStorageOnResize != nil && *workerConfig.RescanBlockStorageOnResize != ptr.Deref(cloudProfileConfig.RescanBlockStorageOnResize, false) {
+			pools = append(pools, pool.Name)
+		}
+	}
+	return pools, nil
+}
+
+func (vp *valuesProvider) getControlPlaneShootChartCSIValues(ctx context.Context, cpConfig *stackitv1alpha1.ControlPlaneConfig, cp *extensionsv1alpha1.ControlPlane, cluster *extensionscontroller.Cluster, cloudProfileConfig *stackitv1alpha1.CloudProfileConfig, rescanPools []string) map[string]any {
+	credentials, _ := vp.getCredentials(ctx, cp) // missing credentials are logged with the control plane chart values
 	userAgentHeader := vp.getUserAgentHeaders(credentials, cluster)
 
 	values := map[string]any{
@@ -1289,6 +1628,9 @@ func (vp *valuesProvider) getControlPlaneShootChartCSIValues(ctx context.Context
 		"nodeVolumeAttachLimit": cloudProfileConfig.NodeVolumeAttachLimit,
 	}
 
+	if len(rescanPools) > 0 {
+		values["rescanBlockStorageOnResizePools"] = rescanPools
+	}
 	if userAgentHeader != nil {
 		values["userAgentHeaders"] = userAgentHeader
 	}
@@ -1296,8 +1638,8 @@ func (vp *valuesProvider) getControlPlaneShootChartCSIValues(ctx context.Context
 	return values
 }
 
-func (vp *valuesProvider) getControlPlaneShootChartCSISTACKITValues(ctx context.Context, cpConfig *stackitv1alpha1.ControlPlaneConfig, cp *extensionsv1alpha1.ControlPlane, cluster *extensionscontroller.Cluster, cloudProfileConfig *stackitv1alpha1.CloudProfileConfig) map[string]any {
-	credentials, _ := vp.getCredentials(ctx, cp) // ignore missing credentials
+func (vp *valuesProvider) getControlPlaneShootChartCSISTACKITValues(ctx context.Context, cpConfig *stackitv1alpha1.ControlPlaneConfig, cp *extensionsv1alpha1.ControlPlane, cluster *extensionscontroller.Cluster, cloudProfileConfig *stackitv1alpha1.CloudProfileConfig, rescanPools []string) map[string]any {
+	credentials, _ := vp.getCredentials(ctx, cp) // missing credentials are logged with the control plane chart values
 	userAgentHeader := vp.getUserAgentHeaders(credentials, cluster)
 
 	values := map[string]any{
@@ -1305,6 +1647,9 @@ func (vp *valuesProvider) getControlPlaneShootChartCSISTACKITValues(ctx context.
 		"rescanBlockStorageOnResize": cloudProfileConfig.RescanBlockStorageOnResize != nil && *cloudProfileConfig.RescanBlockStorageOnResize,
 	}
 
+	if len(rescanPools) > 0 {
+		values["rescanBlockStorageOnResizePools"] = rescanPools
+	}
 	if userAgentHeader != nil {
 		values["userAgentHeaders"] = userAgentHeader
 	}
@@ -5,36 +5,50 @@
 package controlplane
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"path/filepath"
 	"time"
 
 	calicov1alpha1 "github.com/gardener/gardener-extension-networking-calico/pkg/apis/calico/v1alpha1"
 	extensionscontroller "github.com/gardener/gardener/extensions/pkg/controller"
+	gardenv1beta1helper "github.com/gardener/gardener/pkg/api/core/v1beta1/helper"
 	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
 	v1beta1constants "github.com/gardener/gardener/pkg/apis/core/v1beta1/constants"
 	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"github.com/gardener/gardener/pkg/chartrenderer"
 	gardenerutils "github.com/gardener/gardener/pkg/utils"
 	"github.com/gardener/gardener/pkg/utils/chart"
 	secretutils "github.com/gardener/gardener/pkg/utils/secrets"
 	secretsmanager "github.com/gardener/gardener/pkg/utils/secrets/manager"
 	fakesecretsmanager "github.com/gardener/gardener/pkg/utils/secrets/manager/fake"
 	testutils "github.com/gardener/gardener/pkg/utils/test"
+	"github.com/go-logr/logr"
+	"github.com/go-logr/logr/funcr"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/version"
 	vpaautoscalingv1 "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/apis/autoscaling.k8s.io/v1"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/yaml"
 
+	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/charts"
+	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/apis/config"
 	stackitv1alpha1 "github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/apis/stackit/v1alpha1"
 	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/feature"
 	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/openstack"
@@ -64,7 +78,7 @@ func newTestScheme() *runtime.Scheme {
 
 func newTestValuesProvider(cl client.Client, scheme *runtime.Scheme, customLabelDomain string) *valuesProvider {
 	mgr := &testutils.FakeManager{Scheme: scheme, Client: cl}
-	return NewValuesProvider(mgr, customLabelDomain, new(noopCSICompatibilityHandler)).(*valuesProvider)
+	return NewValuesProvider(mgr, customLabelDomain, config.ControlPlaneControllerConfiguration{}, new(noopCSICompatibilityHandler)).(*valuesProvider)
 }
 
 func baseControlPlaneConfig() *stackitv1alpha1.ControlPlaneConfig {
@@ -284,6 +298,16 @@ func emergencyLBSecret() *corev1.Secret {
 	}
 }
 
+func caCertificatePEM() string {
+	caCert, err := (&secretutils.CertificateSecretConfig{
+		Name:       "lb-api-emergency-ca",
+		CommonName: "lb-api-emergency-ca",
+		CertType:   secretutils.CACert,
+	}).GenerateCertificate()
+	Expect(err).NotTo(HaveOccurred())
+	return string(caCert.CertificatePEM)
+}
+
 func encode(obj any) []byte {
 	data, err := json.Marshal(obj)
 	Expect(err).NotTo(HaveOccurred())
@@ -491,6 +515,43 @@ var _ = Describe("ValuesProvider fake client", func() {
 			Expect(values).To(Equal(expectedValues))
 		})
 
+		It("fails if the route controller is enabled but the infrastructure status contains no router", func() {
+			cp := baseControlPlane()
+			cp.Spec.InfrastructureProviderStatus.Raw = encode(&stackitv1alpha1.InfrastructureStatus{
+				Networks: stackitv1alpha1.NetworkStatus{
+					Name: technicalID,
+					ID:   "network-acbd1234",
+				},
+			})
+			cluster := clusterWithoutOverlay()
+			createObjects(ctx, c, baseProviderSecret())
+
+			_, err := vp.GetConfigChartValues(ctx, cp, cluster)
+			Expect(err).To(MatchError(ContainSubstring("does not contain a router ID")))
+			coder, ok := err.(gardenv1beta1helper.Coder)
+			Expect(ok).To(BeTrue())
+			Expect(coder.Codes()).To(ConsistOf(gardencorev1beta1.ErrorConfigurationProblem))
+		})
+
+		It("does not require a router if the route controller is disabled in the ControlPlaneConfig", func() {
+			cpConfig := baseControlPlaneConfig()
+			cpConfig.CloudControllerManager.RouteController = new(false)
+			cp := baseControlPlane()
+			cp.Spec.ProviderConfig.Raw = encode(cpConfig)
+			cp.Spec.InfrastructureProviderStatus.Raw = encode(&stackitv1alpha1.InfrastructureStatus{
+				Networks: stackitv1alpha1.NetworkStatus{
+					Name: technicalID,
+					ID:   "network-acbd1234",
+				},
+			})
+			cluster := clusterWithoutOverlay()
+			createObjects(ctx, c, baseProviderSecret())
+
+			values, err := vp.GetConfigChartValues(ctx, cp, cluster)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(values).To(Equal(expectedConfigChartValues()))
+		})
+
 		It("disables route controller when overlay is disabled but BGP backend is active", func() {
 			cp := baseControlPlane()
 			cluster := clusterWithoutOverlay()
@@ -504,6 +565,49 @@ var _ = Describe("ValuesProvider fake client", func() {
 			Expect(values).To(Equal(expectedConfigChartValues()))
 		})
 
+		It("keeps the detected route controller setting if the override matches it", func() {
+			cpConfig := baseControlPlaneConfig()
+			cpConfig.CloudControllerManager.RouteController = new(true)
+			cp := baseControlPlane()
+			cp.Spec.ProviderConfig.Raw = encode(cpConfig)
+			cluster := clusterWithoutOverlay()
+			createObjects(ctx, c, baseProviderSecret())
+
+			values, err := vp.GetConfigChartValues(ctx, cp, cluster)
+			Expect(err).NotTo(HaveOccurred())
+			expectedValues := expectedConfigChartValues()
+			expectedValues["routerID"] = "routerID"
+			Expect(values).To(Equal(expectedValues))
+		})
+
+		It("enables route controller if forced although the overlay is enabled", func() {
+			cpConfig := baseControlPlaneConfig()
+			cpConfig.CloudControllerManager.RouteController = new(true)
+			cp := baseControlPlane()
+			cp.Spec.ProviderConfig.Raw = encode(cpConfig)
+			cluster := baseCluster()
+			createObjects(ctx, c, baseProviderSecret())
+
+			values, err := vp.GetConfigChartValues(ctx, cp, cluster)
+			Expect(err).NotTo(HaveOccurred())
+			expectedValues := expectedConfigChartValues()
+			expectedValues["routerID"] = "routerID"
+			Expect(values).To(Equal(expectedValues))
+		})
+
+		It("disables route controller if forced although the overlay is disabled", func() {
+			cpConfig := baseControlPlaneConfig()
+			cpConfig.CloudControllerManager.RouteController = new(false)
+			cp := baseControlPlane()
+			cp.Spec.ProviderConfig.Raw = encode(cpConfig)
+			cluster := clusterWithoutOverlay()
+			createObjects(ctx, c, baseProviderSecret())
+
+			values, err := vp.GetConfigChartValues(ctx, cp, cluster)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(values).To(Equal(expectedConfigChartValues()))
+		})
+
 		It("propagates a custom keystone CA certificate", func() {
 			cp := baseControlPlane()
 			cluster := baseCluster()
@@ -529,8 +633,29 @@ var _ = Describe("ValuesProvider fake client", func() {
 			values, err := vp.GetConfigChartValues(ctx, cp, cluster)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(values).To(Equal(map[string]any{
-				"stackitonly": true,
+				"stackitOnly": true,
+			}))
+		})
+
+		It("returns only the stackit-only marker if the secret contains OpenStack credentials", func() {
+			cp := baseControlPlane()
+			cluster := baseCluster()
+			cluster.Shoot.Annotations = map[string]string{
+				feature.ShootUseSTACKITAPIInfrastructureController: "true",
+				feature.ShootUseSTACKITMachineControllerManager:    "true",
+			}
+			providerSecret := baseProviderSecret()
+			providerSecret.Data["caCert"] = []byte("custom-cert")
+			createObjects(ctx, c, providerSecret)
+
+			values, err := vp.GetConfigChartValues(ctx, cp, cluster)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(values).To(Equal(map[string]any{
+				"stackitOnly": true,
 			}))
+			for key := range expectedConfigChartValues() {
+				Expect(values).NotTo(HaveKey(key))
+			}
 		})
 	})
 
@@ -583,10 +708,12 @@ var _ = Describe("ValuesProvider fake client", func() {
 					"checksum/secret-" + v1beta1constants.SecretNameCloudProvider: gardenerutils.ComputeChecksum(providerSecret.Data),
 				},
 				"csiSnapshotController": map[string]any{
+					"enabled":  true,
 					"replicas": 1,
 				},
 				"stackitEndpoints":  map[string]string{},
 				"customLabelDomain": "kubernetes.io",
+				"clusterLabelValue": technicalID,
 				"userAgentHeaders":  expectedUserAgentHeaders(),
 			}))
 
@@ -600,6 +727,85 @@ var _ = Describe("ValuesProvider fake client", func() {
 			Expect(values[openstack.STACKITApplicationLoadBalancerControllerName]).To(BeNil())
 		})
 
+		It("returns STACKIT-only control plane values without the cloud provider config secrets", func() {
+			cp := baseControlPlane()
+			cluster := baseCluster()
+			cluster.Shoot.Annotations = map[string]string{
+				feature.ShootUseSTACKITAPIInfrastructureController: "true",
+				feature.ShootUseSTACKITMachineControllerManager:    "true",
+			}
+			providerSecret := baseProviderSecret()
+			createObjects(ctx, c, providerSecret)
+			createObjects(ctx, c, managedSecrets()...)
+
+			checksums := checksumsFor(providerSecret)
+			values, err := vp.GetControlPlaneChartValues(ctx, cp, cluster, secretsManager, checksums, false)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(checksums).NotTo(HaveKey(openstack.CloudProviderConfigName))
+			Expect(checksums).NotTo(HaveKey(openstack.CloudProviderCSIDiskConfigName))
+			Expect(chartValues(values, openstack.STACKITCloudControllerManagerName)).To(HaveKeyWithValue("enabled", true))
+			Expect(chartValues(values, openstack.CSISTACKITControllerName)).To(HaveKeyWithValue("podAnnotations", map[string]any{
+				"checksum/secret-" + v1beta1constants.SecretNameCloudProvider: gardenerutils.ComputeChecksum(providerSecret.Data),
+			}))
+		})
+
+		It("passes the configured image pull policy to all control plane components", func() {
+			vp.configuration.ImagePullPolicy = corev1.PullAlways
+			cp, cluster, providerSecret, _ := seedReadyControlPlane(ctx, c)
+
+			values, err := vp.GetControlPlaneChartValues(ctx, cp, cluster, secretsManager, checksumsFor(providerSecret), false)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(values).To(HaveKeyWithValue("global", map[string]any{
+				"genericTokenKubeconfigSecretName": genericTokenKubeconfigSecretName,
+				"imagePullPolicy":                  corev1.PullAlways,
+			}))
+		})
+
+		It("configures the emergency load balancer API with its CA certificate", func() {
+			cp, cluster, providerSecret, _ := seedReadyControlPlane(ctx, c)
+			caCert := caCertificatePEM()
+			secret := emergencyLBSecret()
+			secret.Data[LoadBalancerEmergencyAccessAPICACertKey] = []byte(caCert)
+			createObjects(ctx, c, secret)
+
+			values, err := vp.GetControlPlaneChartValues(ctx, cp, cluster, secretsManager, checksumsFor(providerSecret), false)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(chartValues(values, openstack.STACKITCloudControllerManagerName)["config"]).To(SatisfyAll(
+				HaveKeyWithValue("loadBalancerApiUrl", "foo"),
+				HaveKeyWithValue("loadBalancerEmergencyToken", "bar"),
+				HaveKeyWithValue("loadBalancerApiCaCert", caCert),
+			))
+		})
+
+		It("logs missing OpenStack credentials once if the OpenStack CSI driver is used", func() {
+			cp := baseControlPlane()
+			cluster := baseCluster()
+			providerSecret := baseProviderSecret()
+			delete(providerSecret.Data, "domainName")
+			delete(providerSecret.Data, "tenantName")
+			createObjects(ctx, c, providerSecret, baseCloudProviderConfigSecret(), baseCSIDiskConfigSecret())
+			createObjects(ctx, c, managedSecrets()...)
+			cpConfig := baseControlPlaneConfig()
+			cpConfig.Storage.CSI.Name = string(stackitv1alpha1.OPENSTACK)
+			cp.Spec.ProviderConfig.Raw = encode(cpConfig)
+
+			var logs []string
+			logCtx := logr.NewContext(ctx, funcr.New(func(_, args string) { logs = append(logs, args) }, funcr.Options{}))
+
+			_, err := vp.GetControlPlaneChartValues(logCtx, cp, cluster, secretsManager, checksumsFor(providerSecret), false)
+			Expect(err).NotTo(HaveOccurred())
+			values, err := vp.GetControlPlaneShootChartValues(logCtx, cp, cluster, secretsManager, map[string]string{})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(chartValues(values, openstack.CSINodeName)).To(HaveKeyWithValue("userAgentHeaders", []string{technicalID}))
+			var missingCredentialsLogs []string
+			Expect(logs).To(ContainElement(ContainSubstring("OpenStack credentials are missing in the cloudprovider secret"), &missingCredentialsLogs))
+			Expect(missingCredentialsLogs).To(HaveLen(1))
+		})
+
 		It("returns OpenStack CSI values when selected", func() {
 			cp, cluster, providerSecret, diskSecret := seedReadyControlPlane(ctx, c)
 			cpConfig := baseControlPlaneConfig()
@@ -621,12 +827,33 @@ var _ = Describe("ValuesProvider fake client", func() {
 					"checksum/secret-" + openstack.CloudProviderCSIDiskConfigName: gardenerutils.ComputeChecksum(diskSecret.Data),
 				},
 				"csiSnapshotController": map[string]any{
+					"enabled":  true,
 					"replicas": 1,
 				},
 				"userAgentHeaders": expectedUserAgentHeaders(),
 			}))
 		})
 
+		DescribeTable("disables the csi-snapshot-controller when configured",
+			func(csiDriver stackitv1alpha1.ControllerName, chartName string) {
+				cp, cluster, providerSecret, _ := seedReadyControlPlane(ctx, c)
+				cpConfig := baseControlPlaneConfig()
+				cpConfig.Storage.CSI.Name = string(csiDriver)
+				cpConfig.Storage.DisableSnapshotController = new(true)
+				cp.Spec.ProviderConfig.Raw = encode(cpConfig)
+
+				values, err := vp.GetControlPlaneChartValues(ctx, cp, cluster, secretsManager, checksumsFor(providerSecret), false)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(chartValues(values, chartName)).To(HaveKeyWithValue("csiSnapshotController", map[string]any{
+					"enabled":  false,
+					"replicas": 1,
+				}))
+			},
+			Entry("OpenStack CSI", stackitv1alpha1.OPENSTACK, openstack.CSIControllerName),
+			Entry("STACKIT CSI", stackitv1alpha1.STACKIT, openstack.CSISTACKITControllerName),
+		)
+
 		It("enables OpenStack CCM while reducing STACKIT CCM controllers", func() {
 			cp, cluster, providerSecret, _ := seedReadyControlPlane(ctx, c)
 			cpConfig := baseControlPlaneConfig()
@@ -641,6 +868,96 @@ var _ = Describe("ValuesProvider fake client", func() {
 			Expect(chartValues(values, openstack.CSIControllerName)).To(HaveKeyWithValue("enabled", false))
 		})
 
+		DescribeTable("passes the pod networks to the OpenStack CCM",
+			func(specPods *string, statusPods []string, expected string) {
+				cp, cluster, providerSecret, _ := seedReadyControlPlane(ctx, c)
+				cluster.Shoot.Spec.Networking.Pods = specPods
+				if statusPods != nil {
+					cluster.Shoot.Status.Networking = &gardencorev1beta1.NetworkingStatus{Pods: statusPods}
+				}
+
+				values, err := vp.GetControlPlaneChartValues(ctx, cp, cluster, secretsManager, checksumsFor(providerSecret), false)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(chartValues(values, openstack.CloudControllerManagerName)).To(HaveKeyWithValue("podNetwork", expected))
+			},
+			Entry("single-stack", new("10.250.0.0/19"), nil, "10.250.0.0/19"),
+			Entry("dual-stack", new("10.250.0.0/19"), []string{"10.250.0.0/19", "2001:db8::/64"}, "10.250.0.0/19,2001:db8::/64"),
+			Entry("dual-stack with the IPv6 CIDR first", nil, []string{"2001:db8::/64", "10.250.0.0/19"}, "10.250.0.0/19,2001:db8::/64"),
+		)
+
+		It("fails for an invalid pod network", func() {
+			cp, cluster, providerSecret, _ := seedReadyControlPlane(ctx, c)
+			cluster.Shoot.Spec.Networking.Pods = new("invalid")
+
+			_, err := vp.GetControlPlaneChartValues(ctx, cp, cluster, secretsManager, checksumsFor(providerSecret), false)
+			Expect(err).To(MatchError(ContainSubstring(`invalid pod network CIDR "invalid"`)))
+		})
+
+		It("places the load balancers of the STACKIT CCM in the configured network", func() {
+			cp, cluster, providerSecret, _ := seedReadyControlPlane(ctx, c)
+			cpConfig := baseControlPlaneConfig()
+			cpConfig.CloudControllerManager.ServiceLoadBalancerNetworkID = new("lb-network")
+			cp.Spec.ProviderConfig.Raw = encode(cpConfig)
+
+			values, err := vp.GetControlPlaneChartValues(ctx, cp, cluster, secretsManager, checksumsFor(providerSecret), false)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(chartValues(values, openstack.STACKITCloudControllerManagerName)["config"]).To(HaveKeyWithValue("stackitNetworkID", "lb-network"))
+		})
+
+		DescribeTable("scales all control plane components of hibernated shoots",
+			func(controllerName stackitv1alpha1.ControllerName, ccmChartName, csiChartName string, scaledDown bool, expectedReplicas int) {
+				cp, cluster, providerSecret, _ := seedReadyControlPlane(ctx, c)
+				cluster.Shoot.Spec.Hibernation = &gardencorev1beta1.Hibernation{Enabled: new(true)}
+				cpConfig := baseControlPlaneConfig()
+				cpConfig.CloudControllerManager.Name = string(controllerName)
+				cpConfig.Storage.CSI.Name = string(controllerName)
+				cpConfig.ApplicationLoadBalancer = &stackitv1alpha1.ApplicationLoadBalancerConfig{Enabled: true}
+				cp.Spec.ProviderConfig.Raw = encode(cpConfig)
+
+				values, err := vp.GetControlPlaneChartValues(ctx, cp, cluster, secretsManager, checksumsFor(providerSecret), scaledDown)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(chartValues(values, ccmChartName)).To(HaveKeyWithValue("replicas", expectedReplicas))
+				Expect(chartValues(values, openstack.STACKITCloudControllerManagerName)).To(HaveKeyWithValue("replicas", expectedReplicas))
+				csiValues := chartValues(values, csiChartName)
+				Expect(csiValues).To(HaveKeyWithValue("replicas", expectedReplicas))
+				Expect(csiValues).To(HaveKeyWithValue("csiSnapshotController", map[string]any{
+					"enabled":  true,
+					"replicas": expectedReplicas,
+				}))
+				Expect(chartValues(values, openstack.STACKITApplicationLoadBalancerControllerName)).To(HaveKeyWithValue("replicas", expectedReplicas))
+				Expect(chartValues(values, stackit.PodIdentityWebhookName)).To(HaveKeyWithValue("replicaCount", expectedReplicas))
+			},
+			Entry("STACKIT components, scaled down", stackitv1alpha1.STACKIT, openstack.STACKITCloudControllerManagerName, openstack.CSISTACKITControllerName, true, 0),
+			Entry("STACKIT components, not yet scaled down", stackitv1alpha1.STACKIT, openstack.STACKITCloudControllerManagerName, openstack.CSISTACKITControllerName, false, 1),
+			Entry("OpenStack components, scaled down", stackitv1alpha1.OPENSTACK, openstack.CloudControllerManagerName, openstack.CSIControllerName, true, 0),
+			Entry("OpenStack components, not yet scaled down", stackitv1alpha1.OPENSTACK, openstack.CloudControllerManagerName, openstack.CSIControllerName, false, 1),
+		)
+
+		It("passes CCM extra args to both cloud-controller-managers", func() {
+			cp, cluster, providerSecret, _ := seedReadyControlPlane(ctx, c)
+			cpConfig := baseControlPlaneConfig()
+			cpConfig.CloudControllerManager.ExtraArgs = []string{"--concurrent-service-syncs=5", "--v=4"}
+			cp.Spec.ProviderConfig.Raw = encode(cpConfig)
+
+			values, err := vp.GetControlPlaneChartValues(ctx, cp, cluster, secretsManager, checksumsFor(providerSecret), false)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(chartValues(values, openstack.CloudControllerManagerName)).To(HaveKeyWithValue("extraArgs", cpConfig.CloudControllerManager.ExtraArgs))
+			Expect(chartValues(values, openstack.STACKITCloudControllerManagerName)).To(HaveKeyWithValue("extraArgs", cpConfig.CloudControllerManager.ExtraArgs))
+		})
+
+		It("omits CCM extra args when none are configured", func() {
+			cp, cluster, providerSecret, _ := seedReadyControlPlane(ctx, c)
+
+			values, err := vp.GetControlPlaneChartValues(ctx, cp, cluster, secretsManager, checksumsFor(providerSecret), false)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(chartValues(values, openstack.CloudControllerManagerName)).NotTo(HaveKey("extraArgs"))
+			Expect(chartValues(values, openstack.STACKITCloudControllerManagerName)).NotTo(HaveKey("extraArgs"))
+		})
+
 		DescribeTable("renders STACKIT CCM config variants",
 			func(apiEndpoints *stackitv1alpha1.APIEndpoints, cpConfig *stackitv1alpha1.ControlPlaneConfig, expectedControllers []string) {
 				cp, cluster, providerSecret, _ := seedReadyControlPlane(ctx, c)
@@ -685,22 +1002,88 @@ var _ = Describe("ValuesProvider fake client", func() {
 			),
 		)
 
+		It("uses the allowed IaaS endpoint of the InfrastructureConfig for the CCM and the CSI driver", func() {
+			cp, cluster, providerSecret, _ := seedReadyControlPlane(ctx, c)
+
+			cloudProfileConfig := baseCloudProfileConfig()
+			cloudProfileConfig.APIEndpoints = &stackitv1alpha1.APIEndpoints{IaaS: new("https://iaas.stackit.cloud")}
+			cloudProfileConfig.AllowedIaaSEndpoints = []string{"https://iaas.staging.stackit.cloud"}
+			cluster.CloudProfile.Spec.ProviderConfig = &runtime.RawExtension{Raw: encode(cloudProfileConfig)}
+			cluster.Shoot.Spec.Provider.InfrastructureConfig = &runtime.RawExtension{Raw: encode(&stackitv1alpha1.InfrastructureConfig{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: stackitv1alpha1.SchemeGroupVersion.String(),
+					Kind:       "InfrastructureConfig",
+				},
+				Networks: stackitv1alpha1.Networks{
+					Workers: "10.200.0.0/19",
+				},
+				IaaSEndpoint: new("https://iaas.staging.stackit.cloud"),
+			})}
+
+			values, err := vp.GetControlPlaneChartValues(ctx, cp, cluster, secretsManager, checksumsFor(providerSecret), false)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(chartValues(values, openstack.STACKITCloudControllerManagerName)).To(HaveKeyWithValue("config", HaveKeyWithValue("iaasApiUrl", "https://iaas.staging.stackit.cloud")))
+			Expect(chartValues(values, openstack.CSISTACKITControllerName)).To(HaveKeyWithValue("stackitEndpoints", HaveKeyWithValue("iaasUrl", "https://iaas.staging.stackit.cloud")))
+		})
+
 		DescribeTable("propagates custom label domains",
-			func(customLabelDomain string) {
+			func(customLabelDomain string, shootLabelDomain *string, expected string) {
 				vp = newTestValuesProvider(c, scheme, customLabelDomain)
 				cp, cluster, providerSecret, _ := seedReadyControlPlane(ctx, c)
+				cpConfig := baseControlPlaneConfig()
+				cpConfig.CustomLabelDomain = shootLabelDomain
+				cp.Spec.ProviderConfig.Raw = encode(cpConfig)
 
 				values, err := vp.GetControlPlaneChartValues(ctx, cp, cluster, secretsManager, checksumsFor(providerSecret), false)
 				Expect(err).NotTo(HaveOccurred())
 
 				stackitCCMConfig := chartValues(values, openstack.STACKITCloudControllerManagerName)["config"].(map[string]any)
-				Expect(stackitCCMConfig).To(HaveKeyWithValue("customLabelDomain", customLabelDomain))
-				Expect(chartValues(values, openstack.CSISTACKITControllerName)).To(HaveKeyWithValue("customLabelDomain", customLabelDomain))
+				Expect(stackitCCMConfig).To(HaveKeyWithValue("customLabelDomain", expected))
+				Expect(chartValues(values, openstack.CSISTACKITControllerName)).To(HaveKeyWithValue("customLabelDomain", expected))
 				Expect(chartValues(values, openstack.CSIControllerName)).NotTo(HaveKey("customLabelDomain"))
 			},
-			Entry("default kubernetes.io domain", "kubernetes.io"),
-			Entry("custom ske.stackit.cloud domain", "ske.stackit.cloud"),
-			Entry("custom example.com domain", "example.com"),
+			Entry("default kubernetes.io domain", "kubernetes.io", nil, "kubernetes.io"),
+			Entry("custom ske.stackit.cloud domain", "ske.stackit.cloud", nil, "ske.stackit.cloud"),
+			Entry("custom example.com domain", "example.com", nil, "example.com"),
+			Entry("domain of the ControlPlaneConfig", "kubernetes.io", new("customer.example.com"), "customer.example.com"),
+		)
+
+		DescribeTable("uses the configured cluster label value source",
+			func(source config.ClusterLabelValueSource, expected string) {
+				vp.configuration.ClusterLabelValueSource = source
+				cp, cluster, providerSecret, _ := seedReadyControlPlane(ctx, c)
+				cluster.Shoot.UID = "shoot-uid"
+				cpConfig := baseControlPlaneConfig()
+				cpConfig.ApplicationLoadBalancer = &stackitv1alpha1.ApplicationLoadBalancerConfig{Enabled: true}
+				cp.Spec.ProviderConfig.Raw = encode(cpConfig)
+
+				values, err := vp.GetControlPlaneChartValues(ctx, cp, cluster, secretsManager, checksumsFor(providerSecret), false)
+				Expect(err).NotTo(HaveOccurred())
+
+				stackitCCMValues := chartValues(values, openstack.STACKITCloudControllerManagerName)
+				Expect(stackitCCMValues).To(HaveKeyWithValue("technicalID", technicalID))
+				Expect(stackitCCMValues["config"]).To(HaveKeyWithValue("extraLabels", map[string]string{STACKITLBClusterLabelKey: expected}))
+				Expect(chartValues(values, openstack.CSISTACKITControllerName)).To(HaveKeyWithValue("clusterLabelValue", expected))
+				albConfig := chartValues(values, openstack.STACKITApplicationLoadBalancerControllerName)["config"].(map[string]any)
+				Expect(albConfig["applicationLoadBalancer"]).To(HaveKeyWithValue("extraLabels", map[string]string{STACKITALBClusterLabelKey: expected}))
+			},
+			Entry("default", config.ClusterLabelValueSource(""), technicalID),
+			Entry("technical ID", config.ClusterLabelValueSourceTechnicalID, technicalID),
+			Entry("shoot UID", config.ClusterLabelValueSourceShootUID, "shoot-uid"),
+		)
+
+		DescribeTable("fails if the configured cluster label value source is not available",
+			func(source config.ClusterLabelValueSource, mutate func(*extensionscontroller.Cluster)) {
+				vp.configuration.ClusterLabelValueSource = source
+				cp, cluster, providerSecret, _ := seedReadyControlPlane(ctx, c)
+				mutate(cluster)
+
+				_, err := vp.GetControlPlaneChartValues(ctx, cp, cluster, secretsManager, checksumsFor(providerSecret), false)
+				Expect(err).To(MatchError(ContainSubstring("cluster label value source " + string(source) + " is not available")))
+			},
+			Entry("technical ID", config.ClusterLabelValueSourceTechnicalID, func(cluster *extensionscontroller.Cluster) { cluster.Shoot.Status.TechnicalID = "" }),
+			Entry("shoot UID", config.ClusterLabelValueSourceShootUID, func(cluster *extensionscontroller.Cluster) { cluster.Shoot.UID = "" }),
 		)
 
 		It("returns ALB controller values when enabled", func() {
@@ -740,6 +1123,95 @@ var _ = Describe("ValuesProvider fake client", func() {
 			}))
 		})
 
+		It("adds configured scheduling values to all enabled control plane components", func() {
+			nodeSelector := map[string]string{"worker.gardener.cloud/pool": "control-plane"}
+			tolerations := []corev1.Toleration{{
+				Key:      "dedicated",
+				Operator: corev1.TolerationOpEqual,
+				Value:    "control-plane",
+				Effect:   corev1.TaintEffectNoSchedule,
+			}}
+			vp.configuration = config.ControlPlaneControllerConfiguration{
+				NodeSelector: nodeSelector,
+				Tolerations:  tolerations,
+			}
+			cp, cluster, providerSecret, _ := seedReadyControlPlane(ctx, c)
+			cpConfig := baseControlPlaneConfig()
+			cpConfig.ApplicationLoadBalancer = &stackitv1alpha1.ApplicationLoadBalancerConfig{Enabled: true}
+			cp.Spec.ProviderConfig.Raw = encode(cpConfig)
+
+			values, err := vp.GetControlPlaneChartValues(ctx, cp, cluster, secretsManager, checksumsFor(providerSecret), false)
+			Expect(err).NotTo(HaveOccurred())
+
+			for _, name := range []string{
+				openstack.CloudControllerManagerName,
+				openstack.STACKITCloudControllerManagerName,
+				openstack.CSISTACKITControllerName,
+				openstack.STACKITApplicationLoadBalancerControllerName,
+				stackit.PodIdentityWebhookName,
+			} {
+				Expect(chartValues(values, name)).To(HaveKeyWithValue("nodeSelector", nodeSelector), name)
+				Expect(chartValues(values, name)).To(HaveKeyWithValue("tolerations", tolerations), name)
+			}
+			Expect(chartValues(values, openstack.CSIControllerName)).NotTo(HaveKey("nodeSelector"))
+		})
+
+		It("adds the configured metrics endpoint to the STACKIT control plane components", func() {
+			vp.configuration = config.ControlPlaneControllerConfiguration{
+				MetricsBindAddress:                "::",
+				CloudControllerManagerMetricsPort: new(int32(9091)),
+				CSIControllerMetricsPort:          new(int32(9092)),
+				ServiceMonitors:                   true,
+			}
+			cp, cluster, providerSecret, _ := seedReadyControlPlane(ctx, c)
+
+			values, err := vp.GetControlPlaneChartValues(ctx, cp, cluster, secretsManager, checksumsFor(providerSecret), false)
+			Expect(err).NotTo(HaveOccurred())
+
+			ccmValues := chartValues(values, openstack.STACKITCloudControllerManagerName)
+			Expect(ccmValues["config"]).To(HaveKeyWithValue("metricsBindAddress", "[::]"))
+			Expect(ccmValues["config"]).To(HaveKeyWithValue("metricsPort", int32(9091)))
+			csiValues := chartValues(values, openstack.CSISTACKITControllerName)
+			Expect(csiValues["config"]).To(HaveKeyWithValue("metricsBindAddress", "[::]"))
+			Expect(csiValues["config"]).To(HaveKeyWithValue("metricsPort", int32(9092)))
+
+			By("not deploying ServiceMonitors without the ServiceMonitor CRD")
+			Expect(ccmValues).NotTo(HaveKey("serviceMonitor"))
+			Expect(csiValues).NotTo(HaveKey("serviceMonitor"))
+		})
+
+		Context("with the ServiceMonitor CRD", func() {
+			BeforeEach(func() {
+				utilruntime.Must(monitoringv1.AddToScheme(scheme))
+				c = fake.NewClientBuilder().WithScheme(scheme).Build()
+				secretsManager = fakesecretsmanager.New(c, namespace)
+				vp = newTestValuesProvider(c, scheme, "kubernetes.io")
+			})
+
+			It("deploys ServiceMonitors if enabled", func() {
+				vp.configuration.ServiceMonitors = true
+				cp, cluster, providerSecret, _ := seedReadyControlPlane(ctx, c)
+
+				values, err := vp.GetControlPlaneChartValues(ctx, cp, cluster, secretsManager, checksumsFor(providerSecret), false)
+				Expect(err).NotTo(HaveOccurred())
+
+				for _, name := range []string{openstack.STACKITCloudControllerManagerName, openstack.CSISTACKITControllerName} {
+					Expect(chartValues(values, name)).To(HaveKeyWithValue("serviceMonitor", map[string]any{"enabled": true}), name)
+				}
+			})
+
+			It("does not deploy ServiceMonitors if disabled", func() {
+				cp, cluster, providerSecret, _ := seedReadyControlPlane(ctx, c)
+
+				values, err := vp.GetControlPlaneChartValues(ctx, cp, cluster, secretsManager, checksumsFor(providerSecret), false)
+				Expect(err).NotTo(HaveOccurred())
+
+				for _, name := range []string{openstack.STACKITCloudControllerManagerName, openstack.CSISTACKITControllerName} {
+					Expect(chartValues(values, name)).NotTo(HaveKey("serviceMonitor"), name)
+				}
+			})
+		})
+
 		It("omits ALB controller values when the alb is ALB deployment", func() {
 			vp = newTestValuesProvider(c, scheme, "kubernetes.io")
 			cp, cluster, providerSecret, _ := seedReadyControlPlane(ctx, c)
@@ -753,6 +1225,37 @@ var _ = Describe("ValuesProvider fake client", func() {
 		})
 	})
 
+	DescribeTable("#getCSIMaxEntries",
+		func(configured *int32, poolMaxima []int32, expected int32) {
+			cpConfig := baseControlPlaneConfig()
+			cpConfig.Storage.CSI = &stackitv1alpha1.CSI{Name: string(stackitv1alpha1.OPENSTACK), MaxEntries: configured}
+			cluster := baseCluster()
+			cluster.Shoot.Spec.Provider.Workers = nil
+			for i, maximum := range poolMaxima {
+				cluster.Shoot.Spec.Provider.Workers = append(cluster.Shoot.Spec.Provider.Workers, gardencorev1beta1.Worker{
+					Name:    fmt.Sprintf("pool%d", i),
+					Maximum: maximum,
+				})
+			}
+			Expect(getCSIMaxEntries(cpConfig, cluster)).To(Equal(expected))
+		},
+		Entry("small cluster", nil, []int32{3, 5}, int32(1000)),
+		Entry("large cluster", nil, []int32{200, 150}, int32(3500)),
+		Entry("very large cluster", nil, []int32{800, 600}, int32(10000)),
+		Entry("configured value", new(int32(500)), []int32{200, 150}, int32(500)),
+	)
+
+	DescribeTable("#DeploySTACKITApplicationLoadBalancer",
+		func(applicationLoadBalancer *stackitv1alpha1.ApplicationLoadBalancerConfig, expected bool) {
+			cpConfig := baseControlPlaneConfig()
+			cpConfig.ApplicationLoadBalancer = applicationLoadBalancer
+			Expect(DeploySTACKITApplicationLoadBalancer(cpConfig)).To(Equal(expected))
+		},
+		Entry("not configured", nil, false),
+		Entry("disabled", &stackitv1alpha1.ApplicationLoadBalancerConfig{Enabled: false}, false),
+		Entry("enabled", &stackitv1alpha1.ApplicationLoadBalancerConfig{Enabled: true}, true),
+	)
+
 	Describe("#GetControlPlaneShootChartValues", func() {
 		It("returns OpenStack shoot chart values and deletes unused STACKIT CSI control-plane objects", func() {
 			cp, cluster := seedReadyShoot(ctx, c)
@@ -805,6 +1308,137 @@ var _ = Describe("ValuesProvider fake client", func() {
 			}))
 			expectObjectsDeleted(ctx, c, unusedObjects...)
 		})
+
+		DescribeTable("keeps the resources of the csi-snapshot-validation which are not part of the control plane chart",
+			func(csiDriver stackitv1alpha1.ControllerName) {
+				cp, cluster := seedReadyShoot(ctx, c)
+				cpConfig := baseControlPlaneConfig()
+				cpConfig.Storage.CSI.Name = string(csiDriver)
+				cp.Spec.ProviderConfig.Raw = encode(cpConfig)
+				snapshotValidation := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: openstack.CSISnapshotValidationName, Namespace: namespace}}
+				createObjects(ctx, c, snapshotValidation)
+
+				_, err := vp.GetControlPlaneShootChartValues(ctx, cp, cluster, secretsManager, map[string]string{})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(c.Get(ctx, client.ObjectKeyFromObject(snapshotValidation), &appsv1.Deployment{})).To(Succeed())
+			},
+			Entry("OpenStack CSI", stackitv1alpha1.OPENSTACK),
+			Entry("STACKIT CSI", stackitv1alpha1.STACKIT),
+		)
+
+		It("keeps the cloud-provider-config secret of the STACKIT CSI driver in use", func() {
+			cp, cluster := seedReadyShoot(ctx, c)
+			configSecret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: CSIStackitPrefix + "-cloud-provider-config", Namespace: namespace}}
+			createObjects(ctx, c, configSecret)
+
+			for range 2 {
+				_, err := vp.GetControlPlaneShootChartValues(ctx, cp, cluster, secretsManager, map[string]string{})
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			Expect(c.Get(ctx, client.ObjectKeyFromObject(configSecret), &corev1.Secret{})).To(Succeed())
+		})
+
+		Context("rescanBlockStorageOnResize of worker pools", func() {
+			workerConfig := func(rescan *bool) *runtime.RawExtension {
+				return &runtime.RawExtension{Raw: encode(&stackitv1alpha1.WorkerConfig{
+					TypeMeta: metav1.TypeMeta{
+						APIVersion: stackitv1alpha1.SchemeGroupVersion.String(),
+						Kind:       "WorkerConfig",
+					},
+					RescanBlockStorageOnResize: rescan,
+				})}
+			}
+
+			It("passes the pools overriding the cloud profile value to the CSI node charts", func() {
+				cp, cluster := seedReadyShoot(ctx, c)
+				cluster.Shoot.Spec.Provider.Workers = []gardencorev1beta1.Worker{
+					{Name: "default"},
+					{Name: "same", ProviderConfig: workerConfig(new(true))},
+					{Name: "override", ProviderConfig: workerConfig(new(false))},
+				}
+
+				values, err := vp.GetControlPlaneShootChartValues(ctx, cp, cluster, secretsManager, map[string]string{})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(chartValues(values, openstack.CSISTACKITNodeName)).To(HaveKeyWithValue("rescanBlockStorageOnResize", true))
+				Expect(chartValues(values, openstack.CSISTACKITNodeName)).To(HaveKeyWithValue("rescanBlockStorageOnResizePools", []string{"override"}))
+			})
+
+			It("does not pass pools if no pool overrides the cloud profile value", func() {
+				cp, cluster := seedReadyShoot(ctx, c)
+				cluster.Shoot.Spec.Provider.Workers = []gardencorev1beta1.Worker{
+					{Name: "default"},
+					{Name: "same", ProviderConfig: workerConfig(new(true))},
+				}
+
+				values, err := vp.GetControlPlaneShootChartValues(ctx, cp, cluster, secretsManager, map[string]string{})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(chartValues(values, openstack.CSISTACKITNodeName)).NotTo(HaveKey("rescanBlockStorageOnResizePools"))
+			})
+
+			It("renders a CSI node DaemonSet for the overriding pools which does not overlap with the main DaemonSet", func() {
+				renderer := chartrenderer.NewWithServerVersion(&version.Info{GitVersion: "v1.33.0"})
+				rendered, err := renderer.RenderEmbeddedFS(charts.InternalChart, filepath.Join(charts.InternalChartsPath, "shoot-system-components", "charts", "stackit-blockstorage-csi-driver"), "stackit-blockstorage-csi-driver", metav1.NamespaceSystem, map[string]any{
+					"rescanBlockStorageOnResize":      true,
+					"rescanBlockStorageOnResizePools": []string{"override"},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				var daemonSets []*appsv1.DaemonSet
+				for doc := range bytes.SplitSeq(rendered.Manifest(), []byte("\n---")) {
+					if bytes.Contains(doc, []byte("kind: DaemonSet")) {
+						ds := &appsv1.DaemonSet{}
+						Expect(yaml.Unmarshal(doc, ds)).To(Succeed())
+						daemonSets = append(daemonSets, ds)
+					}
+				}
+				Expect(daemonSets).To(HaveLen(2))
+
+				for _, ds := range daemonSets {
+					selector, err := metav1.LabelSelectorAsSelector(ds.Spec.Selector)
+					Expect(err).NotTo(HaveOccurred())
+					for _, other := range daemonSets {
+						Expect(selector.Matches(labels.Set(other.Spec.Template.Labels))).To(Equal(ds == other), "selector of %s matching pods of %s", ds.Name, other.Name)
+					}
+				}
+			})
+
+			It("fails if the WorkerConfig of a pool cannot be decoded", func() {
+				cp, cluster := seedReadyShoot(ctx, c)
+				cluster.Shoot.Spec.Provider.Workers = []gardencorev1beta1.Worker{
+					{Name: "invalid", ProviderConfig: &runtime.RawExtension{Raw: []byte(`{"apiVersion":"stackit.provider.extensions.gardener.cloud/v1alpha1","kind":"WorkerConfig","rescanBlockStorageOnResize":"yes"}`)}},
+				}
+
+				_, err := vp.GetControlPlaneShootChartValues(ctx, cp, cluster, secretsManager, map[string]string{})
+				Expect(err).To(MatchError(ContainSubstring("could not decode the WorkerConfig of worker pool invalid")))
+			})
+		})
+	})
+
+	Describe("#GetControlPlaneShootCRDsChartValues", func() {
+		It("enables the volume snapshot CRDs by default", func() {
+			values, err := vp.GetControlPlaneShootCRDsChartValues(ctx, baseControlPlane(), baseCluster())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(values).To(Equal(map[string]any{
+				"volumesnapshots": map[string]any{"enabled": true},
+			}))
+		})
+
+		It("disables the volume snapshot CRDs if the snapshot controller is disabled", func() {
+			cp := baseControlPlane()
+			cpConfig := baseControlPlaneConfig()
+			cpConfig.Storage.DisableSnapshotController = new(true)
+			cp.Spec.ProviderConfig.Raw = encode(cpConfig)
+
+			values, err := vp.GetControlPlaneShootCRDsChartValues(ctx, cp, baseCluster())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(values).To(Equal(map[string]any{
+				"volumesnapshots": map[string]any{"enabled": false},
+			}))
+		})
 	})
 
 	Describe("#GetStorageClassesChartValues", func() {
@@ -820,14 +1454,75 @@ var _ = Describe("ValuesProvider fake client", func() {
 			Expect(storageClasses[0]).To(HaveKeyWithValue("provisioner", openstack.CSIStorageProvisioner))
 			Expect(storageClasses[1]).To(HaveKeyWithValue("name", "default-class"))
 			Expect(storageClasses[1]).To(HaveKeyWithValue("provisioner", openstack.CSIStorageProvisioner))
+			Expect(storageClasses[0]).NotTo(HaveKey("parameters"))
+		})
+
+		It("adds the default volume type to the default storage classes", func() {
+			cloudProfileConfig := baseCloudProfileConfig()
+			cloudProfileConfig.DefaultVolumeType = new("storage_premium_perf4")
+			cluster := baseCluster()
+			cluster.CloudProfile.Spec.ProviderConfig = &runtime.RawExtension{Raw: encode(cloudProfileConfig)}
+
+			values, err := vp.GetStorageClassesChartValues(ctx, baseControlPlane(), cluster)
+			Expect(err).NotTo(HaveOccurred())
+
+			storageClasses, ok := values["storageclasses"].([]map[string]any)
+			Expect(ok).To(BeTrue())
+			Expect(storageClasses).To(HaveLen(2))
+			Expect(storageClasses[0]).To(HaveKeyWithValue("parameters", map[string]string{"type": "storage_premium_perf4"}))
+			Expect(storageClasses[1]).To(HaveKeyWithValue("parameters", map[string]string{"type": "storage_premium_perf4"}))
+		})
+
+		It("adds the default volume type only to configured storage classes without type", func() {
+			cloudProfileConfig := baseCloudProfileConfig()
+			cloudProfileConfig.DefaultVolumeType = new("storage_premium_perf4")
+			cloudProfileConfig.StorageClasses = []stackitv1alpha1.StorageClassDefinition{
+				{Name: "default", Parameters: map[string]string{"encrypted": "true"}},
+				{Name: "fast", Parameters: map[string]string{"type": "storage_premium_perf12"}},
+			}
+			cluster := baseCluster()
+			cluster.CloudProfile.Spec.ProviderConfig = &runtime.RawExtension{Raw: encode(cloudProfileConfig)}
+
+			values, err := vp.GetStorageClassesChartValues(ctx, baseControlPlane(), cluster)
+			Expect(err).NotTo(HaveOccurred())
+
+			storageClasses, ok := values["storageclasses"].([]map[string]any)
+			Expect(ok).To(BeTrue())
+			Expect(storageClasses).To(HaveLen(2))
+			Expect(storageClasses[0]).To(HaveKeyWithValue("parameters", map[string]string{"encrypted": "true", "type": "storage_premium_perf4"}))
+			Expect(storageClasses[1]).To(HaveKeyWithValue("parameters", map[string]string{"type": "storage_premium_perf12"}))
 		})
 	})
 
+	DescribeTable("fails gracefully for incomplete clusters",
+		func(cluster *extensionscontroller.Cluster, expectedErr string) {
+			cp := baseControlPlane()
+
+			_, err := vp.GetConfigChartValues(ctx, cp, cluster)
+			Expect(err).To(MatchError(expectedErr))
+			_, err = vp.GetControlPlaneChartValues(ctx, cp, cluster, secretsManager, map[string]string{}, false)
+			Expect(err).To(MatchError(expectedErr))
+			_, err = vp.GetControlPlaneShootChartValues(ctx, cp, cluster, secretsManager, map[string]string{})
+			Expect(err).To(MatchError(expectedErr))
+			_, err = vp.GetStorageClassesChartValues(ctx, cp, cluster)
+			Expect(err).To(MatchError(expectedErr))
+		},
+		Entry("without cluster", nil, "cluster is missing"),
+		Entry("without shoot", &extensionscontroller.Cluster{
+			ObjectMeta:   metav1.ObjectMeta{Name: namespace},
+			CloudProfile: &gardencorev1beta1.CloudProfile{},
+		}, "shoot of cluster "+namespace+" is missing"),
+		Entry("without cloud profile", &extensionscontroller.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: namespace},
+			Shoot:      &gardencorev1beta1.Shoot{},
+		}, "cloud profile of cluster "+namespace+" is missing"),
+	)
+
 	Describe("#checkEmergencyLoadBalancerAccess", func() {
 		secretKey := client.ObjectKey{Name: LoadBalancerEmergencyAccessSecretName, Namespace: namespace}
 
 		It("returns empty values when the secret is missing", func() {
-			apiURL, apiToken, err := vp.checkEmergencyLoadBalancerAccess(ctx, secretKey)
+			apiURL, apiToken, _, err := vp.checkEmergencyLoadBalancerAccess(ctx, baseControlPlane())
 			Expect(err).NotTo(HaveOccurred())
 			Expect(apiURL).To(BeEmpty())
 			Expect(apiToken).To(BeEmpty())
@@ -848,7 +1543,7 @@ var _ = Describe("ValuesProvider fake client", func() {
 				Build()
 			interceptedProvider := newTestValuesProvider(interceptedClient, scheme, "kubernetes.io")
 
-			apiURL, apiToken, err := interceptedProvider.checkEmergencyLoadBalancerAccess(ctx, secretKey)
+			apiURL, apiToken, _, err := interceptedProvider.checkEmergencyLoadBalancerAccess(ctx, baseControlPlane())
 			Expect(err).To(MatchError(expectedErr))
 			Expect(apiURL).To(BeEmpty())
 			Expect(apiToken).To(BeEmpty())
@@ -857,15 +1552,48 @@ var _ = Describe("ValuesProvider fake client", func() {
 		It("returns decoded emergency access values for a valid secret", func() {
 			createObjects(ctx, c, emergencyLBSecret())
 
-			apiURL, apiToken, err := vp.checkEmergencyLoadBalancerAccess(ctx, secretKey)
+			apiURL, apiToken, _, err := vp.checkEmergencyLoadBalancerAccess(ctx, baseControlPlane())
 			Expect(err).NotTo(HaveOccurred())
 			Expect(apiURL).To(Equal("foo"))
 			Expect(apiToken).To(Equal("bar"))
 		})
+
+		Context("malformed secret", func() {
+			var cp *extensionsv1alpha1.ControlPlane
+
+			BeforeEach(func() {
+				cp = baseControlPlane()
+				c = fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(cp).Build()
+				vp = newTestValuesProvider(c, scheme, "kubernetes.io")
+
+				secret := emergencyLBSecret()
+				delete(secret.Data, LoadBalancerEmergencyAccessAPITokenKey)
+				createObjects(ctx, c, cp, secret)
+			})
+
+			It("returns an error by default", func() {
+				_, _, _, err := vp.checkEmergencyLoadBalancerAccess(ctx, cp)
+				Expect(err).To(MatchError(ContainSubstring("malformed secret " + LoadBalancerEmergencyAccessSecretName)))
+				Expect(cp.Status.Conditions).To(BeEmpty())
+			})
+
+			It("falls back to the regular access with the Ignore policy", func() {
+				vp.configuration.MalformedEmergencyAccessSecretPolicy = config.MalformedEmergencyAccessSecretPolicyIgnore
+
+				apiURL, apiToken, _, err := vp.checkEmergencyLoadBalancerAccess(ctx, cp)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(apiURL).To(BeEmpty())
+				Expect(apiToken).To(BeEmpty())
+
+				persisted := &extensionsv1alpha1.ControlPlane{}
+				Expect(c.Get(ctx, client.ObjectKeyFromObject(cp), persisted)).To(Succeed())
+				Expect(persisted.Status.Conditions).To(BeEmpty())
+			})
+		})
 	})
 
 	DescribeTable("#decodeLoadBalancerAPIEmergencySecret",
-		func(url, token *string, expectedErr error) {
+		func(url, token, caCert *string, expectedErr error) {
 			secret := &corev1.Secret{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      LoadBalancerEmergencyAccessSecretName,
@@ -880,24 +1608,32 @@ var _ = Describe("ValuesProvider fake client", func() {
 			if token != nil {
 				secret.Data[LoadBalancerEmergencyAccessAPITokenKey] = []byte(*token)
 			}
+			if caCert != nil {
+				secret.Data[LoadBalancerEmergencyAccessAPICACertKey] = []byte(*caCert)
+			}
 
-			apiURL, apiToken, err := decodeLoadBalancerAPIEmergencySecret(secret)
+			apiURL, apiToken, apiCACert, err := decodeLoadBalancerAPIEmergencySecret(secret)
 			if expectedErr != nil {
 				Expect(err).To(MatchError(expectedErr))
 				Expect(apiURL).To(BeEmpty())
 				Expect(apiToken).To(BeEmpty())
+				Expect(apiCACert).To(BeEmpty())
 				return
 			}
 
 			Expect(err).NotTo(HaveOccurred())
 			Expect(apiURL).To(Equal(*url))
 			Expect(apiToken).To(Equal(*token))
+			Expect(apiCACert).To(Equal(ptr.Deref(caCert, "")))
 		},
-		Entry("missing URL", nil, new("token"), fmt.Errorf("missing or empty secret key %s", LoadBalancerEmergencyAccessAPIURLKey)),
-		Entry("empty URL", new(""), new("token"), fmt.Errorf("missing or empty secret key %s", LoadBalancerEmergencyAccessAPIURLKey)),
-		Entry("missing token", new("url"), nil, fmt.Errorf("missing or empty secret key %s", LoadBalancerEmergencyAccessAPITokenKey)),
-		Entry("empty token", new("url"), new(""), fmt.Errorf("missing or empty secret key %s", LoadBalancerEmergencyAccessAPITokenKey)),
-		Entry("valid secret", new("url"), new("token"), nil),
+		Entry("missing URL", nil, new("token"), nil, fmt.Errorf("missing or empty secret key %s", LoadBalancerEmergencyAccessAPIURLKey)),
+		Entry("empty URL", new(""), new("token"), nil, fmt.Errorf("missing or empty secret key %s", LoadBalancerEmergencyAccessAPIURLKey)),
+		Entry("missing token", new("url"), nil, nil, fmt.Errorf("missing or empty secret key %s", LoadBalancerEmergencyAccessAPITokenKey)),
+		Entry("empty token", new("url"), new(""), nil, fmt.Errorf("missing or empty secret key %s", LoadBalancerEmergencyAccessAPITokenKey)),
+		Entry("valid secret", new("url"), new("token"), nil, nil),
+		Entry("valid secret with CA certificate", new("url"), new("token"), new(caCertificatePEM()), nil),
+		Entry("malformed CA certificate", new("url"), new("token"), new("not a certificate"), fmt.Errorf("secret key %s does not contain a PEM encoded certificate", LoadBalancerEmergencyAccessAPICACertKey)),
+		Entry("empty CA certificate", new("url"), new("token"), new(""), fmt.Errorf("secret key %s does not contain a PEM encoded certificate", LoadBalancerEmergencyAccessAPICACertKey)),
 	)
 
 	DescribeTable("#shouldEnablePodIdentityWebhook",
@@ -11,30 +11,40 @@ import (
 	"github.com/gardener/gardener/extensions/pkg/controller/infrastructure"
 	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
 	"github.com/go-logr/logr"
+	"k8s.io/utils/clock"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 
+	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/apis/config"
 	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/controller/infrastructure/openstack"
 	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/controller/infrastructure/stackit"
 	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/feature"
 )
 
 type actuator struct {
+	client            client.Client
+	clock             clock.PassiveClock
 	stackitActuator   infrastructure.Actuator
 	openstackActuator infrastructure.Actuator
 }
 
 // NewActuator creates a new Actuator that updates the status of the handled Infrastructure resources.
-func NewActuator(mgr manager.Manager, customLabelDomain string) infrastructure.Actuator {
+func NewActuator(mgr manager.Manager, customLabelDomain string, clusterLabelValueSource config.ClusterLabelValueSource, configuration config.InfrastructureControllerConfiguration) infrastructure.Actuator {
 	return &actuator{
-		stackitActuator:   stackit.NewActuator(mgr, customLabelDomain),
-		openstackActuator: openstack.NewActuator(mgr),
+		client:            mgr.GetClient(),
+		clock:             clock.RealClock{},
+		stackitActuator:   stackit.NewActuator(mgr, customLabelDomain, clusterLabelValueSource, configuration),
+		openstackActuator: openstack.NewActuator(mgr, clusterLabelValueSource, configuration),
 	}
 }
 
 // Reconcile the Infrastructure config.
 func (a *actuator) Reconcile(ctx context.Context, log logr.Logger, infra *extensionsv1alpha1.Infrastructure, cluster *extensionscontroller.Cluster) error {
+	if err := a.recordEffectiveFeatureGates(ctx, infra, cluster); err != nil {
+		return err
+	}
 	if feature.UseStackitAPIInfrastructureController(cluster) {
-		return a.stackitActuator.Reconcile(ctx, log, infra, cluster)
+		return a.reportAPIRequests(ctx, infra, a.stackitActuator.Reconcile(ctx, log, infra, cluster))
 	}
 	return a.openstackActuator.Reconcile(ctx, log, infra, cluster)
 }
@@ -42,7 +52,7 @@ func (a *actuator) Reconcile(ctx context.Context, log logr.Logger, infra *extens
 // Delete the Infrastructure config.
 func (a *actuator) Delete(ctx context.Context, log logr.Logger, infra *extensionsv1alpha1.Infrastructure, cluster *extensionscontroller.Cluster) error {
 	if feature.UseStackitAPIInfrastructureController(cluster) {
-		return a.stackitActuator.Delete(ctx, log, infra, cluster)
+		return a.reportAPIRequests(ctx, infra, a.stackitActuator.Delete(ctx, log, infra, cluster))
 	}
 	return a.openstackActuator.Delete(ctx, log, infra, cluster)
 }
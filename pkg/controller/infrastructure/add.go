@@ -13,6 +13,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 
+	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/apis/config"
 	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/apis/stackit/helper"
 	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/stackit"
 )
@@ -30,13 +31,18 @@ type AddOptions struct {
 	ExtensionClasses []extensionsv1alpha1.ExtensionClass
 	// CustomLabelDomain is the domain prefix for custom labels applied to STACKIT infrastructure resources.
 	CustomLabelDomain string
+	// ClusterLabelValueSource defines which identifier of the shoot is used as value of the cluster label of STACKIT
+	// resources created by the control plane components, which are looked up when deleting the infrastructure.
+	ClusterLabelValueSource config.ClusterLabelValueSource
+	// Configuration is the configuration of the infrastructure controller.
+	Configuration config.InfrastructureControllerConfiguration
 }
 
 // AddToManagerWithOptions adds a controller with the given AddOptions to the given manager.
 // The opts.Reconciler is being set with a newly instantiated actuator.
 func AddToManagerWithOptions(ctx context.Context, mgr manager.Manager, options AddOptions) error {
 	return infrastructure.Add(mgr, infrastructure.AddArgs{
-		Actuator:          NewActuator(mgr, options.CustomLabelDomain),
+		Actuator:          NewActuator(mgr, options.CustomLabelDomain, options.ClusterLabelValueSource, options.Configuration),
 		ConfigValidator:   NewConfigValidator(mgr, log.Log),
 		ControllerOptions: options.Controller,
 		Predicates:        infrastructure.DefaultPredicates(ctx, mgr, options.IgnoreOperationAnnotation),
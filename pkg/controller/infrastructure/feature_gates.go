@@ -0,0 +1,38 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package infrastructure
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	extensionscontroller "github.com/gardener/gardener/extensions/pkg/controller"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/feature"
+	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/stackit"
+)
+
+// recordEffectiveFeatureGates records the feature gates which are effective for the shoot together with their source
+// in an annotation of the Infrastructure. This allows reconstructing which flow reconciled the infrastructure.
+func (a *actuator) recordEffectiveFeatureGates(ctx context.Context, infra *extensionsv1alpha1.Infrastructure, cluster *extensionscontroller.Cluster) error {
+	data, err := json.Marshal(feature.ShootGates(cluster))
+	if err != nil {
+		return fmt.Errorf("could not marshal effective feature gates: %w", err)
+	}
+	if infra.Annotations[stackit.AnnotationEffectiveFeatureGates] == string(data) {
+		return nil
+	}
+
+	patch := client.MergeFrom(infra.DeepCopy())
+	metav1.SetMetaDataAnnotation(&infra.ObjectMeta, stackit.AnnotationEffectiveFeatureGates, string(data))
+	if err := a.client.Patch(ctx, infra, patch); err != nil {
+		return fmt.Errorf("could not record effective feature gates: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package infrastructure
+
+import (
+	"context"
+	"encoding/json"
+
+	extensionscontroller "github.com/gardener/gardener/extensions/pkg/controller"
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	testutils "github.com/gardener/gardener/pkg/utils/test"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/feature"
+	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/stackit"
+)
+
+var _ = Describe("Effective feature gates", func() {
+	var (
+		ctx     context.Context
+		c       client.Client
+		a       *actuator
+		infra   *extensionsv1alpha1.Infrastructure
+		cluster *extensionscontroller.Cluster
+	)
+
+	BeforeEach(func() {
+		DeferCleanup(testutils.WithFeatureGate(feature.MutableGate, feature.UseSTACKITAPIInfrastructureController, true))
+		DeferCleanup(testutils.WithFeatureGate(feature.MutableGate, feature.UseSTACKITMachineControllerManager, true))
+
+		ctx = context.Background()
+		scheme := runtime.NewScheme()
+		Expect(extensionsv1alpha1.AddToScheme(scheme)).To(Succeed())
+
+		infra = &extensionsv1alpha1.Infrastructure{ObjectMeta: metav1.ObjectMeta{Name: "infra", Namespace: "shoot--foo--bar"}}
+		c = fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(infra).Build()
+		a = &actuator{client: c}
+		cluster = &extensionscontroller.Cluster{
+			Shoot: &gardencorev1beta1.Shoot{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+				feature.ShootUseSTACKITMachineControllerManager: "false",
+			}}},
+		}
+	})
+
+	recordedGates := func() []feature.EffectiveGate {
+		current := &extensionsv1alpha1.Infrastructure{}
+		ExpectWithOffset(1, c.Get(ctx, client.ObjectKeyFromObject(infra), current)).To(Succeed())
+		var gates []feature.EffectiveGate
+		ExpectWithOffset(1, json.Unmarshal([]byte(current.Annotations[stackit.AnnotationEffectiveFeatureGates]), &gates)).To(Succeed())
+		return gates
+	}
+
+	It("should record the effective feature gates and their source", func() {
+		Expect(a.recordEffectiveFeatureGates(ctx, infra, cluster)).To(Succeed())
+
+		Expect(recordedGates()).To(ConsistOf(
+			feature.EffectiveGate{Name: feature.UseSTACKITAPIInfrastructureController, Enabled: true, Source: feature.GateSourceGlobal},
+			feature.EffectiveGate{Name: feature.UseSTACKITMachineControllerManager, Enabled: false, Source: feature.GateSourceShootAnnotation},
+		))
+	})
+
+	It("should update the recorded feature gates if they change", func() {
+		Expect(a.recordEffectiveFeatureGates(ctx, infra, cluster)).To(Succeed())
+		delete(cluster.Shoot.Annotations, feature.ShootUseSTACKITMachineControllerManager)
+
+		Expect(a.recordEffectiveFeatureGates(ctx, infra, cluster)).To(Succeed())
+
+		Expect(recordedGates()).To(ContainElement(
+			feature.EffectiveGate{Name: feature.UseSTACKITMachineControllerManager, Enabled: true, Source: feature.GateSourceGlobal},
+		))
+	})
+})
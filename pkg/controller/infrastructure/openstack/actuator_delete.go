@@ -79,16 +79,19 @@ func (a *actuator) delete(ctx context.Context, log logr.Logger, infra *extension
 	}
 
 	fctx, err := infraflow.NewFlowContext(ctx, infraflow.Opts{
-		Log:            log,
-		Infrastructure: infra,
-		State:          infraState,
-		Cluster:        cluster,
-		ClientFactory:  clientFactory,
-		Client:         a.client,
-		StackitLB:      stackitLBClient,
-		StackitALB:     stackitALBClient,
-		StackitALBCert: stackitALBCertClient,
-		IaaSClient:     iaasClient,
+		Log:                        log,
+		Infrastructure:             infra,
+		State:                      infraState,
+		Cluster:                    cluster,
+		ClientFactory:              clientFactory,
+		Client:                     a.client,
+		StackitLB:                  stackitLBClient,
+		StackitALB:                 stackitALBClient,
+		StackitALBCert:             stackitALBCertClient,
+		IaaSClient:                 iaasClient,
+		ClusterLabelValueSource:    a.clusterLabelValueSource,
+		LoadBalancerRequestTimeout: a.configuration.LoadBalancerRequestTimeout,
+		LoadBalancerRequestRetries: a.configuration.LoadBalancerRequestRetries,
 	})
 	if err != nil {
 		return fmt.Errorf("failed to create flow context: %w", err)
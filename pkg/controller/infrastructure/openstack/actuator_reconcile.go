@@ -12,6 +12,7 @@ import (
 	"github.com/gardener/gardener/extensions/pkg/util"
 	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
 	"github.com/go-logr/logr"
+	"k8s.io/utils/ptr"
 
 	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/apis/stackit/helper"
 	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/controller/infrastructure/openstack/infraflow"
@@ -52,13 +53,19 @@ func (a *actuator) reconcile(ctx context.Context, log logr.Logger, infra *extens
 	}
 
 	fctx, err := infraflow.NewFlowContext(ctx, infraflow.Opts{
-		Log:            log,
-		Infrastructure: infra,
-		State:          infraState,
-		Cluster:        cluster,
-		ClientFactory:  clientFactory,
-		Client:         a.client,
-		IaaSClient:     iaasClient,
+		Log:                               log,
+		Infrastructure:                    infra,
+		State:                             infraState,
+		Cluster:                           cluster,
+		ClientFactory:                     clientFactory,
+		Client:                            a.client,
+		IaaSClient:                        iaasClient,
+		EmptySSHPublicKeyPolicy:           a.configuration.EmptySSHPublicKeyPolicy,
+		AggregateEgressCIDRs:              a.configuration.AggregateEgressCIDRs,
+		SecurityGroupDescription:          a.configuration.SecurityGroupDescription,
+		DeleteDuplicateSecurityGroupRules: a.configuration.DeleteDuplicateSecurityGroupRules,
+		AllowMetadataServiceEgress:        ptr.Deref(a.configuration.AllowMetadataServiceEgress, true),
+		RouterInterfaceTimeout:            a.configuration.RouterInterfaceTimeout,
 	})
 	if err != nil {
 		return fmt.Errorf("failed to create flow context: %w", err)
@@ -52,7 +52,7 @@ type NetworkingAccess interface {
 	CreateSecurityGroup(ctx context.Context, desired *groups.SecGroup) (*groups.SecGroup, error)
 	GetSecurityGroupByID(ctx context.Context, id string) (*groups.SecGroup, error)
 	GetSecurityGroupByName(ctx context.Context, name string) ([]*groups.SecGroup, error)
-	UpdateSecurityGroupRules(ctx context.Context, group *groups.SecGroup, desiredRules []rules.SecGroupRule, allowDelete func(rule *rules.SecGroupRule) bool) (modified bool, err error)
+	UpdateSecurityGroupRules(ctx context.Context, group *groups.SecGroup, desiredRules []rules.SecGroupRule, deleteDuplicates bool, allowDelete func(rule *rules.SecGroupRule) bool) (modified bool, err error)
 }
 
 // Router is a simplified router resource
@@ -65,6 +65,7 @@ type Router struct {
 
 	Status           string                    // only output
 	ExternalFixedIPs []routers.ExternalFixedIP // only output
+	ProjectID        string                    // only output
 }
 
 // Network is a simplified network resource
@@ -169,6 +170,10 @@ func (a *networkingAccess) toRouter(raw *routers.Router) *Router {
 		EnableSNAT:        raw.GatewayInfo.EnableSNAT,
 		Status:            raw.Status,
 		ExternalFixedIPs:  raw.GatewayInfo.ExternalFixedIPs,
+		ProjectID:         raw.ProjectID,
+	}
+	if router.ProjectID == "" {
+		router.ProjectID = raw.TenantID
 	}
 	return router
 }
@@ -200,7 +205,7 @@ func (a *networkingAccess) UpdateRouter(ctx context.Context, desired, current *R
 	return modified, current, nil
 }
 
-// AddRouterInterfaceAndWait adds router interface and waits up to
+// AddRouterInterfaceAndWait adds router interface and waits until its port is active or the context is done.
 func (a *networkingAccess) AddRouterInterfaceAndWait(ctx context.Context, routerID, subnetID string) error {
 	info, err := a.networking.AddRouterInterface(ctx, routerID, routers.AddInterfaceOpts{SubnetID: subnetID})
 	if err != nil {
@@ -240,6 +245,8 @@ func (a *networkingAccess) AddRouterInterfaceAndWait(ctx context.Context, router
 	}
 }
 
+// GetRouterInterfacePortID returns the ID of the router interface port connecting the given subnet or nil if the
+// router has no interface in the subnet.
 func (a *networkingAccess) GetRouterInterfacePortID(ctx context.Context, routerID, subnetID string) (portID *string, err error) {
 	port, err := a.networking.GetRouterInterfacePort(ctx, routerID, subnetID)
 	if err != nil {
@@ -461,6 +468,7 @@ func (a *networkingAccess) UpdateSecurityGroupRules(
 	ctx context.Context,
 	group *groups.SecGroup,
 	desiredRules []rules.SecGroupRule,
+	deleteDuplicates bool,
 	allowDelete func(rule *rules.SecGroupRule) bool,
 ) (modified bool, err error) {
 	for i := range desiredRules {
@@ -473,10 +481,20 @@ func (a *networkingAccess) UpdateSecurityGroupRules(
 		}
 	}
 
+	var matchedRules []*rules.SecGroupRule
 	for i := range group.Rules {
 		rule := &group.Rules[i]
 		if desiredRule, _ := a.findMatchingRule(rule, desiredRules); desiredRule == nil {
-			if allowDelete == nil || allowDelete(rule) {
+			// Duplicates of desired rules are deleted on request, all other rules not desired anymore are subject to
+			// allowDelete, independent of whether they duplicate a desired rule.
+			if duplicate := findDuplicateRule(rule, matchedRules); duplicate != nil && deleteDuplicates {
+				a.log.Info("deleting duplicate security group rule", "rule", rule.ID, "duplicateOf", duplicate.ID, "description", rule.Description)
+				if err = a.networking.DeleteRule(ctx, rule.ID); err != nil {
+					err = fmt.Errorf("error deleting duplicate rule %s of security group: %s", rule.ID, err)
+					return
+				}
+				modified = true
+			} else if allowDelete == nil || allowDelete(rule) {
 				if err = a.networking.DeleteRule(ctx, rule.ID); err != nil {
 					err = fmt.Errorf("error deleting rule for security group %s: %s", rule.ID, err)
 					return
@@ -485,6 +503,7 @@ func (a *networkingAccess) UpdateSecurityGroupRules(
 			}
 		} else {
 			desiredRule.ID = rule.ID // mark as found
+			matchedRules = append(matchedRules, rule)
 		}
 	}
 	for i := range desiredRules {
@@ -514,6 +533,26 @@ func (a *networkingAccess) UpdateSecurityGroupRules(
 	return
 }
 
+// findDuplicateRule returns the item in candidates which is an exact duplicate of the given rule, i.e. which only
+// differs in its ID.
+func findDuplicateRule(rule *rules.SecGroupRule, candidates []*rules.SecGroupRule) *rules.SecGroupRule {
+	for _, other := range candidates {
+		if rule.Direction == other.Direction &&
+			rule.Description == other.Description &&
+			rule.EtherType == other.EtherType &&
+			rule.Protocol == other.Protocol &&
+			rule.RemoteIPPrefix == other.RemoteIPPrefix &&
+			rule.RemoteGroupID == other.RemoteGroupID &&
+			rule.PortRangeMin == other.PortRangeMin &&
+			rule.PortRangeMax == other.PortRangeMax &&
+			rule.ProjectID == other.ProjectID &&
+			rule.TenantID == other.TenantID {
+			return other
+		}
+	}
+	return nil
+}
+
 func (a *networkingAccess) findMatchingRule(rule *rules.SecGroupRule, desiredRules []rules.SecGroupRule) (*rules.SecGroupRule, bool) {
 	for i := range desiredRules {
 		desired := &desiredRules[i]
@@ -0,0 +1,108 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package access_test
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	"github.com/gophercloud/gophercloud/v2/openstack/networking/v2/extensions/security/groups"
+	"github.com/gophercloud/gophercloud/v2/openstack/networking/v2/extensions/security/rules"
+	"github.com/gophercloud/gophercloud/v2/openstack/networking/v2/ports"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+
+	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/controller/infrastructure/openstack/infraflow/access"
+	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/openstack/client/mocks"
+)
+
+var _ = Describe("NetworkingAccess", func() {
+	var (
+		ctx        context.Context
+		ctrl       *gomock.Controller
+		networking *mocks.MockNetworking
+		a          access.NetworkingAccess
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		ctrl = gomock.NewController(GinkgoT())
+		networking = mocks.NewMockNetworking(ctrl)
+
+		var err error
+		a, err = access.NewNetworkingAccess(ctx, networking, logr.Discard())
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Describe("#GetRouterInterfacePortID", func() {
+		It("should return the port connecting the subnet", func() {
+			networking.EXPECT().GetRouterInterfacePort(ctx, "router", "subnet").Return(&ports.Port{
+				ID:       "port",
+				FixedIPs: []ports.IP{{SubnetID: "subnet", IPAddress: "10.250.0.1"}},
+			}, nil)
+
+			Expect(a.GetRouterInterfacePortID(ctx, "router", "subnet")).To(HaveValue(Equal("port")))
+		})
+
+		It("should return nil if the router has no interface", func() {
+			networking.EXPECT().GetRouterInterfacePort(ctx, "router", "subnet").Return(nil, nil)
+
+			Expect(a.GetRouterInterfacePortID(ctx, "router", "subnet")).To(BeNil())
+		})
+	})
+
+	Describe("#UpdateSecurityGroupRules", func() {
+		var (
+			group        *groups.SecGroup
+			desiredRules []rules.SecGroupRule
+			keepUnknown  = func(*rules.SecGroupRule) bool { return false }
+		)
+
+		BeforeEach(func() {
+			nodePortRule := rules.SecGroupRule{
+				Direction:      string(rules.DirIngress),
+				EtherType:      string(rules.EtherType4),
+				Protocol:       string(rules.ProtocolTCP),
+				PortRangeMin:   30000,
+				PortRangeMax:   32767,
+				RemoteIPPrefix: "0.0.0.0/0",
+				Description:    "node ports",
+				SecGroupID:     "group",
+				ProjectID:      "project",
+			}
+			duplicate := nodePortRule
+			unknown := nodePortRule
+			unknown.Description = "unknown"
+
+			group = &groups.SecGroup{ID: "group", ProjectID: "project", Rules: []rules.SecGroupRule{nodePortRule, duplicate, unknown}}
+			group.Rules[0].ID = "rule-1"
+			group.Rules[1].ID = "rule-2"
+			group.Rules[2].ID = "rule-3"
+			desiredRules = []rules.SecGroupRule{nodePortRule}
+		})
+
+		It("should keep duplicated existing rules by default unless they may be deleted", func() {
+			Expect(a.UpdateSecurityGroupRules(ctx, group, desiredRules, false, keepUnknown)).To(BeFalse())
+		})
+
+		It("should delete duplicated existing rules which may be deleted", func() {
+			networking.EXPECT().DeleteRule(ctx, "rule-2").Return(nil)
+			networking.EXPECT().DeleteRule(ctx, "rule-3").Return(nil)
+
+			Expect(a.UpdateSecurityGroupRules(ctx, group, desiredRules, false, func(*rules.SecGroupRule) bool { return true })).To(BeTrue())
+		})
+
+		It("should only delete the duplicated existing rules if configured", func() {
+			networking.EXPECT().DeleteRule(ctx, "rule-2").Return(nil)
+
+			Expect(a.UpdateSecurityGroupRules(ctx, group, desiredRules, true, keepUnknown)).To(BeTrue())
+		})
+	})
+})
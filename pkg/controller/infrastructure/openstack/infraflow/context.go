@@ -7,6 +7,7 @@ package infraflow
 import (
 	"context"
 	"fmt"
+	"time"
 
 	extensionscontroller "github.com/gardener/gardener/extensions/pkg/controller"
 	corev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
@@ -17,6 +18,7 @@ import (
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/apis/config"
 	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/apis/stackit/helper"
 	stackitv1alpha1 "github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/apis/stackit/v1alpha1"
 	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/controller/infrastructure/openstack/infraflow/access"
@@ -61,8 +63,27 @@ const (
 
 	// CreatedResourcesExistKey marks that there are infrastructure resources created by Gardener.
 	CreatedResourcesExistKey = "resource_exist"
+
+	// ManagedRouterFallbackKey marks that a managed router replaces the missing router of the InfrastructureConfig.
+	ManagedRouterFallbackKey = "managed_router_fallback"
 )
 
+// knownStateKeys are the keys which are persisted in the InfrastructureState. All other keys are pruned on reconciliation.
+var knownStateKeys = []string{
+	IdentifierRouter,
+	IdentifierNetwork,
+	IdentifierSubnet,
+	IdentifierFloatingNetwork,
+	IdentifierSecGroup,
+	NameFloatingNetwork,
+	NameFloatingPoolSubnet,
+	NameNetwork,
+	NameKeyPair,
+	NameSecGroup,
+	CreatedResourcesExistKey,
+	ManagedRouterFallbackKey,
+}
+
 // Opts contain options to initiliaze a FlowContext
 type Opts struct {
 	Log            logr.Logger
@@ -75,30 +96,59 @@ type Opts struct {
 	StackitALB     stackitclient.ApplicationLoadBalancingClient
 	StackitALBCert stackitclient.ApplicationLoadBalancerCertificateClient
 	IaaSClient     stackitclient.IaaSClient
+	// EmptySSHPublicKeyPolicy defines how an Infrastructure without SSH public key is handled.
+	EmptySSHPublicKeyPolicy config.EmptySSHPublicKeyPolicy
+	// AggregateEgressCIDRs merges contiguous egress IPs into the smallest covering CIDRs.
+	AggregateEgressCIDRs bool
+	// SecurityGroupDescription is the template of the description of newly created security groups.
+	SecurityGroupDescription string
+	// DeleteDuplicateSecurityGroupRules deletes exact duplicates of desired security group rules.
+	DeleteDuplicateSecurityGroupRules bool
+	// AllowMetadataServiceEgress adds an explicit egress rule for the instance metadata service.
+	AllowMetadataServiceEgress bool
+	// RouterInterfaceTimeout is the maximum duration to wait for a new router interface to become active.
+	RouterInterfaceTimeout *metav1.Duration
+	// ClusterLabelValueSource defines which identifier of the shoot is used as value of the cluster label of the STACKIT
+	// load balancers which are deleted with the infrastructure.
+	ClusterLabelValueSource config.ClusterLabelValueSource
+	// LoadBalancerRequestTimeout is the timeout of a single STACKIT load balancer API request when deleting.
+	LoadBalancerRequestTimeout *metav1.Duration
+	// LoadBalancerRequestRetries is the number of retries of STACKIT load balancer API requests failing transiently.
+	LoadBalancerRequestRetries *int32
 }
 
 // FlowContext contains the logic to reconcile or delete the infrastructure.
 type FlowContext struct {
-	state              shared.Whiteboard
-	client             client.Client
-	log                logr.Logger
-	infra              *extensionsv1alpha1.Infrastructure
-	config             *stackitv1alpha1.InfrastructureConfig
-	cloudProfileConfig *stackitv1alpha1.CloudProfileConfig
-	networkSpec        *corev1beta1.Networking
-	isSNAShoot         bool
-	nodesCIDR          *string
-	dnsNameservers     *[]string
-	networking         osclient.Networking
-	loadbalancing      osclient.Loadbalancing
-	access             access.NetworkingAccess
-	compute            osclient.Compute
-	stackitLB          stackitclient.LoadBalancingClient
-	stackitALB         stackitclient.ApplicationLoadBalancingClient
-	stackitALBCert     stackitclient.ApplicationLoadBalancerCertificateClient
-	iaasClient         stackitclient.IaaSClient
-	hasStackitMCM      bool
-	technicalID        string
+	state                             shared.Whiteboard
+	client                            client.Client
+	log                               logr.Logger
+	infra                             *extensionsv1alpha1.Infrastructure
+	config                            *stackitv1alpha1.InfrastructureConfig
+	cloudProfileConfig                *stackitv1alpha1.CloudProfileConfig
+	cluster                           *extensionscontroller.Cluster
+	networkSpec                       *corev1beta1.Networking
+	isSNAShoot                        bool
+	nodesCIDR                         *string
+	dnsNameservers                    *[]string
+	networking                        osclient.Networking
+	loadbalancing                     osclient.Loadbalancing
+	access                            access.NetworkingAccess
+	compute                           osclient.Compute
+	stackitLB                         stackitclient.LoadBalancingClient
+	stackitALB                        stackitclient.ApplicationLoadBalancingClient
+	stackitALBCert                    stackitclient.ApplicationLoadBalancerCertificateClient
+	iaasClient                        stackitclient.IaaSClient
+	hasStackitMCM                     bool
+	technicalID                       string
+	projectID                         string
+	emptySSHPublicKeyPolicy           config.EmptySSHPublicKeyPolicy
+	aggregateEgressCIDRs              bool
+	securityGroupDescription          string
+	deleteDuplicateSecurityGroupRules bool
+	allowMetadataServiceEgress        bool
+	routerInterfaceTimeout            time.Duration
+	clusterLabelValueSource           config.ClusterLabelValueSource
+	loadBalancerRetryConfig           stackitclient.RetryConfig
 
 	*shared.BasicFlowContext
 }
@@ -141,30 +191,40 @@ func NewFlowContext(ctx context.Context, opts Opts) (*FlowContext, error) {
 	}
 
 	flowContext := &FlowContext{
-		state:              whiteboard,
-		infra:              opts.Infrastructure,
-		config:             infraConfig,
-		cloudProfileConfig: cloudProfileConfig,
-		networkSpec:        networkSpec,
-		isSNAShoot:         isSNAShoot,
-		networking:         networking,
-		access:             access,
-		compute:            compute,
-		log:                opts.Log,
-		client:             opts.Client,
-		stackitLB:          opts.StackitLB,
-		stackitALB:         opts.StackitALB,
-		stackitALBCert:     opts.StackitALBCert,
-		iaasClient:         opts.IaaSClient,
-		hasStackitMCM:      feature.UseStackitMachineControllerManager(opts.Cluster),
-		technicalID:        opts.Cluster.Shoot.Status.TechnicalID,
+		state:                             whiteboard,
+		infra:                             opts.Infrastructure,
+		config:                            infraConfig,
+		cloudProfileConfig:                cloudProfileConfig,
+		cluster:                           opts.Cluster,
+		networkSpec:                       networkSpec,
+		isSNAShoot:                        isSNAShoot,
+		networking:                        networking,
+		access:                            access,
+		compute:                           compute,
+		log:                               opts.Log,
+		client:                            opts.Client,
+		stackitLB:                         opts.StackitLB,
+		stackitALB:                        opts.StackitALB,
+		stackitALBCert:                    opts.StackitALBCert,
+		iaasClient:                        opts.IaaSClient,
+		hasStackitMCM:                     feature.UseStackitMachineControllerManager(opts.Cluster),
+		technicalID:                       opts.Cluster.Shoot.Status.TechnicalID,
+		projectID:                         opts.ClientFactory.ProjectID(),
+		emptySSHPublicKeyPolicy:           opts.EmptySSHPublicKeyPolicy,
+		aggregateEgressCIDRs:              opts.AggregateEgressCIDRs,
+		securityGroupDescription:          opts.SecurityGroupDescription,
+		deleteDuplicateSecurityGroupRules: opts.DeleteDuplicateSecurityGroupRules,
+		allowMetadataServiceEgress:        opts.AllowMetadataServiceEgress,
+		routerInterfaceTimeout:            ptr.Deref(opts.RouterInterfaceTimeout, metav1.Duration{Duration: infrainternal.DefaultRouterInterfaceTimeout}).Duration,
+		clusterLabelValueSource:           opts.ClusterLabelValueSource,
+		loadBalancerRetryConfig:           infrainternal.NewLoadBalancerRetryConfig(opts.LoadBalancerRequestTimeout, opts.LoadBalancerRequestRetries),
 	}
 	return flowContext, nil
 }
 
 func (fctx *FlowContext) persistState(ctx context.Context) error {
 	// status is nil such that there's no need to pass the nodesCIDR
-	return infrainternal.PatchProviderStatusAndState(ctx, fctx.client, fctx.infra, nil, nil, fctx.computeInfrastructureState())
+	return infrainternal.PatchProviderStatusAndState(ctx, fctx.client, fctx.infra, nil, nil, false, fctx.computeInfrastructureState())
 }
 
 func (fctx *FlowContext) computeInfrastructureState() *runtime.RawExtension {
@@ -23,6 +23,7 @@ import (
 	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/controller/infrastructure/openstack/infraflow/shared"
 	infrainternal "github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/internal/infrastructure"
 	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/openstack/client"
+	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/stackit"
 	stackitclient "github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/stackit/client"
 )
 
@@ -33,7 +34,7 @@ const (
 
 // Reconcile creates and runs the flow to reconcile the AWS infrastructure.
 func (fctx *FlowContext) Reconcile(ctx context.Context) error {
-	fctx.BasicFlowContext = shared.NewBasicFlowContext().WithSpan().WithLogger(fctx.log).WithPersist(fctx.persistState)
+	fctx.BasicFlowContext = shared.NewBasicFlowContext().WithSpan().WithMetrics(fctx.technicalID).WithLogger(fctx.log).WithPersist(fctx.persistState)
 	g := fctx.buildReconcileGraph()
 	f := g.Compile()
 	if err := f.Run(ctx, flow.Opts{Log: fctx.log}); err != nil {
@@ -43,15 +44,18 @@ func (fctx *FlowContext) Reconcile(ctx context.Context) error {
 
 	state := fctx.computeInfrastructureState()
 	status := fctx.computeInfrastructureStatus()
-	return infrainternal.PatchProviderStatusAndState(ctx, fctx.client, fctx.infra, status, fctx.nodesCIDR, state)
+	return infrainternal.PatchProviderStatusAndState(ctx, fctx.client, fctx.infra, status, fctx.nodesCIDR, fctx.aggregateEgressCIDRs, state)
 }
 
 func (fctx *FlowContext) buildReconcileGraph() *flow.Graph {
 	g := flow.NewGraph("Openstack infrastructure reconciliation")
 
-	prehook := fctx.AddTask(g, "pre-reconcile hook", func(_ context.Context) error {
-		// delete unnecessary state object. RouterIP was replaced by IdentifierEgressCIDRs to handle cases where the router had multiple externalFixedIPs attached to it.
-		fctx.state.Delete(RouterIP)
+	prehook := fctx.AddTask(g, "pre-reconcile hook", func(ctx context.Context) error {
+		// delete state keys which are not used anymore, e.g. RouterIP which was replaced by IdentifierEgressCIDRs to handle
+		// cases where the router had multiple externalFixedIPs attached to it.
+		if pruned := shared.PruneUnknownKeys(fctx.state, knownStateKeys...); len(pruned) > 0 {
+			shared.LogFromContext(ctx).Info("pruned obsolete state keys", "keys", pruned)
+		}
 		return nil
 	})
 
@@ -76,9 +80,10 @@ func (fctx *FlowContext) buildReconcileGraph() *flow.Graph {
 		fctx.ensureSubnet,
 		shared.Timeout(defaultTimeout), shared.Dependencies(ensureNetwork))
 
+	// waiting for the router interface has its own timeout, see ensureRouterInterface
 	_ = fctx.AddTask(g, "ensure router interface",
 		fctx.ensureRouterInterface,
-		shared.Timeout(defaultTimeout), shared.Dependencies(ensureRouter, ensureSubnet))
+		shared.Timeout(defaultTimeout+fctx.routerInterfaceTimeout), shared.Dependencies(ensureRouter, ensureSubnet))
 
 	ensureSecGroup := fctx.AddTask(g, "ensure security group",
 		fctx.ensureSecGroup,
@@ -113,7 +118,7 @@ func (fctx *FlowContext) ensureSNAState(ctx context.Context) error {
 }
 
 func (fctx *FlowContext) ensureExternalNetwork(ctx context.Context) error {
-	externalNetwork, err := fctx.networking.GetExternalNetworkByName(ctx, fctx.config.FloatingPoolName)
+	externalNetwork, err := infrainternal.GetExternalNetwork(ctx, fctx.networking, fctx.config.FloatingPoolName, fctx.config.FloatingPoolID, fctx.state.Get(IdentifierFloatingNetwork))
 	if err != nil {
 		return err
 	}
@@ -131,12 +136,17 @@ func (fctx *FlowContext) ensureRouter(ctx context.Context) error {
 		return fmt.Errorf("missing external network ID")
 	}
 
-	if fctx.config.Networks.Router != nil {
+	if fctx.config.Networks.Router != nil && !fctx.usesManagedRouterFallback() {
 		return fctx.ensureConfiguredRouter(ctx)
 	}
 	return fctx.ensureNewRouter(ctx, *externalNetworkID)
 }
 
+// usesManagedRouterFallback returns whether a managed router replaces the missing router of the InfrastructureConfig.
+func (fctx *FlowContext) usesManagedRouterFallback() bool {
+	return ptr.Deref(fctx.state.Get(ManagedRouterFallbackKey), "") == "true"
+}
+
 func (fctx *FlowContext) ensureConfiguredRouter(ctx context.Context) error {
 	router, err := fctx.access.GetRouterByID(ctx, fctx.config.Networks.Router.ID)
 	if err != nil {
@@ -146,8 +156,18 @@ func (fctx *FlowContext) ensureConfiguredRouter(ctx context.Context) error {
 	if router == nil {
 		fctx.state.Set(IdentifierRouter, "")
 		fctx.state.Set(RouterIP, "")
+		if fctx.infra != nil && fctx.infra.Annotations[stackit.AnnotationRecreateMissingRouter] == "true" {
+			shared.LogFromContext(ctx).Info("configured router is missing, falling back to a managed router", "router", fctx.config.Networks.Router.ID)
+			// the fallback is persisted, so that the managed router is kept and deleted with the infrastructure
+			fctx.state.Set(ManagedRouterFallbackKey, "true")
+			return fctx.ensureRouter(ctx)
+		}
 		return fmt.Errorf("missing expected router %s", fctx.config.Networks.Router.ID)
 	}
+	if err := fctx.checkRouterProject(ctx, router); err != nil {
+		fctx.state.Set(IdentifierRouter, "")
+		return err
+	}
 	fctx.state.Set(IdentifierRouter, fctx.config.Networks.Router.ID)
 	if len(router.ExternalFixedIPs) < 1 {
 		return fmt.Errorf("expected at least one external fixed ip")
@@ -156,6 +176,23 @@ func (fctx *FlowContext) ensureConfiguredRouter(ctx context.Context) error {
 	return fctx.ensureEgressCIDRs(router)
 }
 
+// checkRouterProject verifies that a configured router belongs to the project of the credentials, so that the shoot
+// is not coupled to a router of another project. The check is skipped if the project of the credentials or of the
+// router is not exposed by the API.
+func (fctx *FlowContext) checkRouterProject(ctx context.Context, router *access.Router) error {
+	if fctx.projectID == "" || router.ProjectID == "" {
+		shared.LogFromContext(ctx).Info("skipping project check of configured router, project is unknown", "router", router.ID)
+		return nil
+	}
+	if router.ProjectID != fctx.projectID {
+		return gardenv1beta1helper.NewErrorWithCodes(
+			fmt.Errorf("configured router %s belongs to project %s and not to the project %s of the credentials", router.ID, router.ProjectID, fctx.projectID),
+			gardencorev1beta1.ErrorConfigurationProblem,
+		)
+	}
+	return nil
+}
+
 func (fctx *FlowContext) ensureNewRouter(ctx context.Context, externalNetworkID string) error {
 	log := shared.LogFromContext(ctx)
 
@@ -308,20 +345,42 @@ func (fctx *FlowContext) ensureSubnet(ctx context.Context) error {
 }
 
 func (fctx *FlowContext) ensureConfiguredSubnet(ctx context.Context) error {
-	current, err := fctx.access.GetSubnetByID(ctx, *fctx.config.Networks.SubnetID)
+	subnetID := *fctx.config.Networks.SubnetID
+	current, err := fctx.access.GetSubnetByID(ctx, subnetID)
 	if err != nil {
 		fctx.state.Set(IdentifierSubnet, "")
 		return err
 	}
+	// The nodes are attached to the subnet, but the routes and the load balancers are set up for the network. Both have
+	// to match, otherwise the nodes are not reachable.
+	if networkID := ptr.Deref(fctx.getExpectedNetworkID(), ""); current != nil && networkID != "" && current.NetworkID != networkID {
+		fctx.state.Set(IdentifierSubnet, "")
+		return gardenv1beta1helper.NewErrorWithCodes(
+			fmt.Errorf("configured subnet %s belongs to network %s and not to the network %s of the cluster", subnetID, current.NetworkID, networkID),
+			gardencorev1beta1.ErrorConfigurationProblem,
+		)
+	}
 	if current == nil {
 		fctx.dnsNameservers = nil
 	} else {
 		fctx.dnsNameservers = &current.DNSNameservers
+		if fctx.nodesCIDR == nil {
+			fctx.nodesCIDR = &current.CIDR
+		}
 	}
-	fctx.state.Set(IdentifierSubnet, *fctx.config.Networks.SubnetID)
+	fctx.state.Set(IdentifierSubnet, subnetID)
 	return nil
 }
 
+// getExpectedNetworkID returns the ID of the configured network or of the network ensured before without looking it
+// up.
+func (fctx *FlowContext) getExpectedNetworkID() *string {
+	if fctx.config.Networks.ID != nil {
+		return fctx.config.Networks.ID
+	}
+	return fctx.state.Get(IdentifierNetwork)
+}
+
 func (fctx *FlowContext) ensureNewSubnet(ctx context.Context) error {
 	log := shared.LogFromContext(ctx)
 
@@ -357,6 +416,7 @@ func (fctx *FlowContext) ensureNewSubnet(ctx context.Context) error {
 		}
 		// Update dnsNameservers when update was successful
 		fctx.dnsNameservers = &desired.DNSNameservers
+		fctx.nodesCIDR = &current.CIDR
 	} else {
 		log.Info("creating...")
 		created, err := fctx.access.CreateSubnet(ctx, desired)
@@ -365,6 +425,7 @@ func (fctx *FlowContext) ensureNewSubnet(ctx context.Context) error {
 		}
 		fctx.state.Set(IdentifierSubnet, created.ID)
 		fctx.dnsNameservers = &created.DNSNameservers
+		fctx.nodesCIDR = &created.CIDR
 	}
 	return nil
 }
@@ -402,15 +463,34 @@ func (fctx *FlowContext) ensureRouterInterface(ctx context.Context) error {
 		return nil
 	}
 	log.Info("creating...")
-	return fctx.access.AddRouterInterfaceAndWait(ctx, *routerID, *subnetID)
+	// Attaching the interface can take longer than the other tasks in some regions, so the wait is limited by its own
+	// timeout instead of the task timeout.
+	waitCtx, cancel := context.WithTimeout(ctx, fctx.routerInterfaceTimeout)
+	defer cancel()
+	if err := fctx.access.AddRouterInterfaceAndWait(waitCtx, *routerID, *subnetID); err != nil {
+		err = fmt.Errorf("router %s could not accept an interface for subnet %s: %w", *routerID, *subnetID, err)
+		if client.IsBadRequest(err) || client.IsConflict(err) {
+			return gardenv1beta1helper.NewErrorWithCodes(err, gardencorev1beta1.ErrorInfraDependencies)
+		}
+		if errors.Is(err, context.DeadlineExceeded) {
+			// the interface was created and is found by the next reconciliation
+			return gardenv1beta1helper.NewErrorWithCodes(err, gardencorev1beta1.ErrorRetryableInfraDependencies)
+		}
+		return err
+	}
+	return nil
 }
 
 func (fctx *FlowContext) ensureSecGroup(ctx context.Context) error {
 	log := shared.LogFromContext(ctx)
 
+	description, err := infrainternal.RenderSecurityGroupDescription(fctx.securityGroupDescription, fctx.technicalID)
+	if err != nil {
+		return err
+	}
 	desired := &groups.SecGroup{
 		Name:        fctx.defaultSecurityGroupName(),
-		Description: "Cluster Nodes",
+		Description: description,
 	}
 	current, err := findExisting(ctx, fctx.state.Get(IdentifierSecGroup), fctx.defaultSecurityGroupName(), fctx.access.GetSecurityGroupByID, fctx.access.GetSecurityGroupByName)
 	if err != nil {
@@ -455,58 +535,28 @@ func (fctx *FlowContext) ensureSecGroupRules(ctx context.Context) error {
 		nodesCIDR = *fctx.nodesCIDR
 	}
 
-	desiredRules := []rules.SecGroupRule{
-		{
-			Direction:     string(rules.DirIngress),
-			EtherType:     string(rules.EtherType4),
-			RemoteGroupID: access.SecurityGroupIDSelf,
-			Description:   "IPv4: allow all incoming traffic within the same security group",
-		},
-		{
-			Direction:   string(rules.DirEgress),
-			EtherType:   string(rules.EtherType4),
-			Description: "IPv4: allow all outgoing traffic",
-		},
-		// {
-		// 	Direction:   string(rules.DirEgress),
-		// 	EtherType:   string(rules.EtherType6),
-		// 	Description: "IPv6: allow all outgoing traffic",
-		// },
-		{
-			Direction:      string(rules.DirIngress),
-			EtherType:      string(rules.EtherType4),
-			Protocol:       string(rules.ProtocolTCP),
-			PortRangeMin:   30000,
-			PortRangeMax:   32767,
-			RemoteIPPrefix: nodesCIDR,
-			Description:    "IPv4: allow all incoming tcp traffic with port range 30000-32767",
-		},
-		{
-			Direction:      string(rules.DirIngress),
-			EtherType:      string(rules.EtherType4),
-			Protocol:       string(rules.ProtocolUDP),
-			PortRangeMin:   30000,
-			PortRangeMax:   32767,
-			RemoteIPPrefix: nodesCIDR,
-			Description:    "IPv4: allow all incoming udp traffic with port range 30000-32767",
-		},
-	}
-
-	if fctx.networkSpec != nil && fctx.networkSpec.Pods != nil {
-		podCIDRRule := rules.SecGroupRule{
-			Direction:      string(rules.DirIngress),
-			EtherType:      string(rules.EtherType4),
-			RemoteIPPrefix: *fctx.networkSpec.Pods,
-			Description:    "IPv4: allow all incoming traffic from cluster pod CIDR",
-		}
-		desiredRules = append(desiredRules, podCIDRRule)
+	var podCIDR *string
+	if fctx.networkSpec != nil {
+		podCIDR = fctx.networkSpec.Pods
 	}
+	if podCIDR == nil {
+		log.Info("pod CIDR of the shoot is unknown, skipping security group rule for the pod CIDR: traffic from pods to the nodes may be restricted until the networking of the shoot is populated")
+	}
+
+	// the intra node traffic can only be restricted if the infrastructure is reconciled via the STACKIT API
+	desiredRules := infrainternal.ToOpenStackSecurityGroupRules(infrainternal.DesiredSecurityGroupRules(infrainternal.SecurityGroupRulesOptions{
+		NodePortsCIDR:              nodesCIDR,
+		PodCIDR:                    podCIDR,
+		AllowMetadataServiceEgress: fctx.allowMetadataServiceEgress,
+		AdditionalRules:            fctx.config.AdditionalSecurityGroupRules,
+	}), access.SecurityGroupIDSelf)
 
-	if modified, err := fctx.access.UpdateSecurityGroupRules(ctx, group, desiredRules, func(rule *rules.SecGroupRule) bool {
+	if modified, err := fctx.access.UpdateSecurityGroupRules(ctx, group, desiredRules, fctx.deleteDuplicateSecurityGroupRules, func(rule *rules.SecGroupRule) bool {
 		// Do NOT delete unknown rules to keep permissive behavior as with terraform.
 		// As we don't store the role ids in the state, this function needs to be adjusted
 		// if values in existing rules are changed to identify them for update by replacement.
-		return false
+		// Additional rules are identified by their description and deleted once they are removed from the config.
+		return infrainternal.IsAdditionalSecurityGroupRule(rule.Description)
 	}); err != nil {
 		return err
 	} else if modified {
@@ -518,6 +568,14 @@ func (fctx *FlowContext) ensureSecGroupRules(ctx context.Context) error {
 func (fctx *FlowContext) ensureStackitSSHKeyPair(ctx context.Context) error {
 	log := shared.LogFromContext(ctx)
 
+	if skip, err := fctx.skipSSHKeyPair(); err != nil {
+		return err
+	} else if skip {
+		log.Info("SSH public key is empty, skipping stackit SSH key pair: nodes will not be accessible via SSH")
+		fctx.state.Set(NameKeyPair, "")
+		return fctx.deleteStackitSSHKeyPair(ctx)
+	}
+
 	keyPair, err := fctx.iaasClient.GetKeypair(ctx, fctx.defaultSSHKeypairName())
 	if err != nil {
 		return err
@@ -552,6 +610,14 @@ func (fctx *FlowContext) ensureStackitSSHKeyPair(ctx context.Context) error {
 func (fctx *FlowContext) ensureSSHKeyPair(ctx context.Context) error {
 	log := shared.LogFromContext(ctx)
 
+	if skip, err := fctx.skipSSHKeyPair(); err != nil {
+		return err
+	} else if skip {
+		log.Info("SSH public key is empty, skipping SSH key pair: nodes will not be accessible via SSH")
+		fctx.state.Set(NameKeyPair, "")
+		return fctx.deleteSSHKeyPair(ctx)
+	}
+
 	keyPair, err := fctx.compute.GetKeyPair(ctx, fctx.defaultSSHKeypairName())
 	if err != nil {
 		return err
@@ -0,0 +1,512 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package infraflow
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	gardencorev1beta1helper "github.com/gardener/gardener/pkg/api/core/v1beta1/helper"
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"github.com/go-logr/logr"
+	"github.com/gophercloud/gophercloud/v2"
+	"github.com/gophercloud/gophercloud/v2/openstack/compute/v2/keypairs"
+	"github.com/gophercloud/gophercloud/v2/openstack/networking/v2/extensions/layer3/routers"
+	"github.com/gophercloud/gophercloud/v2/openstack/networking/v2/extensions/security/groups"
+	"github.com/gophercloud/gophercloud/v2/openstack/networking/v2/extensions/security/rules"
+	"github.com/gophercloud/gophercloud/v2/openstack/networking/v2/ports"
+	"github.com/gophercloud/gophercloud/v2/openstack/networking/v2/subnets"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	iaas "github.com/stackitcloud/stackit-sdk-go/services/iaas/v2api"
+	"go.uber.org/mock/gomock"
+
+	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/apis/config"
+	stackitv1alpha1 "github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/apis/stackit/v1alpha1"
+	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/controller/infrastructure/openstack/infraflow/access"
+	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/controller/infrastructure/openstack/infraflow/shared"
+	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/openstack/client/mocks"
+	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/stackit"
+	mockclient "github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/stackit/client/mock"
+)
+
+// fakeNetworkingAccess is a fake for the router, subnet and security group rule methods of the NetworkingAccess.
+type fakeNetworkingAccess struct {
+	access.NetworkingAccess
+
+	routers map[string]*access.Router
+	subnets map[string]*subnets.Subnet
+	// desiredRules are the desired rules of the last UpdateSecurityGroupRules call.
+	desiredRules []rules.SecGroupRule
+}
+
+func (f *fakeNetworkingAccess) GetRouterByName(_ context.Context, name string) ([]*access.Router, error) {
+	var result []*access.Router
+	for _, router := range f.routers {
+		if router.Name == name {
+			result = append(result, router)
+		}
+	}
+	return result, nil
+}
+
+func (f *fakeNetworkingAccess) CreateRouter(_ context.Context, desired *access.Router) (*access.Router, error) {
+	created := *desired
+	created.ID = "managed-router"
+	created.ExternalFixedIPs = []routers.ExternalFixedIP{{IPAddress: "5.6.7.8"}}
+	f.routers[created.ID] = &created
+	return &created, nil
+}
+
+func (f *fakeNetworkingAccess) UpdateRouter(_ context.Context, _, current *access.Router) (bool, *access.Router, error) {
+	return false, current, nil
+}
+
+func (f *fakeNetworkingAccess) GetRouterByID(_ context.Context, id string) (*access.Router, error) {
+	return f.routers[id], nil
+}
+
+func (f *fakeNetworkingAccess) GetSubnetByID(_ context.Context, id string) (*subnets.Subnet, error) {
+	return f.subnets[id], nil
+}
+
+func (f *fakeNetworkingAccess) GetSubnetByName(_ context.Context, networkID, name string) ([]*subnets.Subnet, error) {
+	var result []*subnets.Subnet
+	for _, subnet := range f.subnets {
+		if subnet.NetworkID == networkID && subnet.Name == name {
+			result = append(result, subnet)
+		}
+	}
+	return result, nil
+}
+
+func (f *fakeNetworkingAccess) CreateSubnet(_ context.Context, desired *subnets.Subnet) (*subnets.Subnet, error) {
+	created := *desired
+	created.ID = "managed-subnet"
+	f.subnets[created.ID] = &created
+	return &created, nil
+}
+
+func (f *fakeNetworkingAccess) UpdateSubnet(_ context.Context, _, _ *subnets.Subnet) (bool, error) {
+	return false, nil
+}
+
+func (f *fakeNetworkingAccess) UpdateSecurityGroupRules(_ context.Context, _ *groups.SecGroup, desiredRules []rules.SecGroupRule, _ bool, _ func(rule *rules.SecGroupRule) bool) (bool, error) {
+	f.desiredRules = desiredRules
+	return true, nil
+}
+
+var _ = Describe("OpenStack infraflow reconcile", func() {
+	Describe("#ensureRouterInterface", func() {
+		var (
+			ctx        context.Context
+			ctrl       *gomock.Controller
+			networking *mocks.MockNetworking
+			fctx       *FlowContext
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			ctrl = gomock.NewController(GinkgoT())
+			networking = mocks.NewMockNetworking(ctrl)
+			networkingAccess, err := access.NewNetworkingAccess(ctx, networking, logr.Discard())
+			Expect(err).NotTo(HaveOccurred())
+			fctx = &FlowContext{
+				state:                  shared.NewWhiteboard(),
+				access:                 networkingAccess,
+				routerInterfaceTimeout: time.Minute,
+			}
+			fctx.state.Set(IdentifierRouter, "router")
+			fctx.state.Set(IdentifierSubnet, "subnet")
+		})
+
+		AfterEach(func() {
+			ctrl.Finish()
+		})
+
+		It("should keep an existing interface in the subnet", func() {
+			networking.EXPECT().GetRouterInterfacePort(gomock.Any(), "router", "subnet").Return(&ports.Port{ID: "port"}, nil)
+
+			Expect(fctx.ensureRouterInterface(ctx)).To(Succeed())
+		})
+
+		It("should add the interface if the router has none in the subnet", func() {
+			networking.EXPECT().GetRouterInterfacePort(gomock.Any(), "router", "subnet").Return(nil, nil)
+			networking.EXPECT().AddRouterInterface(gomock.Any(), "router", routers.AddInterfaceOpts{SubnetID: "subnet"}).Return(&routers.InterfaceInfo{PortID: "port"}, nil)
+			networking.EXPECT().GetPort(gomock.Any(), "port").Return(&ports.Port{ID: "port", Status: "ACTIVE"}, nil)
+
+			Expect(fctx.ensureRouterInterface(ctx)).To(Succeed())
+		})
+
+		It("should return a dependency error if the router cannot accept the interface", func() {
+			networking.EXPECT().GetRouterInterfacePort(gomock.Any(), "router", "subnet").Return(nil, nil)
+			networking.EXPECT().AddRouterInterface(gomock.Any(), "router", routers.AddInterfaceOpts{SubnetID: "subnet"}).Return(nil, gophercloud.ErrUnexpectedResponseCode{Actual: http.StatusConflict})
+
+			err := fctx.ensureRouterInterface(ctx)
+			Expect(err).To(MatchError(ContainSubstring("router router could not accept an interface for subnet subnet")))
+			coder, ok := err.(gardencorev1beta1helper.Coder)
+			Expect(ok).To(BeTrue())
+			Expect(coder.Codes()).To(Equal([]gardencorev1beta1.ErrorCode{gardencorev1beta1.ErrorInfraDependencies}))
+		})
+
+		It("should return a retryable error if the interface does not become active in time", func() {
+			fctx.routerInterfaceTimeout = 10 * time.Millisecond
+			networking.EXPECT().GetRouterInterfacePort(gomock.Any(), "router", "subnet").Return(nil, nil)
+			networking.EXPECT().AddRouterInterface(gomock.Any(), "router", routers.AddInterfaceOpts{SubnetID: "subnet"}).Return(&routers.InterfaceInfo{PortID: "port"}, nil)
+
+			err := fctx.ensureRouterInterface(ctx)
+			Expect(err).To(MatchError(ContainSubstring(context.DeadlineExceeded.Error())))
+			coder, ok := err.(gardencorev1beta1helper.Coder)
+			Expect(ok).To(BeTrue())
+			Expect(coder.Codes()).To(Equal([]gardencorev1beta1.ErrorCode{gardencorev1beta1.ErrorRetryableInfraDependencies}))
+
+			// the next reconciliation finds the created interface
+			networking.EXPECT().GetRouterInterfacePort(gomock.Any(), "router", "subnet").Return(&ports.Port{ID: "port"}, nil)
+			Expect(fctx.ensureRouterInterface(ctx)).To(Succeed())
+		})
+
+		It("should return other errors without error codes", func() {
+			networking.EXPECT().GetRouterInterfacePort(gomock.Any(), "router", "subnet").Return(nil, nil)
+			networking.EXPECT().AddRouterInterface(gomock.Any(), "router", routers.AddInterfaceOpts{SubnetID: "subnet"}).Return(nil, fmt.Errorf("timeout"))
+
+			err := fctx.ensureRouterInterface(ctx)
+			Expect(err).To(MatchError(ContainSubstring("timeout")))
+			_, ok := err.(gardencorev1beta1helper.Coder)
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	Describe("#ensureConfiguredRouter", func() {
+		var (
+			ctx        context.Context
+			fakeAccess *fakeNetworkingAccess
+			fctx       *FlowContext
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			fakeAccess = &fakeNetworkingAccess{routers: map[string]*access.Router{
+				"router": {
+					ID:               "router",
+					ProjectID:        "project",
+					ExternalFixedIPs: []routers.ExternalFixedIP{{IPAddress: "1.2.3.4"}},
+				},
+			}}
+			fctx = &FlowContext{
+				state:     shared.NewWhiteboard(),
+				access:    fakeAccess,
+				projectID: "project",
+				config: &stackitv1alpha1.InfrastructureConfig{
+					Networks: stackitv1alpha1.Networks{
+						Router: &stackitv1alpha1.Router{ID: "router"},
+					},
+				},
+			}
+		})
+
+		It("should use a router of the project of the credentials", func() {
+			Expect(fctx.ensureConfiguredRouter(ctx)).To(Succeed())
+			Expect(fctx.state.Get(IdentifierRouter)).To(HaveValue(Equal("router")))
+		})
+
+		It("should report all external fixed IPs of the router as egress CIDRs", func() {
+			fakeAccess.routers["router"].ExternalFixedIPs = []routers.ExternalFixedIP{{IPAddress: "1.2.3.4"}, {IPAddress: "1.2.3.5"}}
+
+			Expect(fctx.ensureConfiguredRouter(ctx)).To(Succeed())
+			Expect(fctx.state.GetObject(IdentifierEgressCIDRs)).To(Equal([]string{"1.2.3.4", "1.2.3.5"}))
+		})
+
+		It("should use the router if its project is unknown", func() {
+			fakeAccess.routers["router"].ProjectID = ""
+
+			Expect(fctx.ensureConfiguredRouter(ctx)).To(Succeed())
+			Expect(fctx.state.Get(IdentifierRouter)).To(HaveValue(Equal("router")))
+		})
+
+		It("should use the router if the project of the credentials is unknown", func() {
+			fctx.projectID = ""
+
+			Expect(fctx.ensureConfiguredRouter(ctx)).To(Succeed())
+			Expect(fctx.state.Get(IdentifierRouter)).To(HaveValue(Equal("router")))
+		})
+
+		It("should reject a router of another project", func() {
+			fakeAccess.routers["router"].ProjectID = "other-project"
+
+			err := fctx.ensureConfiguredRouter(ctx)
+			Expect(err).To(MatchError(ContainSubstring("configured router router belongs to project other-project and not to the project project of the credentials")))
+			coder, ok := err.(gardencorev1beta1helper.Coder)
+			Expect(ok).To(BeTrue())
+			Expect(coder.Codes()).To(Equal([]gardencorev1beta1.ErrorCode{gardencorev1beta1.ErrorConfigurationProblem}))
+			Expect(fctx.state.Get(IdentifierRouter)).To(BeNil())
+		})
+
+		Context("missing router", func() {
+			BeforeEach(func() {
+				delete(fakeAccess.routers, "router")
+				fctx.infra = &extensionsv1alpha1.Infrastructure{}
+				fctx.technicalID = "shoot--foo--bar"
+				fctx.cloudProfileConfig = &stackitv1alpha1.CloudProfileConfig{}
+				fctx.state.Set(IdentifierFloatingNetwork, "floating-network")
+			})
+
+			It("should fail by default", func() {
+				Expect(fctx.ensureRouter(ctx)).To(MatchError("missing expected router router"))
+				Expect(fctx.state.Get(IdentifierRouter)).To(BeNil())
+				Expect(fctx.usesManagedRouterFallback()).To(BeFalse())
+			})
+
+			It("should fall back to a managed router if the annotation is set", func() {
+				fctx.infra.Annotations = map[string]string{stackit.AnnotationRecreateMissingRouter: "true"}
+
+				Expect(fctx.ensureRouter(ctx)).To(Succeed())
+				Expect(fctx.state.Get(IdentifierRouter)).To(HaveValue(Equal("managed-router")))
+				Expect(fctx.state.GetObject(IdentifierEgressCIDRs)).To(Equal([]string{"5.6.7.8"}))
+				Expect(fctx.usesManagedRouterFallback()).To(BeTrue())
+				Expect(fakeAccess.routers["managed-router"].Name).To(Equal("shoot--foo--bar"))
+			})
+
+			It("should keep using the managed router once it replaced the configured router", func() {
+				fctx.state.Set(ManagedRouterFallbackKey, "true")
+				fakeAccess.routers["managed-router"] = &access.Router{
+					ID:               "managed-router",
+					Name:             "shoot--foo--bar",
+					ExternalFixedIPs: []routers.ExternalFixedIP{{IPAddress: "5.6.7.8"}},
+				}
+
+				Expect(fctx.ensureRouter(ctx)).To(Succeed())
+				Expect(fctx.state.Get(IdentifierRouter)).To(HaveValue(Equal("managed-router")))
+			})
+		})
+	})
+
+	Describe("#ensureSubnet", func() {
+		var (
+			ctx        context.Context
+			fakeAccess *fakeNetworkingAccess
+			fctx       *FlowContext
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			fakeAccess = &fakeNetworkingAccess{subnets: map[string]*subnets.Subnet{}}
+			fctx = &FlowContext{
+				state:              shared.NewWhiteboard(),
+				access:             fakeAccess,
+				technicalID:        "shoot--foo--bar",
+				cloudProfileConfig: &stackitv1alpha1.CloudProfileConfig{},
+				config: &stackitv1alpha1.InfrastructureConfig{
+					Networks: stackitv1alpha1.Networks{
+						Workers: "10.250.0.0/16",
+					},
+				},
+			}
+			fctx.state.Set(IdentifierNetwork, "network")
+		})
+
+		It("should report the CIDR of a created subnet as nodes CIDR", func() {
+			Expect(fctx.ensureSubnet(ctx)).To(Succeed())
+			Expect(fctx.state.Get(IdentifierSubnet)).To(HaveValue(Equal("managed-subnet")))
+			Expect(fctx.nodesCIDR).To(HaveValue(Equal("10.250.0.0/16")))
+		})
+
+		It("should reconcile a single nodes subnet without zones", func() {
+			Expect(fctx.ensureSubnet(ctx)).To(Succeed())
+			Expect(fakeAccess.subnets).To(HaveLen(1))
+
+			fctx.state.SetObject(IdentifierEgressCIDRs, []string{})
+			Expect(fctx.computeInfrastructureStatus().Networks.Subnets).To(ConsistOf(stackitv1alpha1.Subnet{
+				Purpose: stackitv1alpha1.PurposeNodes,
+				ID:      "managed-subnet",
+			}))
+		})
+
+		It("should report the CIDR of an existing subnet as nodes CIDR", func() {
+			fakeAccess.subnets["subnet"] = &subnets.Subnet{
+				ID:        "subnet",
+				Name:      "shoot--foo--bar",
+				NetworkID: "network",
+				CIDR:      "10.250.0.0/16",
+			}
+
+			Expect(fctx.ensureSubnet(ctx)).To(Succeed())
+			Expect(fctx.state.Get(IdentifierSubnet)).To(HaveValue(Equal("subnet")))
+			Expect(fctx.nodesCIDR).To(HaveValue(Equal("10.250.0.0/16")))
+		})
+
+		It("should report the CIDR of the configured subnet as nodes CIDR for SNA shoots", func() {
+			fakeAccess.subnets["sna-subnet"] = &subnets.Subnet{
+				ID:        "sna-subnet",
+				NetworkID: "sna-network",
+				CIDR:      "10.1.0.0/24",
+			}
+			fctx.config.Networks.ID = new("sna-network")
+			fctx.config.Networks.SubnetID = new("sna-subnet")
+
+			Expect(fctx.ensureSubnet(ctx)).To(Succeed())
+			Expect(fctx.state.Get(IdentifierSubnet)).To(HaveValue(Equal("sna-subnet")))
+			Expect(fctx.nodesCIDR).To(HaveValue(Equal("10.1.0.0/24")))
+		})
+
+		It("should reject a configured subnet of another network", func() {
+			fakeAccess.subnets["sna-subnet"] = &subnets.Subnet{
+				ID:        "sna-subnet",
+				NetworkID: "other-network",
+				CIDR:      "10.1.0.0/24",
+			}
+			fctx.config.Networks.ID = new("sna-network")
+			fctx.config.Networks.SubnetID = new("sna-subnet")
+
+			err := fctx.ensureSubnet(ctx)
+			Expect(err).To(MatchError("configured subnet sna-subnet belongs to network other-network and not to the network sna-network of the cluster"))
+			coder, ok := err.(gardencorev1beta1helper.Coder)
+			Expect(ok).To(BeTrue())
+			Expect(coder.Codes()).To(Equal([]gardencorev1beta1.ErrorCode{gardencorev1beta1.ErrorConfigurationProblem}))
+			Expect(fctx.state.Get(IdentifierSubnet)).To(BeNil())
+		})
+	})
+
+	Describe("#ensureSecGroupRules", func() {
+		var (
+			ctx        context.Context
+			fakeAccess *fakeNetworkingAccess
+			fctx       *FlowContext
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			fakeAccess = &fakeNetworkingAccess{}
+			fctx = &FlowContext{
+				state:  shared.NewWhiteboard(),
+				access: fakeAccess,
+				config: &stackitv1alpha1.InfrastructureConfig{},
+			}
+			fctx.state.SetObject(ObjectSecGroup, &groups.SecGroup{ID: "security-group-id"})
+		})
+
+		It("should append the additional security group rules", func() {
+			fctx.config.AdditionalSecurityGroupRules = []stackitv1alpha1.SecurityGroupRuleConfig{
+				{Direction: "ingress", Protocol: new("udp"), PortRangeMin: new(int32(1194)), RemoteIPPrefix: new("192.168.0.0/24")},
+				{Direction: "egress", EtherType: new("IPv6"), RemoteSecurityGroupID: new("peer-group"), Description: new("peering")},
+			}
+
+			Expect(fctx.ensureSecGroupRules(ctx)).To(Succeed())
+			Expect(len(fakeAccess.desiredRules)).To(BeNumerically(">", 2))
+			Expect(fakeAccess.desiredRules[len(fakeAccess.desiredRules)-2:]).To(Equal([]rules.SecGroupRule{
+				{
+					Direction:      "ingress",
+					EtherType:      "IPv4",
+					Protocol:       "udp",
+					PortRangeMin:   1194,
+					PortRangeMax:   1194,
+					RemoteIPPrefix: "192.168.0.0/24",
+					Description:    "gardener-additional: IPv4: allow incoming udp traffic with port range 1194-1194 from 192.168.0.0/24",
+				},
+				{
+					Direction:     "egress",
+					EtherType:     "IPv6",
+					RemoteGroupID: "peer-group",
+					Description:   "gardener-additional: peering",
+				},
+			}))
+		})
+	})
+
+	Describe("SSH key pairs", func() {
+		const publicKey = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB"
+
+		var (
+			ctx      context.Context
+			ctrl     *gomock.Controller
+			compute  *mocks.MockCompute
+			mockIaaS *mockclient.MockIaaSClient
+			fctx     *FlowContext
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			ctrl = gomock.NewController(GinkgoT())
+			compute = mocks.NewMockCompute(ctrl)
+			mockIaaS = mockclient.NewMockIaaSClient(ctrl)
+
+			fctx = &FlowContext{
+				state:       shared.NewWhiteboard(),
+				compute:     compute,
+				iaasClient:  mockIaaS,
+				technicalID: "shoot--foo--bar",
+				infra:       &extensionsv1alpha1.Infrastructure{},
+			}
+			fctx.state.Set(NameKeyPair, "shoot--foo--bar")
+		})
+
+		AfterEach(func() {
+			ctrl.Finish()
+		})
+
+		Describe("#ensureSSHKeyPair", func() {
+			DescribeTable("should skip the key pair for an empty SSH public key",
+				func(policy config.EmptySSHPublicKeyPolicy) {
+					fctx.emptySSHPublicKeyPolicy = policy
+
+					compute.EXPECT().GetKeyPair(ctx, "shoot--foo--bar").Return(&keypairs.KeyPair{Name: "shoot--foo--bar", PublicKey: publicKey}, nil)
+					compute.EXPECT().DeleteKeyPair(ctx, "shoot--foo--bar").Return(nil)
+
+					Expect(fctx.ensureSSHKeyPair(ctx)).To(Succeed())
+					Expect(fctx.state.Get(NameKeyPair)).To(BeNil())
+				},
+				Entry("unset policy", config.EmptySSHPublicKeyPolicy("")),
+				Entry("skip policy", config.EmptySSHPublicKeyPolicySkip),
+			)
+
+			It("should not fail if there is no key pair to delete", func() {
+				compute.EXPECT().GetKeyPair(ctx, "shoot--foo--bar").Return(nil, nil)
+
+				Expect(fctx.ensureSSHKeyPair(ctx)).To(Succeed())
+				Expect(fctx.state.Get(NameKeyPair)).To(BeNil())
+			})
+
+			It("should reject an empty SSH public key if configured", func() {
+				fctx.emptySSHPublicKeyPolicy = config.EmptySSHPublicKeyPolicyReject
+				fctx.infra.Spec.SSHPublicKey = []byte("  ")
+
+				err := fctx.ensureSSHKeyPair(ctx)
+				Expect(err).To(MatchError(ContainSubstring("SSH public key of infrastructure is empty")))
+				coder, ok := err.(gardencorev1beta1helper.Coder)
+				Expect(ok).To(BeTrue())
+				Expect(coder.Codes()).To(Equal([]gardencorev1beta1.ErrorCode{gardencorev1beta1.ErrorConfigurationProblem}))
+			})
+		})
+
+		Describe("#ensureStackitSSHKeyPair", func() {
+			DescribeTable("should skip the key pair for an empty SSH public key",
+				func(policy config.EmptySSHPublicKeyPolicy) {
+					fctx.emptySSHPublicKeyPolicy = policy
+
+					mockIaaS.EXPECT().GetKeypair(ctx, "shoot--foo--bar").Return(&iaas.Keypair{Name: new("shoot--foo--bar"), PublicKey: publicKey}, nil)
+					mockIaaS.EXPECT().DeleteKeypair(ctx, "shoot--foo--bar").Return(nil)
+
+					Expect(fctx.ensureStackitSSHKeyPair(ctx)).To(Succeed())
+					Expect(fctx.state.Get(NameKeyPair)).To(BeNil())
+				},
+				Entry("unset policy", config.EmptySSHPublicKeyPolicy("")),
+				Entry("skip policy", config.EmptySSHPublicKeyPolicySkip),
+			)
+
+			It("should reject an empty SSH public key if configured", func() {
+				fctx.emptySSHPublicKeyPolicy = config.EmptySSHPublicKeyPolicyReject
+				fctx.infra.Spec.SSHPublicKey = []byte("  ")
+
+				err := fctx.ensureStackitSSHKeyPair(ctx)
+				Expect(err).To(MatchError(ContainSubstring("SSH public key of infrastructure is empty")))
+				coder, ok := err.(gardencorev1beta1helper.Coder)
+				Expect(ok).To(BeTrue())
+				Expect(coder.Codes()).To(Equal([]gardencorev1beta1.ErrorCode{gardencorev1beta1.ErrorConfigurationProblem}))
+			})
+		})
+	})
+})
@@ -65,6 +65,8 @@ type BasicFlowContext struct {
 
 	span      bool
 	persistFn flow.TaskFn
+	// cluster is the cluster label of the task duration metrics. Metrics are only recorded if it is set.
+	cluster string
 
 	lastPersistedGeneration int64
 	lastPersistedAt         time.Time
@@ -92,6 +94,12 @@ func (c *BasicFlowContext) WithSpan() *BasicFlowContext {
 	return c
 }
 
+// WithMetrics when enabled will record the execution time of the tasks in the TaskDuration metric for the given cluster.
+func (c *BasicFlowContext) WithMetrics(cluster string) *BasicFlowContext {
+	c.cluster = cluster
+	return c
+}
+
 // WithPersist is the Task that will be called after each successful node directly after the node execution.
 func (c *BasicFlowContext) WithPersist(task flow.TaskFn) *BasicFlowContext {
 	c.persistFn = task
@@ -161,13 +169,18 @@ func (c *BasicFlowContext) wrapTaskFn(flowName, taskName string, fn flow.TaskFn)
 		ctx = w.IntoContext(ctx)
 		defer w.Done()
 
-		var beforeTS time.Time
-		if c.span {
-			beforeTS = c.timer.Now()
-		}
+		beforeTS := c.timer.Now()
 		err := fn(ctx)
+		duration := c.timer.Now().Sub(beforeTS)
 		if c.span {
-			log.Info(fmt.Sprintf("task finished - total execution time: %v", c.timer.Now().Sub(beforeTS)))
+			log.Info(fmt.Sprintf("task finished - total execution time: %v", duration))
+		}
+		if c.cluster != "" {
+			result := resultSuccess
+			if err != nil {
+				result = resultError
+			}
+			TaskDuration.WithLabelValues(flowName, taskName, c.cluster, result).Observe(duration.Seconds())
 		}
 		if err != nil {
 			// don't wrap error with '%w', as otherwise the error context get lost
@@ -14,6 +14,8 @@ import (
 	"github.com/go-logr/logr"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
 	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/controller/infrastructure/openstack/infraflow/shared"
@@ -100,4 +102,45 @@ var _ = Describe("BasicFlowContext", func() {
 			Expect(persistedData["task3"]).To(Equal("done"))
 		})
 	})
+
+	It("should record the task durations if metrics are enabled", func() {
+		var (
+			ctx = context.Background()
+			c   = shared.NewBasicFlowContext().WithLogger(logr.Discard()).WithMetrics("shoot--foo--bar")
+			g   = flow.NewGraph("metrics-test")
+		)
+
+		succeeding := c.AddTask(g, "succeeding task", func(_ context.Context) error { return nil })
+		_ = c.AddTask(g, "failing task", func(_ context.Context) error { return fmt.Errorf("forced error") }, shared.Dependencies(succeeding))
+		Expect(g.Compile().Run(ctx, flow.Opts{Log: logr.Discard()})).NotTo(Succeed())
+
+		sampleCount := func(task, result string) uint64 {
+			GinkgoHelper()
+			observer, err := shared.TaskDuration.GetMetricWithLabelValues("metrics-test", task, "shoot--foo--bar", result)
+			Expect(err).NotTo(HaveOccurred())
+			metric := &dto.Metric{}
+			Expect(observer.(prometheus.Metric).Write(metric)).To(Succeed())
+			return metric.GetHistogram().GetSampleCount()
+		}
+		Expect(sampleCount("succeeding task", "success")).To(BeEquivalentTo(1))
+		Expect(sampleCount("failing task", "error")).To(BeEquivalentTo(1))
+		Expect(sampleCount("succeeding task", "error")).To(BeZero())
+	})
+
+	It("should remove the task durations of a deleted cluster", func() {
+		var (
+			ctx = context.Background()
+			g   = flow.NewGraph("metrics-deletion-test")
+		)
+
+		for _, cluster := range []string{"shoot--foo--deleted", "shoot--foo--kept"} {
+			c := shared.NewBasicFlowContext().WithLogger(logr.Discard()).WithMetrics(cluster)
+			_ = c.AddTask(g, "task of "+cluster, func(_ context.Context) error { return nil })
+		}
+		Expect(g.Compile().Run(ctx, flow.Opts{Log: logr.Discard()})).To(Succeed())
+
+		Expect(shared.DeleteTaskDurations("shoot--foo--deleted")).To(Equal(1))
+		Expect(shared.DeleteTaskDurations("shoot--foo--deleted")).To(BeZero())
+		Expect(shared.DeleteTaskDurations("shoot--foo--kept")).To(Equal(1))
+	})
 })
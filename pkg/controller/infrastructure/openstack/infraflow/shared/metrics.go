@@ -0,0 +1,38 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package shared
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	resultSuccess = "success"
+	resultError   = "error"
+)
+
+// TaskDuration is the histogram of the execution durations of flow tasks, labeled by flow, task, cluster and result.
+var TaskDuration = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Namespace: "provider_stackit",
+		Subsystem: "infrastructure_flow",
+		Name:      "task_duration_seconds",
+		Help:      "Duration of the infrastructure flow tasks in seconds.",
+		Buckets:   prometheus.ExponentialBuckets(0.05, 2, 12),
+	},
+	[]string{"flow", "task", "cluster", "result"},
+)
+
+func init() {
+	metrics.Registry.MustRegister(TaskDuration)
+}
+
+// DeleteTaskDurations removes all TaskDuration series of the given cluster. It is called once the infrastructure of
+// the cluster is deleted, as the series would otherwise be exported until the next restart of the extension. It returns
+// the number of removed series.
+func DeleteTaskDurations(cluster string) int {
+	return TaskDuration.DeletePartialMatch(prometheus.Labels{"cluster": cluster})
+}
@@ -5,6 +5,7 @@
 package shared
 
 import (
+	"slices"
 	"sort"
 	"strings"
 	"sync"
@@ -269,6 +270,19 @@ func sortedKeys[V any](m map[string]V) []string {
 	return keys
 }
 
+// PruneUnknownKeys deletes all keys of the whiteboard which are not contained in knownKeys and returns the pruned keys
+// in ascending order. Only the keys on the top level are considered, children and cached objects are left untouched.
+func PruneUnknownKeys(wb Whiteboard, knownKeys ...string) []string {
+	var pruned []string
+	for _, key := range wb.Keys() {
+		if !slices.Contains(knownKeys, key) {
+			wb.Delete(key)
+			pruned = append(pruned, key)
+		}
+	}
+	return pruned
+}
+
 // IsValidValue returns true if an exported value is valid, i.e. not empty and not special value for deleted.
 func IsValidValue(value string) bool {
 	return value != "" && value != deleted
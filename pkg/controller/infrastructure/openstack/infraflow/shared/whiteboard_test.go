@@ -89,4 +89,40 @@ var _ = Describe("Whiteboard", func() {
 		exported := w.ExportAsFlatMap()
 		Expect(exported).To(Equal(expectedData))
 	})
+
+	Describe("#PruneUnknownKeys", func() {
+		It("should only prune unknown keys", func() {
+			w := shared.NewWhiteboard()
+			w.ImportFromFlatMap(shared.FlatMap{
+				"known1":      "id1",
+				"known2":      "<deleted>",
+				"unknown1":    "id3",
+				"unknown2":    "<deleted>",
+				"child/key1":  "id4",
+				"child/stale": "id5",
+			})
+			w.SetObject("object", "value")
+			generation := w.CurrentGeneration()
+
+			Expect(shared.PruneUnknownKeys(w, "known1", "known2", "notPresent")).To(Equal([]string{"unknown1", "unknown2"}))
+			Expect(w.CurrentGeneration()).To(BeNumerically(">", generation))
+			Expect(w.ExportAsFlatMap()).To(Equal(shared.FlatMap{
+				"known1":      "id1",
+				"known2":      "<deleted>",
+				"child/key1":  "id4",
+				"child/stale": "id5",
+			}))
+			Expect(w.GetObject("object")).To(Equal("value"))
+		})
+
+		It("should not prune anything if all keys are known", func() {
+			w := shared.NewWhiteboard()
+			w.Set("known", "id")
+			generation := w.CurrentGeneration()
+
+			Expect(shared.PruneUnknownKeys(w, "known")).To(BeEmpty())
+			Expect(w.CurrentGeneration()).To(Equal(generation))
+			Expect(w.Get("known")).To(Equal(new("id")))
+		})
+	})
 })
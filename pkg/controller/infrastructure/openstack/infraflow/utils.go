@@ -7,9 +7,16 @@ package infraflow
 import (
 	"context"
 	"fmt"
+	"strings"
+
+	gardenv1beta1helper "github.com/gardener/gardener/pkg/api/core/v1beta1/helper"
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+
+	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/apis/config"
 )
 
-// ErrorMultipleMatches is returned when the findExisting finds multiple resources matching a name.
+// ErrorMultipleMatches is returned when the findExisting finds multiple resources matching a name and the resource is
+// not identified by an ID in the state.
 var ErrorMultipleMatches = fmt.Errorf("error multiple matches")
 
 func findExisting[T any](ctx context.Context, id *string, name string,
@@ -33,7 +40,8 @@ func findExisting[T any](ctx context.Context, id *string, name string,
 		return nil, nil
 	}
 	if len(found) > 1 {
-		return nil, fmt.Errorf("%w: found %d matches for name %q", ErrorMultipleMatches, len(found), name)
+		// adopting an arbitrary match could make the cluster use a leftover of a failed reconciliation
+		return nil, fmt.Errorf("%w: found %d matches for name %q, the leftover resources have to be deleted manually", ErrorMultipleMatches, len(found), name)
 	}
 	return found[0], nil
 }
@@ -67,3 +75,18 @@ func (fctx *FlowContext) workerCIDR() string {
 
 	return s
 }
+
+// skipSSHKeyPair returns true if the Infrastructure has no SSH public key and the SSH key pair should not be created.
+// Depending on the configured EmptySSHPublicKeyPolicy an error is returned instead.
+func (fctx *FlowContext) skipSSHKeyPair() (bool, error) {
+	if strings.TrimSpace(string(fctx.infra.Spec.SSHPublicKey)) != "" {
+		return false, nil
+	}
+	if fctx.emptySSHPublicKeyPolicy == config.EmptySSHPublicKeyPolicyReject {
+		return false, gardenv1beta1helper.NewErrorWithCodes(
+			fmt.Errorf("SSH public key of infrastructure is empty, but empty SSH public keys are rejected"),
+			gardencorev1beta1.ErrorConfigurationProblem,
+		)
+	}
+	return true, nil
+}
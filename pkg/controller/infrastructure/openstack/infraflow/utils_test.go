@@ -0,0 +1,69 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package infraflow
+
+import (
+	"context"
+
+	"github.com/gophercloud/gophercloud/v2/openstack/networking/v2/extensions/security/groups"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("OpenStack infraflow utils", func() {
+	Describe("#findExisting", func() {
+		var (
+			ctx       context.Context
+			secGroups []*groups.SecGroup
+		)
+
+		getter := func(_ context.Context, id string) (*groups.SecGroup, error) {
+			for _, group := range secGroups {
+				if group.ID == id {
+					return group, nil
+				}
+			}
+			return nil, nil
+		}
+		finder := func(_ context.Context, name string) ([]*groups.SecGroup, error) {
+			var found []*groups.SecGroup
+			for _, group := range secGroups {
+				if group.Name == name {
+					found = append(found, group)
+				}
+			}
+			return found, nil
+		}
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			secGroups = nil
+		})
+
+		It("should return nil without matches", func() {
+			Expect(findExisting(ctx, nil, "shoot--foo--bar", getter, finder)).To(BeNil())
+		})
+
+		It("should return the single match of the name", func() {
+			secGroups = []*groups.SecGroup{{ID: "group-1", Name: "shoot--foo--bar"}, {ID: "group-2", Name: "other"}}
+
+			Expect(findExisting(ctx, nil, "shoot--foo--bar", getter, finder)).To(HaveField("ID", "group-1"))
+		})
+
+		It("should fail for multiple matches of the name", func() {
+			secGroups = []*groups.SecGroup{{ID: "group-1", Name: "shoot--foo--bar"}, {ID: "group-2", Name: "shoot--foo--bar"}}
+
+			_, err := findExisting(ctx, nil, "shoot--foo--bar", getter, finder)
+			Expect(err).To(MatchError(ErrorMultipleMatches))
+			Expect(err).To(MatchError(ContainSubstring(`found 2 matches for name "shoot--foo--bar"`)))
+		})
+
+		It("should prefer the resource of the ID in the state over multiple matches of the name", func() {
+			secGroups = []*groups.SecGroup{{ID: "group-1", Name: "shoot--foo--bar"}, {ID: "group-2", Name: "shoot--foo--bar"}}
+
+			Expect(findExisting(ctx, new("group-2"), "shoot--foo--bar", getter, finder)).To(HaveField("ID", "group-2"))
+		})
+	})
+})
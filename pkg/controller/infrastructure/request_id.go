@@ -0,0 +1,54 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	v1beta1helper "github.com/gardener/gardener/pkg/api/core/v1beta1/helper"
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	stackitclient "github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/stackit/client"
+)
+
+const (
+	// ConditionTypeAPIRequestsSucceeded is the condition type of the Infrastructure reporting whether the last operation
+	// failed because of a STACKIT API request. If so, the condition contains the ID of the failed request.
+	ConditionTypeAPIRequestsSucceeded gardencorev1beta1.ConditionType = "APIRequestsSucceeded"
+
+	reasonAPIRequestFailed     = "APIRequestFailed"
+	reasonAPIRequestsSucceeded = "APIRequestsSucceeded"
+)
+
+// reportAPIRequests records the ID of the failed STACKIT API request in a condition of the given Infrastructure if the
+// given operation error contains one, so that operators can hand it to STACKIT support. The condition is only added
+// once a request failed and is set to true again after the next successful operation. Errors without a request ID leave
+// the condition untouched. The given operation error is returned.
+func (a *actuator) reportAPIRequests(ctx context.Context, infra *extensionsv1alpha1.Infrastructure, opErr error) error {
+	existing := v1beta1helper.GetCondition(infra.Status.Conditions, ConditionTypeAPIRequestsSucceeded)
+
+	condition := v1beta1helper.GetOrInitConditionWithClock(a.clock, infra.Status.Conditions, ConditionTypeAPIRequestsSucceeded)
+	switch requestID := stackitclient.GetRequestID(opErr); {
+	case requestID != "":
+		condition = v1beta1helper.UpdatedConditionWithClock(a.clock, condition, gardencorev1beta1.ConditionFalse, reasonAPIRequestFailed,
+			fmt.Sprintf("STACKIT API request %s failed: %s", requestID, opErr.Error()))
+	case opErr == nil && existing != nil && existing.Status != gardencorev1beta1.ConditionTrue:
+		condition = v1beta1helper.UpdatedConditionWithClock(a.clock, condition, gardencorev1beta1.ConditionTrue, reasonAPIRequestsSucceeded,
+			"The STACKIT API requests of the last operation succeeded")
+	default:
+		return opErr
+	}
+
+	patch := client.MergeFrom(infra.DeepCopy())
+	infra.Status.Conditions = v1beta1helper.MergeConditions(infra.Status.Conditions, condition)
+	if err := a.client.Status().Patch(ctx, infra, patch); err != nil {
+		return errors.Join(opErr, fmt.Errorf("could not update the %s condition: %w", ConditionTypeAPIRequestsSucceeded, err))
+	}
+	return opErr
+}
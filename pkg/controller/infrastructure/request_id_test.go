@@ -0,0 +1,75 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	v1beta1helper "github.com/gardener/gardener/pkg/api/core/v1beta1/helper"
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	testclock "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	stackitclient "github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/stackit/client"
+)
+
+var _ = Describe("API request condition", func() {
+	var (
+		ctx   context.Context
+		c     client.Client
+		a     *actuator
+		infra *extensionsv1alpha1.Infrastructure
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme := runtime.NewScheme()
+		Expect(extensionsv1alpha1.AddToScheme(scheme)).To(Succeed())
+
+		infra = &extensionsv1alpha1.Infrastructure{ObjectMeta: metav1.ObjectMeta{Name: "infra", Namespace: "shoot--foo--bar"}}
+		c = fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(infra).WithStatusSubresource(infra).Build()
+		a = &actuator{client: c, clock: testclock.NewFakePassiveClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))}
+	})
+
+	condition := func() *gardencorev1beta1.Condition {
+		current := &extensionsv1alpha1.Infrastructure{}
+		ExpectWithOffset(1, c.Get(ctx, client.ObjectKeyFromObject(infra), current)).To(Succeed())
+		return v1beta1helper.GetCondition(current.Status.Conditions, ConditionTypeAPIRequestsSucceeded)
+	}
+
+	It("should not add the condition if no request failed", func() {
+		Expect(a.reportAPIRequests(ctx, infra, nil)).To(Succeed())
+		Expect(a.reportAPIRequests(ctx, infra, errors.New("boom"))).To(MatchError("boom"))
+
+		Expect(condition()).To(BeNil())
+	})
+
+	It("should record the ID of a failed request and reset the condition after a successful operation", func() {
+		opErr := fmt.Errorf("could not create network: %w", &stackitclient.RequestIDError{Err: errors.New("boom"), RequestID: "req-1234"})
+
+		Expect(a.reportAPIRequests(ctx, infra, opErr)).To(MatchError(opErr))
+		Expect(condition()).To(PointTo(MatchFields(IgnoreExtras, Fields{
+			"Status":  Equal(gardencorev1beta1.ConditionFalse),
+			"Reason":  Equal(reasonAPIRequestFailed),
+			"Message": And(ContainSubstring("req-1234"), ContainSubstring("could not create network")),
+		})))
+
+		Expect(a.reportAPIRequests(ctx, infra, nil)).To(Succeed())
+		Expect(condition()).To(PointTo(MatchFields(IgnoreExtras, Fields{
+			"Status": Equal(gardencorev1beta1.ConditionTrue),
+			"Reason": Equal(reasonAPIRequestsSucceeded),
+		})))
+	})
+})
@@ -9,21 +9,26 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 
+	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/apis/config"
 	stackitv1alpha1 "github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/apis/stackit/v1alpha1"
 )
 
 type actuator struct {
-	client            client.Client
-	restConfig        *rest.Config
-	customLabelDomain string
+	client                  client.Client
+	restConfig              *rest.Config
+	customLabelDomain       string
+	clusterLabelValueSource config.ClusterLabelValueSource
+	configuration           config.InfrastructureControllerConfiguration
 }
 
 // NewActuator creates a new Actuator that updates the status of the handled Infrastructure resources.
-func NewActuator(mgr manager.Manager, customLabelDomain string) infrastructure.Actuator {
+func NewActuator(mgr manager.Manager, customLabelDomain string, clusterLabelValueSource config.ClusterLabelValueSource, configuration config.InfrastructureControllerConfiguration) infrastructure.Actuator {
 	return &actuator{
-		client:            mgr.GetClient(),
-		restConfig:        mgr.GetConfig(),
-		customLabelDomain: customLabelDomain,
+		client:                  mgr.GetClient(),
+		restConfig:              mgr.GetConfig(),
+		customLabelDomain:       customLabelDomain,
+		clusterLabelValueSource: clusterLabelValueSource,
+		configuration:           configuration,
 	}
 }
 
@@ -84,19 +84,27 @@ func (a *actuator) delete(ctx context.Context, log logr.Logger, infra *extension
 		useOpenStackClient = true
 	}
 
+	customLabelDomain, err := helper.CustomLabelDomainFromCluster(cluster, a.customLabelDomain)
+	if err != nil {
+		return err
+	}
+
 	fctx, err := infraflow.NewFlowContext(ctx, infraflow.Opts{
-		Log:                log,
-		Infrastructure:     infra,
-		State:              infraState,
-		Cluster:            cluster,
-		ClientFactory:      clientFactory,
-		UseOpenStackClient: useOpenStackClient,
-		Client:             a.client,
-		IaaSClient:         iaasClient,
-		StackitALB:         stackitALBClient,
-		StackitALBCert:     stackitALBCertClient,
-		StackitLB:          stackitLBClient,
-		CustomLabelDomain:  a.customLabelDomain,
+		Log:                        log,
+		Infrastructure:             infra,
+		State:                      infraState,
+		Cluster:                    cluster,
+		ClientFactory:              clientFactory,
+		UseOpenStackClient:         useOpenStackClient,
+		Client:                     a.client,
+		IaaSClient:                 iaasClient,
+		StackitALB:                 stackitALBClient,
+		StackitALBCert:             stackitALBCertClient,
+		StackitLB:                  stackitLBClient,
+		CustomLabelDomain:          customLabelDomain,
+		ClusterLabelValueSource:    a.clusterLabelValueSource,
+		LoadBalancerRequestTimeout: a.configuration.LoadBalancerRequestTimeout,
+		LoadBalancerRequestRetries: a.configuration.LoadBalancerRequestRetries,
 	})
 	if err != nil {
 		return fmt.Errorf("failed to create flow context: %w", err)
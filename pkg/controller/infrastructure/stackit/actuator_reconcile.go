@@ -12,6 +12,7 @@ import (
 	"github.com/gardener/gardener/extensions/pkg/util"
 	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
 	"github.com/go-logr/logr"
+	"k8s.io/utils/ptr"
 
 	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/apis/stackit/helper"
 	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/controller/infrastructure/stackit/infraflow"
@@ -58,16 +59,26 @@ func (a *actuator) reconcile(ctx context.Context, log logr.Logger, infra *extens
 		return err
 	}
 
+	customLabelDomain, err := helper.CustomLabelDomainFromCluster(cluster, a.customLabelDomain)
+	if err != nil {
+		return err
+	}
+
 	fctx, err := infraflow.NewFlowContext(ctx, infraflow.Opts{
-		Log:                log,
-		Infrastructure:     infra,
-		State:              infraState,
-		Cluster:            cluster,
-		ClientFactory:      clientFactory,
-		Client:             a.client,
-		IaaSClient:         iaasClient,
-		UseOpenStackClient: useOpenStackClient,
-		CustomLabelDomain:  a.customLabelDomain,
+		Log:                               log,
+		Infrastructure:                    infra,
+		State:                             infraState,
+		Cluster:                           cluster,
+		ClientFactory:                     clientFactory,
+		Client:                            a.client,
+		IaaSClient:                        iaasClient,
+		UseOpenStackClient:                useOpenStackClient,
+		CustomLabelDomain:                 customLabelDomain,
+		EmptySSHPublicKeyPolicy:           a.configuration.EmptySSHPublicKeyPolicy,
+		AggregateEgressCIDRs:              a.configuration.AggregateEgressCIDRs,
+		SecurityGroupDescription:          a.configuration.SecurityGroupDescription,
+		DeleteDuplicateSecurityGroupRules: a.configuration.DeleteDuplicateSecurityGroupRules,
+		AllowMetadataServiceEgress:        ptr.Deref(a.configuration.AllowMetadataServiceEgress, true),
 	})
 	if err != nil {
 		return fmt.Errorf("failed to create flow context: %w", err)
@@ -13,6 +13,7 @@ import (
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/apis/config"
 	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/apis/stackit/helper"
 	stackitv1alpha1 "github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/apis/stackit/v1alpha1"
 	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/controller/infrastructure/openstack/infraflow/access"
@@ -61,30 +62,55 @@ type Opts struct {
 	IaaSClient         stackitclient.IaaSClient
 	UseOpenStackClient bool
 	CustomLabelDomain  string
+	// EmptySSHPublicKeyPolicy defines how an Infrastructure without SSH public key is handled.
+	EmptySSHPublicKeyPolicy config.EmptySSHPublicKeyPolicy
+	// AggregateEgressCIDRs merges contiguous egress IPs into the smallest covering CIDRs.
+	AggregateEgressCIDRs bool
+	// SecurityGroupDescription is the template of the description of newly created security groups.
+	SecurityGroupDescription string
+	// DeleteDuplicateSecurityGroupRules deletes exact duplicates of desired security group rules.
+	DeleteDuplicateSecurityGroupRules bool
+	// AllowMetadataServiceEgress adds an explicit egress rule for the instance metadata service.
+	AllowMetadataServiceEgress bool
+	// ClusterLabelValueSource defines which identifier of the shoot is used as value of the cluster label of the STACKIT
+	// load balancers which are deleted with the infrastructure.
+	ClusterLabelValueSource config.ClusterLabelValueSource
+	// LoadBalancerRequestTimeout is the timeout of a single STACKIT load balancer API request when deleting.
+	LoadBalancerRequestTimeout *metav1.Duration
+	// LoadBalancerRequestRetries is the number of retries of STACKIT load balancer API requests failing transiently.
+	LoadBalancerRequestRetries *int32
 }
 
 type FlowContext struct {
-	state                   shared.Whiteboard
-	client                  client.Client
-	log                     logr.Logger
-	infra                   *extensionsv1alpha1.Infrastructure
-	config                  *stackitv1alpha1.InfrastructureConfig
-	cloudProfileConfig      *stackitv1alpha1.CloudProfileConfig
-	cluster                 *extensionscontroller.Cluster
-	networkSpec             *corev1beta1.Networking
-	access                  access.NetworkingAccess
-	compute                 osclient.Compute
-	networking              osclient.Networking
-	isSNAShoot              bool
-	nodesCIDR               *string
-	dnsNameservers          *[]string
-	stackitLB               stackitclient.LoadBalancingClient
-	stackitALB              stackitclient.ApplicationLoadBalancingClient
-	stackitALBCert          stackitclient.ApplicationLoadBalancerCertificateClient
-	iaasClient              stackitclient.IaaSClient
-	hasStackitMCM           bool
-	hasOpenStackCredentials bool
-	technicalID             string
+	state                             shared.Whiteboard
+	client                            client.Client
+	log                               logr.Logger
+	infra                             *extensionsv1alpha1.Infrastructure
+	config                            *stackitv1alpha1.InfrastructureConfig
+	cloudProfileConfig                *stackitv1alpha1.CloudProfileConfig
+	cluster                           *extensionscontroller.Cluster
+	networkSpec                       *corev1beta1.Networking
+	access                            access.NetworkingAccess
+	compute                           osclient.Compute
+	networking                        osclient.Networking
+	isSNAShoot                        bool
+	nodesCIDR                         *string
+	dnsNameservers                    *[]string
+	stackitLB                         stackitclient.LoadBalancingClient
+	stackitALB                        stackitclient.ApplicationLoadBalancingClient
+	stackitALBCert                    stackitclient.ApplicationLoadBalancerCertificateClient
+	iaasClient                        stackitclient.IaaSClient
+	hasStackitMCM                     bool
+	hasOpenStackCredentials           bool
+	technicalID                       string
+	customLabelDomain                 string
+	emptySSHPublicKeyPolicy           config.EmptySSHPublicKeyPolicy
+	aggregateEgressCIDRs              bool
+	securityGroupDescription          string
+	deleteDuplicateSecurityGroupRules bool
+	allowMetadataServiceEgress        bool
+	clusterLabelValueSource           config.ClusterLabelValueSource
+	loadBalancerRetryConfig           stackitclient.RetryConfig
 
 	*shared.BasicFlowContext
 }
@@ -114,22 +140,30 @@ func NewFlowContext(ctx context.Context, opts Opts) (*FlowContext, error) {
 	}
 
 	flowContext := &FlowContext{
-		state:                   whiteboard,
-		infra:                   opts.Infrastructure,
-		config:                  infraConfig,
-		cloudProfileConfig:      cloudProfileConfig,
-		networkSpec:             networkSpec,
-		isSNAShoot:              isSNAShoot,
-		log:                     opts.Log,
-		client:                  opts.Client,
-		cluster:                 opts.Cluster,
-		stackitLB:               opts.StackitLB,
-		stackitALB:              opts.StackitALB,
-		stackitALBCert:          opts.StackitALBCert,
-		iaasClient:              opts.IaaSClient,
-		hasStackitMCM:           feature.UseStackitMachineControllerManager(opts.Cluster),
-		hasOpenStackCredentials: opts.UseOpenStackClient,
-		technicalID:             opts.Cluster.Shoot.Status.TechnicalID,
+		state:                             whiteboard,
+		infra:                             opts.Infrastructure,
+		config:                            infraConfig,
+		cloudProfileConfig:                cloudProfileConfig,
+		networkSpec:                       networkSpec,
+		isSNAShoot:                        isSNAShoot,
+		log:                               opts.Log,
+		client:                            opts.Client,
+		cluster:                           opts.Cluster,
+		stackitLB:                         opts.StackitLB,
+		stackitALB:                        opts.StackitALB,
+		stackitALBCert:                    opts.StackitALBCert,
+		iaasClient:                        opts.IaaSClient,
+		hasStackitMCM:                     feature.UseStackitMachineControllerManager(opts.Cluster),
+		hasOpenStackCredentials:           opts.UseOpenStackClient,
+		technicalID:                       opts.Cluster.Shoot.Status.TechnicalID,
+		customLabelDomain:                 opts.CustomLabelDomain,
+		emptySSHPublicKeyPolicy:           opts.EmptySSHPublicKeyPolicy,
+		aggregateEgressCIDRs:              opts.AggregateEgressCIDRs,
+		securityGroupDescription:          opts.SecurityGroupDescription,
+		deleteDuplicateSecurityGroupRules: opts.DeleteDuplicateSecurityGroupRules,
+		allowMetadataServiceEgress:        opts.AllowMetadataServiceEgress,
+		clusterLabelValueSource:           opts.ClusterLabelValueSource,
+		loadBalancerRetryConfig:           infrainternal.NewLoadBalancerRetryConfig(opts.LoadBalancerRequestTimeout, opts.LoadBalancerRequestRetries),
 	}
 
 	// Check if we have a valid ClientFactory
@@ -157,7 +191,7 @@ func NewFlowContext(ctx context.Context, opts Opts) (*FlowContext, error) {
 
 func (fctx *FlowContext) persistState(ctx context.Context) error {
 	// status is nil such that there's no need to pass the nodesCIDR
-	return infrainternal.PatchProviderStatusAndState(ctx, fctx.client, fctx.infra, nil, nil, fctx.computeInfrastructureState())
+	return infrainternal.PatchProviderStatusAndState(ctx, fctx.client, fctx.infra, nil, nil, false, fctx.computeInfrastructureState())
 }
 
 func (fctx *FlowContext) computeInfrastructureStatus() *stackitv1alpha1.InfrastructureStatus {
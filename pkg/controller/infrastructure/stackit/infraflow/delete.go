@@ -14,13 +14,14 @@ import (
 )
 
 func (fctx *FlowContext) Delete(ctx context.Context) error {
-	fctx.BasicFlowContext = shared.NewBasicFlowContext().WithSpan().WithLogger(fctx.log).WithPersist(fctx.persistState)
+	fctx.BasicFlowContext = shared.NewBasicFlowContext().WithSpan().WithMetrics(fctx.technicalID).WithLogger(fctx.log).WithPersist(fctx.persistState)
 	g := fctx.buildDeleteGraph()
 	f := g.Compile()
 
 	if err := f.Run(ctx, flow.Opts{Log: fctx.log}); err != nil {
 		return flow.Causes(err)
 	}
+	shared.DeleteTaskDurations(fctx.technicalID)
 	return nil
 }
 
@@ -70,16 +71,22 @@ func (fctx *FlowContext) buildDeleteGraph() *flow.Graph {
 
 func (fctx *FlowContext) ensureStackitLoadBalancerDeletion(ctx context.Context) error {
 	log := shared.LogFromContext(ctx)
-	lb, err := fctx.stackitLB.ListLoadBalancers(ctx)
+	clusterLabel, err := fctx.clusterLabelValue()
+	if err != nil {
+		return err
+	}
+	lb, err := stackitclient.Retry(ctx, &fctx.loadBalancerRetryConfig, fctx.stackitLB.ListLoadBalancers)
 	if err != nil {
 		return err
 	}
 	for i := range lb {
 		// Filter out all other LB's that are in the project but do not long belong to this shoot
 		// TODO: use utils.BuildLabelKey
-		if val, ok := lb[i].GetLabels()[controlplane.STACKITLBClusterLabelKey]; ok && val == fctx.technicalID {
+		if val, ok := lb[i].GetLabels()[controlplane.STACKITLBClusterLabelKey]; ok && val == clusterLabel {
 			log.Info("deleting...", "load balancer", lb[i].GetName())
-			err = fctx.stackitLB.DeleteLoadBalancer(ctx, lb[i].GetName())
+			err = stackitclient.RetryNoResult(ctx, &fctx.loadBalancerRetryConfig, func(ctx context.Context) error {
+				return stackitclient.IgnoreNotFoundError(fctx.stackitLB.DeleteLoadBalancer(ctx, lb[i].GetName()))
+			})
 			if err != nil {
 				return err
 			}
@@ -90,32 +97,40 @@ func (fctx *FlowContext) ensureStackitLoadBalancerDeletion(ctx context.Context)
 
 func (fctx *FlowContext) ensureSTACKIALBDeletion(ctx context.Context) error {
 	log := shared.LogFromContext(ctx)
-	alb, err := fctx.stackitALB.ListLoadBalancers(ctx)
+	clusterLabel, err := fctx.clusterLabelValue()
+	if err != nil {
+		return err
+	}
+	alb, err := stackitclient.Retry(ctx, &fctx.loadBalancerRetryConfig, fctx.stackitALB.ListLoadBalancers)
 	if err != nil {
 		return err
 	}
 	for i := range alb {
 		// Filter out all other ALB's that are in the project but do not long belong to this shoot
 		// TODO: migrate to utils.BuildLabelKey
-		if val, ok := alb[i].GetLabels()[controlplane.STACKITLBClusterLabelKey]; ok && val == fctx.technicalID {
+		if val, ok := alb[i].GetLabels()[controlplane.STACKITLBClusterLabelKey]; ok && val == clusterLabel {
 			log.Info("deleting...", "application load balancer", alb[i].GetName())
-			err = fctx.stackitALB.DeleteLoadBalancer(ctx, alb[i].GetName())
+			err = stackitclient.RetryNoResult(ctx, &fctx.loadBalancerRetryConfig, func(ctx context.Context) error {
+				return stackitclient.IgnoreNotFoundError(fctx.stackitALB.DeleteLoadBalancer(ctx, alb[i].GetName()))
+			})
 			if err != nil {
 				return err
 			}
 		}
 	}
 
-	albCerts, err := fctx.stackitALBCert.ListApplicationLoadBalancerCertificates(ctx)
+	albCerts, err := stackitclient.Retry(ctx, &fctx.loadBalancerRetryConfig, fctx.stackitALBCert.ListApplicationLoadBalancerCertificates)
 	if err != nil {
 		return err
 	}
 	for i := range albCerts {
 		// Filter out all other ALB's that are in the project but do not long belong to this shoot
 		// TODO: migrate to utils.BuildLabelKey
-		if val, ok := albCerts[i].GetLabels()[controlplane.STACKITLBClusterLabelKey]; ok && val == fctx.technicalID {
+		if val, ok := albCerts[i].GetLabels()[controlplane.STACKITLBClusterLabelKey]; ok && val == clusterLabel {
 			log.Info("deleting...", "application load balancer certificate", albCerts[i].GetName())
-			err = fctx.stackitALBCert.DeleteApplicationLoadBalancerCertificates(ctx, albCerts[i].GetId())
+			err = stackitclient.RetryNoResult(ctx, &fctx.loadBalancerRetryConfig, func(ctx context.Context) error {
+				return stackitclient.IgnoreNotFoundError(fctx.stackitALBCert.DeleteApplicationLoadBalancerCertificates(ctx, albCerts[i].GetId()))
+			})
 			if err != nil {
 				return err
 			}
@@ -205,3 +220,9 @@ func (fctx *FlowContext) deleteStackitSSHKeyPair(ctx context.Context) error {
 	}
 	return nil
 }
+
+// clusterLabelValue returns the value of the cluster label of the STACKIT load balancers of the shoot. It must match the
+// value the control plane components label the load balancers with.
+func (fctx *FlowContext) clusterLabelValue() (string, error) {
+	return controlplane.ClusterLabelValue(fctx.cluster, fctx.clusterLabelValueSource)
+}
@@ -0,0 +1,107 @@
+package infraflow
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	extensionscontroller "github.com/gardener/gardener/extensions/pkg/controller"
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	loadbalancer "github.com/stackitcloud/stackit-sdk-go/services/loadbalancer/v2api"
+	"go.uber.org/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/apis/config"
+	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/controller/controlplane"
+	stackitclient "github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/stackit/client"
+	mockclient "github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/stackit/client/mock"
+)
+
+var _ = Describe("STACKIT infraflow delete", func() {
+	Describe("#ensureStackitLoadBalancerDeletion", func() {
+		var (
+			ctx    context.Context
+			ctrl   *gomock.Controller
+			mockLB *mockclient.MockLoadBalancingClient
+			fctx   *FlowContext
+
+			unavailable = &stackitclient.Error{Message: "service unavailable", StatusCode: http.StatusServiceUnavailable}
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			ctrl = gomock.NewController(GinkgoT())
+			mockLB = mockclient.NewMockLoadBalancingClient(ctrl)
+
+			fctx = &FlowContext{
+				stackitLB:   mockLB,
+				technicalID: "shoot--foo--bar",
+				cluster: &extensionscontroller.Cluster{
+					Shoot: &gardencorev1beta1.Shoot{
+						ObjectMeta: metav1.ObjectMeta{Name: "bar", UID: "shoot-uid"},
+						Status:     gardencorev1beta1.ShootStatus{TechnicalID: "shoot--foo--bar"},
+					},
+				},
+				loadBalancerRetryConfig: stackitclient.RetryConfig{
+					MaxAttempts:    3,
+					BaseDelay:      time.Millisecond,
+					AttemptTimeout: time.Second,
+				},
+			}
+		})
+
+		AfterEach(func() {
+			ctrl.Finish()
+		})
+
+		It("retries transient errors of the load balancer API", func() {
+			lbs := []loadbalancer.LoadBalancer{
+				{Name: new("own"), Labels: &map[string]string{controlplane.STACKITLBClusterLabelKey: "shoot--foo--bar"}},
+				{Name: new("other"), Labels: &map[string]string{controlplane.STACKITLBClusterLabelKey: "shoot--foo--baz"}},
+			}
+			gomock.InOrder(
+				mockLB.EXPECT().ListLoadBalancers(gomock.Any()).Return(nil, unavailable),
+				mockLB.EXPECT().ListLoadBalancers(gomock.Any()).Return(lbs, nil),
+				mockLB.EXPECT().DeleteLoadBalancer(gomock.Any(), "own").Return(unavailable),
+				mockLB.EXPECT().DeleteLoadBalancer(gomock.Any(), "own").Return(nil),
+			)
+
+			Expect(fctx.ensureStackitLoadBalancerDeletion(ctx)).To(Succeed())
+		})
+
+		It("deletes the load balancers labeled with the shoot UID if configured", func() {
+			fctx.clusterLabelValueSource = config.ClusterLabelValueSourceShootUID
+			lbs := []loadbalancer.LoadBalancer{
+				{Name: new("own"), Labels: &map[string]string{controlplane.STACKITLBClusterLabelKey: "shoot-uid"}},
+				{Name: new("technical-id"), Labels: &map[string]string{controlplane.STACKITLBClusterLabelKey: "shoot--foo--bar"}},
+			}
+			gomock.InOrder(
+				mockLB.EXPECT().ListLoadBalancers(gomock.Any()).Return(lbs, nil),
+				mockLB.EXPECT().DeleteLoadBalancer(gomock.Any(), "own").Return(nil),
+			)
+
+			Expect(fctx.ensureStackitLoadBalancerDeletion(ctx)).To(Succeed())
+		})
+
+		It("ignores load balancers which are already gone when retrying the deletion", func() {
+			lbs := []loadbalancer.LoadBalancer{
+				{Name: new("own"), Labels: &map[string]string{controlplane.STACKITLBClusterLabelKey: "shoot--foo--bar"}},
+			}
+			gomock.InOrder(
+				mockLB.EXPECT().ListLoadBalancers(gomock.Any()).Return(lbs, nil),
+				mockLB.EXPECT().DeleteLoadBalancer(gomock.Any(), "own").Return(unavailable),
+				mockLB.EXPECT().DeleteLoadBalancer(gomock.Any(), "own").Return(stackitclient.NewNotFoundError("load balancer", "own")),
+			)
+
+			Expect(fctx.ensureStackitLoadBalancerDeletion(ctx)).To(Succeed())
+		})
+
+		It("fails once all retries are exhausted", func() {
+			mockLB.EXPECT().ListLoadBalancers(gomock.Any()).Return(nil, unavailable).Times(3)
+
+			Expect(fctx.ensureStackitLoadBalancerDeletion(ctx)).To(MatchError("service unavailable"))
+		})
+	})
+})
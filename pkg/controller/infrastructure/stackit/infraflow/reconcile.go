@@ -9,6 +9,7 @@ import (
 	gardenv1beta1helper "github.com/gardener/gardener/pkg/api/core/v1beta1/helper"
 	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
 	"github.com/gardener/gardener/pkg/utils/flow"
+	"github.com/gophercloud/gophercloud/v2/openstack/networking/v2/subnets"
 	iaas "github.com/stackitcloud/stackit-sdk-go/services/iaas/v2api"
 	"k8s.io/utils/ptr"
 
@@ -16,6 +17,7 @@ import (
 	infrainternal "github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/internal/infrastructure"
 	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/stackit"
 	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/stackit/client"
+	stackitutils "github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/utils"
 )
 
 const (
@@ -23,7 +25,8 @@ const (
 )
 
 func (fctx *FlowContext) Reconcile(ctx context.Context) error {
-	fctx.BasicFlowContext = shared.NewBasicFlowContext().WithSpan().WithLogger(fctx.log).WithPersist(fctx.persistState)
+	fctx.BasicFlowContext = shared.NewBasicFlowContext().WithSpan().WithMetrics(fctx.technicalID).WithLogger(fctx.log).WithPersist(fctx.persistState)
+	fctx.logIgnoredFields()
 	g := fctx.buildReconcileGraph()
 	f := g.Compile()
 	if err := f.Run(ctx, flow.Opts{Log: fctx.log}); err != nil {
@@ -33,7 +36,7 @@ func (fctx *FlowContext) Reconcile(ctx context.Context) error {
 
 	state := fctx.computeInfrastructureState()
 	status := fctx.computeInfrastructureStatus()
-	return infrainternal.PatchProviderStatusAndState(ctx, fctx.client, fctx.infra, status, fctx.nodesCIDR, state)
+	return infrainternal.PatchProviderStatusAndState(ctx, fctx.client, fctx.infra, status, fctx.nodesCIDR, fctx.aggregateEgressCIDRs, state)
 }
 
 func (fctx *FlowContext) buildReconcileGraph() *flow.Graph {
@@ -50,7 +53,7 @@ func (fctx *FlowContext) buildReconcileGraph() *flow.Graph {
 		shared.Timeout(defaultTimeout),
 		shared.Dependencies(ensureExternalNetwork))
 
-	_ = fctx.AddTask(g, "ensure openstack subnet id",
+	ensureOpenStackSubnetID := fctx.AddTask(g, "ensure openstack subnet id",
 		fctx.ensureOpenStackSubnetID,
 		shared.Timeout(defaultTimeout), shared.Dependencies(ensureNetwork),
 		shared.DoIf(fctx.hasOpenStackCredentials),
@@ -65,9 +68,11 @@ func (fctx *FlowContext) buildReconcileGraph() *flow.Graph {
 		fctx.ensureSecGroup,
 		shared.Timeout(defaultTimeout), shared.Dependencies(ensureNetwork))
 
+	// The rules reference the nodes CIDR of the network, so they must only be created once the network and subnet are
+	// reconciled, not only transitively via the security group.
 	_ = fctx.AddTask(g, "ensure security group rules",
 		fctx.ensureSecGroupRules,
-		shared.Timeout(defaultTimeout), shared.Dependencies(ensureSecGroup))
+		shared.Timeout(defaultTimeout), shared.Dependencies(ensureSecGroup, ensureNetwork, ensureOpenStackSubnetID))
 
 	_ = fctx.AddTask(g, "ensure openstack keypair",
 		fctx.ensureOpenStackKeyPair,
@@ -83,7 +88,7 @@ func (fctx *FlowContext) buildReconcileGraph() *flow.Graph {
 }
 
 func (fctx *FlowContext) ensureExternalNetwork(ctx context.Context) error {
-	externalNetwork, err := fctx.networking.GetExternalNetworkByName(ctx, fctx.config.FloatingPoolName)
+	externalNetwork, err := infrainternal.GetExternalNetwork(ctx, fctx.networking, fctx.config.FloatingPoolName, fctx.config.FloatingPoolID, fctx.state.Get(IdentifierFloatingNetwork))
 	if err != nil {
 		return err
 	}
@@ -121,6 +126,7 @@ func (fctx *FlowContext) ensureConfiguredNetwork(ctx context.Context) error {
 		return fmt.Errorf("no prefixes found for network '%s'", networkID)
 	}
 	workerCIDR := networkIPv4Config.GetPrefixes()[0]
+	fctx.nodesCIDR = &workerCIDR
 
 	if fctx.isSNAShoot {
 		snaConfig := &infrainternal.SNAConfig{
@@ -129,7 +135,6 @@ func (fctx *FlowContext) ensureConfiguredNetwork(ctx context.Context) error {
 		}
 
 		infrainternal.InjectConfig(&fctx.config.Networks, snaConfig)
-		fctx.nodesCIDR = &snaConfig.WorkersCIDR
 	}
 
 	// Populate dnsNameservers for InfrastructureStatus from provided network
@@ -172,8 +177,37 @@ func (fctx *FlowContext) ensureOpenStackSubnetID(ctx context.Context) error {
 		)
 	}
 
-	// TODO: A network can have multiple subnets. Check if we can just fetch the first one
-	fctx.state.Set(IdentifierSubnet, osNetwork.Subnets[0])
+	// A reused network can have multiple subnets, the nodes are placed in the one of the worker CIDR.
+	osSubnets := make([]*subnets.Subnet, 0, len(osNetwork.Subnets))
+	for _, subnetID := range osNetwork.Subnets {
+		subnet, err := fctx.access.GetSubnetByID(ctx, subnetID)
+		if err != nil {
+			return err
+		}
+		if subnet != nil {
+			osSubnets = append(osSubnets, subnet)
+		}
+	}
+
+	workerCIDR := fctx.workerCIDR()
+	matches := subnetsWithCIDR(osSubnets, workerCIDR)
+	switch len(matches) {
+	case 0:
+		shared.LogFromContext(ctx).Info("no subnet of the network matches the worker CIDR, using the first subnet",
+			"network", networkID, "workerCIDR", workerCIDR, "subnet", osNetwork.Subnets[0])
+		fctx.state.Set(IdentifierSubnet, osNetwork.Subnets[0])
+	case 1:
+		fctx.state.Set(IdentifierSubnet, matches[0].ID)
+	default:
+		ids := make([]string, 0, len(matches))
+		for _, subnet := range matches {
+			ids = append(ids, subnet.ID)
+		}
+		return gardenv1beta1helper.NewErrorWithCodes(
+			fmt.Errorf("multiple subnets of network with ID '%s' match the worker CIDR %s: %s", networkID, workerCIDR, strings.Join(ids, ", ")),
+			gardencorev1beta1.ErrorInfraDependencies,
+		)
+	}
 	return nil
 }
 
@@ -181,6 +215,14 @@ func (fctx *FlowContext) ensureOpenStackSubnetID(ctx context.Context) error {
 func (fctx *FlowContext) ensureOpenStackKeyPair(ctx context.Context) error {
 	log := shared.LogFromContext(ctx)
 
+	if skip, err := fctx.skipSSHKeyPair(); err != nil {
+		return err
+	} else if skip {
+		log.Info("SSH public key is empty, skipping SSH key pair: nodes will not be accessible via SSH")
+		fctx.state.Set(NameKeyPair, "")
+		return fctx.deleteOpenStackKeyPair(ctx)
+	}
+
 	keyPair, err := fctx.compute.GetKeyPair(ctx, fctx.defaultSSHKeypairName())
 	if err != nil {
 		return err
@@ -211,6 +253,14 @@ func (fctx *FlowContext) ensureOpenStackKeyPair(ctx context.Context) error {
 func (fctx *FlowContext) ensureStackitSSHKeyPair(ctx context.Context) error {
 	log := shared.LogFromContext(ctx)
 
+	if skip, err := fctx.skipSSHKeyPair(); err != nil {
+		return err
+	} else if skip {
+		log.Info("SSH public key is empty, skipping stackit SSH key pair: nodes will not be accessible via SSH")
+		fctx.state.Set(NameKeyPair, "")
+		return fctx.deleteStackitSSHKeyPair(ctx)
+	}
+
 	keyPair, err := fctx.iaasClient.GetKeypair(ctx, fctx.defaultSSHKeypairName())
 	if err != nil {
 		return err
@@ -245,9 +295,13 @@ func (fctx *FlowContext) ensureStackitSSHKeyPair(ctx context.Context) error {
 func (fctx *FlowContext) ensureSecGroup(ctx context.Context) error {
 	log := shared.LogFromContext(ctx)
 
+	description, err := infrainternal.RenderSecurityGroupDescription(fctx.securityGroupDescription, fctx.technicalID)
+	if err != nil {
+		return err
+	}
 	payload := iaas.CreateSecurityGroupPayload{
 		Name:        fctx.defaultSecurityGroupName(),
-		Description: new("Cluster Nodes"),
+		Description: new(description),
 	}
 
 	current, err := findExisting(ctx, fctx.state.Get(IdentifierSecGroup), fctx.defaultSecurityGroupName(), fctx.iaasClient.GetSecurityGroupById, fctx.iaasClient.GetSecurityGroupByName)
@@ -295,6 +349,9 @@ func (fctx *FlowContext) ensureSecGroupRules(ctx context.Context) error {
 	if !ok {
 		return fmt.Errorf("internal error: casting to SecurityGroup failed")
 	}
+	if err := fctx.checkNetworkReady(); err != nil {
+		return err
+	}
 
 	// usual clusters have all nodes in an internal network, for which NAT prevents access by non-cluster nodes
 	// for SNA we need to be more restrictive as other project in the same network area would otherwise gain
@@ -304,57 +361,32 @@ func (fctx *FlowContext) ensureSecGroupRules(ctx context.Context) error {
 		nodesCIDR = *fctx.nodesCIDR
 	}
 
-	desiredRules := []iaas.SecurityGroupRule{
-		{
-			Direction:             stackit.DirectionIngress,
-			Ethertype:             new(stackit.EtherTypeIPv4),
-			RemoteSecurityGroupId: new(group.GetId()),
-			Description:           new("IPv4: allow all incoming traffic within the same security group"),
-		},
-		{
-			Direction:   stackit.DirectionEgress,
-			Ethertype:   new(stackit.EtherTypeIPv4),
-			Description: new("IPv4: allow all outgoing traffic"),
-		},
-		{
-			Direction: stackit.DirectionIngress,
-			Ethertype: new(stackit.EtherTypeIPv4),
-			Protocol:  new(stackit.ProtocolTCP),
-			PortRange: &iaas.PortRange{
-				Max: int64(32767),
-				Min: int64(30000),
-			},
-			IpRange:     new(nodesCIDR),
-			Description: new("IPv4: allow all incoming tcp traffic with port range 30000-32767"),
-		},
-		{
-			Direction: stackit.DirectionIngress,
-			Ethertype: new(stackit.EtherTypeIPv4),
-			Protocol:  new(stackit.ProtocolUDP),
-			PortRange: &iaas.PortRange{
-				Max: int64(32767),
-				Min: int64(30000),
-			},
-			IpRange:     new(nodesCIDR),
-			Description: new("IPv4: allow all incoming udp traffic with port range 30000-32767"),
-		},
+	var podCIDR *string
+	if fctx.cluster.Shoot.Spec.Networking != nil {
+		podCIDR = fctx.cluster.Shoot.Spec.Networking.Pods
 	}
-
-	if fctx.cluster.Shoot.Spec.Networking != nil && fctx.cluster.Shoot.Spec.Networking.Pods != nil {
-		podCIDRRule := iaas.SecurityGroupRule{
-			Direction:   stackit.DirectionIngress,
-			Ethertype:   new(stackit.EtherTypeIPv4),
-			IpRange:     new(*fctx.cluster.Shoot.Spec.Networking.Pods),
-			Description: new("IPv4: allow all incoming traffic from cluster pod CIDR"),
-		}
-		desiredRules = append(desiredRules, podCIDRRule)
+	if podCIDR == nil {
+		log.Info("pod CIDR of the shoot is unknown, skipping security group rule for the pod CIDR: traffic from pods to the nodes may be restricted until the networking of the shoot is populated")
 	}
 
-	if modified, err := fctx.iaasClient.UpdateSecurityGroupRules(ctx, group, desiredRules, func(rule *iaas.SecurityGroupRule) bool {
+	desiredRules := infrainternal.ToSTACKITSecurityGroupRules(infrainternal.DesiredSecurityGroupRules(infrainternal.SecurityGroupRulesOptions{
+		NodePortsCIDR:              nodesCIDR,
+		PodCIDR:                    podCIDR,
+		IntraNodeTraffic:           fctx.config.IntraNodeTraffic,
+		AllowMetadataServiceEgress: fctx.allowMetadataServiceEgress,
+		AllowIPv6Egress:            fctx.config.Networks.IPv6 != nil,
+		AdditionalRules:            fctx.config.AdditionalSecurityGroupRules,
+	}), group.GetId())
+
+	if modified, err := fctx.iaasClient.UpdateSecurityGroupRules(ctx, group, desiredRules, fctx.deleteDuplicateSecurityGroupRules, func(rule *iaas.SecurityGroupRule) bool {
 		// Do NOT delete unknown rules to keep permissive behavior as with terraform.
 		// As we don't store the role ids in the state, this function needs to be adjusted
 		// if values in existing rules are changed to identify them for update by replacement.
-		return false
+		// Rules within the same security group created for the intra node traffic configuration as well as additional
+		// rules are identified by their description, so outdated ones are deleted when the configuration changes.
+		return (rule.GetDirection() == stackit.DirectionIngress && rule.GetRemoteSecurityGroupId() == group.GetId() &&
+			infrainternal.IsIntraGroupRule(rule.GetDescription())) ||
+			infrainternal.IsAdditionalSecurityGroupRule(rule.GetDescription())
 	}); err != nil {
 		return err
 	} else if modified {
@@ -364,6 +396,20 @@ func (fctx *FlowContext) ensureSecGroupRules(ctx context.Context) error {
 	return nil
 }
 
+// checkNetworkReady verifies that the network (and the subnet if OpenStack credentials are used) has been reconciled.
+func (fctx *FlowContext) checkNetworkReady() error {
+	if fctx.state.Get(IdentifierNetwork) == nil {
+		return fmt.Errorf("network is not ready yet")
+	}
+	if fctx.hasOpenStackCredentials && fctx.state.Get(IdentifierSubnet) == nil {
+		return fmt.Errorf("subnet is not ready yet")
+	}
+	if fctx.isSNAShoot && fctx.nodesCIDR == nil {
+		return fmt.Errorf("nodes CIDR of network is not ready yet")
+	}
+	return nil
+}
+
 func (fctx *FlowContext) ensureNetwork(ctx context.Context) error {
 	// SNA Case: Network already provided
 	if fctx.config.Networks.ID != nil {
@@ -375,13 +421,10 @@ func (fctx *FlowContext) ensureNetwork(ctx context.Context) error {
 func (fctx *FlowContext) ensureIsolatedNetwork(ctx context.Context) error {
 	log := shared.LogFromContext(ctx)
 
-	// Configure DNS settings with the cloud profile as default, while allowing overrides through shoot configuration.
-	var dnsServers []string
-	dnsServers = fctx.cloudProfileConfig.DNSServers
-	if fctx.config.Networks.DNSServers != nil {
-		dnsServers = *fctx.config.Networks.DNSServers
+	dnsServers, err := fctx.dnsServers()
+	if err != nil {
+		return err
 	}
-
 	network := iaas.CreateNetworkIPv4{
 		CreateNetworkIPv4WithPrefix: &iaas.CreateNetworkIPv4WithPrefix{
 			Nameservers: dnsServers,
@@ -390,10 +433,18 @@ func (fctx *FlowContext) ensureIsolatedNetwork(ctx context.Context) error {
 	}
 
 	desired := iaas.CreateIsolatedNetworkPayload{
-		Dhcp: new(true),
+		Dhcp: new(ptr.Deref(fctx.config.Networks.DHCP, true)),
 		Ipv4: new(network),
 		Name: fctx.technicalID,
 	}
+	if fctx.config.Networks.IPv6 != nil {
+		desired.Ipv6 = new(ipv6Network(fctx.config.Networks.IPv6))
+	}
+	if fctx.customLabelDomain != "" {
+		desired.Labels = map[string]any{
+			stackitutils.ClusterLabelKey(fctx.customLabelDomain): fctx.technicalID,
+		}
+	}
 	current, err := findExisting(ctx, fctx.state.Get(IdentifierNetwork), fctx.defaultNetworkName(), fctx.iaasClient.GetNetworkById, fctx.iaasClient.GetNetworkByName)
 	if err != nil {
 		return err
@@ -401,11 +452,15 @@ func (fctx *FlowContext) ensureIsolatedNetwork(ctx context.Context) error {
 	if current != nil {
 		fctx.state.Set(IdentifierNetwork, current.GetId())
 		fctx.state.Set(NameNetwork, current.GetName())
-		if _, err := fctx.iaasClient.UpdateNetwork(ctx, current.GetId(), client.IsolatedNetworkToPartialUpdate(desired)); err != nil {
+		if _, err := fctx.iaasClient.UpdateNetwork(ctx, current.GetId(), client.IsolatedNetworkToPartialUpdate(current, desired)); err != nil {
 			return err
 		}
 		// Update dnsNameservers when update was successful
 		fctx.dnsNameservers = new(desired.Ipv4.CreateNetworkIPv4WithPrefix.GetNameservers())
+		if len(*fctx.dnsNameservers) == 0 {
+			// the nameservers are not updated, the network keeps the ones inherited from STACKIT
+			fctx.dnsNameservers = new(current.Ipv4.GetNameservers())
+		}
 	} else {
 		log.Info("creating...", "network", fctx.defaultNetworkName())
 		created, err := fctx.iaasClient.CreateIsolatedNetwork(ctx, desired)
@@ -416,6 +471,8 @@ func (fctx *FlowContext) ensureIsolatedNetwork(ctx context.Context) error {
 		fctx.state.Set(NameNetwork, created.GetName())
 		fctx.dnsNameservers = new(created.Ipv4.GetNameservers())
 	}
+	// the prefix of a network cannot be changed, so it is always the configured workers CIDR
+	fctx.nodesCIDR = new(fctx.workerCIDR())
 	return nil
 }
 
@@ -436,5 +493,10 @@ func (fctx *FlowContext) ensureEgressIP(ctx context.Context) error {
 		fctx.state.SetObject(IdentifierEgressCIDRs, result)
 		return nil
 	}
+	if fctx.config.Networks.AllowMissingEgressIP {
+		shared.LogFromContext(ctx).Info("network has no egress IP, proceeding without egress CIDRs as allowed by the InfrastructureConfig", "network", network.GetId())
+		fctx.state.SetObject(IdentifierEgressCIDRs, []string{})
+		return nil
+	}
 	return fmt.Errorf("egress IP not found for network: %s", network.GetId())
 }
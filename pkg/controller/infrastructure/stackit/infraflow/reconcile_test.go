@@ -2,12 +2,23 @@ package infraflow
 
 import (
 	"context"
+	"strings"
 
+	extensionscontroller "github.com/gardener/gardener/extensions/pkg/controller"
+	gardenv1beta1helper "github.com/gardener/gardener/pkg/api/core/v1beta1/helper"
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"github.com/go-logr/logr/funcr"
+	"github.com/gophercloud/gophercloud/v2/openstack/networking/v2/subnets"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/types"
 	iaas "github.com/stackitcloud/stackit-sdk-go/services/iaas/v2api"
 	"go.uber.org/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/apis/config"
+	stackitv1alpha1 "github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/apis/stackit/v1alpha1"
 	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/controller/infrastructure/openstack/infraflow/shared"
 	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/stackit"
 	mockclient "github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/stackit/client/mock"
@@ -28,9 +39,10 @@ var _ = Describe("STACKIT infraflow reconcile", func() {
 			mockIaaS = mockclient.NewMockIaaSClient(ctrl)
 
 			fctx = &FlowContext{
-				state:       shared.NewWhiteboard(),
-				iaasClient:  mockIaaS,
-				technicalID: "shoot--foo--bar",
+				state:                    shared.NewWhiteboard(),
+				iaasClient:               mockIaaS,
+				technicalID:              "shoot--foo--bar",
+				securityGroupDescription: "Nodes of {{ .TechnicalID }}",
 			}
 		})
 
@@ -41,7 +53,7 @@ var _ = Describe("STACKIT infraflow reconcile", func() {
 		It("clears default egress rules before saving the security group in state", func() {
 			expectedPayload := iaas.CreateSecurityGroupPayload{
 				Name:        "shoot--foo--bar",
-				Description: new("Cluster Nodes"),
+				Description: new("Nodes of shoot--foo--bar"),
 			}
 			defaultEgressRules := []iaas.SecurityGroupRule{
 				{
@@ -83,5 +95,619 @@ var _ = Describe("STACKIT infraflow reconcile", func() {
 			Expect(savedSecurityGroup.GetName()).To(Equal("shoot--foo--bar"))
 			Expect(savedSecurityGroup.GetRules()).To(BeEmpty())
 		})
+
+		It("fails without creating the security group if the description is too long", func() {
+			fctx.securityGroupDescription = strings.Repeat("x", 250) + "{{ .TechnicalID }}"
+
+			Expect(fctx.ensureSecGroup(ctx)).To(MatchError(ContainSubstring("must not be longer than 255 characters")))
+		})
+	})
+
+	Describe("#ensureSecGroupRules", func() {
+		var (
+			ctx      context.Context
+			ctrl     *gomock.Controller
+			mockIaaS *mockclient.MockIaaSClient
+			fctx     *FlowContext
+			group    *iaas.SecurityGroup
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			ctrl = gomock.NewController(GinkgoT())
+			mockIaaS = mockclient.NewMockIaaSClient(ctrl)
+			group = &iaas.SecurityGroup{Id: new("security-group-id"), Name: "shoot--foo--bar"}
+
+			fctx = &FlowContext{
+				state:                      shared.NewWhiteboard(),
+				iaasClient:                 mockIaaS,
+				config:                     &stackitv1alpha1.InfrastructureConfig{},
+				cluster:                    &extensionscontroller.Cluster{Shoot: &gardencorev1beta1.Shoot{}},
+				allowMetadataServiceEgress: true,
+			}
+			fctx.state.Set(IdentifierNetwork, "network-id")
+			fctx.state.SetObject(ObjectSecGroup, group)
+		})
+
+		AfterEach(func() {
+			ctrl.Finish()
+		})
+
+		metadataServiceRule := iaas.SecurityGroupRule{
+			Direction: stackit.DirectionEgress,
+			Ethertype: new(stackit.EtherTypeIPv4),
+			Protocol:  new(stackit.ProtocolTCP),
+			PortRange: &iaas.PortRange{
+				Max: int64(80),
+				Min: int64(80),
+			},
+			IpRange:     new("169.254.169.254/32"),
+			Description: new("IPv4: allow outgoing tcp traffic to the metadata service"),
+		}
+
+		DescribeTable("should configure the metadata service egress rule",
+			func(allow bool, matcher types.GomegaMatcher) {
+				fctx.allowMetadataServiceEgress = allow
+
+				var desiredRules []iaas.SecurityGroupRule
+				mockIaaS.EXPECT().UpdateSecurityGroupRules(ctx, group, gomock.Any(), false, gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ *iaas.SecurityGroup, rules []iaas.SecurityGroupRule, _ bool, _ func(*iaas.SecurityGroupRule) bool) (bool, error) {
+						desiredRules = rules
+						return false, nil
+					})
+
+				Expect(fctx.ensureSecGroupRules(ctx)).To(Succeed())
+				Expect(desiredRules).To(matcher)
+			},
+			Entry("enabled", true, ContainElement(metadataServiceRule)),
+			Entry("disabled", false, Not(ContainElement(metadataServiceRule))),
+		)
+
+		ipv6EgressRule := iaas.SecurityGroupRule{
+			Direction:   stackit.DirectionEgress,
+			Ethertype:   new(stackit.EtherTypeIPv6),
+			Description: new("IPv6: allow all outgoing traffic"),
+		}
+
+		DescribeTable("should configure the IPv6 egress rule",
+			func(ipv6 *stackitv1alpha1.IPv6Network, matcher types.GomegaMatcher) {
+				fctx.config.Networks.IPv6 = ipv6
+
+				var desiredRules []iaas.SecurityGroupRule
+				mockIaaS.EXPECT().UpdateSecurityGroupRules(ctx, group, gomock.Any(), false, gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ *iaas.SecurityGroup, rules []iaas.SecurityGroupRule, _ bool, _ func(*iaas.SecurityGroupRule) bool) (bool, error) {
+						desiredRules = rules
+						return false, nil
+					})
+
+				Expect(fctx.ensureSecGroupRules(ctx)).To(Succeed())
+				Expect(desiredRules).To(matcher)
+			},
+			Entry("with IPv6", &stackitv1alpha1.IPv6Network{PrefixLength: new(int32(64))}, ContainElement(ipv6EgressRule)),
+			Entry("without IPv6", nil, Not(ContainElement(ipv6EgressRule))),
+		)
+
+		It("should append the additional security group rules", func() {
+			fctx.config.AdditionalSecurityGroupRules = []stackitv1alpha1.SecurityGroupRuleConfig{
+				{Direction: "ingress", Protocol: new("udp"), PortRangeMin: new(int32(1194)), RemoteIPPrefix: new("192.168.0.0/24")},
+				{Direction: "egress", EtherType: new("IPv6"), RemoteSecurityGroupID: new("peer-group"), Description: new("peering")},
+			}
+
+			var desiredRules []iaas.SecurityGroupRule
+			mockIaaS.EXPECT().UpdateSecurityGroupRules(ctx, group, gomock.Any(), false, gomock.Any()).
+				DoAndReturn(func(_ context.Context, _ *iaas.SecurityGroup, rules []iaas.SecurityGroupRule, _ bool, _ func(*iaas.SecurityGroupRule) bool) (bool, error) {
+					desiredRules = rules
+					return true, nil
+				})
+
+			Expect(fctx.ensureSecGroupRules(ctx)).To(Succeed())
+			Expect(len(desiredRules)).To(BeNumerically(">", 2))
+			Expect(desiredRules[len(desiredRules)-2:]).To(Equal([]iaas.SecurityGroupRule{
+				{
+					Direction:   stackit.DirectionIngress,
+					Ethertype:   new(stackit.EtherTypeIPv4),
+					Protocol:    &iaas.Protocol{Name: new("udp")},
+					PortRange:   &iaas.PortRange{Min: 1194, Max: 1194},
+					IpRange:     new("192.168.0.0/24"),
+					Description: new("gardener-additional: IPv4: allow incoming udp traffic with port range 1194-1194 from 192.168.0.0/24"),
+				},
+				{
+					Direction:             stackit.DirectionEgress,
+					Ethertype:             new(stackit.EtherTypeIPv6),
+					RemoteSecurityGroupId: new("peer-group"),
+					Description:           new("gardener-additional: peering"),
+				},
+			}))
+		})
+
+		It("should only allow deleting rules of the extension", func() {
+			var allowDelete func(*iaas.SecurityGroupRule) bool
+			mockIaaS.EXPECT().UpdateSecurityGroupRules(ctx, group, gomock.Any(), false, gomock.Any()).
+				DoAndReturn(func(_ context.Context, _ *iaas.SecurityGroup, _ []iaas.SecurityGroupRule, _ bool, fn func(*iaas.SecurityGroupRule) bool) (bool, error) {
+					allowDelete = fn
+					return false, nil
+				})
+
+			Expect(fctx.ensureSecGroupRules(ctx)).To(Succeed())
+			Expect(allowDelete(&iaas.SecurityGroupRule{Direction: stackit.DirectionIngress, Description: new("gardener-additional: peering")})).To(BeTrue())
+			Expect(allowDelete(&iaas.SecurityGroupRule{Direction: stackit.DirectionIngress, Description: new("peering")})).To(BeFalse())
+			Expect(allowDelete(&iaas.SecurityGroupRule{Direction: stackit.DirectionEgress})).To(BeFalse())
+			Expect(allowDelete(&iaas.SecurityGroupRule{
+				Direction:             stackit.DirectionIngress,
+				RemoteSecurityGroupId: group.Id,
+				Description:           new("IPv4: allow incoming tcp traffic within the same security group"),
+			})).To(BeTrue())
+			Expect(allowDelete(&iaas.SecurityGroupRule{
+				Direction:             stackit.DirectionIngress,
+				RemoteSecurityGroupId: group.Id,
+				Description:           new("monitoring"),
+			})).To(BeFalse())
+		})
+	})
+
+	Describe("#ensureStackitSSHKeyPair", func() {
+		const publicKey = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB"
+
+		var (
+			ctx      context.Context
+			ctrl     *gomock.Controller
+			mockIaaS *mockclient.MockIaaSClient
+			fctx     *FlowContext
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			ctrl = gomock.NewController(GinkgoT())
+			mockIaaS = mockclient.NewMockIaaSClient(ctrl)
+
+			fctx = &FlowContext{
+				state:       shared.NewWhiteboard(),
+				iaasClient:  mockIaaS,
+				technicalID: "shoot--foo--bar",
+				infra:       &extensionsv1alpha1.Infrastructure{},
+			}
+		})
+
+		AfterEach(func() {
+			ctrl.Finish()
+		})
+
+		It("should create the key pair for a valid SSH public key", func() {
+			fctx.infra.Spec.SSHPublicKey = []byte(publicKey)
+
+			mockIaaS.EXPECT().GetKeypair(ctx, "shoot--foo--bar").Return(nil, nil)
+			mockIaaS.EXPECT().CreateKeypair(ctx, "shoot--foo--bar", publicKey).Return(&iaas.Keypair{
+				Name:      new("shoot--foo--bar"),
+				PublicKey: publicKey,
+			}, nil)
+
+			Expect(fctx.ensureStackitSSHKeyPair(ctx)).To(Succeed())
+			Expect(fctx.state.Get(NameKeyPair)).To(HaveValue(Equal("shoot--foo--bar")))
+		})
+
+		It("should keep an existing key pair with a matching SSH public key", func() {
+			fctx.infra.Spec.SSHPublicKey = []byte(publicKey)
+
+			mockIaaS.EXPECT().GetKeypair(ctx, "shoot--foo--bar").Return(&iaas.Keypair{
+				Name:      new("shoot--foo--bar"),
+				PublicKey: publicKey,
+			}, nil)
+
+			Expect(fctx.ensureStackitSSHKeyPair(ctx)).To(Succeed())
+			Expect(fctx.state.Get(NameKeyPair)).To(HaveValue(Equal("shoot--foo--bar")))
+		})
+
+		DescribeTable("should skip the key pair for an empty SSH public key",
+			func(policy config.EmptySSHPublicKeyPolicy) {
+				fctx.emptySSHPublicKeyPolicy = policy
+				fctx.state.Set(NameKeyPair, "shoot--foo--bar")
+
+				mockIaaS.EXPECT().GetKeypair(ctx, "shoot--foo--bar").Return(&iaas.Keypair{
+					Name:      new("shoot--foo--bar"),
+					PublicKey: publicKey,
+				}, nil)
+				mockIaaS.EXPECT().DeleteKeypair(ctx, "shoot--foo--bar").Return(nil)
+
+				Expect(fctx.ensureStackitSSHKeyPair(ctx)).To(Succeed())
+				Expect(fctx.state.Get(NameKeyPair)).To(BeNil())
+			},
+			Entry("unset policy", config.EmptySSHPublicKeyPolicy("")),
+			Entry("skip policy", config.EmptySSHPublicKeyPolicySkip),
+		)
+
+		It("should reject an empty SSH public key if configured", func() {
+			fctx.emptySSHPublicKeyPolicy = config.EmptySSHPublicKeyPolicyReject
+			fctx.infra.Spec.SSHPublicKey = []byte("  ")
+
+			err := fctx.ensureStackitSSHKeyPair(ctx)
+			Expect(err).To(MatchError(ContainSubstring("SSH public key of infrastructure is empty")))
+		})
+	})
+
+	DescribeTable("#checkNetworkReady",
+		func(state map[string]string, hasOpenStackCredentials, isSNAShoot bool, nodesCIDR *string, errMatcher types.GomegaMatcher) {
+			fctx := &FlowContext{
+				state:                   shared.NewWhiteboard(),
+				hasOpenStackCredentials: hasOpenStackCredentials,
+				isSNAShoot:              isSNAShoot,
+				nodesCIDR:               nodesCIDR,
+			}
+			for k, v := range state {
+				fctx.state.Set(k, v)
+			}
+			Expect(fctx.checkNetworkReady()).To(errMatcher)
+		},
+		Entry("network is missing", nil, false, false, nil, MatchError("network is not ready yet")),
+		Entry("network is ready", map[string]string{IdentifierNetwork: "network-id"}, false, false, nil, Succeed()),
+		Entry("subnet is missing with OpenStack credentials", map[string]string{IdentifierNetwork: "network-id"}, true, false, nil, MatchError("subnet is not ready yet")),
+		Entry("network and subnet are ready with OpenStack credentials", map[string]string{IdentifierNetwork: "network-id", IdentifierSubnet: "subnet-id"}, true, false, nil, Succeed()),
+		Entry("nodes CIDR is missing for SNA shoots", map[string]string{IdentifierNetwork: "network-id"}, false, true, nil, MatchError("nodes CIDR of network is not ready yet")),
+		Entry("nodes CIDR is set for SNA shoots", map[string]string{IdentifierNetwork: "network-id"}, false, true, new("10.0.0.0/24"), Succeed()),
+	)
+
+	Describe("#ensureIsolatedNetwork", func() {
+		var (
+			ctx      context.Context
+			ctrl     *gomock.Controller
+			mockIaaS *mockclient.MockIaaSClient
+			fctx     *FlowContext
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			ctrl = gomock.NewController(GinkgoT())
+			mockIaaS = mockclient.NewMockIaaSClient(ctrl)
+
+			fctx = &FlowContext{
+				state:              shared.NewWhiteboard(),
+				iaasClient:         mockIaaS,
+				technicalID:        "shoot--foo--bar",
+				cloudProfileConfig: &stackitv1alpha1.CloudProfileConfig{},
+				config: &stackitv1alpha1.InfrastructureConfig{
+					Networks: stackitv1alpha1.Networks{Workers: "10.250.0.0/16"},
+				},
+			}
+		})
+
+		AfterEach(func() {
+			ctrl.Finish()
+		})
+
+		DescribeTable("should create the network with the configured nameservers",
+			func(infraDNSServers *[]string, expectedNameservers []string) {
+				fctx.config.Networks.DNSServers = infraDNSServers
+
+				mockIaaS.EXPECT().GetNetworkByName(ctx, "shoot--foo--bar").Return(nil, nil)
+				mockIaaS.EXPECT().CreateIsolatedNetwork(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, payload iaas.CreateIsolatedNetworkPayload) (*iaas.Network, error) {
+					Expect(payload.Ipv4.CreateNetworkIPv4WithPrefix.Nameservers).To(Equal(expectedNameservers))
+					return &iaas.Network{
+						Id:   "network-id",
+						Name: "shoot--foo--bar",
+						Ipv4: &iaas.NetworkIPv4{Nameservers: []string{"10.0.0.53"}},
+					}, nil
+				})
+
+				Expect(fctx.ensureIsolatedNetwork(ctx)).To(Succeed())
+				Expect(fctx.state.Get(IdentifierNetwork)).To(HaveValue(Equal("network-id")))
+				Expect(fctx.dnsNameservers).To(HaveValue(Equal([]string{"10.0.0.53"})))
+				Expect(fctx.nodesCIDR).To(HaveValue(Equal("10.250.0.0/16")))
+			},
+			Entry("without DNS servers", nil, nil),
+			Entry("with DNS servers", &[]string{"1.1.1.1"}, []string{"1.1.1.1"}),
+		)
+
+		DescribeTable("should create the network with the configured DHCP setting",
+			func(dhcp *bool, expectedDHCP bool) {
+				fctx.config.Networks.DHCP = dhcp
+
+				mockIaaS.EXPECT().GetNetworkByName(ctx, "shoot--foo--bar").Return(nil, nil)
+				mockIaaS.EXPECT().CreateIsolatedNetwork(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, payload iaas.CreateIsolatedNetworkPayload) (*iaas.Network, error) {
+					Expect(payload.Dhcp).To(HaveValue(Equal(expectedDHCP)))
+					return &iaas.Network{Id: "network-id", Name: "shoot--foo--bar"}, nil
+				})
+
+				Expect(fctx.ensureIsolatedNetwork(ctx)).To(Succeed())
+			},
+			Entry("DHCP by default", nil, true),
+			Entry("DHCP enabled", new(true), true),
+			Entry("DHCP disabled", new(false), false),
+		)
+
+		It("should create the network with an IPv6 prefix", func() {
+			fctx.config.Networks.IPv6 = &stackitv1alpha1.IPv6Network{PrefixLength: new(int32(64))}
+
+			mockIaaS.EXPECT().GetNetworkByName(ctx, "shoot--foo--bar").Return(nil, nil)
+			mockIaaS.EXPECT().CreateIsolatedNetwork(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, payload iaas.CreateIsolatedNetworkPayload) (*iaas.Network, error) {
+				Expect(payload.Ipv4.CreateNetworkIPv4WithPrefix.Prefix).To(Equal("10.250.0.0/16"))
+				Expect(payload.Ipv6).To(HaveValue(Equal(iaas.CreateNetworkIPv6{
+					CreateNetworkIPv6WithPrefixLength: &iaas.CreateNetworkIPv6WithPrefixLength{PrefixLength: 64},
+				})))
+				return &iaas.Network{Id: "network-id", Name: "shoot--foo--bar"}, nil
+			})
+
+			Expect(fctx.ensureIsolatedNetwork(ctx)).To(Succeed())
+		})
+
+		It("should update the DHCP setting of an existing network", func() {
+			fctx.config.Networks.DHCP = new(false)
+			fctx.config.Networks.DNSServers = &[]string{"1.1.1.1"}
+
+			mockIaaS.EXPECT().GetNetworkByName(ctx, "shoot--foo--bar").Return([]iaas.Network{{
+				Id:   "network-id",
+				Name: "shoot--foo--bar",
+			}}, nil)
+			mockIaaS.EXPECT().UpdateNetwork(ctx, "network-id", gomock.Any()).DoAndReturn(func(_ context.Context, _ string, payload iaas.PartialUpdateNetworkPayload) (*iaas.Network, error) {
+				Expect(payload.Dhcp).To(HaveValue(BeFalse()))
+				Expect(payload.Ipv4.Nameservers).To(Equal([]string{"1.1.1.1"}))
+				return nil, nil
+			})
+
+			Expect(fctx.ensureIsolatedNetwork(ctx)).To(Succeed())
+			Expect(fctx.state.Get(IdentifierNetwork)).To(HaveValue(Equal("network-id")))
+			Expect(fctx.dnsNameservers).To(HaveValue(Equal([]string{"1.1.1.1"})))
+		})
+
+		It("should converge a drifted network to the desired state", func() {
+			fctx.customLabelDomain = "kubernetes.io"
+			fctx.config.Networks.DNSServers = &[]string{"1.1.1.1"}
+
+			mockIaaS.EXPECT().GetNetworkByName(ctx, "shoot--foo--bar").Return([]iaas.Network{{
+				Id:     "network-id",
+				Name:   "shoot--foo--bar",
+				Dhcp:   new(false),
+				Labels: map[string]any{"foo": "bar", "kubernetes.io/cluster": "other"},
+				Ipv4:   &iaas.NetworkIPv4{Nameservers: []string{"8.8.8.8"}},
+			}}, nil)
+			mockIaaS.EXPECT().UpdateNetwork(ctx, "network-id", gomock.Any()).DoAndReturn(func(_ context.Context, _ string, payload iaas.PartialUpdateNetworkPayload) (*iaas.Network, error) {
+				Expect(payload.Name).To(HaveValue(Equal("shoot--foo--bar")))
+				Expect(payload.Dhcp).To(HaveValue(BeTrue()))
+				Expect(payload.Labels).To(Equal(map[string]any{"foo": "bar", "kubernetes.io/cluster": "shoot--foo--bar"}))
+				Expect(payload.Ipv4.Nameservers).To(Equal([]string{"1.1.1.1"}))
+				return nil, nil
+			})
+
+			Expect(fctx.ensureIsolatedNetwork(ctx)).To(Succeed())
+			Expect(fctx.dnsNameservers).To(HaveValue(Equal([]string{"1.1.1.1"})))
+		})
+
+		It("should label a new network with the cluster", func() {
+			fctx.customLabelDomain = "kubernetes.io"
+
+			mockIaaS.EXPECT().GetNetworkByName(ctx, "shoot--foo--bar").Return(nil, nil)
+			mockIaaS.EXPECT().CreateIsolatedNetwork(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, payload iaas.CreateIsolatedNetworkPayload) (*iaas.Network, error) {
+				Expect(payload.Labels).To(Equal(map[string]any{"kubernetes.io/cluster": "shoot--foo--bar"}))
+				return &iaas.Network{Id: "network-id", Name: "shoot--foo--bar"}, nil
+			})
+
+			Expect(fctx.ensureIsolatedNetwork(ctx)).To(Succeed())
+		})
+	})
+
+	Describe("#ensureConfiguredNetwork", func() {
+		var (
+			ctx      context.Context
+			ctrl     *gomock.Controller
+			mockIaaS *mockclient.MockIaaSClient
+			fctx     *FlowContext
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			ctrl = gomock.NewController(GinkgoT())
+			mockIaaS = mockclient.NewMockIaaSClient(ctrl)
+
+			fctx = &FlowContext{
+				state:      shared.NewWhiteboard(),
+				iaasClient: mockIaaS,
+				config: &stackitv1alpha1.InfrastructureConfig{
+					Networks: stackitv1alpha1.Networks{ID: new("network-id")},
+				},
+			}
+		})
+
+		AfterEach(func() {
+			ctrl.Finish()
+		})
+
+		DescribeTable("should use the prefix of the network as nodes CIDR",
+			func(isSNAShoot bool) {
+				fctx.isSNAShoot = isSNAShoot
+
+				mockIaaS.EXPECT().GetNetworkById(ctx, "network-id").Return(&iaas.Network{
+					Id:   "network-id",
+					Name: "network",
+					Ipv4: &iaas.NetworkIPv4{Prefixes: []string{"10.1.0.0/24"}},
+				}, nil)
+
+				Expect(fctx.ensureConfiguredNetwork(ctx)).To(Succeed())
+				Expect(fctx.state.Get(IdentifierNetwork)).To(HaveValue(Equal("network-id")))
+				Expect(fctx.nodesCIDR).To(HaveValue(Equal("10.1.0.0/24")))
+			},
+			Entry("SNA shoot", true),
+			Entry("shoot with a configured network", false),
+		)
+	})
+
+	Describe("#ensureEgressIP", func() {
+		var (
+			ctx      context.Context
+			ctrl     *gomock.Controller
+			mockIaaS *mockclient.MockIaaSClient
+			fctx     *FlowContext
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			ctrl = gomock.NewController(GinkgoT())
+			mockIaaS = mockclient.NewMockIaaSClient(ctrl)
+
+			fctx = &FlowContext{
+				state:      shared.NewWhiteboard(),
+				iaasClient: mockIaaS,
+				config:     &stackitv1alpha1.InfrastructureConfig{},
+			}
+			fctx.state.Set(IdentifierNetwork, "network-id")
+		})
+
+		AfterEach(func() {
+			ctrl.Finish()
+		})
+
+		It("should store the public IP of the network as egress CIDR", func() {
+			mockIaaS.EXPECT().GetNetworkById(ctx, "network-id").Return(&iaas.Network{
+				Id:   "network-id",
+				Ipv4: &iaas.NetworkIPv4{PublicIp: new("192.0.2.1")},
+			}, nil)
+
+			Expect(fctx.ensureEgressIP(ctx)).To(Succeed())
+			Expect(fctx.state.GetObject(IdentifierEgressCIDRs)).To(Equal([]string{"192.0.2.1"}))
+		})
+
+		It("should fail if the network has no public IP", func() {
+			mockIaaS.EXPECT().GetNetworkById(ctx, "network-id").Return(&iaas.Network{
+				Id:   "network-id",
+				Ipv4: &iaas.NetworkIPv4{},
+			}, nil)
+
+			Expect(fctx.ensureEgressIP(ctx)).To(MatchError(ContainSubstring("egress IP not found for network: network-id")))
+		})
+
+		It("should proceed without egress CIDRs if a missing egress IP is allowed", func() {
+			fctx.config.Networks.AllowMissingEgressIP = true
+			mockIaaS.EXPECT().GetNetworkById(ctx, "network-id").Return(&iaas.Network{
+				Id:   "network-id",
+				Ipv4: &iaas.NetworkIPv4{},
+			}, nil)
+
+			Expect(fctx.ensureEgressIP(ctx)).To(Succeed())
+			Expect(fctx.state.GetObject(IdentifierEgressCIDRs)).To(BeEmpty())
+		})
+	})
+
+	DescribeTable("#dnsServers",
+		func(cloudProfileDNSServers []string, infraDNSServers *[]string, expected []string) {
+			fctx := &FlowContext{
+				cloudProfileConfig: &stackitv1alpha1.CloudProfileConfig{DNSServers: cloudProfileDNSServers},
+				config:             &stackitv1alpha1.InfrastructureConfig{Networks: stackitv1alpha1.Networks{DNSServers: infraDNSServers}},
+			}
+			Expect(fctx.dnsServers()).To(Equal(expected))
+		},
+		Entry("no DNS servers inherit the STACKIT defaults", nil, nil, nil),
+		Entry("empty DNS servers inherit the STACKIT defaults", []string{}, &[]string{}, nil),
+		Entry("DNS servers of the cloud profile", []string{"1.1.1.1"}, nil, []string{"1.1.1.1"}),
+		Entry("DNS servers of the InfrastructureConfig override the cloud profile", []string{"1.1.1.1"}, &[]string{"8.8.8.8"}, []string{"8.8.8.8"}),
+		Entry("duplicate DNS servers are removed", nil, &[]string{"8.8.8.8", "1.1.1.1", "8.8.8.8", "9.9.9.9", "1.1.1.1"}, []string{"8.8.8.8", "1.1.1.1", "9.9.9.9"}),
+	)
+
+	It("#dnsServers should reject more DNS servers than supported by STACKIT", func() {
+		fctx := &FlowContext{
+			cloudProfileConfig: &stackitv1alpha1.CloudProfileConfig{},
+			config: &stackitv1alpha1.InfrastructureConfig{Networks: stackitv1alpha1.Networks{
+				DNSServers: &[]string{"1.1.1.1", "8.8.8.8", "9.9.9.9", "1.0.0.1"},
+			}},
+		}
+
+		_, err := fctx.dnsServers()
+		Expect(err).To(MatchError("4 DNS servers are configured for the worker network, but STACKIT supports at most 3"))
+		coder, ok := err.(gardenv1beta1helper.Coder)
+		Expect(ok).To(BeTrue())
+		Expect(coder.Codes()).To(ConsistOf(gardencorev1beta1.ErrorConfigurationProblem))
+	})
+
+	DescribeTable("#ipv6Network",
+		func(config *stackitv1alpha1.IPv6Network, expected iaas.CreateNetworkIPv6) {
+			Expect(ipv6Network(config)).To(Equal(expected))
+		},
+		Entry("prefix", &stackitv1alpha1.IPv6Network{
+			Prefix:     new("2001:db8::/64"),
+			DNSServers: []string{"2001:4860:4860::8888"},
+		}, iaas.CreateNetworkIPv6{
+			CreateNetworkIPv6WithPrefix: &iaas.CreateNetworkIPv6WithPrefix{
+				Prefix:      "2001:db8::/64",
+				Nameservers: []string{"2001:4860:4860::8888"},
+			},
+		}),
+		Entry("prefix length", &stackitv1alpha1.IPv6Network{
+			PrefixLength: new(int32(56)),
+		}, iaas.CreateNetworkIPv6{
+			CreateNetworkIPv6WithPrefixLength: &iaas.CreateNetworkIPv6WithPrefixLength{PrefixLength: 56},
+		}),
+	)
+
+	DescribeTable("#subnetsWithCIDR",
+		func(cidr string, expectedIDs []string) {
+			candidates := []*subnets.Subnet{
+				{ID: "subnet-a", CIDR: "10.250.0.0/16"},
+				{ID: "subnet-b", CIDR: "10.1.0.0/24"},
+				{ID: "subnet-c", CIDR: "10.1.0.0/24"},
+			}
+
+			var ids []string
+			for _, subnet := range subnetsWithCIDR(candidates, cidr) {
+				ids = append(ids, subnet.ID)
+			}
+			Expect(ids).To(Equal(expectedIDs))
+		},
+		Entry("no matching subnet", "10.2.0.0/16", nil),
+		Entry("one matching subnet", "10.250.0.0/16", []string{"subnet-a"}),
+		Entry("one matching subnet in another notation", "10.250.1.0/16", []string{"subnet-a"}),
+		Entry("multiple matching subnets", "10.1.0.0/24", []string{"subnet-b", "subnet-c"}),
+		Entry("invalid worker CIDR", "invalid", nil),
+	)
+
+	DescribeTable("#ignoredFields",
+		func(hasOpenStackCredentials bool, infraConfig *stackitv1alpha1.InfrastructureConfig, expected []string) {
+			fctx := &FlowContext{
+				hasOpenStackCredentials: hasOpenStackCredentials,
+				config:                  infraConfig,
+			}
+			Expect(fctx.ignoredFields()).To(Equal(expected))
+		},
+		Entry("isolated network with floating pool name", false, &stackitv1alpha1.InfrastructureConfig{FloatingPoolName: "floating-pool"}, []string{"floatingPoolName"}),
+		Entry("isolated network without floating pool name", false, &stackitv1alpha1.InfrastructureConfig{}, nil),
+		Entry("OpenStack credentials with floating pool name", true, &stackitv1alpha1.InfrastructureConfig{FloatingPoolName: "floating-pool"}, nil),
+		Entry("isolated network with floating pool settings and router", false, &stackitv1alpha1.InfrastructureConfig{
+			FloatingPoolName:       "floating-pool",
+			FloatingPoolID:         new("floating-pool-id"),
+			FloatingPoolSubnetName: new("floating-pool-subnet"),
+			Networks:               stackitv1alpha1.Networks{Router: &stackitv1alpha1.Router{ID: "router"}},
+		}, []string{"floatingPoolName", "floatingPoolId", "floatingPoolSubnetName", "networks.router"}),
+		Entry("OpenStack credentials with floating pool subnet name and router", true, &stackitv1alpha1.InfrastructureConfig{
+			FloatingPoolName:       "floating-pool",
+			FloatingPoolSubnetName: new("floating-pool-subnet"),
+			Networks:               stackitv1alpha1.Networks{Router: &stackitv1alpha1.Router{ID: "router"}},
+		}, []string{"floatingPoolSubnetName", "networks.router"}),
+	)
+
+	It("#checkNetworkReady should only require the OpenStack subnet if OpenStack credentials are used", func() {
+		// The STACKIT IaaS API has no subnets, so the STACKIT-only path does not resolve a subnet at all.
+		fctx := &FlowContext{state: shared.NewWhiteboard()}
+		fctx.state.Set(IdentifierNetwork, "network")
+		fctx.state.SetObject(IdentifierEgressCIDRs, []string{})
+
+		Expect(fctx.checkNetworkReady()).To(Succeed())
+		Expect(fctx.computeInfrastructureStatus().Networks.Subnets).To(BeEmpty())
+
+		fctx.hasOpenStackCredentials = true
+		Expect(fctx.checkNetworkReady()).To(MatchError("subnet is not ready yet"))
+		fctx.state.Set(IdentifierSubnet, "openstack-subnet")
+		Expect(fctx.checkNetworkReady()).To(Succeed())
+	})
+
+	It("#logIgnoredFields should only log the ignored fields after a change of the Infrastructure", func() {
+		var logs []string
+		fctx := &FlowContext{
+			log:    funcr.New(func(_, args string) { logs = append(logs, args) }, funcr.Options{}),
+			config: &stackitv1alpha1.InfrastructureConfig{FloatingPoolName: "floating-pool"},
+			infra:  &extensionsv1alpha1.Infrastructure{ObjectMeta: metav1.ObjectMeta{Generation: 2}},
+		}
+		fctx.infra.Status.ObservedGeneration = 1
+
+		fctx.logIgnoredFields()
+		Expect(logs).To(ConsistOf(ContainSubstring("floatingPoolName")))
+
+		fctx.infra.Status.ObservedGeneration = 2
+		fctx.logIgnoredFields()
+		Expect(logs).To(HaveLen(1))
 	})
 })
@@ -3,11 +3,24 @@ package infraflow
 import (
 	"context"
 	"fmt"
+	"net/netip"
+	"slices"
+	"strings"
 
+	gardenv1beta1helper "github.com/gardener/gardener/pkg/api/core/v1beta1/helper"
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	"github.com/gophercloud/gophercloud/v2/openstack/networking/v2/subnets"
+	iaas "github.com/stackitcloud/stackit-sdk-go/services/iaas/v2api"
+	"k8s.io/utils/ptr"
+
+	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/apis/config"
+	stackitv1alpha1 "github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/apis/stackit/v1alpha1"
+	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/stackit"
 	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/stackit/client"
 )
 
-// ErrorMultipleMatches is returned when the findExisting finds multiple resources matching a name.
+// ErrorMultipleMatches is returned when the findExisting finds multiple resources matching a name and the resource is
+// not identified by an ID in the state.
 var ErrorMultipleMatches = fmt.Errorf("error multiple matches")
 
 func (fctx *FlowContext) workerCIDR() string {
@@ -19,6 +32,71 @@ func (fctx *FlowContext) workerCIDR() string {
 
 	return s
 }
+
+// ipv6Network returns the IPv6 configuration of the isolated network, either with the configured prefix or with a
+// prefix of the configured length assigned by STACKIT.
+func ipv6Network(config *stackitv1alpha1.IPv6Network) iaas.CreateNetworkIPv6 {
+	if config.Prefix != nil {
+		return iaas.CreateNetworkIPv6{
+			CreateNetworkIPv6WithPrefix: &iaas.CreateNetworkIPv6WithPrefix{
+				Nameservers: config.DNSServers,
+				Prefix:      *config.Prefix,
+			},
+		}
+	}
+	return iaas.CreateNetworkIPv6{
+		CreateNetworkIPv6WithPrefixLength: &iaas.CreateNetworkIPv6WithPrefixLength{
+			Nameservers:  config.DNSServers,
+			PrefixLength: int64(ptr.Deref(config.PrefixLength, 0)),
+		},
+	}
+}
+
+// subnetsWithCIDR returns the subnets with the given CIDR. The CIDRs are compared as prefixes, so that different
+// notations of the same network match.
+func subnetsWithCIDR(candidates []*subnets.Subnet, cidr string) []*subnets.Subnet {
+	prefix, err := netip.ParsePrefix(cidr)
+	if err != nil {
+		return nil
+	}
+
+	var matches []*subnets.Subnet
+	for _, subnet := range candidates {
+		if subnetPrefix, err := netip.ParsePrefix(subnet.CIDR); err == nil && subnetPrefix.Masked() == prefix.Masked() {
+			matches = append(matches, subnet)
+		}
+	}
+	return matches
+}
+
+// dnsServers returns the DNS servers of the worker network. The DNS servers of the cloud profile are used as default,
+// while allowing overrides through the InfrastructureConfig. If no DNS servers are configured, nil is returned, so that
+// the network inherits the default nameservers provided by STACKIT via DHCP. Duplicates are removed, as the STACKIT API
+// limits the number of DNS servers of a network.
+func (fctx *FlowContext) dnsServers() ([]string, error) {
+	dnsServers := fctx.cloudProfileConfig.DNSServers
+	if fctx.config.Networks.DNSServers != nil {
+		dnsServers = *fctx.config.Networks.DNSServers
+	}
+	if len(dnsServers) == 0 {
+		return nil, nil
+	}
+
+	var result []string
+	for _, dnsServer := range dnsServers {
+		if !slices.Contains(result, dnsServer) {
+			result = append(result, dnsServer)
+		}
+	}
+	if len(result) > stackit.MaxDNSServers {
+		return nil, gardenv1beta1helper.NewErrorWithCodes(
+			fmt.Errorf("%d DNS servers are configured for the worker network, but STACKIT supports at most %d", len(result), stackit.MaxDNSServers),
+			gardencorev1beta1.ErrorConfigurationProblem,
+		)
+	}
+	return result, nil
+}
+
 func (fctx *FlowContext) defaultSecurityGroupName() string {
 	return fctx.technicalID
 }
@@ -52,7 +130,62 @@ func findExisting[T any](ctx context.Context, id *string, name string,
 		return nil, nil
 	}
 	if len(found) > 1 {
-		return nil, fmt.Errorf("%w: found %d matches for name %q", ErrorMultipleMatches, len(found), name)
+		// adopting an arbitrary match could make the cluster use a leftover of a failed reconciliation
+		return nil, fmt.Errorf("%w: found %d matches for name %q, the leftover resources have to be deleted manually", ErrorMultipleMatches, len(found), name)
 	}
 	return &found[0], nil
 }
+
+// skipSSHKeyPair returns true if the Infrastructure has no SSH public key and the SSH key pair should not be created.
+// Depending on the configured EmptySSHPublicKeyPolicy an error is returned instead.
+func (fctx *FlowContext) skipSSHKeyPair() (bool, error) {
+	if strings.TrimSpace(string(fctx.infra.Spec.SSHPublicKey)) != "" {
+		return false, nil
+	}
+	if fctx.emptySSHPublicKeyPolicy == config.EmptySSHPublicKeyPolicyReject {
+		return false, gardenv1beta1helper.NewErrorWithCodes(
+			fmt.Errorf("SSH public key of infrastructure is empty, but empty SSH public keys are rejected"),
+			gardencorev1beta1.ErrorConfigurationProblem,
+		)
+	}
+	return true, nil
+}
+
+// logIgnoredFields logs the configured fields of the InfrastructureConfig which the STACKIT flow does not use. The
+// message is only emitted if the spec of the Infrastructure changed since the last successful reconciliation, so that
+// periodic reconciliations do not repeat it.
+func (fctx *FlowContext) logIgnoredFields() {
+	if fctx.infra != nil && fctx.infra.Generation == fctx.infra.Status.ObservedGeneration {
+		return
+	}
+	if fields := fctx.ignoredFields(); len(fields) > 0 {
+		fctx.log.Info("fields of the InfrastructureConfig are ignored by the STACKIT infrastructure",
+			"fields", fields)
+	}
+}
+
+// ignoredFields returns the configured fields of the InfrastructureConfig which the STACKIT flow does not use. Without
+// OpenStack credentials the STACKIT flow only manages isolated networks, egress is provided by the network itself. The
+// flow never uses routers or floating pool subnets.
+func (fctx *FlowContext) ignoredFields() []string {
+	if fctx.config == nil {
+		return nil
+	}
+
+	var fields []string
+	if !fctx.hasOpenStackCredentials {
+		if fctx.config.FloatingPoolName != "" {
+			fields = append(fields, "floatingPoolName")
+		}
+		if fctx.config.FloatingPoolID != nil {
+			fields = append(fields, "floatingPoolId")
+		}
+	}
+	if fctx.config.FloatingPoolSubnetName != nil {
+		fields = append(fields, "floatingPoolSubnetName")
+	}
+	if fctx.config.Networks.Router != nil {
+		fields = append(fields, "networks.router")
+	}
+	return fields
+}
@@ -0,0 +1,85 @@
+package infraflow
+
+import (
+	"context"
+	"net/http"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	iaas "github.com/stackitcloud/stackit-sdk-go/services/iaas/v2api"
+
+	stackitclient "github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/stackit/client"
+)
+
+var _ = Describe("STACKIT infraflow utils", func() {
+	Describe("#findExisting", func() {
+		var (
+			ctx      context.Context
+			byID     map[string]*iaas.Network
+			networks []iaas.Network
+		)
+
+		getter := func(_ context.Context, id string) (*iaas.Network, error) {
+			network, ok := byID[id]
+			if !ok {
+				return nil, stackitclient.NewNotFoundError("network", id)
+			}
+			return network, nil
+		}
+		finder := func(_ context.Context, name string) ([]iaas.Network, error) {
+			var found []iaas.Network
+			for _, network := range networks {
+				if network.Name == name {
+					found = append(found, network)
+				}
+			}
+			return found, nil
+		}
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			byID = map[string]*iaas.Network{}
+			networks = nil
+		})
+
+		It("should return nil without matches", func() {
+			Expect(findExisting(ctx, nil, "shoot--foo--bar", getter, finder)).To(BeNil())
+		})
+
+		It("should return the single match of the name", func() {
+			networks = []iaas.Network{{Id: "network-1", Name: "shoot--foo--bar"}, {Id: "network-2", Name: "other"}}
+
+			Expect(findExisting(ctx, nil, "shoot--foo--bar", getter, finder)).To(HaveField("Id", "network-1"))
+		})
+
+		It("should fail for multiple matches of the name", func() {
+			networks = []iaas.Network{{Id: "network-1", Name: "shoot--foo--bar"}, {Id: "network-2", Name: "shoot--foo--bar"}}
+
+			_, err := findExisting(ctx, nil, "shoot--foo--bar", getter, finder)
+			Expect(err).To(MatchError(ErrorMultipleMatches))
+			Expect(err).To(MatchError(ContainSubstring(`found 2 matches for name "shoot--foo--bar"`)))
+		})
+
+		It("should prefer the resource of the ID in the state over multiple matches of the name", func() {
+			networks = []iaas.Network{{Id: "network-1", Name: "shoot--foo--bar"}, {Id: "network-2", Name: "shoot--foo--bar"}}
+			byID["network-2"] = &networks[1]
+
+			Expect(findExisting(ctx, new("network-2"), "shoot--foo--bar", getter, finder)).To(HaveField("Id", "network-2"))
+		})
+
+		It("should fall back to the name if the resource of the ID in the state is gone", func() {
+			networks = []iaas.Network{{Id: "network-1", Name: "shoot--foo--bar"}}
+
+			Expect(findExisting(ctx, new("network-2"), "shoot--foo--bar", getter, finder)).To(HaveField("Id", "network-1"))
+		})
+
+		It("should return other errors of the getter", func() {
+			failingGetter := func(_ context.Context, _ string) (*iaas.Network, error) {
+				return nil, &stackitclient.Error{Message: "service unavailable", StatusCode: http.StatusServiceUnavailable}
+			}
+
+			_, err := findExisting(ctx, new("network-1"), "shoot--foo--bar", failingGetter, finder)
+			Expect(err).To(MatchError("service unavailable"))
+		})
+	})
+})
@@ -14,6 +14,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 
+	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/apis/config"
 	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/apis/stackit/helper"
 	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/stackit"
 	stackitclient "github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/stackit/client"
@@ -22,6 +23,9 @@ import (
 type Actuator struct {
 	Client  client.Client
 	Decoder runtime.Decoder
+	// ClusterLabelValueSource defines which identifier of the shoot is used as value of the cluster label of the load
+	// balancers. It must match the one of the infrastructure controller, which deletes the load balancers of the shoot.
+	ClusterLabelValueSource config.ClusterLabelValueSource
 }
 
 func (a *Actuator) WithManager(mgr manager.Manager) *Actuator {
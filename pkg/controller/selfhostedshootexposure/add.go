@@ -8,6 +8,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 
+	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/apis/config"
 	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/stackit"
 )
 
@@ -22,13 +23,16 @@ type AddOptions struct {
 	IgnoreOperationAnnotation bool
 	// ExtensionClasses defines the extension class this extension is responsible for.
 	ExtensionClasses []extensionsv1alpha1.ExtensionClass
+	// ClusterLabelValueSource defines which identifier of the shoot is used as value of the cluster label of the load
+	// balancers.
+	ClusterLabelValueSource config.ClusterLabelValueSource
 }
 
 // AddToManagerWithOptions adds a controller with the given Options to the given manager.
 // The opts.Reconciler is being set with a newly instantiated Actuator.
 func AddToManagerWithOptions(mgr manager.Manager, opts AddOptions) error {
 	return selfhostedshootexposure.Add(mgr, selfhostedshootexposure.AddArgs{
-		Actuator:          (&Actuator{}).WithManager(mgr),
+		Actuator:          (&Actuator{ClusterLabelValueSource: opts.ClusterLabelValueSource}).WithManager(mgr),
 		ControllerOptions: opts.Controller,
 		Predicates:        selfhostedshootexposure.DefaultPredicates(opts.IgnoreOperationAnnotation),
 		Type:              stackit.Type,
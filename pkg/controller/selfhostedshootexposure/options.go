@@ -48,6 +48,11 @@ type Options struct {
 }
 
 func (a *Actuator) DetermineOptions(ctx context.Context, exposure *extensionsv1alpha1.SelfHostedShootExposure, cluster *extensionscontroller.Cluster, projectID string) (*Options, error) {
+	clusterLabel, err := controlplane.ClusterLabelValue(cluster, a.ClusterLabelValueSource)
+	if err != nil {
+		return nil, err
+	}
+
 	opts := &Options{
 		SelfHostedShootExposure: exposure,
 		ProjectID:               projectID,
@@ -59,7 +64,7 @@ func (a *Actuator) DetermineOptions(ctx context.Context, exposure *extensionsv1a
 		// in label keys; this needs to be coordinated across CCM, controlplane and infraflow so
 		// the infrastructure cleanup keeps finding all LBs by the same key.
 		Labels: map[string]string{
-			controlplane.STACKITLBClusterLabelKey: cluster.Shoot.Status.TechnicalID,
+			controlplane.STACKITLBClusterLabelKey: clusterLabel,
 			ExposureLabelKey:                      exposure.Name,
 		},
 		Region: stackit.DetermineRegion(cluster),
@@ -18,6 +18,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
 
+	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/apis/config"
 	stackitv1alpha1 "github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/apis/stackit/v1alpha1"
 	. "github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/controller/selfhostedshootexposure"
 )
@@ -129,6 +130,16 @@ var _ = Describe("Options", func() {
 		}))
 	})
 
+	It("should label the load balancer with the shoot UID if configured", func() {
+		a.ClusterLabelValueSource = config.ClusterLabelValueSourceShootUID
+		shoot.UID = "shoot-uid"
+
+		opts, err := a.DetermineOptions(ctx, exposure, cluster, projectID)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(opts.Labels).To(HaveKeyWithValue("cluster.stackit.cloud", "shoot-uid"))
+	})
+
 	It("should use PlanID from providerConfig", func() {
 		encoder := serializer.NewCodecFactory(fakeClient.Scheme()).EncoderForVersion(&json.Serializer{}, stackitv1alpha1.SchemeGroupVersion)
 		providerConfig := &stackitv1alpha1.SelfHostedShootExposureConfig{
@@ -18,13 +18,16 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/serializer"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
+	"k8s.io/utils/clock"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/cluster"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 
+	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/apis/config"
 	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/apis/stackit/helper"
 	stackitv1alpha1 "github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/apis/stackit/v1alpha1"
 	openstackclient "github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/openstack/client"
+	stackitclient "github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/stackit/client"
 )
 
 type delegateFactory struct {
@@ -32,27 +35,34 @@ type delegateFactory struct {
 	restConfig        *rest.Config
 	scheme            *runtime.Scheme
 	customLabelDomain string
+	configuration     config.WorkerControllerConfiguration
 }
 
 // NewActuator creates a new Actuator that updates the status of the handled WorkerPoolConfigs.
-func NewActuator(mgr manager.Manager, gardenCluster cluster.Cluster, customLabelDomain string) worker.Actuator {
+func NewActuator(mgr manager.Manager, gardenCluster cluster.Cluster, customLabelDomain string, configuration config.WorkerControllerConfiguration) worker.Actuator {
 	var (
 		workerDelegate = &delegateFactory{
 			seedClient:        mgr.GetClient(),
 			restConfig:        mgr.GetConfig(),
 			scheme:            mgr.GetScheme(),
 			customLabelDomain: customLabelDomain,
+			configuration:     configuration,
 		}
 	)
 
-	return genericactuator.NewActuator(
-		mgr,
-		gardenCluster,
-		workerDelegate,
-		func(err error) []gardencorev1beta1.ErrorCode {
-			return util.DetermineErrorCodes(err, helper.KnownCodes)
-		},
-	)
+	return &validatingActuator{
+		Actuator: genericactuator.NewActuator(
+			mgr,
+			gardenCluster,
+			workerDelegate,
+			func(err error) []gardencorev1beta1.ErrorCode {
+				return util.DetermineErrorCodes(err, helper.KnownCodes)
+			},
+		),
+		client:          mgr.GetClient(),
+		clock:           clock.RealClock{},
+		delegateFactory: workerDelegate,
+	}
 }
 
 func (d *delegateFactory) WorkerDelegate(ctx context.Context, worker *extensionsv1alpha1.Worker, cluster *extensionscontroller.Cluster) (genericactuator.WorkerDelegate, error) {
@@ -81,6 +91,7 @@ func (d *delegateFactory) WorkerDelegate(ctx context.Context, worker *extensions
 		worker,
 		cluster,
 		d.customLabelDomain,
+		d.configuration,
 	)
 }
 
@@ -96,12 +107,14 @@ type workerDelegate struct {
 	cluster            *extensionscontroller.Cluster
 	worker             *extensionsv1alpha1.Worker
 	customLabelDomain  string
+	configuration      config.WorkerControllerConfiguration
 
 	machineClasses     []map[string]any
 	machineDeployments worker.MachineDeployments
 	machineImages      []stackitv1alpha1.MachineImage
 
 	openstackClient openstackclient.Factory
+	iaasClient      stackitclient.IaaSClient
 }
 
 // NewWorkerDelegate creates a new context for a worker reconciliation.
@@ -115,11 +128,16 @@ func NewWorkerDelegate(
 	worker *extensionsv1alpha1.Worker,
 	cluster *extensionscontroller.Cluster,
 	customLabelDomain string,
+	configuration config.WorkerControllerConfiguration,
 ) (genericactuator.WorkerDelegate, error) {
 	config, err := helper.CloudProfileConfigFromCluster(cluster)
 	if err != nil {
 		return nil, err
 	}
+	customLabelDomain, err = helper.CustomLabelDomainFromCluster(cluster, customLabelDomain)
+	if err != nil {
+		return nil, err
+	}
 
 	return &workerDelegate{
 		seedClient: seedClient,
@@ -133,5 +151,6 @@ func NewWorkerDelegate(
 		cluster:            cluster,
 		worker:             worker,
 		customLabelDomain:  customLabelDomain,
+		configuration:      configuration,
 	}, nil
 }
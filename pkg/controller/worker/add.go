@@ -16,6 +16,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 
+	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/apis/config"
 	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/stackit"
 )
 
@@ -38,6 +39,8 @@ type AddOptions struct {
 	SelfHostedShootCluster bool
 	// CustomLabelDomain is the domain prefix for custom labels applied to STACKIT infrastructure resources.
 	CustomLabelDomain string
+	// Configuration is the configuration of the worker controller.
+	Configuration config.WorkerControllerConfiguration
 }
 
 // AddToManagerWithOptions adds a controller with the given Options to the given manager.
@@ -52,7 +55,7 @@ func AddToManagerWithOptions(ctx context.Context, mgr manager.Manager, opts AddO
 	}
 
 	return worker.Add(ctx, mgr, worker.AddArgs{
-		Actuator:               NewActuator(mgr, opts.GardenCluster, opts.CustomLabelDomain),
+		Actuator:               NewActuator(mgr, opts.GardenCluster, opts.CustomLabelDomain, opts.Configuration),
 		ControllerOptions:      opts.Controller,
 		Predicates:             worker.DefaultPredicates(ctx, mgr, opts.IgnoreOperationAnnotation),
 		Type:                   stackit.Type,
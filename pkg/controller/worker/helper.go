@@ -13,6 +13,8 @@ import (
 	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
 
 	stackitv1alpha1 "github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/apis/stackit/v1alpha1"
+	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/stackit"
+	stackitclient "github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/stackit/client"
 )
 
 func (w *workerDelegate) decodeWorkerProviderStatus() (*stackitv1alpha1.WorkerStatus, error) {
@@ -44,6 +46,18 @@ func (w *workerDelegate) updateWorkerProviderStatus(ctx context.Context, workerS
 	return w.seedClient.Status().Patch(ctx, w.worker, patch)
 }
 
+// getIaaSClient returns the IaaS client of the worker, which is created with the cloudprovider credentials on first use.
+func (w *workerDelegate) getIaaSClient(ctx context.Context) (stackitclient.IaaSClient, error) {
+	if w.iaasClient == nil {
+		iaasClient, err := stackitclient.New(stackit.DetermineRegion(w.cluster), w.cluster).IaaS(ctx, w.seedClient, w.worker.Spec.SecretRef)
+		if err != nil {
+			return nil, err
+		}
+		w.iaasClient = iaasClient
+	}
+	return w.iaasClient, nil
+}
+
 // ClusterTechnicalName returns the technical name of the cluster this worker belongs.
 func (w *workerDelegate) ClusterTechnicalName() string {
 	return w.cluster.Shoot.Status.TechnicalID
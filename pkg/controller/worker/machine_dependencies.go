@@ -6,6 +6,16 @@ package worker
 
 import (
 	"context"
+	"fmt"
+	"slices"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/apis/stackit/helper"
+	stackitv1alpha1 "github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/apis/stackit/v1alpha1"
+	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/feature"
+	stackitclient "github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/stackit/client"
 )
 
 // DeployMachineDependencies implements genericactuator.WorkerDelegate.
@@ -23,13 +33,19 @@ func (w *workerDelegate) CleanupMachineDependencies(_ context.Context) error {
 }
 
 // PreReconcileHook implements genericactuator.WorkerDelegate.
-func (w *workerDelegate) PreReconcileHook(_ context.Context) error {
-	return nil
+func (w *workerDelegate) PreReconcileHook(ctx context.Context) error {
+	if err := w.verifyMachineCredentialsSecret(ctx); err != nil {
+		return err
+	}
+	if err := w.verifyMachineImageChecksums(ctx); err != nil {
+		return err
+	}
+	return w.reconcileServerGroups(ctx)
 }
 
 // PostReconcileHook implements genericactuator.WorkerDelegate.
-func (w *workerDelegate) PostReconcileHook(_ context.Context) error {
-	return nil
+func (w *workerDelegate) PostReconcileHook(ctx context.Context) error {
+	return w.cleanupServerGroups(ctx, false)
 }
 
 // PreDeleteHook implements genericactuator.WorkerDelegate.
@@ -38,6 +54,156 @@ func (w *workerDelegate) PreDeleteHook(_ context.Context) error {
 }
 
 // PostDeleteHook implements genericactuator.WorkerDelegate.
-func (w *workerDelegate) PostDeleteHook(_ context.Context) error {
+func (w *workerDelegate) PostDeleteHook(ctx context.Context) error {
+	return w.cleanupServerGroups(ctx, true)
+}
+
+// serverGroupName returns the name of the server group of the given worker pool and policy. The name is deterministic,
+// so that server groups which have been created but not recorded in the worker status are reused.
+func (w *workerDelegate) serverGroupName(poolName, policy string) string {
+	return fmt.Sprintf("%s-%s-%s", w.cluster.Shoot.Status.TechnicalID, poolName, policy)
+}
+
+// reconcileServerGroups ensures a server group for every worker pool with a server group configuration and records it
+// in the ServerGroupDependencies of the worker status. Server groups of a previous policy are kept until they are
+// cleaned up after the worker pool has been rolled.
+func (w *workerDelegate) reconcileServerGroups(ctx context.Context) error {
+	workerStatus, err := w.decodeWorkerProviderStatus()
+	if err != nil {
+		return fmt.Errorf("unable to decode the worker provider status: %w", err)
+	}
+
+	var modified bool
+	for _, pool := range w.worker.Spec.Pools {
+		workerConfig, err := helper.WorkerConfigFromRawExtension(pool.ProviderConfig)
+		if err != nil {
+			return err
+		}
+		if workerConfig.ServerGroup == nil {
+			continue
+		}
+		if !feature.UseStackitMachineControllerManager(w.cluster) {
+			return fmt.Errorf("serverGroup of worker pool %s is only supported with the STACKIT machine controller manager", pool.Name)
+		}
+
+		name := w.serverGroupName(pool.Name, workerConfig.ServerGroup.Policy)
+		if findServerGroupDependency(workerStatus.ServerGroupDependencies, pool.Name, name) != nil {
+			continue
+		}
+
+		id, err := w.ensureServerGroup(ctx, name, workerConfig.ServerGroup.Policy)
+		if err != nil {
+			return fmt.Errorf("could not ensure server group of worker pool %s: %w", pool.Name, err)
+		}
+		workerStatus.ServerGroupDependencies = append(workerStatus.ServerGroupDependencies, stackitv1alpha1.ServerGroupDependency{
+			PoolName: pool.Name,
+			ID:       id,
+			Name:     name,
+		})
+		modified = true
+	}
+
+	if !modified {
+		return nil
+	}
+	if err := w.updateWorkerProviderStatus(ctx, workerStatus); err != nil {
+		return fmt.Errorf("unable to update worker provider status: %w", err)
+	}
+	return nil
+}
+
+// ensureServerGroup returns the ID of the server group with the given name, which is created if it does not exist yet.
+func (w *workerDelegate) ensureServerGroup(ctx context.Context, name, policy string) (string, error) {
+	iaasClient, err := w.getIaaSClient(ctx)
+	if err != nil {
+		return "", fmt.Errorf("could not create IaaS client: %w", err)
+	}
+
+	serverGroups, err := iaasClient.GetAffinityGroupByName(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	if len(serverGroups) > 1 {
+		return "", fmt.Errorf("found %d server groups with name %s", len(serverGroups), name)
+	}
+	if len(serverGroups) == 1 {
+		if serverGroups[0].GetPolicy() != policy {
+			return "", fmt.Errorf("server group %s has policy %s instead of %s", name, serverGroups[0].GetPolicy(), policy)
+		}
+		return serverGroups[0].GetId(), nil
+	}
+
+	logr.FromContextOrDiscard(ctx).Info("Creating server group", "name", name, "policy", policy)
+	serverGroup, err := iaasClient.CreateAffinityGroup(ctx, name, policy)
+	if err != nil {
+		return "", fmt.Errorf("error creating server group %s: %w", name, err)
+	}
+	return serverGroup.GetId(), nil
+}
+
+// cleanupServerGroups deletes the server groups of the ServerGroupDependencies of the worker status which are not used by
+// the worker pools anymore, or all of them if deleteAll is set. During reconciliation, server groups which still contain
+// servers are kept until the next reconciliation.
+func (w *workerDelegate) cleanupServerGroups(ctx context.Context, deleteAll bool) error {
+	workerStatus, err := w.decodeWorkerProviderStatus()
+	if err != nil {
+		return fmt.Errorf("unable to decode the worker provider status: %w", err)
+	}
+	if len(workerStatus.ServerGroupDependencies) == 0 {
+		return nil
+	}
+
+	inUse := sets.New[string]()
+	if !deleteAll {
+		for _, pool := range w.worker.Spec.Pools {
+			workerConfig, err := helper.WorkerConfigFromRawExtension(pool.ProviderConfig)
+			if err != nil {
+				return err
+			}
+			if workerConfig.ServerGroup != nil {
+				inUse.Insert(w.serverGroupName(pool.Name, workerConfig.ServerGroup.Policy))
+			}
+		}
+	}
+
+	iaasClient, err := w.getIaaSClient(ctx)
+	if err != nil {
+		return fmt.Errorf("could not create IaaS client: %w", err)
+	}
+
+	log := logr.FromContextOrDiscard(ctx)
+	dependencies := slices.Clone(workerStatus.ServerGroupDependencies)
+	workerStatus.ServerGroupDependencies = nil
+	for _, dependency := range dependencies {
+		if inUse.Has(dependency.Name) {
+			workerStatus.ServerGroupDependencies = append(workerStatus.ServerGroupDependencies, dependency)
+			continue
+		}
+
+		log.Info("Deleting server group", "name", dependency.Name, "id", dependency.ID, "pool", dependency.PoolName)
+		if err := iaasClient.DeleteAffinityGroup(ctx, dependency.ID); stackitclient.IgnoreNotFoundError(err) != nil {
+			if deleteAll || !stackitclient.IsConflict(err) {
+				return fmt.Errorf("error deleting server group %s of worker pool %s: %w", dependency.Name, dependency.PoolName, err)
+			}
+			log.Info("Keeping server group which is still in use", "name", dependency.Name, "id", dependency.ID, "pool", dependency.PoolName)
+			workerStatus.ServerGroupDependencies = append(workerStatus.ServerGroupDependencies, dependency)
+		}
+	}
+
+	if len(workerStatus.ServerGroupDependencies) == len(dependencies) {
+		return nil
+	}
+	if err := w.updateWorkerProviderStatus(ctx, workerStatus); err != nil {
+		return fmt.Errorf("unable to update worker provider status: %w", err)
+	}
+	return nil
+}
+
+func findServerGroupDependency(dependencies []stackitv1alpha1.ServerGroupDependency, poolName, name string) *stackitv1alpha1.ServerGroupDependency {
+	for i := range dependencies {
+		if dependencies[i].PoolName == poolName && dependencies[i].Name == name {
+			return &dependencies[i]
+		}
+	}
 	return nil
 }
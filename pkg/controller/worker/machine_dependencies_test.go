@@ -0,0 +1,181 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	extensionscontroller "github.com/gardener/gardener/extensions/pkg/controller"
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	iaas "github.com/stackitcloud/stackit-sdk-go/services/iaas/v2api"
+	"go.uber.org/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	stackitv1alpha1 "github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/apis/stackit/v1alpha1"
+	stackitclient "github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/stackit/client"
+	mock "github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/stackit/client/mock"
+)
+
+var _ = Describe("MachineDependencies", func() {
+	var (
+		ctx        context.Context
+		c          client.Client
+		iaasClient *mock.MockIaaSClient
+		worker     *extensionsv1alpha1.Worker
+		w          *workerDelegate
+	)
+
+	newPool := func(name, policy string) extensionsv1alpha1.WorkerPool {
+		workerConfig := &stackitv1alpha1.WorkerConfig{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "WorkerConfig",
+				APIVersion: stackitv1alpha1.SchemeGroupVersion.String(),
+			},
+		}
+		if policy != "" {
+			workerConfig.ServerGroup = &stackitv1alpha1.ServerGroupConfig{Policy: policy}
+		}
+		raw, err := json.Marshal(workerConfig)
+		Expect(err).NotTo(HaveOccurred())
+		return extensionsv1alpha1.WorkerPool{Name: name, ProviderConfig: &runtime.RawExtension{Raw: raw}}
+	}
+
+	setDependencies := func(dependencies ...stackitv1alpha1.ServerGroupDependency) {
+		raw, err := json.Marshal(&stackitv1alpha1.WorkerStatus{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "WorkerStatus",
+				APIVersion: stackitv1alpha1.SchemeGroupVersion.String(),
+			},
+			ServerGroupDependencies: dependencies,
+		})
+		Expect(err).NotTo(HaveOccurred())
+		worker.Status.ProviderStatus = &runtime.RawExtension{Raw: raw}
+	}
+
+	createDelegate := func() {
+		scheme := runtime.NewScheme()
+		utilruntime.Must(extensionsv1alpha1.AddToScheme(scheme))
+		utilruntime.Must(stackitv1alpha1.AddToScheme(scheme))
+		c = fakeclient.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(&extensionsv1alpha1.Worker{}).WithObjects(worker).Build()
+
+		w = &workerDelegate{
+			seedClient: c,
+			decoder:    serializer.NewCodecFactory(scheme, serializer.EnableStrict).UniversalDecoder(),
+			cluster: &extensionscontroller.Cluster{
+				Shoot: &gardencorev1beta1.Shoot{Status: gardencorev1beta1.ShootStatus{TechnicalID: "shoot--foo--bar"}},
+			},
+			worker:     worker,
+			iaasClient: iaasClient,
+		}
+	}
+
+	expectDependencies := func() Assertion {
+		persisted := &extensionsv1alpha1.Worker{}
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(worker), persisted)).To(Succeed())
+		workerStatus := &stackitv1alpha1.WorkerStatus{}
+		if persisted.Status.ProviderStatus != nil {
+			Expect(json.Unmarshal(persisted.Status.ProviderStatus.Raw, workerStatus)).To(Succeed())
+		}
+		return Expect(workerStatus.ServerGroupDependencies)
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		iaasClient = mock.NewMockIaaSClient(gomock.NewController(GinkgoT()))
+		worker = &extensionsv1alpha1.Worker{
+			ObjectMeta: metav1.ObjectMeta{Name: "worker", Namespace: "shoot--foo--bar"},
+			Spec: extensionsv1alpha1.WorkerSpec{
+				Pools: []extensionsv1alpha1.WorkerPool{
+					newPool("pool-1", "soft-anti-affinity"),
+					newPool("pool-2", ""),
+				},
+			},
+		}
+	})
+
+	Describe("#PreReconcileHook", func() {
+		It("should create missing server groups and record them in the worker status", func() {
+			createDelegate()
+			iaasClient.EXPECT().GetAffinityGroupByName(ctx, "shoot--foo--bar-pool-1-soft-anti-affinity").Return(nil, nil)
+			iaasClient.EXPECT().CreateAffinityGroup(ctx, "shoot--foo--bar-pool-1-soft-anti-affinity", "soft-anti-affinity").
+				Return(&iaas.AffinityGroup{Id: new("server-group-1"), Name: "shoot--foo--bar-pool-1-soft-anti-affinity", Policy: "soft-anti-affinity"}, nil)
+
+			Expect(w.PreReconcileHook(ctx)).To(Succeed())
+			expectDependencies().To(ConsistOf(stackitv1alpha1.ServerGroupDependency{
+				PoolName: "pool-1",
+				ID:       "server-group-1",
+				Name:     "shoot--foo--bar-pool-1-soft-anti-affinity",
+			}))
+		})
+
+		It("should reuse existing server groups with the same name", func() {
+			createDelegate()
+			iaasClient.EXPECT().GetAffinityGroupByName(ctx, "shoot--foo--bar-pool-1-soft-anti-affinity").
+				Return([]iaas.AffinityGroup{{Id: new("server-group-1"), Name: "shoot--foo--bar-pool-1-soft-anti-affinity", Policy: "soft-anti-affinity"}}, nil)
+
+			Expect(w.PreReconcileHook(ctx)).To(Succeed())
+			expectDependencies().To(ConsistOf(HaveField("ID", "server-group-1")))
+		})
+
+		It("should not call the API for recorded server groups", func() {
+			setDependencies(stackitv1alpha1.ServerGroupDependency{PoolName: "pool-1", ID: "server-group-1", Name: "shoot--foo--bar-pool-1-soft-anti-affinity"})
+			createDelegate()
+
+			Expect(w.PreReconcileHook(ctx)).To(Succeed())
+		})
+	})
+
+	Describe("#PostReconcileHook", func() {
+		It("should delete the server groups which are not used anymore", func() {
+			worker.Spec.Pools = append(worker.Spec.Pools, newPool("pool-3", "soft-affinity"))
+			setDependencies(
+				stackitv1alpha1.ServerGroupDependency{PoolName: "pool-1", ID: "server-group-1", Name: "shoot--foo--bar-pool-1-soft-anti-affinity"},
+				stackitv1alpha1.ServerGroupDependency{PoolName: "pool-1", ID: "server-group-2", Name: "shoot--foo--bar-pool-1-soft-affinity"},
+				stackitv1alpha1.ServerGroupDependency{PoolName: "pool-3", ID: "server-group-3", Name: "shoot--foo--bar-pool-3-soft-affinity"},
+				stackitv1alpha1.ServerGroupDependency{PoolName: "removed", ID: "server-group-4", Name: "shoot--foo--bar-removed-soft-affinity"},
+				stackitv1alpha1.ServerGroupDependency{PoolName: "removed", ID: "server-group-5", Name: "shoot--foo--bar-removed-soft-anti-affinity"},
+			)
+			createDelegate()
+			iaasClient.EXPECT().DeleteAffinityGroup(ctx, "server-group-2").Return(nil)
+			iaasClient.EXPECT().DeleteAffinityGroup(ctx, "server-group-4").Return(&stackitclient.Error{StatusCode: http.StatusConflict})
+			iaasClient.EXPECT().DeleteAffinityGroup(ctx, "server-group-5").Return(&stackitclient.Error{StatusCode: http.StatusNotFound})
+
+			Expect(w.PostReconcileHook(ctx)).To(Succeed())
+			expectDependencies().To(ConsistOf(
+				HaveField("ID", "server-group-1"),
+				HaveField("ID", "server-group-3"),
+				HaveField("ID", "server-group-4"),
+			))
+		})
+	})
+
+	Describe("#PostDeleteHook", func() {
+		It("should delete all server groups", func() {
+			setDependencies(stackitv1alpha1.ServerGroupDependency{PoolName: "pool-1", ID: "server-group-1", Name: "shoot--foo--bar-pool-1-soft-anti-affinity"})
+			createDelegate()
+			iaasClient.EXPECT().DeleteAffinityGroup(ctx, "server-group-1").Return(nil)
+
+			Expect(w.PostDeleteHook(ctx)).To(Succeed())
+			expectDependencies().To(BeEmpty())
+		})
+
+		It("should not create an IaaS client without server groups", func() {
+			createDelegate()
+			w.iaasClient = nil
+
+			Expect(w.PostDeleteHook(ctx)).To(Succeed())
+		})
+	})
+})
@@ -7,9 +7,13 @@ package worker
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/gardener/gardener/extensions/pkg/controller/worker"
+	gardencorev1beta1helper "github.com/gardener/gardener/pkg/api/core/v1beta1/helper"
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
 	v1beta1constants "github.com/gardener/gardener/pkg/apis/core/v1beta1/constants"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/utils/ptr"
 	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -53,7 +57,7 @@ func (w *workerDelegate) findMachineImage(name, version, architecture string) (*
 
 		machineImage, err := helper.FindMachineImage(workerStatus.MachineImages, name, version, architecture)
 		if err != nil {
-			return nil, worker.ErrorMachineImageNotFound(name, version)
+			return nil, w.machineImageNotFoundError(name, version, architecture)
 		}
 
 		// The architecture field might not be present in the WorkerStatus if the Shoot has been created before introduction
@@ -66,7 +70,114 @@ func (w *workerDelegate) findMachineImage(name, version, architecture string) (*
 		return machineImage, nil
 	}
 
-	return nil, worker.ErrorMachineImageNotFound(name, version)
+	return nil, w.machineImageNotFoundError(name, version, architecture)
+}
+
+// machineImageNotFoundError returns a descriptive error if the machine image version is configured in the
+// CloudProfileConfig, but cannot be resolved for the region of the shoot or for the given architecture. Otherwise, the
+// generic error for missing machine images is returned.
+func (w *workerDelegate) machineImageNotFoundError(name, version, architecture string) error {
+	region := w.cluster.Shoot.Spec.Region
+	versionFound := false
+	architectures := sets.New[string]()
+	regions := sets.New[string]()
+	if w.cloudProfileConfig != nil {
+		for _, machineImage := range w.cloudProfileConfig.MachineImages {
+			if machineImage.Name != name {
+				continue
+			}
+			for _, imageVersion := range machineImage.Versions {
+				if imageVersion.Version != version {
+					continue
+				}
+				versionFound = true
+				for _, regionMapping := range imageVersion.Regions {
+					regions.Insert(regionMapping.Name)
+					if regionMapping.Name == region {
+						architectures.Insert(ptr.Deref(regionMapping.Architecture, v1beta1constants.ArchitectureAMD64))
+					}
+				}
+				// the fallback image name is only used for amd64
+				if imageVersion.Image != "" {
+					architectures.Insert(v1beta1constants.ArchitectureAMD64)
+				}
+			}
+		}
+	}
+
+	switch {
+	case !versionFound:
+		return worker.ErrorMachineImageNotFound(name, version)
+	case architectures.Len() == 0:
+		mappedRegions := "none"
+		if regions.Len() > 0 {
+			mappedRegions = strings.Join(sets.List(regions), ", ")
+		}
+		return gardencorev1beta1helper.NewErrorWithCodes(
+			fmt.Errorf("machine image %s@%s is not available in region %s, as it has neither an image ID for the region nor an image name fallback, regions with image IDs: %s",
+				name, version, region, mappedRegions),
+			gardencorev1beta1.ErrorConfigurationProblem,
+		)
+	default:
+		return gardencorev1beta1helper.NewErrorWithCodes(
+			fmt.Errorf("machine image %s@%s is not available for architecture %s in region %s, available architectures: %s",
+				name, version, architecture, region, strings.Join(sets.List(architectures), ", ")),
+			gardencorev1beta1.ErrorConfigurationProblem,
+		)
+	}
+}
+
+// verifyMachineImageChecksums verifies the checksums of the machine images of all worker pools. It is only called when
+// reconciling, so that a changed image does not block the deletion of the worker.
+func (w *workerDelegate) verifyMachineImageChecksums(ctx context.Context) error {
+	verifiedImageIDs := sets.New[string]()
+	for _, pool := range w.worker.Spec.Pools {
+		machineImage, err := w.findMachineImage(pool.MachineImage.Name, pool.MachineImage.Version, ptr.Deref(pool.Architecture, v1beta1constants.ArchitectureAMD64))
+		if err != nil {
+			return err
+		}
+		if verifiedImageIDs.Has(machineImage.ID) {
+			continue
+		}
+		if err := w.verifyMachineImageChecksum(ctx, machineImage); err != nil {
+			return err
+		}
+		verifiedImageIDs.Insert(machineImage.ID)
+	}
+	return nil
+}
+
+// verifyMachineImageChecksum verifies the checksum configured for the machine image in the CloudProfileConfig against
+// the checksum of the image in the STACKIT API. Machine images without checksum or ID are not verified.
+func (w *workerDelegate) verifyMachineImageChecksum(ctx context.Context, machineImage *stackitv1alpha1.MachineImage) error {
+	if machineImage.Checksum == nil || machineImage.ID == "" {
+		return nil
+	}
+
+	iaasClient, err := w.getIaaSClient(ctx)
+	if err != nil {
+		return fmt.Errorf("could not create IaaS client to verify machine image checksums: %w", err)
+	}
+
+	image, err := iaasClient.GetImageById(ctx, machineImage.ID)
+	if err != nil {
+		return fmt.Errorf("could not get image %s of machine image %s@%s to verify its checksum: %w", machineImage.ID, machineImage.Name, machineImage.Version, err)
+	}
+
+	expected := machineImage.Checksum
+	actual, ok := image.GetChecksumOk()
+	if !ok || !strings.EqualFold(actual.GetAlgorithm(), expected.Algorithm) || !strings.EqualFold(actual.GetDigest(), expected.Digest) {
+		actualChecksum := "none"
+		if ok {
+			actualChecksum = actual.GetAlgorithm() + ":" + actual.GetDigest()
+		}
+		return gardencorev1beta1helper.NewErrorWithCodes(
+			fmt.Errorf("checksum of image %s of machine image %s@%s does not match: expected %s:%s, got %s",
+				machineImage.ID, machineImage.Name, machineImage.Version, expected.Algorithm, expected.Digest, actualChecksum),
+			gardencorev1beta1.ErrorConfigurationProblem,
+		)
+	}
+	return nil
 }
 
 func appendMachineImage(machineImages []stackitv1alpha1.MachineImage, machineImage stackitv1alpha1.MachineImage) []stackitv1alpha1.MachineImage {
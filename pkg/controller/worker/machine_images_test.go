@@ -0,0 +1,238 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	extensionscontroller "github.com/gardener/gardener/extensions/pkg/controller"
+	gardencorev1beta1helper "github.com/gardener/gardener/pkg/api/core/v1beta1/helper"
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	iaas "github.com/stackitcloud/stackit-sdk-go/services/iaas/v2api"
+	"go.uber.org/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+
+	stackitv1alpha1 "github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/apis/stackit/v1alpha1"
+	mock "github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/stackit/client/mock"
+)
+
+var _ = Describe("MachineImages", func() {
+	Describe("#verifyMachineImageChecksum", func() {
+		var (
+			ctx          context.Context
+			iaasClient   *mock.MockIaaSClient
+			w            *workerDelegate
+			machineImage *stackitv1alpha1.MachineImage
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			iaasClient = mock.NewMockIaaSClient(gomock.NewController(GinkgoT()))
+			w = &workerDelegate{iaasClient: iaasClient}
+			machineImage = &stackitv1alpha1.MachineImage{
+				Name:    "ubuntu",
+				Version: "22.04",
+				ID:      "image-id",
+				Checksum: &stackitv1alpha1.ImageChecksum{
+					Algorithm: "sha256",
+					Digest:    "abcdef",
+				},
+			}
+		})
+
+		It("should not verify machine images without checksum", func() {
+			machineImage.Checksum = nil
+
+			Expect(w.verifyMachineImageChecksum(ctx, machineImage)).To(Succeed())
+		})
+
+		It("should succeed if the checksum matches", func() {
+			iaasClient.EXPECT().GetImageById(ctx, "image-id").Return(&iaas.Image{
+				Checksum: &iaas.ImageChecksum{Algorithm: "sha256", Digest: "ABCDEF"},
+			}, nil)
+
+			Expect(w.verifyMachineImageChecksum(ctx, machineImage)).To(Succeed())
+		})
+
+		It("should return a configuration problem if the checksum does not match", func() {
+			iaasClient.EXPECT().GetImageById(ctx, "image-id").Return(&iaas.Image{
+				Checksum: &iaas.ImageChecksum{Algorithm: "sha256", Digest: "123456"},
+			}, nil)
+
+			err := w.verifyMachineImageChecksum(ctx, machineImage)
+			Expect(err).To(MatchError("checksum of image image-id of machine image ubuntu@22.04 does not match: expected sha256:abcdef, got sha256:123456"))
+			coder, ok := err.(gardencorev1beta1helper.Coder)
+			Expect(ok).To(BeTrue())
+			Expect(coder.Codes()).To(ConsistOf(gardencorev1beta1.ErrorConfigurationProblem))
+		})
+
+		It("should fail if the image has no checksum", func() {
+			iaasClient.EXPECT().GetImageById(ctx, "image-id").Return(&iaas.Image{}, nil)
+
+			Expect(w.verifyMachineImageChecksum(ctx, machineImage)).To(MatchError(ContainSubstring("got none")))
+		})
+
+		It("should fail if the image cannot be read", func() {
+			iaasClient.EXPECT().GetImageById(ctx, "image-id").Return(nil, fmt.Errorf("not found"))
+
+			Expect(w.verifyMachineImageChecksum(ctx, machineImage)).To(MatchError(ContainSubstring("could not get image image-id of machine image ubuntu@22.04 to verify its checksum: not found")))
+		})
+	})
+
+	Describe("#verifyMachineImageChecksums", func() {
+		It("should verify the image of every pool once", func() {
+			iaasClient := mock.NewMockIaaSClient(gomock.NewController(GinkgoT()))
+			w := &workerDelegate{
+				iaasClient: iaasClient,
+				cloudProfileConfig: &stackitv1alpha1.CloudProfileConfig{
+					MachineImages: []stackitv1alpha1.MachineImages{{
+						Name: "ubuntu",
+						Versions: []stackitv1alpha1.MachineImageVersion{{
+							Version: "22.04",
+							Regions: []stackitv1alpha1.RegionIDMapping{
+								{Name: "eu01", ID: "image-id", Checksum: &stackitv1alpha1.ImageChecksum{Algorithm: "sha256", Digest: "abcdef"}},
+							},
+						}},
+					}},
+				},
+				cluster: &extensionscontroller.Cluster{
+					Shoot: &gardencorev1beta1.Shoot{Spec: gardencorev1beta1.ShootSpec{Region: "eu01"}},
+				},
+				worker: &extensionsv1alpha1.Worker{
+					Spec: extensionsv1alpha1.WorkerSpec{
+						Pools: []extensionsv1alpha1.WorkerPool{
+							{Name: "pool-1", MachineImage: extensionsv1alpha1.MachineImage{Name: "ubuntu", Version: "22.04"}},
+							{Name: "pool-2", MachineImage: extensionsv1alpha1.MachineImage{Name: "ubuntu", Version: "22.04"}},
+						},
+					},
+				},
+			}
+			iaasClient.EXPECT().GetImageById(gomock.Any(), "image-id").Return(&iaas.Image{
+				Checksum: &iaas.ImageChecksum{Algorithm: "sha256", Digest: "abcdef"},
+			}, nil)
+
+			Expect(w.verifyMachineImageChecksums(context.Background())).To(Succeed())
+		})
+	})
+
+	Describe("#findMachineImage", func() {
+		var w *workerDelegate
+
+		BeforeEach(func() {
+			w = &workerDelegate{
+				cloudProfileConfig: &stackitv1alpha1.CloudProfileConfig{
+					MachineImages: []stackitv1alpha1.MachineImages{{
+						Name: "ubuntu",
+						Versions: []stackitv1alpha1.MachineImageVersion{{
+							Version: "22.04",
+							Regions: []stackitv1alpha1.RegionIDMapping{
+								{Name: "eu01", ID: "image-id-amd64", Architecture: new("amd64")},
+								{Name: "eu02", ID: "image-id-arm64", Architecture: new("arm64")},
+							},
+						}},
+					}},
+				},
+				cluster: &extensionscontroller.Cluster{
+					Shoot: &gardencorev1beta1.Shoot{Spec: gardencorev1beta1.ShootSpec{Region: "eu01"}},
+				},
+				worker: &extensionsv1alpha1.Worker{},
+			}
+		})
+
+		It("should find the image of the architecture in the region", func() {
+			machineImage, err := w.findMachineImage("ubuntu", "22.04", "amd64")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(machineImage.ID).To(Equal("image-id-amd64"))
+		})
+
+		It("should list the available architectures if the architecture is missing in the region", func() {
+			_, err := w.findMachineImage("ubuntu", "22.04", "arm64")
+			Expect(err).To(MatchError("machine image ubuntu@22.04 is not available for architecture arm64 in region eu01, available architectures: amd64"))
+			coder, ok := err.(gardencorev1beta1helper.Coder)
+			Expect(ok).To(BeTrue())
+			Expect(coder.Codes()).To(ConsistOf(gardencorev1beta1.ErrorConfigurationProblem))
+		})
+
+		It("should name the region if it is absent from all mappings of the version", func() {
+			w.cluster.Shoot.Spec.Region = "eu03"
+
+			_, err := w.findMachineImage("ubuntu", "22.04", "amd64")
+			Expect(err).To(MatchError("machine image ubuntu@22.04 is not available in region eu03, as it has neither an image ID for the region nor an image name fallback, regions with image IDs: eu01, eu02"))
+			coder, ok := err.(gardencorev1beta1helper.Coder)
+			Expect(ok).To(BeTrue())
+			Expect(coder.Codes()).To(ConsistOf(gardencorev1beta1.ErrorConfigurationProblem))
+		})
+
+		It("should use the image name fallback for regions without mapping", func() {
+			w.cluster.Shoot.Spec.Region = "eu03"
+			w.cloudProfileConfig.MachineImages[0].Versions[0].Image = "ubuntu-22.04"
+
+			machineImage, err := w.findMachineImage("ubuntu", "22.04", "amd64")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(machineImage.Image).To(Equal("ubuntu-22.04"))
+		})
+
+		It("should return the generic error if the version is not configured", func() {
+			_, err := w.findMachineImage("ubuntu", "24.04", "amd64")
+			Expect(err).To(MatchError(ContainSubstring("could not find machine image for ubuntu/24.04")))
+			_, ok := err.(gardencorev1beta1helper.Coder)
+			Expect(ok).To(BeFalse())
+		})
+
+		Context("with machine images in the worker status", func() {
+			BeforeEach(func() {
+				scheme := runtime.NewScheme()
+				utilruntime.Must(stackitv1alpha1.AddToScheme(scheme))
+				w.decoder = serializer.NewCodecFactory(scheme, serializer.EnableStrict).UniversalDecoder()
+
+				raw, err := json.Marshal(&stackitv1alpha1.WorkerStatus{
+					TypeMeta: metav1.TypeMeta{
+						Kind:       "WorkerStatus",
+						APIVersion: stackitv1alpha1.SchemeGroupVersion.String(),
+					},
+					MachineImages: []stackitv1alpha1.MachineImage{
+						{Name: "ubuntu", Version: "20.04", ID: "image-id-removed", Architecture: new("amd64")},
+					},
+				})
+				Expect(err).NotTo(HaveOccurred())
+				w.worker.Status.ProviderStatus = &runtime.RawExtension{Raw: raw}
+			})
+
+			It("should find the image of a version removed from the cloud profile", func() {
+				machineImage, err := w.findMachineImage("ubuntu", "20.04", "amd64")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(machineImage.ID).To(Equal("image-id-removed"))
+			})
+
+			It("should list the available architectures if the architecture is missing in the region", func() {
+				_, err := w.findMachineImage("ubuntu", "22.04", "arm64")
+				Expect(err).To(MatchError("machine image ubuntu@22.04 is not available for architecture arm64 in region eu01, available architectures: amd64"))
+				coder, ok := err.(gardencorev1beta1helper.Coder)
+				Expect(ok).To(BeTrue())
+				Expect(coder.Codes()).To(ConsistOf(gardencorev1beta1.ErrorConfigurationProblem))
+			})
+
+			It("should name the region if it is absent from all mappings of the version", func() {
+				w.cluster.Shoot.Spec.Region = "eu03"
+
+				_, err := w.findMachineImage("ubuntu", "22.04", "amd64")
+				Expect(err).To(MatchError(ContainSubstring("is not available in region eu03")))
+			})
+
+			It("should return the generic error if the version is not configured", func() {
+				_, err := w.findMachineImage("ubuntu", "24.04", "amd64")
+				Expect(err).To(MatchError(ContainSubstring("could not find machine image for ubuntu/24.04")))
+			})
+		})
+	})
+})
@@ -10,6 +10,7 @@ import (
 	"math"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"sort"
 	"strings"
 
@@ -23,6 +24,9 @@ import (
 	"github.com/gardener/gardener/pkg/client/kubernetes"
 	gardenutils "github.com/gardener/gardener/pkg/utils"
 	machinev1alpha1 "github.com/gardener/machine-controller-manager/pkg/apis/machine/v1alpha1"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -35,6 +39,10 @@ import (
 	stackitutils "github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/utils"
 )
 
+// StackitMachineClassZoneKey is the key of the zone in the provider spec of the machine classes of the STACKIT
+// machine-controller-manager. It is not part of the worker pool hash, so changing it does not roll the nodes.
+const StackitMachineClassZoneKey = "availabilityZone"
+
 // MachineClassKind yields the name of the machine class kind used by OpenStack provider.
 func (w *workerDelegate) MachineClassKind() string {
 	return "MachineClass"
@@ -59,10 +67,27 @@ func (w *workerDelegate) DeployMachineClasses(ctx context.Context) error {
 	}
 
 	chartPath := "machineclass"
+	values := map[string]any{"machineClasses": w.machineClasses}
 	if feature.UseStackitMachineControllerManager(w.cluster) {
 		chartPath = "machineclass-stackit"
+		values["zoneKey"] = StackitMachineClassZoneKey
+	}
+	if err := w.seedChartApplier.ApplyFromEmbeddedFS(ctx, charts.InternalChart, filepath.Join(charts.InternalChartsPath, chartPath), w.worker.Namespace, "machineclass", kubernetes.Values(values)); err != nil {
+		// The chart is applied object by object, so some of the machine classes might have been applied already.
+		// The error names all machine classes of the apply to ease diagnosing partially rolled out worker pools.
+		return fmt.Errorf("could not apply machine classes %s with chart %s: %w", strings.Join(w.machineClassNames(), ", "), chartPath, err)
+	}
+	return nil
+}
+
+func (w *workerDelegate) machineClassNames() []string {
+	names := make([]string, 0, len(w.machineClasses))
+	for _, machineClass := range w.machineClasses {
+		if name, ok := machineClass["name"].(string); ok {
+			names = append(names, name)
+		}
 	}
-	return w.seedChartApplier.ApplyFromEmbeddedFS(ctx, charts.InternalChart, filepath.Join(charts.InternalChartsPath, chartPath), w.worker.Namespace, "machineclass", kubernetes.Values(map[string]any{"machineClasses": w.machineClasses}))
+	return names
 }
 
 // GenerateMachineDeployments generates the configuration for the desired machine deployments.
@@ -89,7 +114,11 @@ func (w *workerDelegate) generateMachineConfig(ctx context.Context) error {
 
 	nodesSecurityGroup, err := helper.FindSecurityGroupByPurpose(infrastructureStatus.SecurityGroups, stackitv1alpha1.PurposeNodes)
 	if err != nil {
-		return err
+		// The infrastructure controller publishes the security group once it has been reconciled successfully.
+		return gardencorev1beta1helper.NewErrorWithCodes(
+			fmt.Errorf("infrastructure status does not contain the %q security group, the infrastructure might not be fully reconciled yet: %w", stackitv1alpha1.PurposeNodes, err),
+			gardencorev1beta1.ErrorRetryableInfraDependencies,
+		)
 	}
 
 	var subnet *stackitv1alpha1.Subnet
@@ -101,6 +130,21 @@ func (w *workerDelegate) generateMachineConfig(ctx context.Context) error {
 		}
 	}
 
+	podNetworkCIDRs, err := stackitutils.PodNetworkCIDRs(w.cluster)
+	if err != nil {
+		return err
+	}
+
+	credentialsSecretRef := w.machineCredentialsSecretRef()
+
+	workerStatus, err := w.decodeWorkerProviderStatus()
+	if err != nil {
+		return fmt.Errorf("unable to decode the worker provider status: %w", err)
+	}
+
+	// Pools commonly share the same user data secret, hence it is read only once per reconciliation.
+	userDataSecrets := map[client.ObjectKey]*corev1.Secret{}
+
 	for _, pool := range w.worker.Spec.Pools {
 		if len(pool.Zones) > math.MaxInt32 {
 			return fmt.Errorf("amount of zones exceeded 32bit, overflow")
@@ -128,18 +172,33 @@ func (w *workerDelegate) generateMachineConfig(ctx context.Context) error {
 		if err != nil {
 			return err
 		}
+		if workerConfig.ServerGroup != nil && !feature.UseStackitMachineControllerManager(w.cluster) {
+			return fmt.Errorf("serverGroup of worker pool %s is only supported with the STACKIT machine controller manager", pool.Name)
+		}
+
+		serverGroupID, err := w.serverGroupID(workerStatus, pool.Name, workerConfig)
+		if err != nil {
+			return err
+		}
+
+		zoneWeights := zoneWeightsOfPool(pool, workerConfig)
 
 		workerPoolHash, err := w.generateWorkerPoolHash(pool, workerConfig)
 		if err != nil {
 			return err
 		}
 
+		maxSurge, maxUnavailable, err := w.updateLimits(ctx, pool)
+		if err != nil {
+			return err
+		}
+
 		machineLabels := map[string]string{}
 		for _, pair := range workerConfig.MachineLabels {
 			machineLabels[pair.Name] = pair.Value
 		}
 
-		userData, err := worker.FetchUserData(ctx, w.seedClient, w.worker.Namespace, pool)
+		userData, err := w.fetchUserData(ctx, userDataSecrets, pool)
 		if err != nil {
 			return err
 		}
@@ -147,8 +206,8 @@ func (w *workerDelegate) generateMachineConfig(ctx context.Context) error {
 		region := w.worker.Spec.Region
 		securityGroups := []string{nodesSecurityGroup.Name}
 		tags := gardenutils.MergeStringMaps(
-			NormalizeLabelsForMachineClass(pool.Labels),
-			NormalizeLabelsForMachineClass(machineLabels),
+			NormalizeLabelsForMachineClass(pool.Labels, w.preservedMachineLabelKeyPrefixes()...),
+			NormalizeLabelsForMachineClass(machineLabels, w.preservedMachineLabelKeyPrefixes()...),
 			map[string]string{
 				fmt.Sprintf("kubernetes.io-cluster-%s", w.cluster.Shoot.Status.TechnicalID): "1",
 				"kubernetes.io-role-node": "1",
@@ -170,12 +229,12 @@ func (w *workerDelegate) generateMachineConfig(ctx context.Context) error {
 				"machineType":      pool.MachineType,
 				"keyName":          infrastructureStatus.Node.KeyName,
 				"networkID":        infrastructureStatus.Networks.ID,
-				"podNetworkCIDRs":  extensionscontroller.GetPodNetwork(w.cluster),
+				"podNetworkCIDRs":  podNetworkCIDRs,
 				"securityGroups":   securityGroups,
 				"tags":             tags,
 				"credentialsSecretRef": map[string]any{
-					"name":      w.worker.Spec.SecretRef.Name,
-					"namespace": w.worker.Spec.SecretRef.Namespace,
+					"name":      credentialsSecretRef.Name,
+					"namespace": credentialsSecretRef.Namespace,
 				},
 				"secret": map[string]any{
 					"cloudConfig": string(userData),
@@ -186,13 +245,21 @@ func (w *workerDelegate) generateMachineConfig(ctx context.Context) error {
 				machineClassSpec["subnetID"] = subnet.ID
 			}
 
+			if workerConfig.DisablePortSecurity && feature.UseStackitMachineControllerManager(w.cluster) {
+				machineClassSpec["nicSecurity"] = false
+			}
+
+			if serverGroupID != "" {
+				machineClassSpec["serverGroupID"] = serverGroupID
+			}
+
 			if volumeSize > 0 {
 				machineClassSpec["rootDiskSize"] = volumeSize
 			}
 
 			// specifying the volume type requires a custom volume size to be specified too.
-			if pool.Volume != nil && pool.Volume.Type != nil {
-				machineClassSpec["rootDiskType"] = *pool.Volume.Type
+			if volumeType := w.volumeType(pool); volumeType != nil {
+				machineClassSpec["rootDiskType"] = *volumeType
 			}
 
 			if machineImage.ID != "" {
@@ -219,14 +286,22 @@ func (w *workerDelegate) generateMachineConfig(ctx context.Context) error {
 				}
 			}
 
+			var (
+				minimum = worker.DistributeOverZones(zoneIdx, pool.Minimum, zoneLen)
+				maximum = worker.DistributeOverZones(zoneIdx, pool.Maximum, zoneLen)
+			)
+			if zoneWeights != nil {
+				minimum, maximum = DistributeLimitsOverWeightedZones(zoneIdx, pool.Minimum, pool.Maximum, zoneWeights)
+			}
+
 			var (
 				deploymentName = fmt.Sprintf("%s-%s-z%d", w.cluster.Shoot.Status.TechnicalID, pool.Name, zoneIndex+1)
 				className      = fmt.Sprintf("%s-%s", deploymentName, workerPoolHash)
 			)
 
 			updateConfiguration := machinev1alpha1.UpdateConfiguration{
-				MaxUnavailable: new(worker.DistributePositiveIntOrPercent(zoneIdx, pool.MaxUnavailable, zoneLen, pool.Minimum)),
-				MaxSurge:       new(worker.DistributePositiveIntOrPercent(zoneIdx, pool.MaxSurge, zoneLen, pool.Maximum)),
+				MaxUnavailable: new(worker.DistributePositiveIntOrPercent(zoneIdx, maxUnavailable, zoneLen, pool.Minimum)),
+				MaxSurge:       new(worker.DistributePositiveIntOrPercent(zoneIdx, maxSurge, zoneLen, pool.Maximum)),
 			}
 
 			machineDeploymentStrategy := machinev1alpha1.MachineDeploymentStrategy{
@@ -255,8 +330,8 @@ func (w *workerDelegate) generateMachineConfig(ctx context.Context) error {
 				ClassName:                    className,
 				SecretName:                   className,
 				PoolName:                     pool.Name,
-				Minimum:                      worker.DistributeOverZones(zoneIdx, pool.Minimum, zoneLen),
-				Maximum:                      worker.DistributeOverZones(zoneIdx, pool.Maximum, zoneLen),
+				Minimum:                      minimum,
+				Maximum:                      maximum,
 				Strategy:                     machineDeploymentStrategy,
 				Priority:                     pool.Priority,
 				Labels:                       addTopologyLabel(pool.Labels, zone),
@@ -289,6 +364,116 @@ func (w *workerDelegate) generateMachineConfig(ctx context.Context) error {
 	return nil
 }
 
+// updateLimits returns the maxSurge and maxUnavailable of the given worker pool, clamped at the worker update limits of
+// the CloudProfileConfig. Percentages are resolved against the maximum respectively minimum of the pool.
+func (w *workerDelegate) updateLimits(ctx context.Context, pool extensionsv1alpha1.WorkerPool) (intstr.IntOrString, intstr.IntOrString, error) {
+	if w.cloudProfileConfig == nil || w.cloudProfileConfig.WorkerUpdateLimits == nil {
+		return pool.MaxSurge, pool.MaxUnavailable, nil
+	}
+	limits := w.cloudProfileConfig.WorkerUpdateLimits
+	log := logr.FromContextOrDiscard(ctx).WithValues("pool", pool.Name)
+
+	maxSurge, err := clampIntOrPercent(log, "maxSurge", pool.MaxSurge, pool.Maximum, true, limits.MaxSurge)
+	if err != nil {
+		return maxSurge, pool.MaxUnavailable, fmt.Errorf("invalid maxSurge of worker pool %s: %w", pool.Name, err)
+	}
+	maxUnavailable, err := clampIntOrPercent(log, "maxUnavailable", pool.MaxUnavailable, pool.Minimum, false, limits.MaxUnavailable)
+	if err != nil {
+		return maxSurge, maxUnavailable, fmt.Errorf("invalid maxUnavailable of worker pool %s: %w", pool.Name, err)
+	}
+
+	// Clamping must not stall the rollout of the pool, so at least one machine may be surged or unavailable. The limits
+	// of the cloud profile are validated to not both be 0.
+	if resolvesToZero(maxSurge, pool.Maximum, true) && resolvesToZero(maxUnavailable, pool.Minimum, false) {
+		if ptr.Deref(limits.MaxSurge, 1) > 0 {
+			log.Info("Raising maxSurge of worker pool to 1 as the clamped update configuration would stall the rollout")
+			maxSurge = intstr.FromInt32(1)
+		} else {
+			log.Info("Raising maxUnavailable of worker pool to 1 as the clamped update configuration would stall the rollout")
+			maxUnavailable = intstr.FromInt32(1)
+		}
+	}
+	return maxSurge, maxUnavailable, nil
+}
+
+// resolvesToZero returns true if the given value resolved against total is 0.
+func resolvesToZero(value intstr.IntOrString, total int32, roundUp bool) bool {
+	scaled, err := intstr.GetScaledValueFromIntOrPercent(&value, int(total), roundUp)
+	return err == nil && scaled == 0
+}
+
+// clampIntOrPercent returns the given value or the limit if the value resolved against total exceeds it.
+func clampIntOrPercent(log logr.Logger, name string, value intstr.IntOrString, total int32, roundUp bool, limit *int32) (intstr.IntOrString, error) {
+	if limit == nil {
+		return value, nil
+	}
+	scaled, err := intstr.GetScaledValueFromIntOrPercent(&value, int(total), roundUp)
+	if err != nil {
+		return value, err
+	}
+	if scaled <= int(*limit) {
+		return value, nil
+	}
+	log.Info("Clamping update configuration of worker pool to the limit of the cloud profile", "field", name, "value", value.String(), "limit", *limit)
+	return intstr.FromInt32(*limit), nil
+}
+
+// fetchUserData returns the user data referenced by the given worker pool. Secrets are read through the given cache,
+// which is scoped to a single reconciliation to never serve stale user data.
+func (w *workerDelegate) fetchUserData(ctx context.Context, secrets map[client.ObjectKey]*corev1.Secret, pool extensionsv1alpha1.WorkerPool) ([]byte, error) {
+	key := client.ObjectKey{Namespace: w.worker.Namespace, Name: pool.UserDataSecretRef.Name}
+	secret, ok := secrets[key]
+	if !ok {
+		secret = &corev1.Secret{}
+		if err := w.seedClient.Get(ctx, key, secret); err != nil {
+			return nil, fmt.Errorf("failed fetching user data secret %s referenced in worker pool %s: %w", key, pool.Name, err)
+		}
+		secrets[key] = secret
+	}
+
+	userData, ok := secret.Data[pool.UserDataSecretRef.Key]
+	if !ok || len(userData) == 0 {
+		return nil, fmt.Errorf("missing %q field in user data secret %s referenced in worker pool %s", pool.UserDataSecretRef.Key, key, pool.Name)
+	}
+	return userData, nil
+}
+
+// machineCredentialsSecretRef returns the reference to the credentials secret of the machine classes. It is the
+// cloudprovider secret of the Worker unless a dedicated secret is configured for the worker controller.
+func (w *workerDelegate) machineCredentialsSecretRef() corev1.SecretReference {
+	if name := w.configuration.MachineCredentialsSecretName; name != "" {
+		return corev1.SecretReference{Name: name, Namespace: w.worker.Namespace}
+	}
+	return w.worker.Spec.SecretRef
+}
+
+// verifyMachineCredentialsSecret verifies that the dedicated machine credentials secret exists, so that a missing secret
+// is reported before the machine classes are deployed. It is only called on reconciliation to never block the deletion.
+func (w *workerDelegate) verifyMachineCredentialsSecret(ctx context.Context) error {
+	name := w.configuration.MachineCredentialsSecretName
+	if name == "" {
+		return nil
+	}
+
+	key := client.ObjectKey{Namespace: w.worker.Namespace, Name: name}
+	if err := w.seedClient.Get(ctx, key, &corev1.Secret{}); err != nil {
+		return fmt.Errorf("failed to get machine credentials secret %s: %w", key, err)
+	}
+	return nil
+}
+
+// volumeType returns the root volume type of the given worker pool. If the pool has a volume without type, the default
+// volume type of the CloudProfileConfig is used.
+func (w *workerDelegate) volumeType(pool extensionsv1alpha1.WorkerPool) *string {
+	if pool.Volume == nil {
+		return nil
+	}
+	if pool.Volume.Type != nil || w.cloudProfileConfig == nil {
+		return pool.Volume.Type
+	}
+	return w.cloudProfileConfig.DefaultVolumeType
+}
+
 func (w *workerDelegate) generateWorkerPoolHash(pool extensionsv1alpha1.WorkerPool, workerConfig *stackitv1alpha1.WorkerConfig) (string, error) {
 	var additionalHashData []string
 
@@ -305,6 +490,16 @@ func (w *workerDelegate) generateWorkerPoolHash(pool extensionsv1alpha1.WorkerPo
 		additionalHashData = append(additionalHashData, pairs...)
 	}
 
+	if workerConfig.DisablePortSecurity {
+		// changing the port security requires new network interfaces
+		additionalHashData = append(additionalHashData, "disablePortSecurity")
+	}
+
+	if workerConfig.ServerGroup != nil {
+		// the machines are added to a new server group when the policy changes
+		additionalHashData = append(additionalHashData, "serverGroupPolicy="+workerConfig.ServerGroup.Policy)
+	}
+
 	// The provider config is not part of the worker pool hash
 	pool.ProviderConfig = nil
 
@@ -313,13 +508,106 @@ func (w *workerDelegate) generateWorkerPoolHash(pool extensionsv1alpha1.WorkerPo
 	return worker.WorkerPoolHash(pool, w.cluster, additionalHashData, nil)
 }
 
+// serverGroupID returns the ID of the server group of the given worker pool from the ServerGroupDependencies of the
+// worker status, or an empty string if the pool does not configure a server group or the worker is being deleted.
+func (w *workerDelegate) serverGroupID(workerStatus *stackitv1alpha1.WorkerStatus, poolName string, workerConfig *stackitv1alpha1.WorkerConfig) (string, error) {
+	if workerConfig.ServerGroup == nil || w.worker.DeletionTimestamp != nil {
+		return "", nil
+	}
+
+	dependency := findServerGroupDependency(workerStatus.ServerGroupDependencies, poolName, w.serverGroupName(poolName, workerConfig.ServerGroup.Policy))
+	if dependency == nil {
+		// The server groups are created by the PreReconcileHook before the machine classes are generated.
+		return "", gardencorev1beta1helper.NewErrorWithCodes(
+			fmt.Errorf("worker status does not contain the server group of worker pool %s", poolName),
+			gardencorev1beta1.ErrorRetryableInfraDependencies,
+		)
+	}
+	return dependency.ID, nil
+}
+
+// preservedMachineLabelKeyPrefixes returns the prefixes of label keys which are not normalized for the machine classes.
+func (w *workerDelegate) preservedMachineLabelKeyPrefixes() []string {
+	if w.cloudProfileConfig == nil {
+		return nil
+	}
+	return w.cloudProfileConfig.PreservedMachineLabelKeyPrefixes
+}
+
+// zoneWeightsOfPool returns the weights of the zones of the given pool in the order of the zones, or nil if the
+// WorkerConfig of the pool does not configure a positive weight for every zone. Invalid weights are rejected by the
+// admission, so they only fall back to an even distribution instead of blocking the reconciliation or deletion.
+func zoneWeightsOfPool(pool extensionsv1alpha1.WorkerPool, workerConfig *stackitv1alpha1.WorkerConfig) []int32 {
+	if len(workerConfig.ZoneWeights) == 0 {
+		return nil
+	}
+
+	weights := make([]int32, 0, len(pool.Zones))
+	for _, zone := range pool.Zones {
+		weight, ok := workerConfig.ZoneWeights[zone]
+		if !ok || weight <= 0 {
+			return nil
+		}
+		weights = append(weights, weight)
+	}
+	return weights
+}
+
+// DistributeLimitsOverWeightedZones returns the minimum and maximum of the zone with the given index, distributed
+// proportionally to the given weights of the zones. The part of the maximum above the minimum is distributed
+// separately, so that the maximum of every zone is at least its minimum and the maxima of all zones sum up to the
+// given maximum.
+func DistributeLimitsOverWeightedZones(zoneIndex, minimum, maximum int32, weights []int32) (int32, int32) {
+	zoneMinimum := DistributeOverWeightedZones(zoneIndex, minimum, weights)
+	return zoneMinimum, zoneMinimum + DistributeOverWeightedZones(zoneIndex, max(maximum-minimum, 0), weights)
+}
+
+// DistributeOverWeightedZones returns the share of the zone with the given index of the given size, distributed
+// proportionally to the given weights of the zones. Shares are rounded down and the remainder is given to the zones
+// with the largest rounding losses, preferring the first zones on ties. For equal weights, this is the same as
+// worker.DistributeOverZones.
+func DistributeOverWeightedZones(zoneIndex, size int32, weights []int32) int32 {
+	var totalWeight int64
+	for _, weight := range weights {
+		totalWeight += int64(weight)
+	}
+	if totalWeight <= 0 {
+		return 0
+	}
+
+	remainder := int64(size)
+	shares := make([]int64, len(weights))
+	losses := make([]int64, len(weights))
+	for i, weight := range weights {
+		shares[i] = int64(size) * int64(weight) / totalWeight
+		losses[i] = int64(size) * int64(weight) % totalWeight
+		remainder -= shares[i]
+	}
+
+	zones := make([]int, len(weights))
+	for i := range zones {
+		zones[i] = i
+	}
+	sort.SliceStable(zones, func(i, j int) bool { return losses[zones[i]] > losses[zones[j]] })
+	for _, zone := range zones[:remainder] {
+		shares[zone]++
+	}
+
+	// nolint:gosec // the share of a zone is at most the given size
+	return int32(shares[zoneIndex])
+}
+
 // NormalizeLabelsForMachineClass because metadata in OpenStack resources do not allow for certain characters that present in k8s labels e.g. "/",
-// normalize the label by replacing illegal characters with "-"
-func NormalizeLabelsForMachineClass(in map[string]string) map[string]string {
+// normalize the label by replacing illegal characters with "-". Keys starting with one of the preserved prefixes are
+// kept as they are.
+func NormalizeLabelsForMachineClass(in map[string]string, preservedKeyPrefixes ...string) map[string]string {
 	notAllowedChars := regexp.MustCompile(`[^a-zA-Z0-9-_:. ]`)
 	res := make(map[string]string)
 	for k, v := range in {
-		newKey := notAllowedChars.ReplaceAllLiteralString(k, "-")
+		newKey := k
+		if !slices.ContainsFunc(preservedKeyPrefixes, func(prefix string) bool { return strings.HasPrefix(k, prefix) }) {
+			newKey = notAllowedChars.ReplaceAllLiteralString(k, "-")
+		}
 		res[newKey] = v
 	}
 	return res
@@ -6,6 +6,7 @@ package worker_test
 
 import (
 	"context"
+	"embed"
 	"encoding/json"
 	"fmt"
 	"maps"
@@ -16,9 +17,11 @@ import (
 	extensionscontroller "github.com/gardener/gardener/extensions/pkg/controller"
 	"github.com/gardener/gardener/extensions/pkg/controller/worker"
 	genericworkeractuator "github.com/gardener/gardener/extensions/pkg/controller/worker/genericactuator"
+	gardencorev1beta1helper "github.com/gardener/gardener/pkg/api/core/v1beta1/helper"
 	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
 	v1beta1constants "github.com/gardener/gardener/pkg/apis/core/v1beta1/constants"
 	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"github.com/gardener/gardener/pkg/chartrenderer"
 	"github.com/gardener/gardener/pkg/client/kubernetes"
 	mockkubernetes "github.com/gardener/gardener/pkg/client/kubernetes/mock"
 	"github.com/gardener/gardener/pkg/utils"
@@ -33,10 +36,13 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/version"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 
 	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/charts"
+	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/apis/config"
 	stackitv1alpha1 "github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/apis/stackit/v1alpha1"
 	. "github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/controller/worker"
 	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/feature"
@@ -72,7 +78,7 @@ var _ = Describe("Machines", func() {
 
 	Context("workerDelegate", func() {
 		BeforeEach(func() {
-			workerDelegate, _ = NewWorkerDelegate(nil, scheme, nil, "", nil, nil, "")
+			workerDelegate, _ = NewWorkerDelegate(nil, scheme, nil, "", nil, nil, "", config.WorkerControllerConfiguration{})
 		})
 
 		Describe("#TestLabelNormalization", func() {
@@ -91,6 +97,66 @@ var _ = Describe("Machines", func() {
 				}
 				Expect(output).To(Equal(expected))
 			})
+
+			It("should preserve keys with an allow-listed prefix", func() {
+				input := map[string]string{
+					"example.com/team":      "value",
+					"example.com.evil/team": "value",
+					"test/node":             "value",
+				}
+
+				output := NormalizeLabelsForMachineClass(input, "example.com/", "other.io/")
+				expected := map[string]string{
+					"example.com/team":      "value",
+					"example.com.evil-team": "value",
+					"test-node":             "value",
+				}
+				Expect(output).To(Equal(expected))
+			})
+		})
+
+		Describe("#DistributeOverWeightedZones", func() {
+			It("should distribute like DistributeOverZones for equal weights", func() {
+				for size := range int32(12) {
+					for zoneIndex := range int32(3) {
+						Expect(DistributeOverWeightedZones(zoneIndex, size, []int32{2, 2, 2})).To(Equal(worker.DistributeOverZones(zoneIndex, size, 3)), "size %d, zone %d", size, zoneIndex)
+					}
+				}
+			})
+
+			DescribeTable("should distribute proportionally to the weights",
+				func(size int32, weights []int32, expected []int32) {
+					var distributed []int32
+					for zoneIndex := range weights {
+						distributed = append(distributed, DistributeOverWeightedZones(int32(zoneIndex), size, weights))
+					}
+					Expect(distributed).To(Equal(expected))
+				},
+				Entry("without remainder", int32(8), []int32{3, 1}, []int32{6, 2}),
+				Entry("remainder to the largest loss", int32(5), []int32{3, 1}, []int32{4, 1}),
+				Entry("remainder to the first zone on ties", int32(10), []int32{3, 1}, []int32{8, 2}),
+				Entry("remainder to a later zone", int32(3), []int32{1, 2, 2}, []int32{1, 1, 1}),
+				Entry("remainder to multiple zones", int32(7), []int32{1, 1, 2}, []int32{2, 2, 3}),
+				Entry("zero size", int32(0), []int32{1, 5}, []int32{0, 0}),
+			)
+		})
+
+		Describe("#DistributeLimitsOverWeightedZones", func() {
+			DescribeTable("should keep the maximum of every zone above its minimum and sum up to the maximum",
+				func(minimum, maximum int32, weights []int32, expectedMinima, expectedMaxima []int32) {
+					var minima, maxima []int32
+					for zoneIndex := range weights {
+						zoneMinimum, zoneMaximum := DistributeLimitsOverWeightedZones(int32(zoneIndex), minimum, maximum, weights)
+						minima = append(minima, zoneMinimum)
+						maxima = append(maxima, zoneMaximum)
+					}
+					Expect(minima).To(Equal(expectedMinima))
+					Expect(maxima).To(Equal(expectedMaxima))
+				},
+				Entry("proportional limits", int32(4), int32(8), []int32{3, 1}, []int32{3, 1}, []int32{6, 2}),
+				Entry("rounding the minimum up", int32(3), int32(4), []int32{1, 3, 3}, []int32{1, 1, 1}, []int32{1, 2, 1}),
+				Entry("equal minimum and maximum", int32(5), int32(5), []int32{1, 2}, []int32{2, 3}, []int32{2, 3}),
+			)
 		})
 
 		Describe("#GenerateMachineDeployments, #DeployMachineClasses", func() {
@@ -439,7 +505,7 @@ var _ = Describe("Machines", func() {
 					},
 				)
 
-				workerDelegate, _ = NewWorkerDelegate(c, scheme, chartApplier, "", w, clusterWithoutImages, "")
+				workerDelegate, _ = NewWorkerDelegate(c, scheme, chartApplier, "", w, clusterWithoutImages, "", config.WorkerControllerConfiguration{})
 			})
 
 			expectWorkerStatus := func(workerObj *extensionsv1alpha1.Worker, expectedStatus *stackitv1alpha1.WorkerStatus) {
@@ -618,6 +684,9 @@ var _ = Describe("Machines", func() {
 					machineClassPool3Zone1,
 					machineClassPool3Zone2,
 				}}
+				if useStackitMCM {
+					(*machineClasses)["zoneKey"] = StackitMachineClassZoneKey
+				}
 
 				labelsZone1 := map[string]string{openstack.CSIDiskDriverTopologyKey: zone1, openstack.CSISTACKITDriverTopologyKey: zone1}
 				labelsZone2 := map[string]string{openstack.CSIDiskDriverTopologyKey: zone2, openstack.CSISTACKITDriverTopologyKey: zone2}
@@ -773,7 +842,7 @@ var _ = Describe("Machines", func() {
 
 				It("should return the expected machine deployments for profile image types", func() {
 					setup(region, machineImage, "", archAMD)
-					workerDelegate, _ := NewWorkerDelegate(c, scheme, chartApplier, "", w, cluster, "")
+					workerDelegate, _ := NewWorkerDelegate(c, scheme, chartApplier, "", w, cluster, "", config.WorkerControllerConfiguration{})
 
 					// Test workerDelegate.DeployMachineClasses()
 					chartApplier.
@@ -826,7 +895,7 @@ var _ = Describe("Machines", func() {
 
 				It("should return the expected machine deployments for profile image types with id", func() {
 					setup(regionWithImages, "", machineImageID, archARM)
-					workerDelegate, _ := NewWorkerDelegate(c, scheme, chartApplier, "", workerWithRegion, clusterWithRegion, "")
+					workerDelegate, _ := NewWorkerDelegate(c, scheme, chartApplier, "", workerWithRegion, clusterWithRegion, "", config.WorkerControllerConfiguration{})
 					clusterWithRegion.Shoot.Spec.Hibernation = &gardencorev1beta1.Hibernation{Enabled: new(true)}
 
 					// Test workerDelegate.DeployMachineClasses()
@@ -894,7 +963,7 @@ var _ = Describe("Machines", func() {
 							w.Spec.Pools[0].ProviderConfig = &runtime.RawExtension{
 								Raw: encode(workerConfig),
 							}
-							workerDelegate, _ := NewWorkerDelegate(c, scheme, chartApplier, "", w, cluster, "")
+							workerDelegate, _ := NewWorkerDelegate(c, scheme, chartApplier, "", w, cluster, "", config.WorkerControllerConfiguration{})
 
 							result, err := workerDelegate.GenerateMachineDeployments(ctx)
 							Expect(err).NotTo(HaveOccurred())
@@ -956,7 +1025,7 @@ var _ = Describe("Machines", func() {
 
 				It("should return the expected machine deployments for STACKIT with profile image types", func() {
 					setup(region, machineImage, "", archAMD)
-					workerDelegate, _ := NewWorkerDelegate(c, scheme, chartApplier, "", workerWithRegion, clusterWithRegion, "kubernetes.io")
+					workerDelegate, _ := NewWorkerDelegate(c, scheme, chartApplier, "", workerWithRegion, clusterWithRegion, "kubernetes.io", config.WorkerControllerConfiguration{})
 
 					// Test workerDelegate.DeployMachineClasses()
 					chartApplier.
@@ -1007,7 +1076,7 @@ var _ = Describe("Machines", func() {
 
 				It("should return the expected machine deployments for STACKIT with profile image types with id", func() {
 					setup(regionWithImages, "", machineImageID, archARM)
-					workerDelegate, _ := NewWorkerDelegate(c, scheme, chartApplier, "", workerWithRegion, clusterWithRegion, "kubernetes.io")
+					workerDelegate, _ := NewWorkerDelegate(c, scheme, chartApplier, "", workerWithRegion, clusterWithRegion, "kubernetes.io", config.WorkerControllerConfiguration{})
 					clusterWithRegion.Shoot.Spec.Hibernation = &gardencorev1beta1.Hibernation{Enabled: new(true)}
 
 					// Test workerDelegate.DeployMachineClasses()
@@ -1060,7 +1129,7 @@ var _ = Describe("Machines", func() {
 
 			It("should fail because the version is invalid", func() {
 				w.Spec.Pools[1].KubernetesVersion = new("invalid")
-				workerDelegate, _ = NewWorkerDelegate(c, scheme, chartApplier, "", w, cluster, "")
+				workerDelegate, _ = NewWorkerDelegate(c, scheme, chartApplier, "", w, cluster, "", config.WorkerControllerConfiguration{})
 
 				result, err := workerDelegate.GenerateMachineDeployments(ctx)
 				Expect(err).To(HaveOccurred())
@@ -1070,7 +1139,7 @@ var _ = Describe("Machines", func() {
 			It("should fail because the infrastructure status cannot be decoded", func() {
 				w.Spec.InfrastructureProviderStatus = &runtime.RawExtension{}
 
-				workerDelegate, _ = NewWorkerDelegate(c, scheme, chartApplier, "", w, cluster, "")
+				workerDelegate, _ = NewWorkerDelegate(c, scheme, chartApplier, "", w, cluster, "", config.WorkerControllerConfiguration{})
 
 				result, err := workerDelegate.GenerateMachineDeployments(ctx)
 				Expect(err).To(HaveOccurred())
@@ -1082,17 +1151,20 @@ var _ = Describe("Machines", func() {
 					Raw: encode(&stackitv1alpha1.InfrastructureStatus{}),
 				}
 
-				workerDelegate, _ = NewWorkerDelegate(c, scheme, chartApplier, "", w, cluster, "")
+				workerDelegate, _ = NewWorkerDelegate(c, scheme, chartApplier, "", w, cluster, "", config.WorkerControllerConfiguration{})
 
 				result, err := workerDelegate.GenerateMachineDeployments(ctx)
-				Expect(err).To(HaveOccurred())
+				Expect(err).To(MatchError(ContainSubstring(`infrastructure status does not contain the "nodes" security group, the infrastructure might not be fully reconciled yet`)))
+				coder, ok := err.(gardencorev1beta1helper.Coder)
+				Expect(ok).To(BeTrue())
+				Expect(coder.Codes()).To(Equal([]gardencorev1beta1.ErrorCode{gardencorev1beta1.ErrorRetryableInfraDependencies}))
 				Expect(result).To(BeNil())
 			})
 
 			It("should fail because the machine image for this cloud profile cannot be found", func() {
 				clusterWithoutImages.CloudProfile.Name = "another-cloud-profile"
 
-				workerDelegate, _ = NewWorkerDelegate(c, scheme, chartApplier, "", w, clusterWithoutImages, "")
+				workerDelegate, _ = NewWorkerDelegate(c, scheme, chartApplier, "", w, clusterWithoutImages, "", config.WorkerControllerConfiguration{})
 
 				result, err := workerDelegate.GenerateMachineDeployments(ctx)
 				Expect(err).To(HaveOccurred())
@@ -1113,7 +1185,7 @@ var _ = Describe("Machines", func() {
 					NodeConditions:         testNodeConditions,
 				}
 
-				workerDelegate, _ = NewWorkerDelegate(c, scheme, chartApplier, "", w, cluster, "")
+				workerDelegate, _ = NewWorkerDelegate(c, scheme, chartApplier, "", w, cluster, "", config.WorkerControllerConfiguration{})
 
 				result, err := workerDelegate.GenerateMachineDeployments(ctx)
 				resultSettings := result[0].MachineConfiguration
@@ -1136,7 +1208,7 @@ var _ = Describe("Machines", func() {
 					ScaleDownUtilizationThreshold:    new("0.5"),
 				}
 				w.Spec.Pools[1].ClusterAutoscaler = nil
-				workerDelegate, _ = NewWorkerDelegate(c, scheme, chartApplier, "", w, cluster, "")
+				workerDelegate, _ = NewWorkerDelegate(c, scheme, chartApplier, "", w, cluster, "", config.WorkerControllerConfiguration{})
 
 				result, err := workerDelegate.GenerateMachineDeployments(ctx)
 				Expect(err).NotTo(HaveOccurred())
@@ -1164,10 +1236,130 @@ var _ = Describe("Machines", func() {
 				Expect(result[1].ClusterAutoscalerAnnotations[extensionsv1alpha1.ScaleDownUtilizationThresholdAnnotation]).To(Equal("0.5"))
 			})
 
+			Context("default volume type", func() {
+				var machineClasses []map[string]any
+
+				BeforeEach(func() {
+					cloudProfileConfig.DefaultVolumeType = new("storage_premium_perf4")
+					cloudProfileConfigJSON, _ = json.Marshal(cloudProfileConfig)
+					cluster.CloudProfile.Spec.ProviderConfig = &runtime.RawExtension{Raw: cloudProfileConfigJSON}
+
+					chartApplier.
+						EXPECT().
+						ApplyFromEmbeddedFS(ctx, charts.InternalChart, gomock.Any(), namespace, "machineclass", gomock.Any()).
+						DoAndReturn(func(_ context.Context, _ embed.FS, _, _, _ string, opts ...kubernetes.ApplyOption) error {
+							applyOptions := &kubernetes.ApplyOptions{}
+							for _, opt := range opts {
+								opt.MutateApplyOptions(applyOptions)
+							}
+							machineClasses = applyOptions.Values.(map[string]any)["machineClasses"].([]map[string]any)
+							return nil
+						})
+				})
+
+				It("should apply the default volume type to volumes without type", func() {
+					w.Spec.Pools[0].Volume = &extensionsv1alpha1.Volume{Size: "20Gi"}
+					w.Spec.Pools[1].Volume = &extensionsv1alpha1.Volume{Size: "20Gi", Type: new("storage_premium_perf1")}
+					workerDelegate, _ = NewWorkerDelegate(c, scheme, chartApplier, "", w, cluster, "", config.WorkerControllerConfiguration{})
+
+					Expect(workerDelegate.DeployMachineClasses(ctx)).To(Succeed())
+
+					Expect(machineClasses[0]).To(HaveKeyWithValue("rootDiskType", "storage_premium_perf4"))
+					Expect(machineClasses[2]).To(HaveKeyWithValue("rootDiskType", "storage_premium_perf1"))
+					Expect(machineClasses[4]).NotTo(HaveKey("rootDiskType"))
+				})
+			})
+
+			Context("worker update limits", func() {
+				BeforeEach(func() {
+					cloudProfileConfig.WorkerUpdateLimits = &stackitv1alpha1.WorkerUpdateLimits{
+						MaxSurge:       new(int32(4)),
+						MaxUnavailable: new(int32(6)),
+					}
+					cloudProfileConfigJSON, _ = json.Marshal(cloudProfileConfig)
+					cluster.CloudProfile.Spec.ProviderConfig = &runtime.RawExtension{Raw: cloudProfileConfigJSON}
+				})
+
+				It("should clamp the values of pools exceeding the limits", func() {
+					workerDelegate, _ = NewWorkerDelegate(c, scheme, chartApplier, "", w, cluster, "", config.WorkerControllerConfiguration{})
+
+					result, err := workerDelegate.GenerateMachineDeployments(ctx)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(result[0].Strategy.RollingUpdate.MaxSurge).To(HaveValue(Equal(worker.DistributePositiveIntOrPercent(0, maxSurgePool1, 2, maxPool1))))
+					Expect(result[0].Strategy.RollingUpdate.MaxUnavailable).To(HaveValue(Equal(worker.DistributePositiveIntOrPercent(0, maxUnavailablePool1, 2, minPool1))))
+					for i := range 2 {
+						Expect(result[2+i].Strategy.InPlaceUpdate.MaxSurge).To(HaveValue(Equal(worker.DistributePositiveIntOrPercent(int32(i), intstr.FromInt32(4), 2, maxPool2))))
+						Expect(result[2+i].Strategy.InPlaceUpdate.MaxUnavailable).To(HaveValue(Equal(worker.DistributePositiveIntOrPercent(int32(i), intstr.FromInt32(6), 2, minPool2))))
+					}
+				})
+
+				It("should clamp percentages exceeding the limits", func() {
+					w.Spec.Pools[0].MaxSurge = intstr.FromString("50%")
+					w.Spec.Pools[0].MaxUnavailable = intstr.FromString("20%")
+					workerDelegate, _ = NewWorkerDelegate(c, scheme, chartApplier, "", w, cluster, "", config.WorkerControllerConfiguration{})
+
+					result, err := workerDelegate.GenerateMachineDeployments(ctx)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(result[0].Strategy.RollingUpdate.MaxSurge).To(HaveValue(Equal(worker.DistributePositiveIntOrPercent(0, intstr.FromInt32(4), 2, maxPool1))))
+					Expect(result[0].Strategy.RollingUpdate.MaxUnavailable).To(HaveValue(Equal(intstr.FromString("20%"))))
+				})
+
+				It("should raise maxUnavailable to 1 if maxSurge is limited to 0 and the rollout would stall", func() {
+					cloudProfileConfig.WorkerUpdateLimits.MaxSurge = new(int32(0))
+					cloudProfileConfigJSON, _ = json.Marshal(cloudProfileConfig)
+					cluster.CloudProfile.Spec.ProviderConfig = &runtime.RawExtension{Raw: cloudProfileConfigJSON}
+					w.Spec.Pools[0].MaxUnavailable = intstr.FromInt32(0)
+					workerDelegate, _ = NewWorkerDelegate(c, scheme, chartApplier, "", w, cluster, "", config.WorkerControllerConfiguration{})
+
+					result, err := workerDelegate.GenerateMachineDeployments(ctx)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(result[0].Strategy.RollingUpdate.MaxSurge).To(HaveValue(Equal(intstr.FromInt32(0))))
+					Expect(result[0].Strategy.RollingUpdate.MaxUnavailable).To(HaveValue(Equal(worker.DistributePositiveIntOrPercent(0, intstr.FromInt32(1), 2, minPool1))))
+				})
+
+				It("should raise maxSurge to 1 if maxUnavailable is clamped to 0 and the rollout would stall", func() {
+					cloudProfileConfig.WorkerUpdateLimits.MaxUnavailable = new(int32(0))
+					cloudProfileConfigJSON, _ = json.Marshal(cloudProfileConfig)
+					cluster.CloudProfile.Spec.ProviderConfig = &runtime.RawExtension{Raw: cloudProfileConfigJSON}
+					w.Spec.Pools[0].MaxSurge = intstr.FromString("0%")
+					w.Spec.Pools[0].MaxUnavailable = intstr.FromInt32(2)
+					workerDelegate, _ = NewWorkerDelegate(c, scheme, chartApplier, "", w, cluster, "", config.WorkerControllerConfiguration{})
+
+					result, err := workerDelegate.GenerateMachineDeployments(ctx)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(result[0].Strategy.RollingUpdate.MaxSurge).To(HaveValue(Equal(worker.DistributePositiveIntOrPercent(0, intstr.FromInt32(1), 2, maxPool1))))
+					Expect(result[0].Strategy.RollingUpdate.MaxUnavailable).To(HaveValue(Equal(intstr.FromInt32(0))))
+				})
+
+				It("should return an error for invalid percentages", func() {
+					w.Spec.Pools[0].MaxSurge = intstr.FromString("many")
+					workerDelegate, _ = NewWorkerDelegate(c, scheme, chartApplier, "", w, cluster, "", config.WorkerControllerConfiguration{})
+
+					_, err := workerDelegate.GenerateMachineDeployments(ctx)
+					Expect(err).To(MatchError(ContainSubstring("invalid maxSurge of worker pool " + namePool1)))
+				})
+			})
+
 			DescribeTable("customLabelDomain in machineclass helm chart",
-				func(customDomain string) {
-					workerDelegate, _ := NewWorkerDelegate(c, scheme, chartApplier, "", w, cluster, customDomain)
+				func(customDomain string, shootDomain *string, expectedDomain string) {
+					if shootDomain != nil {
+						cluster.Shoot.Spec.Provider.ControlPlaneConfig = &runtime.RawExtension{
+							Raw: encode(&stackitv1alpha1.ControlPlaneConfig{
+								TypeMeta: metav1.TypeMeta{
+									Kind:       "ControlPlaneConfig",
+									APIVersion: stackitv1alpha1.SchemeGroupVersion.String(),
+								},
+								CustomLabelDomain: shootDomain,
+							}),
+						}
+					}
+					workerDelegate, _ := NewWorkerDelegate(c, scheme, chartApplier, "", w, cluster, customDomain, config.WorkerControllerConfiguration{})
 
+					var values map[string]any
 					chartApplier.
 						EXPECT().
 						ApplyFromEmbeddedFS(
@@ -1178,24 +1370,406 @@ var _ = Describe("Machines", func() {
 							"machineclass",
 							gomock.Any(),
 						).
-						Return(nil)
+						DoAndReturn(func(_ context.Context, _ embed.FS, _, _, _ string, opts ...kubernetes.ApplyOption) error {
+							applyOptions := &kubernetes.ApplyOptions{}
+							for _, opt := range opts {
+								opt.MutateApplyOptions(applyOptions)
+							}
+							values = applyOptions.Values.(map[string]any)
+							return nil
+						})
 
 					err := workerDelegate.DeployMachineClasses(ctx)
 					Expect(err).NotTo(HaveOccurred())
+					for _, class := range values["machineClasses"].([]map[string]any) {
+						Expect(class["tags"]).To(Equal(map[string]string{expectedDomain + "/cluster": technicalID}))
+					}
 				},
 				Entry("with default kubernetes.io domain",
-					"kubernetes.io",
+					"kubernetes.io", nil, "kubernetes.io",
 				),
 				Entry("with custom ske.stackit.cloud domain",
-					"ske.stackit.cloud",
+					"ske.stackit.cloud", nil, "ske.stackit.cloud",
 				),
 				Entry("with custom example.com domain",
-					"example.com",
+					"example.com", nil, "example.com",
 				),
 				Entry("with empty domain",
-					"",
+					"", nil, "",
+				),
+				Entry("with the domain of the ControlPlaneConfig",
+					"kubernetes.io", new("customer.example.com"), "customer.example.com",
 				),
 			)
+
+			It("should render the zone with the configured key into the STACKIT machine classes", func() {
+				var values map[string]any
+				chartApplier.
+					EXPECT().
+					ApplyFromEmbeddedFS(ctx, charts.InternalChart, filepath.Join("internal", "machineclass-stackit"), namespace, "machineclass", gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ embed.FS, _, _, _ string, opts ...kubernetes.ApplyOption) error {
+						applyOptions := &kubernetes.ApplyOptions{}
+						for _, opt := range opts {
+							opt.MutateApplyOptions(applyOptions)
+						}
+						values = applyOptions.Values.(map[string]any)
+						return nil
+					})
+				workerDelegate, _ = NewWorkerDelegate(c, scheme, chartApplier, "", w, cluster, "", config.WorkerControllerConfiguration{})
+
+				Expect(workerDelegate.DeployMachineClasses(ctx)).To(Succeed())
+				Expect(values).To(HaveKeyWithValue("zoneKey", StackitMachineClassZoneKey))
+
+				renderer := chartrenderer.NewWithServerVersion(&version.Info{GitVersion: "v1.33.0"})
+				rendered, err := renderer.RenderEmbeddedFS(charts.InternalChart, filepath.Join(charts.InternalChartsPath, "machineclass-stackit"), "machineclass", namespace, values)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(rendered.Manifest())).To(ContainSubstring("\n  " + StackitMachineClassZoneKey + ": " + zone1 + "\n"))
+				Expect(string(rendered.Manifest())).To(ContainSubstring("\n  " + StackitMachineClassZoneKey + ": " + zone2 + "\n"))
+			})
+
+			It("should name the machine classes if they cannot be applied", func() {
+				chartApplier.
+					EXPECT().
+					ApplyFromEmbeddedFS(ctx, charts.InternalChart, filepath.Join("internal", "machineclass-stackit"), namespace, "machineclass", gomock.Any()).
+					Return(fmt.Errorf("conflict"))
+				workerDelegate, _ = NewWorkerDelegate(c, scheme, chartApplier, "", w, cluster, "", config.WorkerControllerConfiguration{})
+
+				result, err := workerDelegate.GenerateMachineDeployments(ctx)
+				Expect(err).NotTo(HaveOccurred())
+				var classNames []string
+				for _, deployment := range result {
+					classNames = append(classNames, deployment.ClassName)
+				}
+
+				err = workerDelegate.DeployMachineClasses(ctx)
+				Expect(err).To(MatchError("could not apply machine classes " + strings.Join(classNames, ", ") + " with chart machineclass-stackit: conflict"))
+			})
+
+			It("should reference the configured machine credentials secret in the machine classes", func() {
+				var values map[string]any
+				chartApplier.
+					EXPECT().
+					ApplyFromEmbeddedFS(ctx, charts.InternalChart, filepath.Join("internal", "machineclass-stackit"), namespace, "machineclass", gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ embed.FS, _, _, _ string, opts ...kubernetes.ApplyOption) error {
+						applyOptions := &kubernetes.ApplyOptions{}
+						for _, opt := range opts {
+							opt.MutateApplyOptions(applyOptions)
+						}
+						values = applyOptions.Values.(map[string]any)
+						return nil
+					})
+				Expect(c.Create(ctx, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "machine-credentials", Namespace: namespace}})).To(Succeed())
+				workerDelegate, _ = NewWorkerDelegate(c, scheme, chartApplier, "", w, cluster, "", config.WorkerControllerConfiguration{
+					MachineCredentialsSecretName: "machine-credentials",
+				})
+
+				Expect(workerDelegate.DeployMachineClasses(ctx)).To(Succeed())
+				Expect(values["machineClasses"]).NotTo(BeEmpty())
+				for _, class := range values["machineClasses"].([]map[string]any) {
+					Expect(class).To(HaveKeyWithValue("credentialsSecretRef", map[string]any{
+						"name":      "machine-credentials",
+						"namespace": namespace,
+					}))
+				}
+			})
+
+			It("should return an error on reconciliation if the configured machine credentials secret does not exist", func() {
+				workerDelegate, _ = NewWorkerDelegate(c, scheme, chartApplier, "", w, cluster, "", config.WorkerControllerConfiguration{
+					MachineCredentialsSecretName: "machine-credentials",
+				})
+
+				Expect(workerDelegate.PreReconcileHook(ctx)).To(MatchError(ContainSubstring("failed to get machine credentials secret " + namespace + "/machine-credentials")))
+				Expect(workerDelegate.PreDeleteHook(ctx)).To(Succeed())
+			})
+
+			It("should read the user data secret shared by all pools only once", func() {
+				var secretReads int
+				c = fakeclient.NewClientBuilder().
+					WithScheme(scheme).
+					WithStatusSubresource(&extensionsv1alpha1.Worker{}).
+					WithObjects(
+						w.DeepCopy(),
+						&corev1.Secret{
+							ObjectMeta: metav1.ObjectMeta{
+								Name:      userDataSecretName,
+								Namespace: namespace,
+							},
+							Data: map[string][]byte{userDataSecretDataKey: userData},
+						},
+					).
+					WithInterceptorFuncs(interceptor.Funcs{
+						Get: func(ctx context.Context, cl client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+							if _, ok := obj.(*corev1.Secret); ok && key == (client.ObjectKey{Namespace: namespace, Name: userDataSecretName}) {
+								secretReads++
+							}
+							return cl.Get(ctx, key, obj, opts...)
+						},
+					}).
+					Build()
+				workerDelegate, _ = NewWorkerDelegate(c, scheme, chartApplier, "", w, cluster, "", config.WorkerControllerConfiguration{})
+
+				_, err := workerDelegate.GenerateMachineDeployments(ctx)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(secretReads).To(Equal(1))
+
+				workerDelegate, _ = NewWorkerDelegate(c, scheme, chartApplier, "", w, cluster, "", config.WorkerControllerConfiguration{})
+				_, err = workerDelegate.GenerateMachineDeployments(ctx)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(secretReads).To(Equal(2))
+			})
+
+			Context("pod network CIDRs", func() {
+				var values map[string]any
+
+				BeforeEach(func() {
+					chartApplier.
+						EXPECT().
+						ApplyFromEmbeddedFS(ctx, charts.InternalChart, filepath.Join("internal", "machineclass-stackit"), namespace, "machineclass", gomock.Any()).
+						DoAndReturn(func(_ context.Context, _ embed.FS, _, _, _ string, opts ...kubernetes.ApplyOption) error {
+							applyOptions := &kubernetes.ApplyOptions{}
+							for _, opt := range opts {
+								opt.MutateApplyOptions(applyOptions)
+							}
+							values = applyOptions.Values.(map[string]any)
+							return nil
+						}).
+						AnyTimes()
+				})
+
+				DescribeTable("should render all pod network CIDRs into the machine classes",
+					func(specPods *string, statusPods []string, expected []string) {
+						cluster.Shoot.Spec.Networking.Pods = specPods
+						if statusPods != nil {
+							cluster.Shoot.Status.Networking = &gardencorev1beta1.NetworkingStatus{Pods: statusPods}
+						}
+						workerDelegate, _ = NewWorkerDelegate(c, scheme, chartApplier, "", w, cluster, "", config.WorkerControllerConfiguration{})
+
+						Expect(workerDelegate.DeployMachineClasses(ctx)).To(Succeed())
+						for _, class := range values["machineClasses"].([]map[string]any) {
+							Expect(class).To(HaveKeyWithValue("podNetworkCIDRs", expected))
+						}
+
+						renderer := chartrenderer.NewWithServerVersion(&version.Info{GitVersion: "v1.33.0"})
+						rendered, err := renderer.RenderEmbeddedFS(charts.InternalChart, filepath.Join(charts.InternalChartsPath, "machineclass-stackit"), "machineclass", namespace, values)
+						Expect(err).NotTo(HaveOccurred())
+						Expect(string(rendered.Manifest())).To(ContainSubstring("\n  allowedAddresses:\n  - " + strings.Join(expected, "\n  - ") + "\n"))
+					},
+					Entry("single-stack", new("10.96.0.0/11"), nil, []string{"10.96.0.0/11"}),
+					Entry("single-stack from the status", nil, []string{"10.96.0.0/11"}, []string{"10.96.0.0/11"}),
+					Entry("dual-stack", new("10.96.0.0/11"), []string{"10.96.0.0/11", "2001:db8::/64"}, []string{"10.96.0.0/11", "2001:db8::/64"}),
+					Entry("dual-stack with the IPv6 CIDR first", nil, []string{"2001:db8::/64", "10.96.0.0/11"}, []string{"10.96.0.0/11", "2001:db8::/64"}),
+				)
+
+				It("should fail for an invalid pod network CIDR", func() {
+					cluster.Shoot.Status.Networking = &gardencorev1beta1.NetworkingStatus{Pods: []string{"10.96.0.0/11", "invalid"}}
+					workerDelegate, _ = NewWorkerDelegate(c, scheme, chartApplier, "", w, cluster, "", config.WorkerControllerConfiguration{})
+
+					_, err := workerDelegate.GenerateMachineDeployments(ctx)
+					Expect(err).To(MatchError(ContainSubstring(`invalid pod network CIDR "invalid"`)))
+				})
+			})
+
+			Context("port security", func() {
+				setDisablePortSecurity := func(disable bool) {
+					w.Spec.Pools[0].ProviderConfig = &runtime.RawExtension{
+						Raw: encode(&stackitv1alpha1.WorkerConfig{
+							TypeMeta: metav1.TypeMeta{
+								Kind:       "WorkerConfig",
+								APIVersion: stackitv1alpha1.SchemeGroupVersion.String(),
+							},
+							DisablePortSecurity: disable,
+						}),
+					}
+				}
+
+				It("should disable the NIC security of the STACKIT machine classes and roll the machines", func() {
+					setDisablePortSecurity(false)
+					workerDelegate, _ = NewWorkerDelegate(c, scheme, chartApplier, "", w, cluster, "", config.WorkerControllerConfiguration{})
+					result, err := workerDelegate.GenerateMachineDeployments(ctx)
+					Expect(err).NotTo(HaveOccurred())
+					enabledClassName := result[0].ClassName
+
+					setDisablePortSecurity(true)
+					var values map[string]any
+					chartApplier.
+						EXPECT().
+						ApplyFromEmbeddedFS(ctx, charts.InternalChart, filepath.Join("internal", "machineclass-stackit"), namespace, "machineclass", gomock.Any()).
+						DoAndReturn(func(_ context.Context, _ embed.FS, _, _, _ string, opts ...kubernetes.ApplyOption) error {
+							applyOptions := &kubernetes.ApplyOptions{}
+							for _, opt := range opts {
+								opt.MutateApplyOptions(applyOptions)
+							}
+							values = applyOptions.Values.(map[string]any)
+							return nil
+						})
+					workerDelegate, _ = NewWorkerDelegate(c, scheme, chartApplier, "", w, cluster, "", config.WorkerControllerConfiguration{})
+
+					result, err = workerDelegate.GenerateMachineDeployments(ctx)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(result[0].ClassName).NotTo(Equal(enabledClassName))
+
+					Expect(workerDelegate.DeployMachineClasses(ctx)).To(Succeed())
+					classes := values["machineClasses"].([]map[string]any)
+					Expect(classes[0]).To(HaveKeyWithValue("nicSecurity", false))
+					Expect(classes[len(classes)-1]).NotTo(HaveKey("nicSecurity"))
+
+					renderer := chartrenderer.NewWithServerVersion(&version.Info{GitVersion: "v1.33.0"})
+					rendered, err := renderer.RenderEmbeddedFS(charts.InternalChart, filepath.Join(charts.InternalChartsPath, "machineclass-stackit"), "machineclass", namespace, values)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(strings.Count(string(rendered.Manifest()), "\n  nicSecurity: false\n")).To(Equal(len(w.Spec.Pools[0].Zones)))
+				})
+
+				It("should not disable the NIC security with the OpenStack machine controller manager", func() {
+					DeferCleanup(testutils.WithFeatureGate(feature.MutableGate, feature.UseSTACKITMachineControllerManager, false))
+					setDisablePortSecurity(true)
+					var values map[string]any
+					chartApplier.
+						EXPECT().
+						ApplyFromEmbeddedFS(ctx, charts.InternalChart, filepath.Join("internal", "machineclass-stackit"), namespace, "machineclass", gomock.Any()).
+						DoAndReturn(func(_ context.Context, _ embed.FS, _, _, _ string, opts ...kubernetes.ApplyOption) error {
+							applyOptions := &kubernetes.ApplyOptions{}
+							for _, opt := range opts {
+								opt.MutateApplyOptions(applyOptions)
+							}
+							values = applyOptions.Values.(map[string]any)
+							return nil
+						})
+					workerDelegate, _ = NewWorkerDelegate(c, scheme, chartApplier, "", w, cluster, "", config.WorkerControllerConfiguration{})
+
+					Expect(workerDelegate.DeployMachineClasses(ctx)).To(Succeed())
+					for _, class := range values["machineClasses"].([]map[string]any) {
+						Expect(class).NotTo(HaveKey("nicSecurity"))
+					}
+				})
+			})
+
+			Context("zone weights", func() {
+				setZoneWeights := func(zoneWeights map[string]int32) {
+					w.Spec.Pools[0].ProviderConfig = &runtime.RawExtension{
+						Raw: encode(&stackitv1alpha1.WorkerConfig{
+							TypeMeta: metav1.TypeMeta{
+								Kind:       "WorkerConfig",
+								APIVersion: stackitv1alpha1.SchemeGroupVersion.String(),
+							},
+							ZoneWeights: zoneWeights,
+						}),
+					}
+				}
+
+				It("should distribute the minimum and maximum evenly without zone weights", func() {
+					workerDelegate, _ = NewWorkerDelegate(c, scheme, chartApplier, "", w, cluster, "", config.WorkerControllerConfiguration{})
+
+					result, err := workerDelegate.GenerateMachineDeployments(ctx)
+					Expect(err).NotTo(HaveOccurred())
+					Expect([]int32{result[0].Minimum, result[1].Minimum}).To(Equal([]int32{3, 2}))
+					Expect([]int32{result[0].Maximum, result[1].Maximum}).To(Equal([]int32{5, 5}))
+				})
+
+				It("should distribute the minimum and maximum according to the zone weights", func() {
+					setZoneWeights(map[string]int32{zone1: 3, zone2: 1})
+					workerDelegate, _ = NewWorkerDelegate(c, scheme, chartApplier, "", w, cluster, "", config.WorkerControllerConfiguration{})
+
+					result, err := workerDelegate.GenerateMachineDeployments(ctx)
+					Expect(err).NotTo(HaveOccurred())
+					Expect([]int32{result[0].Minimum, result[1].Minimum}).To(Equal([]int32{4, 1}))
+					Expect([]int32{result[0].Maximum, result[1].Maximum}).To(Equal([]int32{8, 2}))
+					Expect(result[2].Minimum).To(Equal(worker.DistributeOverZones(0, minPool2, 2)))
+				})
+
+				It("should distribute the minimum and maximum evenly if a zone of the pool has no weight", func() {
+					setZoneWeights(map[string]int32{zone1: 3})
+					workerDelegate, _ = NewWorkerDelegate(c, scheme, chartApplier, "", w, cluster, "", config.WorkerControllerConfiguration{})
+
+					result, err := workerDelegate.GenerateMachineDeployments(ctx)
+					Expect(err).NotTo(HaveOccurred())
+					Expect([]int32{result[0].Minimum, result[1].Minimum}).To(Equal([]int32{3, 2}))
+					Expect([]int32{result[0].Maximum, result[1].Maximum}).To(Equal([]int32{5, 5}))
+				})
+			})
+
+			Context("server groups", func() {
+				setServerGroupPolicy := func(policy string) {
+					w.Spec.Pools[0].ProviderConfig = &runtime.RawExtension{
+						Raw: encode(&stackitv1alpha1.WorkerConfig{
+							TypeMeta: metav1.TypeMeta{
+								Kind:       "WorkerConfig",
+								APIVersion: stackitv1alpha1.SchemeGroupVersion.String(),
+							},
+							ServerGroup: &stackitv1alpha1.ServerGroupConfig{Policy: policy},
+						}),
+					}
+				}
+
+				BeforeEach(func() {
+					w.Status.ProviderStatus = &runtime.RawExtension{
+						Raw: encode(&stackitv1alpha1.WorkerStatus{
+							TypeMeta: metav1.TypeMeta{
+								Kind:       "WorkerStatus",
+								APIVersion: stackitv1alpha1.SchemeGroupVersion.String(),
+							},
+							ServerGroupDependencies: []stackitv1alpha1.ServerGroupDependency{
+								{PoolName: namePool1, ID: "server-group-soft", Name: technicalID + "-" + namePool1 + "-soft-anti-affinity"},
+								{PoolName: namePool1, ID: "server-group-hard", Name: technicalID + "-" + namePool1 + "-hard-anti-affinity"},
+							},
+						}),
+					}
+				})
+
+				It("should inject the server group into the STACKIT machine classes and roll the machines on policy changes", func() {
+					setServerGroupPolicy("soft-anti-affinity")
+					var values map[string]any
+					chartApplier.
+						EXPECT().
+						ApplyFromEmbeddedFS(ctx, charts.InternalChart, filepath.Join("internal", "machineclass-stackit"), namespace, "machineclass", gomock.Any()).
+						DoAndReturn(func(_ context.Context, _ embed.FS, _, _, _ string, opts ...kubernetes.ApplyOption) error {
+							applyOptions := &kubernetes.ApplyOptions{}
+							for _, opt := range opts {
+								opt.MutateApplyOptions(applyOptions)
+							}
+							values = applyOptions.Values.(map[string]any)
+							return nil
+						})
+					workerDelegate, _ = NewWorkerDelegate(c, scheme, chartApplier, "", w, cluster, "", config.WorkerControllerConfiguration{})
+
+					result, err := workerDelegate.GenerateMachineDeployments(ctx)
+					Expect(err).NotTo(HaveOccurred())
+					softClassName := result[0].ClassName
+
+					Expect(workerDelegate.DeployMachineClasses(ctx)).To(Succeed())
+					classes := values["machineClasses"].([]map[string]any)
+					Expect(classes[0]).To(HaveKeyWithValue("serverGroupID", "server-group-soft"))
+					Expect(classes[len(classes)-1]).NotTo(HaveKey("serverGroupID"))
+
+					renderer := chartrenderer.NewWithServerVersion(&version.Info{GitVersion: "v1.33.0"})
+					rendered, err := renderer.RenderEmbeddedFS(charts.InternalChart, filepath.Join(charts.InternalChartsPath, "machineclass-stackit"), "machineclass", namespace, values)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(strings.Count(string(rendered.Manifest()), "\n  affinityGroup: server-group-soft\n")).To(Equal(len(w.Spec.Pools[0].Zones)))
+
+					setServerGroupPolicy("hard-anti-affinity")
+					workerDelegate, _ = NewWorkerDelegate(c, scheme, chartApplier, "", w, cluster, "", config.WorkerControllerConfiguration{})
+
+					result, err = workerDelegate.GenerateMachineDeployments(ctx)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(result[0].ClassName).NotTo(Equal(softClassName))
+				})
+
+				It("should fail if the server group has not been created yet", func() {
+					setServerGroupPolicy("soft-affinity")
+					workerDelegate, _ = NewWorkerDelegate(c, scheme, chartApplier, "", w, cluster, "", config.WorkerControllerConfiguration{})
+
+					_, err := workerDelegate.GenerateMachineDeployments(ctx)
+					Expect(err).To(MatchError(ContainSubstring("worker status does not contain the server group of worker pool " + namePool1)))
+				})
+
+				It("should fail with the OpenStack machine controller manager", func() {
+					DeferCleanup(testutils.WithFeatureGate(feature.MutableGate, feature.UseSTACKITMachineControllerManager, false))
+					setServerGroupPolicy("soft-anti-affinity")
+					workerDelegate, _ = NewWorkerDelegate(c, scheme, chartApplier, "", w, cluster, "", config.WorkerControllerConfiguration{})
+
+					_, err := workerDelegate.GenerateMachineDeployments(ctx)
+					Expect(err).To(MatchError(ContainSubstring("serverGroup of worker pool " + namePool1 + " is only supported with the STACKIT machine controller manager")))
+				})
+			})
 		})
 	})
 })
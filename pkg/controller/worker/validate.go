@@ -0,0 +1,128 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package worker
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	extensionscontroller "github.com/gardener/gardener/extensions/pkg/controller"
+	"github.com/gardener/gardener/extensions/pkg/controller/worker"
+	"github.com/gardener/gardener/extensions/pkg/controller/worker/genericactuator"
+	v1beta1helper "github.com/gardener/gardener/pkg/api/core/v1beta1/helper"
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	machinev1alpha1 "github.com/gardener/machine-controller-manager/pkg/apis/machine/v1alpha1"
+	"github.com/go-logr/logr"
+	"k8s.io/utils/clock"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/stackit"
+)
+
+const (
+	// ConditionTypeMachineClassesValid is the condition type of the Worker reporting the result of the last validation
+	// in the validate-only mode.
+	ConditionTypeMachineClassesValid gardencorev1beta1.ConditionType = "MachineClassesValid"
+
+	reasonValidationSucceeded = "ValidationSucceeded"
+	reasonValidationFailed    = "ValidationFailed"
+)
+
+// validatingActuator only generates and validates the machine deployments and classes of Workers with the validate-only
+// annotation instead of applying them. All other Workers and operations are handed to the wrapped actuator.
+type validatingActuator struct {
+	worker.Actuator
+
+	client          client.Client
+	clock           clock.PassiveClock
+	delegateFactory *delegateFactory
+}
+
+// Reconcile validates the given Worker if it has the validate-only annotation or reconciles it otherwise.
+func (a *validatingActuator) Reconcile(ctx context.Context, log logr.Logger, w *extensionsv1alpha1.Worker, cluster *extensionscontroller.Cluster) error {
+	if w.Annotations[stackit.AnnotationValidateOnly] != "true" {
+		return a.Actuator.Reconcile(ctx, log, w, cluster)
+	}
+
+	delegate, err := NewWorkerDelegate(a.delegateFactory.seedClient, a.delegateFactory.scheme, nil, "", w, cluster, a.delegateFactory.customLabelDomain, a.delegateFactory.configuration)
+	if err != nil {
+		return a.reportValidation(ctx, w, err)
+	}
+	return a.validate(ctx, log, w, delegate)
+}
+
+// validate generates the machine deployments of the given Worker and records the intended machine classes as well as
+// the worker pools that would roll in the status of the Worker without applying anything.
+func (a *validatingActuator) validate(ctx context.Context, log logr.Logger, w *extensionsv1alpha1.Worker, delegate genericactuator.WorkerDelegate) error {
+	log.Info("Only validating worker as it has the validate-only annotation")
+
+	machineDeployments, err := delegate.GenerateMachineDeployments(ctx)
+	if err != nil {
+		return a.reportValidation(ctx, w, err)
+	}
+
+	rollingPools, err := a.rollingPools(ctx, w, machineDeployments)
+	if err != nil {
+		return err
+	}
+
+	classNames := make([]string, 0, len(machineDeployments))
+	for _, deployment := range machineDeployments {
+		classNames = append(classNames, deployment.ClassName)
+	}
+	message := fmt.Sprintf("%d machine classes would be applied: %s", len(classNames), strings.Join(classNames, ", "))
+	if len(rollingPools) > 0 {
+		message += fmt.Sprintf(". %d worker pools would roll: %s", len(rollingPools), strings.Join(rollingPools, ", "))
+	} else {
+		message += ". No worker pool would roll"
+	}
+	log.Info("Validated machine deployments", "machineClasses", classNames, "rollingPools", rollingPools)
+	return a.updateCondition(ctx, w, gardencorev1beta1.ConditionTrue, reasonValidationSucceeded, message)
+}
+
+// rollingPools returns the sorted names of the worker pools whose currently deployed machine deployments would get a
+// new machine class. As the machine class names contain a hash of the machine configuration, a changed class name
+// implies a rollout of the machines. Machine deployments which don't exist yet are not considered as rolling.
+func (a *validatingActuator) rollingPools(ctx context.Context, w *extensionsv1alpha1.Worker, machineDeployments worker.MachineDeployments) ([]string, error) {
+	existing := &machinev1alpha1.MachineDeploymentList{}
+	if err := a.client.List(ctx, existing, client.InNamespace(w.Namespace)); err != nil {
+		return nil, fmt.Errorf("could not list the existing machine deployments: %w", err)
+	}
+
+	deployedClassNames := make(map[string]string, len(existing.Items))
+	for _, deployment := range existing.Items {
+		deployedClassNames[deployment.Name] = deployment.Spec.Template.Spec.Class.Name
+	}
+
+	var pools []string
+	for _, deployment := range machineDeployments {
+		className, ok := deployedClassNames[deployment.Name]
+		if ok && className != deployment.ClassName && !slices.Contains(pools, deployment.PoolName) {
+			pools = append(pools, deployment.PoolName)
+		}
+	}
+	slices.Sort(pools)
+	return pools, nil
+}
+
+// reportValidation records the given validation error in the status of the Worker and returns it.
+func (a *validatingActuator) reportValidation(ctx context.Context, w *extensionsv1alpha1.Worker, validationErr error) error {
+	if err := a.updateCondition(ctx, w, gardencorev1beta1.ConditionFalse, reasonValidationFailed, validationErr.Error()); err != nil {
+		return err
+	}
+	return fmt.Errorf("validation of worker failed: %w", validationErr)
+}
+
+func (a *validatingActuator) updateCondition(ctx context.Context, w *extensionsv1alpha1.Worker, status gardencorev1beta1.ConditionStatus, reason, message string) error {
+	condition := v1beta1helper.GetOrInitConditionWithClock(a.clock, w.Status.Conditions, ConditionTypeMachineClassesValid)
+	condition = v1beta1helper.UpdatedConditionWithClock(a.clock, condition, status, reason, message)
+
+	patch := client.MergeFrom(w.DeepCopy())
+	w.Status.Conditions = v1beta1helper.MergeConditions(w.Status.Conditions, condition)
+	return a.client.Status().Patch(ctx, w, patch)
+}
@@ -0,0 +1,171 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package worker
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	extensionscontroller "github.com/gardener/gardener/extensions/pkg/controller"
+	"github.com/gardener/gardener/extensions/pkg/controller/worker"
+	"github.com/gardener/gardener/extensions/pkg/controller/worker/genericactuator"
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"github.com/gardener/gardener/pkg/client/kubernetes"
+	machinev1alpha1 "github.com/gardener/machine-controller-manager/pkg/apis/machine/v1alpha1"
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	testclock "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/stackit"
+)
+
+type fakeActuator struct {
+	worker.Actuator
+
+	reconciled bool
+}
+
+func (f *fakeActuator) Reconcile(context.Context, logr.Logger, *extensionsv1alpha1.Worker, *extensionscontroller.Cluster) error {
+	f.reconciled = true
+	return nil
+}
+
+type fakeWorkerDelegate struct {
+	genericactuator.WorkerDelegate
+
+	machineDeployments worker.MachineDeployments
+	err                error
+}
+
+func (f *fakeWorkerDelegate) GenerateMachineDeployments(context.Context) (worker.MachineDeployments, error) {
+	return f.machineDeployments, f.err
+}
+
+var _ = Describe("validatingActuator", func() {
+	var (
+		ctx       context.Context
+		c         client.Client
+		fakeClock *testclock.FakePassiveClock
+		wrapped   *fakeActuator
+		a         *validatingActuator
+		w         *extensionsv1alpha1.Worker
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		w = &extensionsv1alpha1.Worker{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "worker",
+				Namespace:   "shoot--foo--bar",
+				Annotations: map[string]string{stackit.AnnotationValidateOnly: "true"},
+			},
+			Spec: extensionsv1alpha1.WorkerSpec{
+				InfrastructureProviderStatus: &runtime.RawExtension{Raw: []byte(`{}`)},
+			},
+		}
+		c = fakeclient.NewClientBuilder().WithScheme(kubernetes.SeedScheme).WithObjects(w).WithStatusSubresource(w).Build()
+
+		fakeClock = testclock.NewFakePassiveClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+		wrapped = &fakeActuator{}
+		a = &validatingActuator{
+			Actuator:        wrapped,
+			client:          c,
+			clock:           fakeClock,
+			delegateFactory: &delegateFactory{seedClient: c, scheme: kubernetes.SeedScheme},
+		}
+	})
+
+	condition := func() *gardencorev1beta1.Condition {
+		GinkgoHelper()
+		actual := &extensionsv1alpha1.Worker{}
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(w), actual)).To(Succeed())
+		for _, cond := range actual.Status.Conditions {
+			if cond.Type == ConditionTypeMachineClassesValid {
+				return &cond
+			}
+		}
+		return nil
+	}
+
+	It("should reconcile workers without the validate-only annotation", func() {
+		delete(w.Annotations, stackit.AnnotationValidateOnly)
+
+		Expect(a.Reconcile(ctx, logr.Discard(), w, &extensionscontroller.Cluster{})).To(Succeed())
+		Expect(wrapped.reconciled).To(BeTrue())
+		Expect(condition()).To(BeNil())
+	})
+
+	It("should not reconcile workers with the validate-only annotation", func() {
+		Expect(a.Reconcile(ctx, logr.Discard(), w, &extensionscontroller.Cluster{})).To(MatchError(ContainSubstring("validation of worker failed")))
+		Expect(wrapped.reconciled).To(BeFalse())
+		Expect(condition()).To(PointTo(MatchFields(IgnoreExtras, Fields{
+			"Status": Equal(gardencorev1beta1.ConditionFalse),
+			"Reason": Equal("ValidationFailed"),
+		})))
+	})
+
+	It("should record the intended machine classes", func() {
+		delegate := &fakeWorkerDelegate{machineDeployments: worker.MachineDeployments{
+			{Name: "pool-z1", ClassName: "pool-z1-abcde", PoolName: "pool"},
+			{Name: "pool-z2", ClassName: "pool-z2-abcde", PoolName: "pool"},
+		}}
+
+		Expect(a.validate(ctx, logr.Discard(), w, delegate)).To(Succeed())
+		Expect(condition()).To(PointTo(MatchFields(IgnoreExtras, Fields{
+			"Status":  Equal(gardencorev1beta1.ConditionTrue),
+			"Reason":  Equal("ValidationSucceeded"),
+			"Message": Equal("2 machine classes would be applied: pool-z1-abcde, pool-z2-abcde. No worker pool would roll"),
+		})))
+	})
+
+	It("should report the worker pools whose machine classes change", func() {
+		machineDeployment := func(name, className string) *machinev1alpha1.MachineDeployment {
+			return &machinev1alpha1.MachineDeployment{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: w.Namespace},
+				Spec: machinev1alpha1.MachineDeploymentSpec{
+					Template: machinev1alpha1.MachineTemplateSpec{
+						Spec: machinev1alpha1.MachineSpec{Class: machinev1alpha1.ClassSpec{Name: className}},
+					},
+				},
+			}
+		}
+		Expect(c.Create(ctx, machineDeployment("b-z1", "b-z1-old"))).To(Succeed())
+		Expect(c.Create(ctx, machineDeployment("b-z2", "b-z2-abcde"))).To(Succeed())
+		Expect(c.Create(ctx, machineDeployment("a-z1", "a-z1-old"))).To(Succeed())
+		Expect(c.Create(ctx, machineDeployment("c-z1", "c-z1-abcde"))).To(Succeed())
+
+		delegate := &fakeWorkerDelegate{machineDeployments: worker.MachineDeployments{
+			{Name: "b-z1", ClassName: "b-z1-abcde", PoolName: "b"},
+			{Name: "b-z2", ClassName: "b-z2-abcde", PoolName: "b"},
+			{Name: "a-z1", ClassName: "a-z1-abcde", PoolName: "a"},
+			{Name: "c-z1", ClassName: "c-z1-abcde", PoolName: "c"},
+			{Name: "new-z1", ClassName: "new-z1-abcde", PoolName: "new"},
+		}}
+
+		Expect(a.validate(ctx, logr.Discard(), w, delegate)).To(Succeed())
+		Expect(condition()).To(PointTo(MatchFields(IgnoreExtras, Fields{
+			"Status":  Equal(gardencorev1beta1.ConditionTrue),
+			"Message": HaveSuffix(". 2 worker pools would roll: a, b"),
+		})))
+	})
+
+	It("should record the validation error", func() {
+		delegate := &fakeWorkerDelegate{err: fmt.Errorf("machine image not found")}
+
+		Expect(a.validate(ctx, logr.Discard(), w, delegate)).To(MatchError(ContainSubstring("machine image not found")))
+		Expect(condition()).To(PointTo(MatchFields(IgnoreExtras, Fields{
+			"Status":  Equal(gardencorev1beta1.ConditionFalse),
+			"Message": Equal("machine image not found"),
+		})))
+	})
+})
@@ -59,28 +59,70 @@ func init() {
 	utilruntime.Must(MutableGate.Add(allGates))
 }
 
-func UseStackitMachineControllerManager(cluster *extensionscontroller.Cluster) bool {
-	if cluster != nil && cluster.Shoot != nil {
-		annotation, ok := cluster.Shoot.Annotations[ShootUseSTACKITMachineControllerManager]
-		if ok {
-			enabledByAnnotation, err := strconv.ParseBool(annotation)
-			if err == nil {
-				return enabledByAnnotation
-			}
-		}
+// GateSource is the source of the effective value of a feature gate for a Shoot.
+type GateSource string
+
+const (
+	// GateSourceGlobal means that the value is taken from the feature gates of the extension.
+	GateSourceGlobal GateSource = "Global"
+	// GateSourceShootAnnotation means that the value is overridden by an annotation of the Shoot.
+	GateSourceShootAnnotation GateSource = "ShootAnnotation"
+)
+
+// EffectiveGate is the value of a feature gate which is effective for a Shoot.
+type EffectiveGate struct {
+	// Name is the name of the feature gate.
+	Name featuregate.Feature `json:"name"`
+	// Enabled is true if the feature gate is enabled for the Shoot.
+	Enabled bool `json:"enabled"`
+	// Source is the source of the value.
+	Source GateSource `json:"source"`
+}
+
+// ShootGates returns the effective values of all feature gates which can be overridden by an annotation of the Shoot.
+func ShootGates(cluster *extensionscontroller.Cluster) []EffectiveGate {
+	return []EffectiveGate{
+		effectiveGate(cluster, UseSTACKITAPIInfrastructureController, ShootUseSTACKITAPIInfrastructureController),
+		effectiveGate(cluster, UseSTACKITMachineControllerManager, ShootUseSTACKITMachineControllerManager),
 	}
-	return Gate.Enabled(UseSTACKITMachineControllerManager)
 }
 
-func UseStackitAPIInfrastructureController(cluster *extensionscontroller.Cluster) bool {
+// effectiveGate returns the value of the given feature gate for the cluster. A valid boolean value of the given
+// annotation of the Shoot takes precedence over the global value.
+func effectiveGate(cluster *extensionscontroller.Cluster, gate featuregate.Feature, annotation string) EffectiveGate {
+	var annotations map[string]string
 	if cluster != nil && cluster.Shoot != nil {
-		annotation, ok := cluster.Shoot.Annotations[ShootUseSTACKITAPIInfrastructureController]
-		if ok {
-			enabledByAnnotation, err := strconv.ParseBool(annotation)
-			if err == nil {
-				return enabledByAnnotation
-			}
+		annotations = cluster.Shoot.Annotations
+	}
+	return effectiveGateForAnnotations(annotations, gate, annotation)
+}
+
+// effectiveGateForAnnotations returns the value of the given feature gate for a Shoot with the given annotations.
+func effectiveGateForAnnotations(annotations map[string]string, gate featuregate.Feature, annotation string) EffectiveGate {
+	if value, ok := annotations[annotation]; ok {
+		if enabled, err := strconv.ParseBool(value); err == nil {
+			return EffectiveGate{Name: gate, Enabled: enabled, Source: GateSourceShootAnnotation}
 		}
 	}
-	return Gate.Enabled(UseSTACKITAPIInfrastructureController)
+	return EffectiveGate{Name: gate, Enabled: Gate.Enabled(gate), Source: GateSourceGlobal}
+}
+
+func UseStackitMachineControllerManager(cluster *extensionscontroller.Cluster) bool {
+	return effectiveGate(cluster, UseSTACKITMachineControllerManager, ShootUseSTACKITMachineControllerManager).Enabled
+}
+
+// UseStackitMachineControllerManagerForShoot is like UseStackitMachineControllerManager for a Shoot with the given
+// annotations, e.g. in the admission webhook where no Cluster is available.
+func UseStackitMachineControllerManagerForShoot(annotations map[string]string) bool {
+	return effectiveGateForAnnotations(annotations, UseSTACKITMachineControllerManager, ShootUseSTACKITMachineControllerManager).Enabled
+}
+
+func UseStackitAPIInfrastructureController(cluster *extensionscontroller.Cluster) bool {
+	return effectiveGate(cluster, UseSTACKITAPIInfrastructureController, ShootUseSTACKITAPIInfrastructureController).Enabled
+}
+
+// UseStackitAPIInfrastructureControllerForShoot is like UseStackitAPIInfrastructureController for a Shoot with the
+// given annotations, e.g. in the admission webhook where no Cluster is available.
+func UseStackitAPIInfrastructureControllerForShoot(annotations map[string]string) bool {
+	return effectiveGateForAnnotations(annotations, UseSTACKITAPIInfrastructureController, ShootUseSTACKITAPIInfrastructureController).Enabled
 }
@@ -0,0 +1,79 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package feature_test
+
+import (
+	extensionscontroller "github.com/gardener/gardener/extensions/pkg/controller"
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	testutils "github.com/gardener/gardener/pkg/utils/test"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	. "github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/feature"
+)
+
+var _ = Describe("Feature", func() {
+	Describe("#ShootGates", func() {
+		var cluster *extensionscontroller.Cluster
+
+		BeforeEach(func() {
+			DeferCleanup(testutils.WithFeatureGate(MutableGate, UseSTACKITAPIInfrastructureController, true))
+			DeferCleanup(testutils.WithFeatureGate(MutableGate, UseSTACKITMachineControllerManager, false))
+
+			cluster = &extensionscontroller.Cluster{
+				Shoot: &gardencorev1beta1.Shoot{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{}}},
+			}
+		})
+
+		It("should return the global values without annotations", func() {
+			Expect(ShootGates(cluster)).To(ConsistOf(
+				EffectiveGate{Name: UseSTACKITAPIInfrastructureController, Enabled: true, Source: GateSourceGlobal},
+				EffectiveGate{Name: UseSTACKITMachineControllerManager, Enabled: false, Source: GateSourceGlobal},
+			))
+		})
+
+		It("should return the values of the shoot annotations", func() {
+			cluster.Shoot.Annotations[ShootUseSTACKITAPIInfrastructureController] = "false"
+			cluster.Shoot.Annotations[ShootUseSTACKITMachineControllerManager] = "true"
+
+			Expect(ShootGates(cluster)).To(ConsistOf(
+				EffectiveGate{Name: UseSTACKITAPIInfrastructureController, Enabled: false, Source: GateSourceShootAnnotation},
+				EffectiveGate{Name: UseSTACKITMachineControllerManager, Enabled: true, Source: GateSourceShootAnnotation},
+			))
+			Expect(UseStackitAPIInfrastructureController(cluster)).To(BeFalse())
+			Expect(UseStackitMachineControllerManager(cluster)).To(BeTrue())
+		})
+
+		It("should ignore invalid shoot annotations", func() {
+			cluster.Shoot.Annotations[ShootUseSTACKITMachineControllerManager] = "maybe"
+
+			Expect(ShootGates(cluster)).To(ContainElement(
+				EffectiveGate{Name: UseSTACKITMachineControllerManager, Enabled: false, Source: GateSourceGlobal},
+			))
+		})
+
+		It("should return the global values without cluster", func() {
+			Expect(ShootGates(nil)).To(ConsistOf(
+				EffectiveGate{Name: UseSTACKITAPIInfrastructureController, Enabled: true, Source: GateSourceGlobal},
+				EffectiveGate{Name: UseSTACKITMachineControllerManager, Enabled: false, Source: GateSourceGlobal},
+			))
+		})
+	})
+
+	Describe("#UseStackitMachineControllerManagerForShoot", func() {
+		BeforeEach(func() {
+			DeferCleanup(testutils.WithFeatureGate(MutableGate, UseSTACKITMachineControllerManager, true))
+		})
+
+		It("should return the global value without annotation", func() {
+			Expect(UseStackitMachineControllerManagerForShoot(nil)).To(BeTrue())
+		})
+
+		It("should return the value of the shoot annotation", func() {
+			Expect(UseStackitMachineControllerManagerForShoot(map[string]string{ShootUseSTACKITMachineControllerManager: "false"})).To(BeFalse())
+		})
+	})
+})
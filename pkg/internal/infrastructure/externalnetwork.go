@@ -0,0 +1,65 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	gardenv1beta1helper "github.com/gardener/gardener/pkg/api/core/v1beta1/helper"
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	"github.com/gophercloud/gophercloud/v2/openstack/networking/v2/networks"
+
+	osclient "github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/openstack/client"
+)
+
+// GetExternalNetwork returns the external network of the floating pool with the given name, or nil if there is none.
+// If the configuredID is set, the external network with this ID is returned. Otherwise, if multiple external networks
+// have the name, the one with the previousID (i.e. the one selected by an earlier reconciliation) is preferred. If none
+// of them matches, the floating pool name is ambiguous and a configuration problem is returned.
+func GetExternalNetwork(ctx context.Context, networking osclient.Networking, name string, configuredID, previousID *string) (*networks.Network, error) {
+	externalNetworks, err := networking.GetExternalNetworksByName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if configuredID != nil {
+		if network := findNetworkByID(externalNetworks, *configuredID); network != nil {
+			return network, nil
+		}
+		return nil, gardenv1beta1helper.NewErrorWithCodes(
+			fmt.Errorf("external network %s with name %s not found", *configuredID, name),
+			gardencorev1beta1.ErrorConfigurationProblem,
+		)
+	}
+
+	switch len(externalNetworks) {
+	case 0:
+		return nil, nil
+	case 1:
+		return &externalNetworks[0], nil
+	}
+
+	if previousID != nil {
+		if network := findNetworkByID(externalNetworks, *previousID); network != nil {
+			return network, nil
+		}
+	}
+
+	ids := make([]string, 0, len(externalNetworks))
+	for _, network := range externalNetworks {
+		ids = append(ids, network.ID)
+	}
+	return nil, gardenv1beta1helper.NewErrorWithCodes(
+		fmt.Errorf("floating pool name %s is ambiguous, it matches the external networks %s: the ID of one of them has to be configured as floatingPoolId", name, strings.Join(ids, ", ")),
+		gardencorev1beta1.ErrorConfigurationProblem,
+	)
+}
+
+func findNetworkByID(networkList []networks.Network, id string) *networks.Network {
+	for i := range networkList {
+		if networkList[i].ID == id {
+			return &networkList[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+
+	gardenv1beta1helper "github.com/gardener/gardener/pkg/api/core/v1beta1/helper"
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	"github.com/gophercloud/gophercloud/v2/openstack/networking/v2/networks"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+
+	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/openstack/client/mocks"
+)
+
+var _ = Describe("External network", func() {
+	var (
+		ctrl *gomock.Controller
+		nw   *mocks.MockNetworking
+		ctx  = context.Background()
+		name = "floating-net"
+	)
+
+	expectConfigurationProblem := func(err error) {
+		coder, ok := err.(gardenv1beta1helper.Coder)
+		Expect(ok).To(BeTrue())
+		Expect(coder.Codes()).To(ConsistOf(gardencorev1beta1.ErrorConfigurationProblem))
+	}
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		nw = mocks.NewMockNetworking(ctrl)
+	})
+
+	It("should fail on client error", func() {
+		nw.EXPECT().GetExternalNetworksByName(ctx, name).Return(nil, errors.New("client error"))
+		_, err := GetExternalNetwork(ctx, nw, name, nil, nil)
+		Expect(err).To(MatchError(ContainSubstring("client error")))
+	})
+
+	It("should return nil if no external network matches", func() {
+		nw.EXPECT().GetExternalNetworksByName(ctx, name).Return(nil, nil)
+		Expect(GetExternalNetwork(ctx, nw, name, nil, nil)).To(BeNil())
+	})
+
+	It("should return a single match", func() {
+		nw.EXPECT().GetExternalNetworksByName(ctx, name).Return([]networks.Network{{ID: "a", Name: name}}, nil)
+		Expect(GetExternalNetwork(ctx, nw, name, nil, new("b"))).To(HaveField("ID", "a"))
+	})
+
+	It("should select the configured external network from multiple matches", func() {
+		nw.EXPECT().GetExternalNetworksByName(ctx, name).Return([]networks.Network{{ID: "a", Name: name}, {ID: "b", Name: name}}, nil)
+		Expect(GetExternalNetwork(ctx, nw, name, new("b"), new("a"))).To(HaveField("ID", "b"))
+	})
+
+	It("should select the previous external network from multiple matches", func() {
+		nw.EXPECT().GetExternalNetworksByName(ctx, name).Return([]networks.Network{{ID: "a", Name: name}, {ID: "b", Name: name}}, nil)
+		Expect(GetExternalNetwork(ctx, nw, name, nil, new("b"))).To(HaveField("ID", "b"))
+	})
+
+	It("should fail if the configured external network does not match", func() {
+		nw.EXPECT().GetExternalNetworksByName(ctx, name).Return([]networks.Network{{ID: "a", Name: name}}, nil)
+		_, err := GetExternalNetwork(ctx, nw, name, new("b"), nil)
+		Expect(err).To(MatchError(ContainSubstring("external network b with name floating-net not found")))
+		expectConfigurationProblem(err)
+	})
+
+	It("should fail if multiple external networks match without selector", func() {
+		nw.EXPECT().GetExternalNetworksByName(ctx, name).Return([]networks.Network{{ID: "a", Name: name}, {ID: "b", Name: name}}, nil)
+		_, err := GetExternalNetwork(ctx, nw, name, nil, new("c"))
+		Expect(err).To(MatchError(ContainSubstring("floating pool name floating-net is ambiguous, it matches the external networks a, b")))
+		expectConfigurationProblem(err)
+	})
+})
@@ -29,6 +29,12 @@ import (
 
 const (
 	servicePrefix = "kube_service_"
+
+	// DefaultRouterInterfaceTimeout is the default maximum duration to wait for a new router interface to become active.
+	DefaultRouterInterfaceTimeout = 5 * time.Minute
+
+	// MetadataServiceCIDR is the CIDR of the instance metadata service, which is used by cloud-init and the kubelet.
+	MetadataServiceCIDR = "169.254.169.254/32"
 )
 
 // StatusTypeMeta is the TypeMeta of the InfrastructureStatus
@@ -156,12 +162,14 @@ func WorkersCIDR(config *stackitv1alpha1.InfrastructureConfig) string {
 }
 
 // PatchProviderStatusAndState patches the infrastructure status with the given provider specific status and state.
+// If aggregateEgressCIDRs is set, contiguous egress IPs are merged into the smallest covering CIDRs.
 func PatchProviderStatusAndState(
 	ctx context.Context,
 	runtimeClient client.Client,
 	infra *extensionsv1alpha1.Infrastructure,
 	status *stackitv1alpha1.InfrastructureStatus,
 	nodesCIDR *string,
+	aggregateEgressCIDRs bool,
 	state *runtime.RawExtension,
 ) error {
 	patch := client.MergeFrom(infra.DeepCopy())
@@ -169,6 +177,9 @@ func PatchProviderStatusAndState(
 		infra.Status.ProviderStatus = &runtime.RawExtension{Object: status}
 		infra.Status.NodesCIDR = nodesCIDR
 		infra.Status.EgressCIDRs = utils.ComputeEgressCIDRs(status.Networks.Router.ExternalFixedIPs)
+		if aggregateEgressCIDRs {
+			infra.Status.EgressCIDRs = utils.AggregateCIDRs(infra.Status.EgressCIDRs)
+		}
 	}
 
 	if state != nil {
@@ -0,0 +1,32 @@
+package infrastructure
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	stackitclient "github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/stackit/client"
+)
+
+const (
+	// DefaultLoadBalancerRequestTimeout is the default timeout of a single request to the STACKIT load balancer APIs
+	// when deleting the infrastructure.
+	DefaultLoadBalancerRequestTimeout = 15 * time.Second
+	// DefaultLoadBalancerRequestRetries is the default number of retries of a request to the STACKIT load balancer APIs
+	// failing with a transient error.
+	DefaultLoadBalancerRequestRetries = 3
+	// loadBalancerRequestBackoff is the initial wait between two attempts of a request, which doubles with every retry.
+	loadBalancerRequestBackoff = 2 * time.Second
+)
+
+// NewLoadBalancerRetryConfig returns the retry configuration of the requests to the STACKIT load balancer and
+// application load balancer APIs when deleting the infrastructure for the given timeout and number of retries. Unset
+// values are defaulted.
+func NewLoadBalancerRetryConfig(timeout *metav1.Duration, retries *int32) stackitclient.RetryConfig {
+	return stackitclient.RetryConfig{
+		MaxAttempts:    int(ptr.Deref(retries, DefaultLoadBalancerRequestRetries)) + 1,
+		BaseDelay:      loadBalancerRequestBackoff,
+		AttemptTimeout: ptr.Deref(timeout, metav1.Duration{Duration: DefaultLoadBalancerRequestTimeout}).Duration,
+	}
+}
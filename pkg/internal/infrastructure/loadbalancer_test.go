@@ -0,0 +1,29 @@
+package infrastructure
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	stackitclient "github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/stackit/client"
+)
+
+var _ = Describe("LoadBalancer", func() {
+	Describe("#NewLoadBalancerRetryConfig", func() {
+		It("should default unset values", func() {
+			Expect(NewLoadBalancerRetryConfig(nil, nil)).To(Equal(stackitclient.RetryConfig{
+				MaxAttempts:    DefaultLoadBalancerRequestRetries + 1,
+				BaseDelay:      loadBalancerRequestBackoff,
+				AttemptTimeout: DefaultLoadBalancerRequestTimeout,
+			}))
+		})
+
+		It("should use the configured values", func() {
+			config := NewLoadBalancerRetryConfig(&metav1.Duration{Duration: time.Minute}, new(int32(0)))
+			Expect(config.AttemptTimeout).To(Equal(time.Minute))
+			Expect(config.MaxAttempts).To(Equal(1))
+		})
+	})
+})
@@ -0,0 +1,306 @@
+package infrastructure
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/gophercloud/gophercloud/v2/openstack/networking/v2/extensions/security/rules"
+	iaas "github.com/stackitcloud/stackit-sdk-go/services/iaas/v2api"
+	"k8s.io/utils/ptr"
+
+	stackitv1alpha1 "github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/apis/stackit/v1alpha1"
+	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/stackit"
+)
+
+const (
+	// DefaultSecurityGroupDescription is the default description of the security group of the nodes.
+	DefaultSecurityGroupDescription = "Cluster Nodes"
+	// MaxSecurityGroupDescriptionLength is the maximum length of the description of a security group.
+	MaxSecurityGroupDescriptionLength = 255
+
+	// intraGroupRuleDescriptionSuffix is the suffix of the descriptions of the rules returned by IntraGroupRules.
+	intraGroupRuleDescriptionSuffix = "within the same security group"
+)
+
+// securityGroupDescriptionData is the data available in the security group description template.
+type securityGroupDescriptionData struct {
+	TechnicalID string
+}
+
+// RenderSecurityGroupDescription renders the description of the security group of the nodes from the given
+// text/template. The template can reference the technical ID of the shoot with `{{ .TechnicalID }}`.
+func RenderSecurityGroupDescription(descriptionTemplate, technicalID string) (string, error) {
+	tmpl, err := template.New("securityGroupDescription").Parse(descriptionTemplate)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse security group description template: %w", err)
+	}
+
+	var description strings.Builder
+	if err := tmpl.Execute(&description, securityGroupDescriptionData{TechnicalID: technicalID}); err != nil {
+		return "", fmt.Errorf("failed to render security group description: %w", err)
+	}
+	if description.Len() > MaxSecurityGroupDescriptionLength {
+		return "", fmt.Errorf("security group description must not be longer than %d characters, got %d", MaxSecurityGroupDescriptionLength, description.Len())
+	}
+	return description.String(), nil
+}
+
+// SecurityGroupRule is the provider-agnostic specification of a desired rule of the security group of the nodes. It is
+// converted to the rule types of the OpenStack and STACKIT APIs, so that both infrastructure flows manage the same rules.
+type SecurityGroupRule struct {
+	// Direction is either "ingress" or "egress".
+	Direction string
+	// EtherType is either "IPv4" or "IPv6".
+	EtherType string
+	// Protocol is the name of the protocol. An empty protocol matches all protocols.
+	Protocol string
+	// PortRangeMin is the first port of the rule. The rule is not restricted to ports if it is zero.
+	PortRangeMin int
+	// PortRangeMax is the last port of the rule.
+	PortRangeMax int
+	// RemoteIPPrefix is the CIDR of the remote side of the rule. An empty prefix matches all addresses.
+	RemoteIPPrefix string
+	// RemoteSelf restricts the remote side of the rule to the security group itself.
+	RemoteSelf bool
+	// RemoteGroupID restricts the remote side of the rule to the security group with the given ID.
+	RemoteGroupID string
+	// Description is the description of the rule.
+	Description string
+}
+
+// SecurityGroupRulesOptions contains the inputs of the desired rules of the security group of the nodes.
+type SecurityGroupRulesOptions struct {
+	// NodePortsCIDR is the CIDR which is allowed to access the node ports.
+	NodePortsCIDR string
+	// PodCIDR is the pod network of the shoot. The rule for the pod network is skipped if it is nil.
+	PodCIDR *string
+	// IntraNodeTraffic restricts the traffic within the security group to the configured ports.
+	IntraNodeTraffic *stackitv1alpha1.IntraNodeTraffic
+	// AllowMetadataServiceEgress adds an explicit egress rule for the instance metadata service.
+	AllowMetadataServiceEgress bool
+	// AllowIPv6Egress adds an egress rule for all outgoing IPv6 traffic, e.g. for nodes of dual-stack networks.
+	AllowIPv6Egress bool
+	// AdditionalRules are the additional rules of the InfrastructureConfig, which are appended to the other rules.
+	AdditionalRules []stackitv1alpha1.SecurityGroupRuleConfig
+}
+
+// DesiredSecurityGroupRules returns the desired rules of the security group of the nodes.
+func DesiredSecurityGroupRules(opts SecurityGroupRulesOptions) []SecurityGroupRule {
+	desiredRules := IntraGroupRules(opts.IntraNodeTraffic)
+	desiredRules = append(desiredRules, []SecurityGroupRule{
+		{
+			Direction:   stackit.DirectionEgress,
+			EtherType:   stackit.EtherTypeIPv4,
+			Description: "IPv4: allow all outgoing traffic",
+		},
+		{
+			Direction:      stackit.DirectionIngress,
+			EtherType:      stackit.EtherTypeIPv4,
+			Protocol:       "tcp",
+			PortRangeMin:   30000,
+			PortRangeMax:   32767,
+			RemoteIPPrefix: opts.NodePortsCIDR,
+			Description:    "IPv4: allow all incoming tcp traffic with port range 30000-32767",
+		},
+		{
+			Direction:      stackit.DirectionIngress,
+			EtherType:      stackit.EtherTypeIPv4,
+			Protocol:       "udp",
+			PortRangeMin:   30000,
+			PortRangeMax:   32767,
+			RemoteIPPrefix: opts.NodePortsCIDR,
+			Description:    "IPv4: allow all incoming udp traffic with port range 30000-32767",
+		},
+	}...)
+
+	if opts.PodCIDR != nil {
+		desiredRules = append(desiredRules, SecurityGroupRule{
+			Direction:      stackit.DirectionIngress,
+			EtherType:      stackit.EtherTypeIPv4,
+			RemoteIPPrefix: *opts.PodCIDR,
+			Description:    "IPv4: allow all incoming traffic from cluster pod CIDR",
+		})
+	}
+
+	if opts.AllowMetadataServiceEgress {
+		// explicitly allowed, so that cloud-init and the kubelet keep access if the outgoing traffic is restricted
+		desiredRules = append(desiredRules, SecurityGroupRule{
+			Direction:      stackit.DirectionEgress,
+			EtherType:      stackit.EtherTypeIPv4,
+			Protocol:       "tcp",
+			PortRangeMin:   80,
+			PortRangeMax:   80,
+			RemoteIPPrefix: MetadataServiceCIDR,
+			Description:    "IPv4: allow outgoing tcp traffic to the metadata service",
+		})
+	}
+
+	if opts.AllowIPv6Egress {
+		desiredRules = append(desiredRules, SecurityGroupRule{
+			Direction:   stackit.DirectionEgress,
+			EtherType:   stackit.EtherTypeIPv6,
+			Description: "IPv6: allow all outgoing traffic",
+		})
+	}
+
+	return append(desiredRules, AdditionalSecurityGroupRules(opts.AdditionalRules)...)
+}
+
+// AdditionalSecurityGroupRules converts the additional rules of the InfrastructureConfig. Rules without description
+// get a description generated from their fields, which is truncated to stackit.MaxSecurityGroupRuleDescriptionLength.
+// All descriptions are prefixed with stackit.AdditionalSecurityGroupRuleDescriptionPrefix to identify the rules, see
+// IsAdditionalSecurityGroupRule.
+func AdditionalSecurityGroupRules(configs []stackitv1alpha1.SecurityGroupRuleConfig) []SecurityGroupRule {
+	rules := make([]SecurityGroupRule, 0, len(configs))
+	for _, config := range configs {
+		rule := SecurityGroupRule{
+			Direction:      config.Direction,
+			EtherType:      ptr.Deref(config.EtherType, stackit.EtherTypeIPv4),
+			Protocol:       ptr.Deref(config.Protocol, ""),
+			RemoteIPPrefix: ptr.Deref(config.RemoteIPPrefix, ""),
+			RemoteGroupID:  ptr.Deref(config.RemoteSecurityGroupID, ""),
+		}
+		if config.PortRangeMin != nil {
+			rule.PortRangeMin = int(*config.PortRangeMin)
+			rule.PortRangeMax = int(ptr.Deref(config.PortRangeMax, *config.PortRangeMin))
+		}
+		rule.Description = stackit.AdditionalSecurityGroupRuleDescriptionPrefix + ptr.Deref(config.Description, additionalRuleDescription(rule))
+		// custom descriptions are validated to fit, but the generated ones can exceed the limit, e.g. for long IDs of
+		// remote security groups
+		if len(rule.Description) > stackit.MaxSecurityGroupRuleDescriptionLength {
+			rule.Description = rule.Description[:stackit.MaxSecurityGroupRuleDescriptionLength]
+		}
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+// IsAdditionalSecurityGroupRule returns true if the rule with the given description was created for the additional rules
+// of the InfrastructureConfig. Such rules can be deleted if they are not desired anymore.
+func IsAdditionalSecurityGroupRule(description string) bool {
+	return strings.HasPrefix(description, stackit.AdditionalSecurityGroupRuleDescriptionPrefix)
+}
+
+func additionalRuleDescription(rule SecurityGroupRule) string {
+	direction, remotePreposition := "incoming", "from"
+	if rule.Direction == stackit.DirectionEgress {
+		direction, remotePreposition = "outgoing", "to"
+	}
+
+	protocol := rule.Protocol
+	if protocol == "" {
+		protocol = "all"
+	}
+
+	description := fmt.Sprintf("%s: allow %s %s traffic", rule.EtherType, direction, protocol)
+	if rule.PortRangeMin != 0 {
+		description += fmt.Sprintf(" with port range %d-%d", rule.PortRangeMin, rule.PortRangeMax)
+	}
+	switch {
+	case rule.RemoteIPPrefix != "":
+		description += fmt.Sprintf(" %s %s", remotePreposition, rule.RemoteIPPrefix)
+	case rule.RemoteGroupID != "":
+		description += fmt.Sprintf(" %s security group %s", remotePreposition, rule.RemoteGroupID)
+	}
+	return description
+}
+
+// IntraGroupRules returns the ingress rules for the traffic within the security group. Without configured ports, all
+// traffic within the security group is allowed.
+func IntraGroupRules(traffic *stackitv1alpha1.IntraNodeTraffic) []SecurityGroupRule {
+	if traffic == nil || len(traffic.Ports) == 0 {
+		return []SecurityGroupRule{
+			{
+				Direction:   stackit.DirectionIngress,
+				EtherType:   stackit.EtherTypeIPv4,
+				RemoteSelf:  true,
+				Description: "IPv4: allow all incoming traffic " + intraGroupRuleDescriptionSuffix,
+			},
+		}
+	}
+
+	rules := make([]SecurityGroupRule, 0, len(traffic.Ports))
+	for _, port := range traffic.Ports {
+		rule := SecurityGroupRule{
+			Direction:   stackit.DirectionIngress,
+			EtherType:   stackit.EtherTypeIPv4,
+			Protocol:    port.Protocol,
+			RemoteSelf:  true,
+			Description: fmt.Sprintf("IPv4: allow incoming %s traffic %s", port.Protocol, intraGroupRuleDescriptionSuffix),
+		}
+		if port.Min != nil {
+			maxPort := ptr.Deref(port.Max, *port.Min)
+			rule.PortRangeMin = int(*port.Min)
+			rule.PortRangeMax = int(maxPort)
+			rule.Description = fmt.Sprintf("IPv4: allow incoming %s traffic with port range %d-%d %s", port.Protocol, *port.Min, maxPort, intraGroupRuleDescriptionSuffix)
+		}
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+// IsIntraGroupRule returns true if the rule with the given description was created for the traffic within the security
+// group, see IntraGroupRules. Rules within the security group added by other parties have a different description and
+// are kept.
+func IsIntraGroupRule(description string) bool {
+	return strings.HasSuffix(description, intraGroupRuleDescriptionSuffix)
+}
+
+// ToOpenStackSecurityGroupRules converts the given rules to the rule type of the OpenStack API. Rules restricted to
+// the security group itself reference the given remote group ID.
+func ToOpenStackSecurityGroupRules(specs []SecurityGroupRule, selfGroupID string) []rules.SecGroupRule {
+	result := make([]rules.SecGroupRule, 0, len(specs))
+	for _, spec := range specs {
+		rule := rules.SecGroupRule{
+			Direction:      spec.Direction,
+			EtherType:      spec.EtherType,
+			Protocol:       spec.Protocol,
+			PortRangeMin:   spec.PortRangeMin,
+			PortRangeMax:   spec.PortRangeMax,
+			RemoteIPPrefix: spec.RemoteIPPrefix,
+			Description:    spec.Description,
+		}
+		if spec.RemoteGroupID != "" {
+			rule.RemoteGroupID = spec.RemoteGroupID
+		}
+		if spec.RemoteSelf {
+			rule.RemoteGroupID = selfGroupID
+		}
+		result = append(result, rule)
+	}
+	return result
+}
+
+// ToSTACKITSecurityGroupRules converts the given rules to the rule type of the STACKIT API. Rules restricted to the
+// security group itself reference the given remote group ID.
+func ToSTACKITSecurityGroupRules(specs []SecurityGroupRule, selfGroupID string) []iaas.SecurityGroupRule {
+	result := make([]iaas.SecurityGroupRule, 0, len(specs))
+	for _, spec := range specs {
+		rule := iaas.SecurityGroupRule{
+			Direction:   spec.Direction,
+			Ethertype:   new(spec.EtherType),
+			Description: new(spec.Description),
+		}
+		if spec.Protocol != "" {
+			rule.Protocol = &iaas.Protocol{Name: new(spec.Protocol)}
+		}
+		if spec.PortRangeMin != 0 {
+			rule.PortRange = &iaas.PortRange{
+				Max: int64(spec.PortRangeMax),
+				Min: int64(spec.PortRangeMin),
+			}
+		}
+		if spec.RemoteIPPrefix != "" {
+			rule.IpRange = new(spec.RemoteIPPrefix)
+		}
+		if spec.RemoteGroupID != "" {
+			rule.RemoteSecurityGroupId = new(spec.RemoteGroupID)
+		}
+		if spec.RemoteSelf {
+			rule.RemoteSecurityGroupId = new(selfGroupID)
+		}
+		result = append(result, rule)
+	}
+	return result
+}
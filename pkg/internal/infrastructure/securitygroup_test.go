@@ -0,0 +1,199 @@
+package infrastructure
+
+import (
+	"strings"
+
+	"github.com/gophercloud/gophercloud/v2/openstack/networking/v2/extensions/security/rules"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	iaas "github.com/stackitcloud/stackit-sdk-go/services/iaas/v2api"
+	"k8s.io/utils/ptr"
+
+	stackitv1alpha1 "github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/apis/stackit/v1alpha1"
+	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/stackit"
+)
+
+var _ = Describe("#RenderSecurityGroupDescription", func() {
+	It("should render the technical ID", func() {
+		Expect(RenderSecurityGroupDescription("Nodes of {{ .TechnicalID }}", "shoot--foo--bar")).To(Equal("Nodes of shoot--foo--bar"))
+	})
+
+	It("should return a static description unchanged", func() {
+		Expect(RenderSecurityGroupDescription(DefaultSecurityGroupDescription, "shoot--foo--bar")).To(Equal("Cluster Nodes"))
+	})
+
+	It("should fail for an invalid template", func() {
+		_, err := RenderSecurityGroupDescription("Nodes of {{ .TechnicalID", "shoot--foo--bar")
+		Expect(err).To(MatchError(ContainSubstring("failed to parse")))
+	})
+
+	It("should fail for unknown fields", func() {
+		_, err := RenderSecurityGroupDescription("Nodes of {{ .Name }}", "shoot--foo--bar")
+		Expect(err).To(MatchError(ContainSubstring("failed to render")))
+	})
+
+	It("should fail if the description is too long", func() {
+		_, err := RenderSecurityGroupDescription(strings.Repeat("x", 250)+"{{ .TechnicalID }}", "shoot--foo--bar")
+		Expect(err).To(MatchError(ContainSubstring("must not be longer than 255 characters")))
+	})
+})
+
+var _ = Describe("#DesiredSecurityGroupRules", func() {
+	It("should return the rules of the security group of the nodes", func() {
+		Expect(DesiredSecurityGroupRules(SecurityGroupRulesOptions{
+			NodePortsCIDR:              "0.0.0.0/0",
+			PodCIDR:                    new("100.96.0.0/11"),
+			AllowMetadataServiceEgress: true,
+			AllowIPv6Egress:            true,
+		})).To(Equal([]SecurityGroupRule{
+			{Direction: "ingress", EtherType: "IPv4", RemoteSelf: true, Description: "IPv4: allow all incoming traffic within the same security group"},
+			{Direction: "egress", EtherType: "IPv4", Description: "IPv4: allow all outgoing traffic"},
+			{Direction: "ingress", EtherType: "IPv4", Protocol: "tcp", PortRangeMin: 30000, PortRangeMax: 32767, RemoteIPPrefix: "0.0.0.0/0", Description: "IPv4: allow all incoming tcp traffic with port range 30000-32767"},
+			{Direction: "ingress", EtherType: "IPv4", Protocol: "udp", PortRangeMin: 30000, PortRangeMax: 32767, RemoteIPPrefix: "0.0.0.0/0", Description: "IPv4: allow all incoming udp traffic with port range 30000-32767"},
+			{Direction: "ingress", EtherType: "IPv4", RemoteIPPrefix: "100.96.0.0/11", Description: "IPv4: allow all incoming traffic from cluster pod CIDR"},
+			{Direction: "egress", EtherType: "IPv4", Protocol: "tcp", PortRangeMin: 80, PortRangeMax: 80, RemoteIPPrefix: "169.254.169.254/32", Description: "IPv4: allow outgoing tcp traffic to the metadata service"},
+			{Direction: "egress", EtherType: "IPv6", Description: "IPv6: allow all outgoing traffic"},
+		}))
+	})
+
+	It("should skip the optional rules", func() {
+		Expect(DesiredSecurityGroupRules(SecurityGroupRulesOptions{NodePortsCIDR: "10.250.0.0/16"})).To(HaveExactElements(
+			HaveField("RemoteSelf", BeTrue()),
+			HaveField("Direction", "egress"),
+			HaveField("Protocol", "tcp"),
+			HaveField("Protocol", "udp"),
+		))
+	})
+})
+
+var _ = Describe("#AdditionalSecurityGroupRules", func() {
+	It("should generate a prefixed description", func() {
+		Expect(AdditionalSecurityGroupRules([]stackitv1alpha1.SecurityGroupRuleConfig{{
+			Direction:      "ingress",
+			Protocol:       new("udp"),
+			PortRangeMin:   new(int32(1194)),
+			RemoteIPPrefix: new("192.168.0.0/24"),
+		}})).To(ConsistOf(HaveField("Description", "gardener-additional: IPv4: allow incoming udp traffic with port range 1194-1194 from 192.168.0.0/24")))
+	})
+
+	It("should keep a custom description", func() {
+		Expect(AdditionalSecurityGroupRules([]stackitv1alpha1.SecurityGroupRuleConfig{{
+			Direction:   "ingress",
+			Description: new("peering"),
+		}})).To(ConsistOf(HaveField("Description", "gardener-additional: peering")))
+	})
+
+	It("should truncate a generated description exceeding the maximum length", func() {
+		rules := AdditionalSecurityGroupRules([]stackitv1alpha1.SecurityGroupRuleConfig{{
+			Direction:             "ingress",
+			EtherType:             new("IPv6"),
+			Protocol:              new("udplite"),
+			PortRangeMin:          new(int32(10000)),
+			PortRangeMax:          new(int32(20000)),
+			RemoteSecurityGroupID: new("0b5c4fd8-2d2c-4a4e-9d57-0f1a2b3c4d5e"),
+		}})
+
+		Expect(rules).To(HaveLen(1))
+		Expect(rules[0].Description).To(HaveLen(stackit.MaxSecurityGroupRuleDescriptionLength))
+		Expect(rules[0].Description).To(HavePrefix("gardener-additional: IPv6: allow incoming udplite traffic with port range 10000-20000 from security group 0b5c4fd8"))
+		Expect(IsAdditionalSecurityGroupRule(rules[0].Description)).To(BeTrue())
+	})
+})
+
+var _ = Describe("#IntraGroupRules", func() {
+	It("should allow all traffic within the security group by default", func() {
+		Expect(IntraGroupRules(nil)).To(ConsistOf(And(
+			HaveField("Direction", "ingress"),
+			HaveField("RemoteSelf", BeTrue()),
+			HaveField("Protocol", BeEmpty()),
+			HaveField("PortRangeMin", BeZero()),
+		)))
+	})
+
+	It("should only allow the configured ports within the security group", func() {
+		rules := IntraGroupRules(&stackitv1alpha1.IntraNodeTraffic{
+			Ports: []stackitv1alpha1.IntraNodePort{
+				{Protocol: "tcp", Min: new(int32(10250))},
+				{Protocol: "udp", Min: new(int32(8472)), Max: new(int32(8473))},
+				{Protocol: "ipip"},
+			},
+		})
+
+		Expect(rules).To(HaveExactElements(
+			And(
+				HaveField("Protocol", "tcp"),
+				HaveField("PortRangeMin", 10250),
+				HaveField("PortRangeMax", 10250),
+			),
+			And(
+				HaveField("Protocol", "udp"),
+				HaveField("PortRangeMin", 8472),
+				HaveField("PortRangeMax", 8473),
+			),
+			And(
+				HaveField("Protocol", "ipip"),
+				HaveField("PortRangeMin", BeZero()),
+			),
+		))
+		Expect(rules).To(HaveEach(HaveField("RemoteSelf", BeTrue())))
+		Expect(rules).To(HaveEach(HaveField("Description", WithTransform(IsIntraGroupRule, BeTrue()))))
+	})
+})
+
+var _ = Describe("#IsIntraGroupRule", func() {
+	It("should only match the descriptions of the rules within the security group", func() {
+		Expect(IsIntraGroupRule("IPv4: allow all incoming traffic within the same security group")).To(BeTrue())
+		Expect(IsIntraGroupRule("IPv4: allow all incoming traffic from cluster pod CIDR")).To(BeFalse())
+		Expect(IsIntraGroupRule("")).To(BeFalse())
+	})
+})
+
+var _ = Describe("SecurityGroupRule adapters", func() {
+	specs := DesiredSecurityGroupRules(SecurityGroupRulesOptions{
+		NodePortsCIDR: "0.0.0.0/0",
+		PodCIDR:       new("100.96.0.0/11"),
+		IntraNodeTraffic: &stackitv1alpha1.IntraNodeTraffic{
+			Ports: []stackitv1alpha1.IntraNodePort{{Protocol: "tcp", Min: new(int32(10250))}, {Protocol: "ipip"}},
+		},
+		AllowMetadataServiceEgress: true,
+	})
+
+	It("should convert the rules to equivalent OpenStack and STACKIT rules", func() {
+		openStackRules := ToOpenStackSecurityGroupRules(specs, "self")
+		stackitRules := ToSTACKITSecurityGroupRules(specs, "group-id")
+
+		Expect(openStackRules).To(HaveLen(len(specs)))
+		Expect(stackitRules).To(HaveLen(len(specs)))
+		for i := range specs {
+			openStackRule, stackitRule := openStackRules[i], stackitRules[i]
+			Expect(stackitRule.Direction).To(Equal(openStackRule.Direction))
+			Expect(stackitRule.GetEthertype()).To(Equal(openStackRule.EtherType))
+			Expect(ptr.Deref(stackitRule.GetProtocol().Name, "")).To(Equal(openStackRule.Protocol))
+			Expect(int(stackitRule.GetPortRange().Min)).To(Equal(openStackRule.PortRangeMin))
+			Expect(int(stackitRule.GetPortRange().Max)).To(Equal(openStackRule.PortRangeMax))
+			Expect(stackitRule.GetIpRange()).To(Equal(openStackRule.RemoteIPPrefix))
+			Expect(stackitRule.HasRemoteSecurityGroupId()).To(Equal(openStackRule.RemoteGroupID != ""))
+			Expect(stackitRule.GetDescription()).To(Equal(openStackRule.Description))
+		}
+	})
+
+	It("should reference the given group for rules within the security group", func() {
+		Expect(ToOpenStackSecurityGroupRules(specs[:1], "self")).To(Equal([]rules.SecGroupRule{{
+			Direction:     "ingress",
+			EtherType:     "IPv4",
+			Protocol:      "tcp",
+			PortRangeMin:  10250,
+			PortRangeMax:  10250,
+			RemoteGroupID: "self",
+			Description:   "IPv4: allow incoming tcp traffic with port range 10250-10250 within the same security group",
+		}}))
+		Expect(ToSTACKITSecurityGroupRules(specs[:1], "group-id")).To(Equal([]iaas.SecurityGroupRule{{
+			Direction:             "ingress",
+			Ethertype:             new("IPv4"),
+			Protocol:              &iaas.Protocol{Name: new("tcp")},
+			PortRange:             &iaas.PortRange{Min: 10250, Max: 10250},
+			RemoteSecurityGroupId: new("group-id"),
+			Description:           new("IPv4: allow incoming tcp traffic with port range 10250-10250 within the same security group"),
+		}}))
+	})
+})
@@ -5,9 +5,13 @@ import (
 	"errors"
 	"fmt"
 	"slices"
+	"time"
 
+	gardenv1beta1helper "github.com/gardener/gardener/pkg/api/core/v1beta1/helper"
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
 	"github.com/gophercloud/gophercloud/v2/openstack/networking/v2/extensions/layer3/routers"
 	"github.com/gophercloud/gophercloud/v2/openstack/networking/v2/subnets"
+	"k8s.io/apimachinery/pkg/util/wait"
 
 	stackitv1alpha1 "github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/apis/stackit/v1alpha1"
 	osclient "github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/openstack/client"
@@ -27,23 +31,92 @@ const (
 	LabelAreaID = "stackit.cloud/area-id"
 )
 
+// snaConfigBackoff is the backoff of fetching the SNA config if the networking API is transiently unavailable.
+var snaConfigBackoff = wait.Backoff{
+	Duration: 2 * time.Second,
+	Factor:   2,
+	Steps:    4,
+}
+
+// snaRequestError is a failed request to the networking API while fetching the SNA config. In contrast to the other
+// errors of the SNA config lookup, it is considered transient.
+type snaRequestError struct {
+	err error
+}
+
+func (e *snaRequestError) Error() string {
+	return e.err.Error()
+}
+
+func (e *snaRequestError) Unwrap() error {
+	return e.err
+}
+
+// newSNARequestError wraps the given error of a request to the networking API. Not found errors are not transient, as
+// they are caused by a network or router that does not exist.
+func newSNARequestError(err error) error {
+	if osclient.IsNotFoundError(err) {
+		return err
+	}
+	return &snaRequestError{err: err}
+}
+
+// GetSNAConfigFromNetworkID determines the SNAConfig of the given network. Transient failures of the networking API
+// are retried with an exponential backoff and reported as retryable error if all attempts fail. If the network is not
+// set up as part of a network area, a configuration problem is returned.
 func GetSNAConfigFromNetworkID(ctx context.Context, networking osclient.Networking, networkID *string) (*SNAConfig, error) {
 	if networkID == nil {
-		return nil, fmt.Errorf("no networkID available")
+		return nil, gardenv1beta1helper.NewErrorWithCodes(fmt.Errorf("no networkID available"), gardencorev1beta1.ErrorConfigurationProblem)
 	}
 
-	subnet, err := getSubnet(ctx, networking, *networkID)
+	var (
+		snaConfig *SNAConfig
+		lastErr   error
+	)
+	err := wait.ExponentialBackoffWithContext(ctx, snaConfigBackoff, func(ctx context.Context) (bool, error) {
+		snaConfig, lastErr = getSNAConfig(ctx, networking, *networkID)
+		if lastErr == nil {
+			return true, nil
+		}
+		var requestErr *snaRequestError
+		if !errors.As(lastErr, &requestErr) {
+			return false, lastErr
+		}
+		return false, nil
+	})
+
+	var requestErr *snaRequestError
+	switch {
+	case err == nil:
+		return snaConfig, nil
+	case lastErr == nil:
+		return nil, err
+	case errors.As(lastErr, &requestErr):
+		return nil, gardenv1beta1helper.NewErrorWithCodes(
+			fmt.Errorf("network area API is unavailable, could not fetch the SNA config of network %s: %w", *networkID, lastErr),
+			gardencorev1beta1.ErrorRetryableInfraDependencies,
+		)
+	default:
+		return nil, gardenv1beta1helper.NewErrorWithCodes(
+			fmt.Errorf("network %s is not part of a network area: %w", *networkID, lastErr),
+			gardencorev1beta1.ErrorConfigurationProblem,
+		)
+	}
+}
+
+func getSNAConfig(ctx context.Context, networking osclient.Networking, networkID string) (*SNAConfig, error) {
+	subnet, err := getSubnet(ctx, networking, networkID)
 	if err != nil {
 		return nil, err
 	}
 
-	routerID, err := getSNARouterIDFromNetworkID(ctx, networking, *networkID)
+	routerID, err := getSNARouterIDFromNetworkID(ctx, networking, networkID)
 	if err != nil {
 		return nil, err
 	}
 
 	return &SNAConfig{
-		NetworkID:   *networkID,
+		NetworkID:   networkID,
 		RouterID:    routerID,
 		SubnetID:    subnet.ID,
 		WorkersCIDR: subnet.CIDR,
@@ -53,14 +126,14 @@ func GetSNAConfigFromNetworkID(ctx context.Context, networking osclient.Networki
 func getSNARouterIDFromNetworkID(ctx context.Context, networking osclient.Networking, networkID string) (string, error) {
 	list, err := networking.GetRouterInterfacePortsByNetwork(ctx, networkID)
 	if err != nil {
-		return "", fmt.Errorf("failed to list ports for network %s: %w", networkID, err)
+		return "", newSNARequestError(fmt.Errorf("failed to list ports for network %s: %w", networkID, err))
 	}
 
 	filtered := make([]*routers.Router, 0, len(list))
 	for _, port := range list {
 		router, err := networking.GetRouterByID(ctx, port.DeviceID)
 		if err != nil {
-			return "", fmt.Errorf("failed to resolve router %s: %w", port.DeviceID, err)
+			return "", newSNARequestError(fmt.Errorf("failed to resolve router %s: %w", port.DeviceID, err))
 		}
 		if len(router.GatewayInfo.ExternalFixedIPs) == 0 {
 			continue
@@ -105,9 +178,8 @@ func IsSNAShoot(labels map[string]string) bool {
 
 func getSubnet(ctx context.Context, networking osclient.Networking, networkID string) (*subnets.Subnet, error) {
 	snets, err := networking.ListSubnets(ctx, subnets.ListOpts{NetworkID: networkID})
-
 	if err != nil {
-		return nil, fmt.Errorf("error retrieving routers: %w", err)
+		return nil, newSNARequestError(fmt.Errorf("error retrieving subnets: %w", err))
 	}
 	if len(snets) == 0 {
 		return nil, fmt.Errorf("no subnets available")
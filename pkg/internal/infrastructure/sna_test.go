@@ -3,13 +3,19 @@ package infrastructure
 import (
 	"context"
 	"errors"
+	"net/http"
+	"time"
 
+	gardencorev1beta1helper "github.com/gardener/gardener/pkg/api/core/v1beta1/helper"
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	"github.com/gophercloud/gophercloud/v2"
 	"github.com/gophercloud/gophercloud/v2/openstack/networking/v2/extensions/layer3/routers"
 	"github.com/gophercloud/gophercloud/v2/openstack/networking/v2/ports"
 	"github.com/gophercloud/gophercloud/v2/openstack/networking/v2/subnets"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"go.uber.org/mock/gomock"
+	"k8s.io/apimachinery/pkg/util/wait"
 
 	stackitv1alpha1 "github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/apis/stackit/v1alpha1"
 	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/openstack/client/mocks"
@@ -150,21 +156,68 @@ var _ = Describe("SNA", func() {
 	})
 
 	Context("get sna config", func() {
+		BeforeEach(func() {
+			DeferCleanup(func(backoff wait.Backoff) { snaConfigBackoff = backoff }, snaConfigBackoff)
+			snaConfigBackoff = wait.Backoff{Duration: time.Millisecond, Steps: 3}
+		})
+
+		expectConfigurationProblem := func(err error) {
+			GinkgoHelper()
+			coder, ok := err.(gardencorev1beta1helper.Coder)
+			Expect(ok).To(BeTrue())
+			Expect(coder.Codes()).To(ConsistOf(gardencorev1beta1.ErrorConfigurationProblem))
+		}
+
 		It("should err for nil networkID", func() {
 			_, err := GetSNAConfigFromNetworkID(ctx, nw, nil)
 			Expect(err).To(HaveOccurred())
+			expectConfigurationProblem(err)
 		})
-		It("should err on subnet lookup error", func() {
-			nw.EXPECT().ListSubnets(ctx, subnets.ListOpts{NetworkID: networkID}).Return(nil, errors.New("subnet error"))
+		It("should retry on transient subnet lookup errors", func() {
+			gomock.InOrder(
+				nw.EXPECT().ListSubnets(ctx, subnets.ListOpts{NetworkID: networkID}).Return(nil, errors.New("subnet error")),
+				nw.EXPECT().ListSubnets(ctx, subnets.ListOpts{NetworkID: networkID}).Return(
+					[]subnets.Subnet{{ID: subnetID, CIDR: subnetCIDR}}, nil),
+			)
+			nw.EXPECT().GetRouterInterfacePortsByNetwork(ctx, networkID).Return([]ports.Port{{DeviceID: routerID}}, nil)
+			addMockRouter(nw, routerID, []string{"SNA"})
+			config, err := GetSNAConfigFromNetworkID(ctx, nw, &networkID)
+			Expect(err).To(Succeed())
+			Expect(config.SubnetID).To(Equal(subnetID))
+		})
+		It("should report a retryable error if the networking API stays unavailable", func() {
+			nw.EXPECT().ListSubnets(ctx, subnets.ListOpts{NetworkID: networkID}).Return(
+				[]subnets.Subnet{{ID: subnetID, CIDR: subnetCIDR}}, nil).Times(3)
+			nw.EXPECT().GetRouterInterfacePortsByNetwork(ctx, networkID).Return(nil, errors.New("router error")).Times(3)
 			_, err := GetSNAConfigFromNetworkID(ctx, nw, &networkID)
-			Expect(err).To(HaveOccurred())
+			Expect(err).To(MatchError(And(ContainSubstring("network area API is unavailable"), ContainSubstring("router error"))))
+			coder, ok := err.(gardencorev1beta1helper.Coder)
+			Expect(ok).To(BeTrue())
+			Expect(coder.Codes()).To(ConsistOf(gardencorev1beta1.ErrorRetryableInfraDependencies))
+		})
+		It("should report a configuration problem if the network has no subnet", func() {
+			nw.EXPECT().ListSubnets(ctx, subnets.ListOpts{NetworkID: networkID}).Return([]subnets.Subnet{}, nil)
+			_, err := GetSNAConfigFromNetworkID(ctx, nw, &networkID)
+			Expect(err).To(MatchError(ContainSubstring("network " + networkID + " is not part of a network area")))
+			expectConfigurationProblem(err)
 		})
-		It("should err on router lookup error", func() {
+		It("should report a configuration problem if the router does not exist", func() {
 			nw.EXPECT().ListSubnets(ctx, subnets.ListOpts{NetworkID: networkID}).Return(
 				[]subnets.Subnet{{ID: subnetID, CIDR: subnetCIDR}}, nil)
-			nw.EXPECT().GetRouterInterfacePortsByNetwork(ctx, networkID).Return(nil, errors.New("router error"))
+			nw.EXPECT().GetRouterInterfacePortsByNetwork(ctx, networkID).Return([]ports.Port{{DeviceID: routerID}}, nil)
+			nw.EXPECT().GetRouterByID(ctx, routerID).Return(nil, gophercloud.ErrUnexpectedResponseCode{Actual: http.StatusNotFound})
 			_, err := GetSNAConfigFromNetworkID(ctx, nw, &networkID)
-			Expect(err).To(HaveOccurred())
+			Expect(err).To(MatchError(ContainSubstring("is not part of a network area")))
+			expectConfigurationProblem(err)
+		})
+		It("should report a configuration problem if no SNA router exists", func() {
+			nw.EXPECT().ListSubnets(ctx, subnets.ListOpts{NetworkID: networkID}).Return(
+				[]subnets.Subnet{{ID: subnetID, CIDR: subnetCIDR}}, nil)
+			nw.EXPECT().GetRouterInterfacePortsByNetwork(ctx, networkID).Return([]ports.Port{{DeviceID: routerID}}, nil)
+			addMockRouter(nw, routerID, nil)
+			_, err := GetSNAConfigFromNetworkID(ctx, nw, &networkID)
+			Expect(err).To(MatchError(ContainSubstring("found non-SNA router with external gateway")))
+			expectConfigurationProblem(err)
 		})
 		It("should succeed for proper network setup", func() {
 			nw.EXPECT().ListSubnets(ctx, subnets.ListOpts{NetworkID: networkID}).Return(
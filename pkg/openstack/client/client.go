@@ -14,6 +14,7 @@ import (
 
 	"github.com/gophercloud/gophercloud/v2"
 	"github.com/gophercloud/gophercloud/v2/openstack"
+	"github.com/gophercloud/gophercloud/v2/openstack/identity/v3/tokens"
 	"github.com/gophercloud/utils/v2/openstack/clientconfig"
 	corev1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -84,8 +85,16 @@ func NewOpenstackClientFromCredentials(ctx context.Context, credentials *os.Cred
 
 	provider.UserAgent.Prepend("Gardener Extension for STACKIT provider")
 
+	var projectID string
+	if result, ok := provider.GetAuthResult().(tokens.CreateResult); ok {
+		if project, err := result.ExtractProject(); err == nil && project != nil {
+			projectID = project.ID
+		}
+	}
+
 	return &OpenstackClientFactory{
 		providerClient: provider,
+		projectID:      projectID,
 	}, nil
 }
 
@@ -111,6 +120,11 @@ func WithRegion(region string) Option {
 	}
 }
 
+// ProjectID returns the ID of the project the clients are authenticated for.
+func (oc *OpenstackClientFactory) ProjectID() string {
+	return oc.projectID
+}
+
 // Storage returns a Storage client. The client uses Swift v1 API for issuing calls.
 func (oc *OpenstackClientFactory) Storage(options ...Option) (Storage, error) {
 	eo := gophercloud.EndpointOpts{}
@@ -238,6 +252,19 @@ func IsConflict(err error) bool {
 	return false
 }
 
+// IsBadRequest checks if an error returned by OpenStack is caused by HTTP 400 status code.
+func IsBadRequest(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	if gophercloud.ResponseCodeIs(err, http.StatusBadRequest) {
+		return true
+	}
+
+	return false
+}
+
 // IgnoreNotFoundError ignore not found error
 func IgnoreNotFoundError(err error) error {
 	if IsNotFoundError(err) {
@@ -148,6 +148,20 @@ func (mr *MockFactoryMockRecorder) Networking(options ...any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Networking", reflect.TypeOf((*MockFactory)(nil).Networking), options...)
 }
 
+// ProjectID mocks base method.
+func (m *MockFactory) ProjectID() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProjectID")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ProjectID indicates an expected call of ProjectID.
+func (mr *MockFactoryMockRecorder) ProjectID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProjectID", reflect.TypeOf((*MockFactory)(nil).ProjectID))
+}
+
 // Storage mocks base method.
 func (m *MockFactory) Storage(options ...client.Option) (client.Storage, error) {
 	m.ctrl.T.Helper()
@@ -686,34 +700,34 @@ func (mr *MockNetworkingMockRecorder) DeleteSubnet(ctx, subnetID any) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSubnet", reflect.TypeOf((*MockNetworking)(nil).DeleteSubnet), ctx, subnetID)
 }
 
-// GetExternalNetworkByName mocks base method.
-func (m *MockNetworking) GetExternalNetworkByName(ctx context.Context, name string) (*networks.Network, error) {
+// GetExternalNetworkNames mocks base method.
+func (m *MockNetworking) GetExternalNetworkNames(ctx context.Context) ([]string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetExternalNetworkByName", ctx, name)
-	ret0, _ := ret[0].(*networks.Network)
+	ret := m.ctrl.Call(m, "GetExternalNetworkNames", ctx)
+	ret0, _ := ret[0].([]string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetExternalNetworkByName indicates an expected call of GetExternalNetworkByName.
-func (mr *MockNetworkingMockRecorder) GetExternalNetworkByName(ctx, name any) *gomock.Call {
+// GetExternalNetworkNames indicates an expected call of GetExternalNetworkNames.
+func (mr *MockNetworkingMockRecorder) GetExternalNetworkNames(ctx any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExternalNetworkByName", reflect.TypeOf((*MockNetworking)(nil).GetExternalNetworkByName), ctx, name)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExternalNetworkNames", reflect.TypeOf((*MockNetworking)(nil).GetExternalNetworkNames), ctx)
 }
 
-// GetExternalNetworkNames mocks base method.
-func (m *MockNetworking) GetExternalNetworkNames(ctx context.Context) ([]string, error) {
+// GetExternalNetworksByName mocks base method.
+func (m *MockNetworking) GetExternalNetworksByName(ctx context.Context, name string) ([]networks.Network, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetExternalNetworkNames", ctx)
-	ret0, _ := ret[0].([]string)
+	ret := m.ctrl.Call(m, "GetExternalNetworksByName", ctx, name)
+	ret0, _ := ret[0].([]networks.Network)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetExternalNetworkNames indicates an expected call of GetExternalNetworkNames.
-func (mr *MockNetworkingMockRecorder) GetExternalNetworkNames(ctx any) *gomock.Call {
+// GetExternalNetworksByName indicates an expected call of GetExternalNetworksByName.
+func (mr *MockNetworkingMockRecorder) GetExternalNetworksByName(ctx, name any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExternalNetworkNames", reflect.TypeOf((*MockNetworking)(nil).GetExternalNetworkNames), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExternalNetworksByName", reflect.TypeOf((*MockNetworking)(nil).GetExternalNetworksByName), ctx, name)
 }
 
 // GetFipByName mocks base method.
@@ -6,7 +6,6 @@ package client
 
 import (
 	"context"
-	"fmt"
 	"slices"
 
 	"github.com/gophercloud/gophercloud/v2/openstack/networking/v2/extensions/attributestags"
@@ -56,19 +55,17 @@ func (c *NetworkingClient) listExternalNetworks(ctx context.Context, listOpts ne
 	return externalNetworks, nil
 }
 
-// GetExternalNetworkByName returns an external network by name
-func (c *NetworkingClient) GetExternalNetworkByName(ctx context.Context, name string) (*networks.Network, error) {
+// GetExternalNetworksByName returns all external networks with the given name
+func (c *NetworkingClient) GetExternalNetworksByName(ctx context.Context, name string) ([]networks.Network, error) {
 	externalNetworks, err := c.listExternalNetworks(ctx, networks.ListOpts{Name: name})
 	if err != nil {
 		return nil, err
 	}
-	if len(externalNetworks) == 0 {
-		return nil, nil
-	}
-	if len(externalNetworks) > 1 {
-		return nil, fmt.Errorf("duplicate external network name: %s (%d)", name, len(externalNetworks))
+	result := make([]networks.Network, 0, len(externalNetworks))
+	for _, externalNetwork := range externalNetworks {
+		result = append(result, externalNetwork.Network)
 	}
-	return &externalNetworks[0].Network, nil
+	return result, nil
 }
 
 // ListNetwork returns a list of all network info by listOpts
@@ -28,6 +28,7 @@ import (
 // OpenstackClientFactory implements a factory that can construct clients for Openstack services.
 type OpenstackClientFactory struct {
 	providerClient *gophercloud.ProviderClient
+	projectID      string
 }
 
 // StorageClient is a client for the Swift service.
@@ -71,6 +72,9 @@ type Factory interface {
 	Networking(options ...Option) (Networking, error)
 	Loadbalancing(options ...Option) (Loadbalancing, error)
 	Images(options ...Option) (Images, error)
+	// ProjectID returns the ID of the project the clients are authenticated for. It is empty if the identity service
+	// did not return a project scoped token.
+	ProjectID() string
 }
 
 // Storage describes the operations of a client interacting with OpenStack's ObjectStorage service.
@@ -112,7 +116,7 @@ type DNS interface {
 type Networking interface {
 	// External Network
 	GetExternalNetworkNames(ctx context.Context) ([]string, error)
-	GetExternalNetworkByName(ctx context.Context, name string) (*networks.Network, error)
+	GetExternalNetworksByName(ctx context.Context, name string) ([]networks.Network, error)
 	// Network
 	CreateNetwork(ctx context.Context, opts networks.CreateOpts) (*networks.Network, error)
 	ListNetwork(ctx context.Context, listOpts networks.ListOpts) ([]networks.Network, error)
@@ -97,6 +97,31 @@ func ExtractCredentials(secret *corev1.Secret, allowDNSKeys bool) (*Credentials,
 	}, nil
 }
 
+// credentialKeys are the keys of the OpenStack credentials in a provider secret.
+var credentialKeys = []string{
+	DomainName,
+	TenantName,
+	UserName,
+	Password,
+	ApplicationCredentialID,
+	ApplicationCredentialName,
+	ApplicationCredentialSecret,
+	AuthURL,
+	CACert,
+	Insecure,
+}
+
+// CredentialKeys returns the keys of OpenStack credentials which are set in the given provider secret.
+func CredentialKeys(secret *corev1.Secret) []string {
+	var keys []string
+	for _, key := range credentialKeys {
+		if len(secret.Data[key]) > 0 {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
 // ValidateSecrets checks if either basic auth or application credentials are completely provided
 func ValidateSecrets(userName, password, appID, appName, appSecret string) error {
 	if password != "" {
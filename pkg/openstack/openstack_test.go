@@ -7,6 +7,7 @@ package openstack_test
 import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
 
 	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/openstack"
 )
@@ -17,6 +18,22 @@ var _ = Describe("Openstack", func() {
 	testAppID := "appID"
 	testAppName := "appName"
 	testAppSecret := "appSecret"
+	Describe("CredentialKeys", func() {
+		It("should return the OpenStack credential keys set in the secret", func() {
+			secret := &corev1.Secret{Data: map[string][]byte{
+				openstack.DomainName: []byte("domain"),
+				openstack.TenantName: []byte("tenant"),
+				openstack.Password:   []byte(""),
+				"project-id":         []byte("project"),
+			}}
+			Expect(openstack.CredentialKeys(secret)).To(Equal([]string{openstack.DomainName, openstack.TenantName}))
+		})
+
+		It("should return nothing for a secret without OpenStack credentials", func() {
+			Expect(openstack.CredentialKeys(&corev1.Secret{Data: map[string][]byte{"project-id": []byte("project")}})).To(BeEmpty())
+		})
+	})
+
 	Describe("ValidateSecrets", func() {
 		It("should fail if both basic auth and app credentials are provided", func() {
 			err := openstack.ValidateSecrets(testUser, testPassword, testAppID, testAppName, testAppSecret)
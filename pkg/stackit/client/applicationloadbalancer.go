@@ -5,6 +5,7 @@ import (
 
 	sdkconfig "github.com/stackitcloud/stackit-sdk-go/core/config"
 	alb "github.com/stackitcloud/stackit-sdk-go/services/alb/v2api"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	stackitv1alpha1 "github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/apis/stackit/v1alpha1"
 	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/stackit"
@@ -23,8 +24,8 @@ type applicationLoadBalancingClient struct {
 	region    string
 }
 
-func NewApplicationLoadBalancingClient(_ context.Context, region string, endpoints stackitv1alpha1.APIEndpoints, credentials *stackit.Credentials, caBundle string) (ApplicationLoadBalancingClient, error) {
-	options, err := clientOptions(endpoints, credentials, caBundle)
+func NewApplicationLoadBalancingClient(_ context.Context, region string, endpoints stackitv1alpha1.APIEndpoints, credentials *stackit.Credentials, caBundle string, requestTimeout *metav1.Duration) (ApplicationLoadBalancingClient, error) {
+	options, err := clientOptions(endpoints, credentials, caBundle, requestTimeout)
 	if err != nil {
 		return nil, err
 	}
@@ -48,14 +49,16 @@ func (l applicationLoadBalancingClient) ProjectID() string {
 }
 
 func (l applicationLoadBalancingClient) ListLoadBalancers(ctx context.Context) ([]alb.LoadBalancer, error) {
+	ctx, withRequestID := captureRequestID(ctx)
 	lbResponse, err := l.Client.ListLoadBalancers(ctx, l.projectID, l.region).Execute()
 	if err != nil {
-		return nil, err
+		return nil, withRequestID(err)
 	}
 	return lbResponse.GetLoadBalancers(), nil
 }
 
 func (l applicationLoadBalancingClient) DeleteLoadBalancer(ctx context.Context, name string) error {
+	ctx, withRequestID := captureRequestID(ctx)
 	_, err := l.Client.DeleteLoadBalancer(ctx, l.projectID, l.region, name).Execute()
-	return err
+	return withRequestID(err)
 }
@@ -5,6 +5,7 @@ import (
 
 	sdkconfig "github.com/stackitcloud/stackit-sdk-go/core/config"
 	albcert "github.com/stackitcloud/stackit-sdk-go/services/certificates/v2api"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	stackitv1alpha1 "github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/apis/stackit/v1alpha1"
 	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/stackit"
@@ -23,8 +24,8 @@ type applicationLoadBalancingCertificateClient struct {
 	region    string
 }
 
-func NewApplicationLoadBalancerCertificateClient(_ context.Context, region string, endpoints stackitv1alpha1.APIEndpoints, credentials *stackit.Credentials, caBundle string) (ApplicationLoadBalancerCertificateClient, error) {
-	options, err := clientOptions(endpoints, credentials, caBundle)
+func NewApplicationLoadBalancerCertificateClient(_ context.Context, region string, endpoints stackitv1alpha1.APIEndpoints, credentials *stackit.Credentials, caBundle string, requestTimeout *metav1.Duration) (ApplicationLoadBalancerCertificateClient, error) {
+	options, err := clientOptions(endpoints, credentials, caBundle, requestTimeout)
 	if err != nil {
 		return nil, err
 	}
@@ -48,14 +49,16 @@ func (l applicationLoadBalancingCertificateClient) ProjectID() string {
 }
 
 func (l applicationLoadBalancingCertificateClient) ListApplicationLoadBalancerCertificates(ctx context.Context) ([]albcert.GetCertificateResponse, error) {
+	ctx, withRequestID := captureRequestID(ctx)
 	certResponse, err := l.Client.ListCertificates(ctx, l.projectID, l.region).Execute()
 	if err != nil {
-		return nil, err
+		return nil, withRequestID(err)
 	}
 	return certResponse.GetItems(), nil
 }
 
 func (l applicationLoadBalancingCertificateClient) DeleteApplicationLoadBalancerCertificates(ctx context.Context, id string) error {
+	ctx, withRequestID := captureRequestID(ctx)
 	_, err := l.Client.DeleteCertificate(ctx, l.projectID, l.region, id).Execute()
-	return err
+	return withRequestID(err)
 }
@@ -7,14 +7,15 @@ import (
 
 	sdkconfig "github.com/stackitcloud/stackit-sdk-go/core/config"
 	dns "github.com/stackitcloud/stackit-sdk-go/services/dns/v1api"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/set"
 
 	stackitv1alpha1 "github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/apis/stackit/v1alpha1"
 	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/stackit"
 )
 
-func NewDNSClient(_ context.Context, endpoints stackitv1alpha1.APIEndpoints, credentials *stackit.Credentials, caBundle string) (DNSClient, error) {
-	options, err := clientOptions(endpoints, credentials, caBundle)
+func NewDNSClient(_ context.Context, endpoints stackitv1alpha1.APIEndpoints, credentials *stackit.Credentials, caBundle string, requestTimeout *metav1.Duration) (DNSClient, error) {
+	options, err := clientOptions(endpoints, credentials, caBundle, requestTimeout)
 	if err != nil {
 		return nil, err
 	}
@@ -52,9 +53,10 @@ type dnsClient struct {
 }
 
 func (c *dnsClient) ListZones(ctx context.Context) ([]DNSZone, error) {
+	ctx, withRequestID := captureRequestID(ctx)
 	dnsZonesResp, err := c.api.ListZones(ctx, c.projectID).Execute()
 	if err != nil {
-		return nil, err
+		return nil, withRequestID(err)
 	}
 
 	if dnsZonesResp == nil {
@@ -102,6 +104,7 @@ func (c *dnsClient) CreateOrUpdateRecordSet(ctx context.Context,
 		return fmt.Errorf("invalid DNS record type %q for create payload: %w", recordType, err)
 	}
 
+	ctx, withRequestID := captureRequestID(ctx)
 	if recordSet == nil {
 		_, err := c.api.CreateRecordSet(ctx, c.projectID, zoneID).CreateRecordSetPayload(dns.CreateRecordSetPayload{
 			Name:    name,
@@ -110,7 +113,7 @@ func (c *dnsClient) CreateOrUpdateRecordSet(ctx context.Context,
 			Ttl:     new(cacheTTL),
 		}).Execute()
 		if err != nil {
-			return fmt.Errorf("failed to create record set: %w", err)
+			return fmt.Errorf("failed to create record set: %w", withRequestID(err))
 		}
 		return nil
 	}
@@ -126,7 +129,7 @@ func (c *dnsClient) CreateOrUpdateRecordSet(ctx context.Context,
 		Ttl:     new(cacheTTL),
 	}).Execute()
 	if err != nil {
-		return fmt.Errorf("failed to update record set: %w", err)
+		return fmt.Errorf("failed to update record set: %w", withRequestID(err))
 	}
 
 	return nil
@@ -146,17 +149,19 @@ func (c *dnsClient) DeleteRecordSet(ctx context.Context, zoneID, name, recordTyp
 		return nil
 	}
 
+	ctx, withRequestID := captureRequestID(ctx)
 	_, err = c.api.DeleteRecordSet(ctx, c.projectID, zoneID, recordSet.GetId()).Execute()
 	if err != nil {
-		return fmt.Errorf("failed to delete record set: %w", err)
+		return fmt.Errorf("failed to delete record set: %w", withRequestID(err))
 	}
 	return nil
 }
 
 func (c *dnsClient) findRecordSet(ctx context.Context, zoneID, name string, recordType *dns.RecordSetType) (*dns.RecordSet, error) {
+	ctx, withRequestID := captureRequestID(ctx)
 	resp, err := c.api.ListRecordSets(ctx, c.projectID, zoneID).Execute()
 	if err != nil {
-		return nil, err
+		return nil, withRequestID(err)
 	}
 	// in case either name is a FQDN we remove the trailing dot
 	name = strings.TrimSuffix(name, ".")
@@ -1,11 +1,18 @@
 package client
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
+
+	sdkruntime "github.com/stackitcloud/stackit-sdk-go/core/runtime"
 )
 
+// RequestIDHeader is the header of STACKIT API responses containing the ID of the request. STACKIT support needs this
+// ID to investigate failed requests.
+const RequestIDHeader = "X-Request-Id"
+
 // StatusCodeError is a common interface implemented by Error and the SDK's GenericOpenAPIError.
 type StatusCodeError interface {
 	error
@@ -64,3 +71,49 @@ func IgnoreNotFoundError(err error) error {
 func IsConflictError(err error) bool {
 	return GetStatusCode(err) == http.StatusConflict
 }
+
+// RequestIDError wraps an error of a failed STACKIT API request with the ID of the request.
+type RequestIDError struct {
+	Err       error
+	RequestID string
+}
+
+func (e *RequestIDError) Error() string {
+	return fmt.Sprintf("%s (STACKIT request ID: %s)", e.Err.Error(), e.RequestID)
+}
+
+func (e *RequestIDError) Unwrap() error {
+	return e.Err
+}
+
+// GetRequestID returns the ID of the failed STACKIT API request if the given error contains it or an empty string
+// otherwise.
+func GetRequestID(err error) string {
+	var requestIDError *RequestIDError
+	if ok := errors.As(err, &requestIDError); !ok {
+		return ""
+	}
+
+	return requestIDError.RequestID
+}
+
+// captureRequestID returns a context which captures the HTTP response of a STACKIT SDK call and a function which adds
+// the request ID of the captured response to the error returned by the call.
+func captureRequestID(ctx context.Context) (context.Context, func(error) error) {
+	var resp *http.Response
+	return sdkruntime.WithCaptureHTTPResponse(ctx, &resp), func(err error) error {
+		return withRequestID(err, resp)
+	}
+}
+
+// withRequestID wraps the given error with the request ID of the given response if both are set.
+func withRequestID(err error, resp *http.Response) error {
+	if err == nil || resp == nil {
+		return err
+	}
+	requestID := resp.Header.Get(RequestIDHeader)
+	if requestID == "" {
+		return err
+	}
+	return &RequestIDError{Err: err, RequestID: requestID}
+}
@@ -1,11 +1,16 @@
 package client
 
 import (
+	"context"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	sdkconfig "github.com/stackitcloud/stackit-sdk-go/core/config"
 	"github.com/stackitcloud/stackit-sdk-go/core/oapierror"
+	loadbalancer "github.com/stackitcloud/stackit-sdk-go/services/loadbalancer/v2api"
 )
 
 var _ = Describe("Errors", func() {
@@ -63,4 +68,41 @@ var _ = Describe("Errors", func() {
 			Expect(IsConflictError(nil)).To(BeFalse())
 		})
 	})
+
+	Describe("RequestIDError", func() {
+		It("should add the request ID to the error message", func() {
+			err := withRequestID(fmt.Errorf("foo"), &http.Response{Header: http.Header{RequestIDHeader: []string{"request-id"}}})
+			Expect(err).To(MatchError("foo (STACKIT request ID: request-id)"))
+			Expect(GetRequestID(err)).To(Equal("request-id"))
+		})
+
+		It("should keep the status code of the wrapped error", func() {
+			err := withRequestID(&oapierror.GenericOpenAPIError{StatusCode: 404}, &http.Response{Header: http.Header{RequestIDHeader: []string{"request-id"}}})
+			Expect(IsNotFound(fmt.Errorf("wrapped: %w", err))).To(BeTrue())
+			Expect(GetRequestID(fmt.Errorf("wrapped: %w", err))).To(Equal("request-id"))
+		})
+
+		It("should not wrap the error without request ID", func() {
+			err := fmt.Errorf("foo")
+			Expect(withRequestID(err, &http.Response{})).To(BeIdenticalTo(err))
+			Expect(withRequestID(err, nil)).To(BeIdenticalTo(err))
+			Expect(withRequestID(nil, &http.Response{Header: http.Header{RequestIDHeader: []string{"request-id"}}})).To(Succeed())
+		})
+
+		It("should surface the request ID of a failed SDK call", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set(RequestIDHeader, "request-id")
+				w.WriteHeader(http.StatusInternalServerError)
+			}))
+			DeferCleanup(server.Close)
+
+			apiClient, err := loadbalancer.NewAPIClient(sdkconfig.WithEndpoint(server.URL), sdkconfig.WithoutAuthentication())
+			Expect(err).NotTo(HaveOccurred())
+			lbClient := &loadBalancingClient{Client: apiClient.DefaultAPI, projectID: "test-project", region: "eu01"}
+
+			_, err = lbClient.GetLoadBalancer(context.Background(), "test")
+			Expect(err).To(MatchError(ContainSubstring("STACKIT request ID: request-id")))
+			Expect(GetStatusCode(err)).To(Equal(http.StatusInternalServerError))
+		})
+	})
 })
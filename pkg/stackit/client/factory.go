@@ -6,10 +6,12 @@ import (
 	"crypto/x509"
 	"fmt"
 	"net/http"
+	"time"
 
 	extensionscontroller "github.com/gardener/gardener/extensions/pkg/controller"
 	sdkconfig "github.com/stackitcloud/stackit-sdk-go/core/config"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -20,6 +22,9 @@ import (
 
 const (
 	UserAgent = "gardener-extension-provider-stackit"
+
+	// DefaultRequestTimeout is the default HTTP timeout of requests against the STACKIT APIs.
+	DefaultRequestTimeout = 30 * time.Second
 )
 
 // Factory produces clients for various STACKIT services.
@@ -42,14 +47,17 @@ type factory struct {
 	StackitRegion       string
 	StackitAPIEndpoints stackitv1alpha1.APIEndpoints
 	CABundleB64         string
+	RequestTimeout      *metav1.Duration
 }
 
 func New(region string, cluster *extensionscontroller.Cluster) Factory {
 	var apiEndpoints stackitv1alpha1.APIEndpoints
 	var caBundle string
+	var requestTimeout *metav1.Duration
 
-	if cloudProfileConfig, err := helper.CloudProfileConfigFromCluster(cluster); err == nil {
-		apiEndpoints = ptr.Deref(cloudProfileConfig.APIEndpoints, stackitv1alpha1.APIEndpoints{})
+	if cloudProfileConfig, err := helper.CloudProfileConfigFromCluster(cluster); err == nil && cloudProfileConfig != nil {
+		apiEndpoints = helper.APIEndpointsFromCluster(cluster, cloudProfileConfig, region)
+		requestTimeout = cloudProfileConfig.RequestTimeout
 	}
 
 	if cluster.CloudProfile != nil && cluster.CloudProfile.Spec.CABundle != nil {
@@ -60,6 +68,7 @@ func New(region string, cluster *extensionscontroller.Cluster) Factory {
 		StackitRegion:       region,
 		StackitAPIEndpoints: apiEndpoints,
 		CABundleB64:         caBundle,
+		RequestTimeout:      requestTimeout,
 	}
 }
 
@@ -69,7 +78,7 @@ func (f factory) LoadBalancing(ctx context.Context, c client.Client, secretRef c
 		return nil, err
 	}
 
-	return NewLoadBalancingClient(ctx, f.StackitRegion, f.StackitAPIEndpoints, credentials, f.CABundleB64)
+	return NewLoadBalancingClient(ctx, f.StackitRegion, f.StackitAPIEndpoints, credentials, f.CABundleB64, f.RequestTimeout)
 }
 
 func (f factory) ApplicationLoadBalancer(ctx context.Context, c client.Client, secretRef corev1.SecretReference) (ApplicationLoadBalancingClient, error) {
@@ -78,7 +87,7 @@ func (f factory) ApplicationLoadBalancer(ctx context.Context, c client.Client, s
 		return nil, err
 	}
 
-	return NewApplicationLoadBalancingClient(ctx, f.StackitRegion, f.StackitAPIEndpoints, credentials, f.CABundleB64)
+	return NewApplicationLoadBalancingClient(ctx, f.StackitRegion, f.StackitAPIEndpoints, credentials, f.CABundleB64, f.RequestTimeout)
 }
 
 func (f factory) ApplicationLoadBalancerCertificate(ctx context.Context, c client.Client, secretRef corev1.SecretReference) (ApplicationLoadBalancerCertificateClient, error) {
@@ -87,7 +96,7 @@ func (f factory) ApplicationLoadBalancerCertificate(ctx context.Context, c clien
 		return nil, err
 	}
 
-	return NewApplicationLoadBalancerCertificateClient(ctx, f.StackitRegion, f.StackitAPIEndpoints, credentials, f.CABundleB64)
+	return NewApplicationLoadBalancerCertificateClient(ctx, f.StackitRegion, f.StackitAPIEndpoints, credentials, f.CABundleB64, f.RequestTimeout)
 }
 
 func (f factory) IaaS(ctx context.Context, c client.Client, secretRef corev1.SecretReference) (IaaSClient, error) {
@@ -96,7 +105,7 @@ func (f factory) IaaS(ctx context.Context, c client.Client, secretRef corev1.Sec
 		return nil, err
 	}
 
-	return NewIaaSClient(f.StackitRegion, f.StackitAPIEndpoints, credentials, f.CABundleB64)
+	return NewIaaSClient(f.StackitRegion, f.StackitAPIEndpoints, credentials, f.CABundleB64, f.RequestTimeout, WithRetryConfig(DefaultRetryConfig))
 }
 
 func (f factory) DNS(ctx context.Context, c client.Client, secretRef corev1.SecretReference) (DNSClient, error) {
@@ -105,7 +114,7 @@ func (f factory) DNS(ctx context.Context, c client.Client, secretRef corev1.Secr
 		return nil, err
 	}
 
-	return NewDNSClient(ctx, f.StackitAPIEndpoints, credentials, f.CABundleB64)
+	return NewDNSClient(ctx, f.StackitAPIEndpoints, credentials, f.CABundleB64, f.RequestTimeout)
 }
 
 // newHTTPClientWithCustomCA creates an http.Client with a custom CA
@@ -126,23 +135,28 @@ func newHTTPClientWithCustomCA(caBundle []byte) (*http.Client, error) {
 	}}, nil
 }
 
-func clientOptions(endpoints stackitv1alpha1.APIEndpoints, credentials *stackit.Credentials, caBundle string) ([]sdkconfig.ConfigurationOption, error) {
+// clientOptions returns the options of the SDK clients. Requests time out after the given request timeout, or after
+// DefaultRequestTimeout if it is nil, so that reconciliations do not hang if a STACKIT API stalls.
+func clientOptions(endpoints stackitv1alpha1.APIEndpoints, credentials *stackit.Credentials, caBundle string, requestTimeout *metav1.Duration) ([]sdkconfig.ConfigurationOption, error) {
+	httpClient := &http.Client{}
+	if caBundle != "" {
+		customHttpClient, err := newHTTPClientWithCustomCA([]byte(caBundle))
+		if err != nil {
+			return nil, err
+		}
+		httpClient = customHttpClient
+	}
+	httpClient.Timeout = ptr.Deref(requestTimeout, metav1.Duration{Duration: DefaultRequestTimeout}).Duration
+
 	result := []sdkconfig.ConfigurationOption{
 		sdkconfig.WithUserAgent(UserAgent),
 		sdkconfig.WithServiceAccountKey(credentials.SaKeyJSON),
+		sdkconfig.WithHTTPClient(httpClient),
 	}
 
 	if endpoints.TokenEndpoint != nil {
 		result = append(result, sdkconfig.WithTokenEndpoint(*endpoints.TokenEndpoint))
 	}
 
-	if caBundle != "" {
-		customHttpClient, err := newHTTPClientWithCustomCA([]byte(caBundle))
-		if err != nil {
-			return nil, err
-		}
-		result = append(result, sdkconfig.WithHTTPClient(customHttpClient))
-	}
-
 	return result, nil
 }
@@ -0,0 +1,102 @@
+package client
+
+import (
+	"encoding/json"
+	"time"
+
+	extensionscontroller "github.com/gardener/gardener/extensions/pkg/controller"
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	sdkconfig "github.com/stackitcloud/stackit-sdk-go/core/config"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	stackitv1alpha1 "github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/apis/stackit/v1alpha1"
+	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/stackit"
+)
+
+var _ = Describe("Factory", func() {
+	Describe("#New", func() {
+		encode := func(obj runtime.Object) *runtime.RawExtension {
+			GinkgoHelper()
+			raw, err := json.Marshal(obj)
+			Expect(err).NotTo(HaveOccurred())
+			return &runtime.RawExtension{Raw: raw}
+		}
+
+		cluster := func(cloudProfileEndpoint, infrastructureEndpoint *string, allowedEndpoints ...string) *extensionscontroller.Cluster {
+			return &extensionscontroller.Cluster{
+				CloudProfile: &gardencorev1beta1.CloudProfile{
+					Spec: gardencorev1beta1.CloudProfileSpec{
+						ProviderConfig: encode(&stackitv1alpha1.CloudProfileConfig{
+							TypeMeta:             metav1.TypeMeta{APIVersion: stackitv1alpha1.SchemeGroupVersion.String(), Kind: "CloudProfileConfig"},
+							APIEndpoints:         &stackitv1alpha1.APIEndpoints{IaaS: cloudProfileEndpoint},
+							AllowedIaaSEndpoints: allowedEndpoints,
+						}),
+					},
+				},
+				Shoot: &gardencorev1beta1.Shoot{
+					Spec: gardencorev1beta1.ShootSpec{
+						Provider: gardencorev1beta1.Provider{
+							InfrastructureConfig: encode(&stackitv1alpha1.InfrastructureConfig{
+								TypeMeta:     metav1.TypeMeta{APIVersion: stackitv1alpha1.SchemeGroupVersion.String(), Kind: "InfrastructureConfig"},
+								IaaSEndpoint: infrastructureEndpoint,
+							}),
+						},
+					},
+				},
+			}
+		}
+
+		DescribeTable("should select the IaaS endpoint",
+			func(cloudProfileEndpoint, infrastructureEndpoint *string, allowedEndpoints []string, expected *string) {
+				f := New("eu01", cluster(cloudProfileEndpoint, infrastructureEndpoint, allowedEndpoints...)).(*factory)
+				Expect(f.StackitAPIEndpoints.IaaS).To(Equal(expected))
+			},
+			Entry("SDK default", nil, nil, nil, nil),
+			Entry("cloud profile", new("https://iaas.example.com"), nil, nil, new("https://iaas.example.com")),
+			Entry("allowed shoot over cloud profile", new("https://iaas.example.com"), new("https://iaas.staging.example.com"), []string{"https://iaas.staging.example.com"}, new("https://iaas.staging.example.com")),
+			Entry("allowed shoot without cloud profile endpoint", nil, new("https://iaas.staging.example.com"), []string{"https://iaas.staging.example.com"}, new("https://iaas.staging.example.com")),
+			Entry("disallowed shoot", new("https://iaas.example.com"), new("https://attacker.example.com"), []string{"https://iaas.staging.example.com"}, new("https://iaas.example.com")),
+			Entry("shoot without allow-list", nil, new("https://iaas.staging.example.com"), nil, nil),
+		)
+
+		It("should take the request timeout from the cloud profile", func() {
+			c := cluster(nil, nil)
+			c.CloudProfile.Spec.ProviderConfig = encode(&stackitv1alpha1.CloudProfileConfig{
+				TypeMeta:       metav1.TypeMeta{APIVersion: stackitv1alpha1.SchemeGroupVersion.String(), Kind: "CloudProfileConfig"},
+				RequestTimeout: &metav1.Duration{Duration: time.Minute},
+			})
+
+			f := New("eu01", c).(*factory)
+			Expect(f.RequestTimeout).To(Equal(&metav1.Duration{Duration: time.Minute}))
+		})
+	})
+
+	Describe("#clientOptions", func() {
+		configure := func(requestTimeout *metav1.Duration) *sdkconfig.Configuration {
+			GinkgoHelper()
+			options, err := clientOptions(stackitv1alpha1.APIEndpoints{}, &stackit.Credentials{SaKeyJSON: "{}"}, "", requestTimeout)
+			Expect(err).NotTo(HaveOccurred())
+
+			cfg := &sdkconfig.Configuration{}
+			for _, option := range options {
+				Expect(option(cfg)).To(Succeed())
+			}
+			return cfg
+		}
+
+		It("should apply the request timeout to the HTTP client", func() {
+			cfg := configure(&metav1.Duration{Duration: 5 * time.Second})
+			Expect(cfg.HTTPClient).NotTo(BeNil())
+			Expect(cfg.HTTPClient.Timeout).To(Equal(5 * time.Second))
+		})
+
+		It("should fall back to the default request timeout", func() {
+			cfg := configure(nil)
+			Expect(cfg.HTTPClient).NotTo(BeNil())
+			Expect(cfg.HTTPClient.Timeout).To(Equal(DefaultRequestTimeout))
+		})
+	})
+})
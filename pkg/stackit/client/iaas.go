@@ -2,14 +2,19 @@ package client
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"maps"
 	"slices"
+	"strings"
 
 	"github.com/go-logr/logr"
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	sdkconfig "github.com/stackitcloud/stackit-sdk-go/core/config"
 	iaas "github.com/stackitcloud/stackit-sdk-go/services/iaas/v2api"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 
 	stackitv1alpha1 "github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/apis/stackit/v1alpha1"
 	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/stackit"
@@ -32,11 +37,20 @@ type IaaSClient interface {
 
 	CreateSecurityGroupRule(ctx context.Context, securityGroupId string, wantedRule iaas.SecurityGroupRule) (*iaas.SecurityGroupRule, error)
 	ReconcileSecurityGroupRules(ctx context.Context, log logr.Logger, securityGroup *iaas.SecurityGroup, wantedRules []iaas.SecurityGroupRule) error
-	UpdateSecurityGroupRules(ctx context.Context, group *iaas.SecurityGroup, desiredRules []iaas.SecurityGroupRule, allowDelete func(rule *iaas.SecurityGroupRule) bool) (modified bool, err error)
+	// UpdateSecurityGroupRules creates the missing desired rules of the given security group and deletes the other
+	// rules if allowed by allowDelete. Exact duplicates of desired rules are only deleted if deleteDuplicates is set.
+	UpdateSecurityGroupRules(ctx context.Context, group *iaas.SecurityGroup, desiredRules []iaas.SecurityGroupRule, deleteDuplicates bool, allowDelete func(rule *iaas.SecurityGroupRule) bool) (modified bool, err error)
 
 	CreateServer(ctx context.Context, payload iaas.CreateServerPayload) (*iaas.Server, error)
 	DeleteServer(ctx context.Context, serverId string) error
 	GetServerByName(ctx context.Context, name string) ([]iaas.Server, error)
+	// DeleteServersByLabels deletes all servers matching the given label selector and returns the number of deleted
+	// servers. Servers which are already gone are ignored. The selector must not be empty.
+	DeleteServersByLabels(ctx context.Context, selector stackit.LabelSelector) (deleted int, err error)
+
+	CreateAffinityGroup(ctx context.Context, name, policy string) (*iaas.AffinityGroup, error)
+	DeleteAffinityGroup(ctx context.Context, affinityGroupId string) error
+	GetAffinityGroupByName(ctx context.Context, name string) ([]iaas.AffinityGroup, error)
 
 	CreatePublicIp(ctx context.Context, payload iaas.CreatePublicIPPayload) (*iaas.PublicIp, error)
 	DeletePublicIp(ctx context.Context, publicIpId string) error
@@ -46,27 +60,58 @@ type IaaSClient interface {
 	GetKeypair(ctx context.Context, name string) (*iaas.Keypair, error)
 	CreateKeypair(ctx context.Context, name, publicKey string) (*iaas.Keypair, error)
 	DeleteKeypair(ctx context.Context, name string) error
+
+	GetImageById(ctx context.Context, id string) (*iaas.Image, error)
 }
 
 type iaasClient struct {
 	Client    iaas.DefaultAPI
 	projectID string
 	region    string
+	// retryConfig configures the retries of failed requests. Requests are not retried if it is nil.
+	retryConfig *RetryConfig
 }
 
-func (c iaasClient) UpdateSecurityGroupRules(ctx context.Context, group *iaas.SecurityGroup, desiredRules []iaas.SecurityGroupRule, allowDelete func(rule *iaas.SecurityGroupRule) bool) (modified bool, err error) {
+// IaaSClientOption configures optional settings of the IaaS client.
+type IaaSClientOption func(*iaasClient)
+
+// WithRetryConfig retries failed requests of the IaaS client according to the given configuration.
+func WithRetryConfig(config RetryConfig) IaaSClientOption {
+	return func(c *iaasClient) {
+		c.retryConfig = &config
+	}
+}
+
+func (c iaasClient) UpdateSecurityGroupRules(ctx context.Context, group *iaas.SecurityGroup, desiredRules []iaas.SecurityGroupRule, deleteDuplicates bool, allowDelete func(rule *iaas.SecurityGroupRule) bool) (modified bool, err error) {
+	log := logr.FromContextOrDiscard(ctx).WithValues("securityGroup", group.GetId())
+	ctx, withRequestID := captureRequestID(ctx)
+	var matchedRules []iaas.SecurityGroupRule
 	for i := range group.GetRules() {
 		rule := &group.GetRules()[i]
 		if desiredRule := findMatchingRule(*rule, desiredRules); desiredRule == nil {
-			if allowDelete == nil || allowDelete(rule) {
-				if err = c.Client.DeleteSecurityGroupRule(ctx, c.projectID, c.region, group.GetId(), rule.GetId()).Execute(); err != nil {
-					err = fmt.Errorf("error deleting rule for security group %s: %s", rule.GetId(), err)
+			// Duplicates of desired rules are deleted on request, all other rules not desired anymore are subject to
+			// allowDelete, independent of whether they duplicate a desired rule.
+			if duplicate := findDuplicateRule(*rule, matchedRules); duplicate != nil && deleteDuplicates {
+				log.Info("Deleting duplicate security group rule", append(securityGroupRuleLogValues(rule), "duplicateOf", duplicate.GetId())...)
+				if err = retryNoResult(ctx, c.retryConfig, c.Client.DeleteSecurityGroupRule(ctx, c.projectID, c.region, group.GetId(), rule.GetId()).Execute); err != nil {
+					err = fmt.Errorf("error deleting duplicate rule %s of security group: %w", rule.GetId(), withRequestID(err))
+					return
+				}
+				modified = true
+			} else if allowDelete == nil || allowDelete(rule) {
+				log.V(1).Info("Deleting security group rule which is not desired", securityGroupRuleLogValues(rule)...)
+				if err = retryNoResult(ctx, c.retryConfig, c.Client.DeleteSecurityGroupRule(ctx, c.projectID, c.region, group.GetId(), rule.GetId()).Execute); err != nil {
+					err = fmt.Errorf("error deleting rule for security group %s: %w", rule.GetId(), withRequestID(err))
 					return
 				}
 				modified = true
+			} else {
+				log.V(1).Info("Keeping unknown security group rule", securityGroupRuleLogValues(rule)...)
 			}
 		} else {
+			log.V(1).Info("Keeping desired security group rule", securityGroupRuleLogValues(rule)...)
 			desiredRule.Id = rule.Id // mark as found
+			matchedRules = append(matchedRules, *rule)
 		}
 	}
 
@@ -76,23 +121,14 @@ func (c iaasClient) UpdateSecurityGroupRules(ctx context.Context, group *iaas.Se
 			// ignore found rules
 			continue
 		}
-		createOpts := iaas.CreateSecurityGroupRulePayload{
-			Direction:             rule.Direction,
-			Description:           rule.Description,
-			Ethertype:             rule.Ethertype,
-			SecurityGroupId:       rule.SecurityGroupId,
-			RemoteSecurityGroupId: rule.RemoteSecurityGroupId,
-			IpRange:               rule.IpRange,
-		}
-		if rule.HasProtocol() {
-			createOpts.Protocol = new(iaas.StringAsCreateProtocol(rule.Protocol.Name))
-		}
-		// TODO: Ports are only supported for DCCP, SCTP, TCP, UDP and UDPLITE
-		if portRange, ok := rule.GetPortRangeOk(); ok {
-			createOpts.PortRange = iaas.NewPortRange(portRange.GetMax(), portRange.GetMin())
+		var createOpts iaas.CreateSecurityGroupRulePayload
+		if createOpts, err = securityGroupRuleToCreatePayload(*rule); err != nil {
+			err = fmt.Errorf("error creating rule %d for security group: %w", i, err)
+			return
 		}
-		if _, err = c.Client.CreateSecurityGroupRule(ctx, c.projectID, c.region, group.GetId()).CreateSecurityGroupRulePayload(createOpts).Execute(); err != nil {
-			err = fmt.Errorf("error creating rule %d for security group: %s", i, err)
+		log.V(1).Info("Creating missing security group rule", securityGroupRuleLogValues(rule)...)
+		if _, err = retryCreate(ctx, c.retryConfig, c.Client.CreateSecurityGroupRule(ctx, c.projectID, c.region, group.GetId()).CreateSecurityGroupRulePayload(createOpts).Execute); err != nil {
+			err = fmt.Errorf("error creating rule %d for security group: %w", i, withRequestID(err))
 			return
 		}
 		modified = true
@@ -101,21 +137,25 @@ func (c iaasClient) UpdateSecurityGroupRules(ctx context.Context, group *iaas.Se
 }
 
 func (c iaasClient) UpdateNetwork(ctx context.Context, networkId string, payload iaas.PartialUpdateNetworkPayload) (*iaas.Network, error) {
-	err := c.Client.PartialUpdateNetwork(ctx, c.projectID, c.region, networkId).PartialUpdateNetworkPayload(payload).Execute()
+	ctx, withRequestID := captureRequestID(ctx)
+	err := retryNoResult(ctx, c.retryConfig, c.Client.PartialUpdateNetwork(ctx, c.projectID, c.region, networkId).PartialUpdateNetworkPayload(payload).Execute)
 	if err != nil {
-		return nil, err
+		return nil, withRequestID(err)
 	}
 	return nil, nil
 }
 
 func (c iaasClient) GetNetworkById(ctx context.Context, id string) (*iaas.Network, error) {
-	return c.Client.GetNetwork(ctx, c.projectID, c.region, id).Execute()
+	ctx, withRequestID := captureRequestID(ctx)
+	network, err := retry(ctx, c.retryConfig, c.Client.GetNetwork(ctx, c.projectID, c.region, id).Execute)
+	return network, withRequestID(err)
 }
 
 func (c iaasClient) GetNetworkByName(ctx context.Context, name string) ([]iaas.Network, error) {
-	networks, err := c.Client.ListNetworks(ctx, c.projectID, c.region).Execute()
+	ctx, withRequestID := captureRequestID(ctx)
+	networks, err := retry(ctx, c.retryConfig, c.Client.ListNetworks(ctx, c.projectID, c.region).Execute)
 	if err != nil {
-		return nil, fmt.Errorf("error listing security groups: %w", err)
+		return nil, fmt.Errorf("error listing networks: %w", withRequestID(err))
 	}
 
 	filteredNetworks := slices.DeleteFunc(networks.GetItems(), func(network iaas.Network) bool {
@@ -126,8 +166,9 @@ func (c iaasClient) GetNetworkByName(ctx context.Context, name string) ([]iaas.N
 	return filteredNetworks, nil
 }
 
-func NewIaaSClient(region string, endpoints stackitv1alpha1.APIEndpoints, credentials *stackit.Credentials, caBundle string) (IaaSClient, error) {
-	options, err := clientOptions(endpoints, credentials, caBundle)
+func NewIaaSClient(region string, endpoints stackitv1alpha1.APIEndpoints, credentials *stackit.Credentials, caBundle string, requestTimeout *metav1.Duration, opts ...IaaSClientOption) (IaaSClient, error) {
+	endpoints = endpoints.ForRegion(region)
+	options, err := clientOptions(endpoints, credentials, caBundle, requestTimeout)
 	if err != nil {
 		return nil, err
 	}
@@ -144,19 +185,27 @@ func NewIaaSClient(region string, endpoints stackitv1alpha1.APIEndpoints, creden
 		return nil, err
 	}
 
-	return &iaasClient{
+	c := &iaasClient{
 		Client:    apiClient.DefaultAPI,
 		projectID: credentials.ProjectID,
 		region:    region,
-	}, nil
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c, nil
 }
 
 func (c iaasClient) CreateIsolatedNetwork(ctx context.Context, payload iaas.CreateIsolatedNetworkPayload) (*iaas.Network, error) {
-	return c.Client.CreateIsolatedNetwork(ctx, c.projectID, c.region).CreateIsolatedNetworkPayload(payload).Execute()
+	ctx, withRequestID := captureRequestID(ctx)
+	network, err := retryCreate(ctx, c.retryConfig, c.Client.CreateIsolatedNetwork(ctx, c.projectID, c.region).CreateIsolatedNetworkPayload(payload).Execute)
+	return network, withRequestID(err)
 }
 
 func (c iaasClient) DeleteNetwork(ctx context.Context, networkID string) error {
-	return c.Client.DeleteNetwork(ctx, c.projectID, c.region, networkID).Execute()
+	ctx, withRequestID := captureRequestID(ctx)
+	return withRequestID(retryNoResult(ctx, c.retryConfig, c.Client.DeleteNetwork(ctx, c.projectID, c.region, networkID).Execute))
 }
 
 func (c iaasClient) ProjectID() string {
@@ -164,18 +213,22 @@ func (c iaasClient) ProjectID() string {
 }
 
 func (c iaasClient) CreateSecurityGroup(ctx context.Context, payload iaas.CreateSecurityGroupPayload) (*iaas.SecurityGroup, error) {
-	return c.Client.CreateSecurityGroup(ctx, c.projectID, c.region).CreateSecurityGroupPayload(payload).Execute()
+	ctx, withRequestID := captureRequestID(ctx)
+	securityGroup, err := retryCreate(ctx, c.retryConfig, c.Client.CreateSecurityGroup(ctx, c.projectID, c.region).CreateSecurityGroupPayload(payload).Execute)
+	return securityGroup, withRequestID(err)
 }
 
 func (c iaasClient) DeleteSecurityGroup(ctx context.Context, securityGroupId string) error {
-	return c.Client.DeleteSecurityGroup(ctx, c.projectID, c.region, securityGroupId).Execute()
+	ctx, withRequestID := captureRequestID(ctx)
+	return withRequestID(retryNoResult(ctx, c.retryConfig, c.Client.DeleteSecurityGroup(ctx, c.projectID, c.region, securityGroupId).Execute))
 }
 
 // GetSecurityGroupByName finds the first security group with the given name.
 func (c iaasClient) GetSecurityGroupByName(ctx context.Context, name string) ([]iaas.SecurityGroup, error) {
-	securityGroups, err := c.Client.ListSecurityGroups(ctx, c.projectID, c.region).Execute()
+	ctx, withRequestID := captureRequestID(ctx)
+	securityGroups, err := retry(ctx, c.retryConfig, c.Client.ListSecurityGroups(ctx, c.projectID, c.region).Execute)
 	if err != nil {
-		return nil, fmt.Errorf("error listing security groups: %w", err)
+		return nil, fmt.Errorf("error listing security groups: %w", withRequestID(err))
 	}
 
 	filteredSecurityGroups := slices.DeleteFunc(securityGroups.GetItems(), func(secGroup iaas.SecurityGroup) bool {
@@ -187,11 +240,19 @@ func (c iaasClient) GetSecurityGroupByName(ctx context.Context, name string) ([]
 }
 
 func (c iaasClient) GetSecurityGroupById(ctx context.Context, securityGroupId string) (*iaas.SecurityGroup, error) {
-	return c.Client.GetSecurityGroup(ctx, c.projectID, c.region, securityGroupId).Execute()
+	ctx, withRequestID := captureRequestID(ctx)
+	securityGroup, err := retry(ctx, c.retryConfig, c.Client.GetSecurityGroup(ctx, c.projectID, c.region, securityGroupId).Execute)
+	return securityGroup, withRequestID(err)
 }
 
 func (c iaasClient) CreateSecurityGroupRule(ctx context.Context, securityGroupId string, wantedRule iaas.SecurityGroupRule) (*iaas.SecurityGroupRule, error) {
-	return c.Client.CreateSecurityGroupRule(ctx, c.projectID, c.region, securityGroupId).CreateSecurityGroupRulePayload(securityGroupRuleToCreatePayload(wantedRule)).Execute()
+	ctx, withRequestID := captureRequestID(ctx)
+	payload, err := securityGroupRuleToCreatePayload(wantedRule)
+	if err != nil {
+		return nil, err
+	}
+	rule, err := retryCreate(ctx, c.retryConfig, c.Client.CreateSecurityGroupRule(ctx, c.projectID, c.region, securityGroupId).CreateSecurityGroupRulePayload(payload).Execute)
+	return rule, withRequestID(err)
 }
 
 // ReconcileSecurityGroupRules updates the rules of the given security group to the desired state.
@@ -199,6 +260,7 @@ func (c iaasClient) CreateSecurityGroupRule(ctx context.Context, securityGroupId
 // The method relies on SecurityGroup being read from the API beforehand.
 func (c iaasClient) ReconcileSecurityGroupRules(ctx context.Context, log logr.Logger, securityGroup *iaas.SecurityGroup, wantedRules []iaas.SecurityGroupRule) error {
 	log = log.WithValues("securityGroup", securityGroup.GetId())
+	ctx, withRequestID := captureRequestID(ctx)
 
 	// find matching existing rules and deleted unwanted rules
 	for _, existingRule := range securityGroup.GetRules() {
@@ -211,8 +273,9 @@ func (c iaasClient) ReconcileSecurityGroupRules(ctx context.Context, log logr.Lo
 			ruleLog.V(1).Info("Found existing security group rule")
 		} else {
 			// delete unwanted rule
-			if err := c.Client.DeleteSecurityGroupRule(ctx, c.projectID, c.region, securityGroup.GetId(), existingRule.GetId()).Execute(); err != nil {
-				return fmt.Errorf("error deleting unwanted security group rule %s in group %s: %w", existingRule.GetId(), securityGroup.GetId(), err)
+			log.V(1).Info("Deleting unwanted security group rule", securityGroupRuleLogValues(&existingRule)...)
+			if err := retryNoResult(ctx, c.retryConfig, c.Client.DeleteSecurityGroupRule(ctx, c.projectID, c.region, securityGroup.GetId(), existingRule.GetId()).Execute); err != nil {
+				return fmt.Errorf("error deleting unwanted security group rule %s in group %s: %w", existingRule.GetId(), securityGroup.GetId(), withRequestID(err))
 			}
 
 			ruleLog.Info("Deleted unwanted security group rule")
@@ -226,19 +289,48 @@ func (c iaasClient) ReconcileSecurityGroupRules(ctx context.Context, log logr.Lo
 			continue
 		}
 
-		createdRule, err := c.Client.CreateSecurityGroupRule(ctx, c.projectID, c.region, securityGroup.GetId()).
-			CreateSecurityGroupRulePayload(securityGroupRuleToCreatePayload(wantedRule)).
-			Execute()
+		payload, err := securityGroupRuleToCreatePayload(wantedRule)
 		if err != nil {
 			return fmt.Errorf("error creating security group rule %q in group %s: %w", wantedRule.GetDescription(), securityGroup.GetId(), err)
 		}
 
+		log.V(1).Info("Creating missing security group rule", securityGroupRuleLogValues(&wantedRule)...)
+		createdRule, err := retryCreate(ctx, c.retryConfig, c.Client.CreateSecurityGroupRule(ctx, c.projectID, c.region, securityGroup.GetId()).
+			CreateSecurityGroupRulePayload(payload).
+			Execute)
+		if err != nil {
+			return fmt.Errorf("error creating security group rule %q in group %s: %w", wantedRule.GetDescription(), securityGroup.GetId(), withRequestID(err))
+		}
+
 		log.Info("Created security group rule", "securityGroupRule", createdRule.GetId(), "description", createdRule.GetDescription())
 	}
 
 	return nil
 }
 
+// securityGroupRuleLogValues returns the key value pairs identifying the given rule in log messages.
+func securityGroupRuleLogValues(rule *iaas.SecurityGroupRule) []any {
+	values := []any{
+		"securityGroupRule", rule.GetId(),
+		"description", rule.GetDescription(),
+		"direction", rule.GetDirection(),
+		"ethertype", rule.GetEthertype(),
+	}
+	if rule.HasProtocol() {
+		values = append(values, "protocol", rule.Protocol.GetName())
+	}
+	if portRange, ok := rule.GetPortRangeOk(); ok {
+		values = append(values, "portRange", fmt.Sprintf("%d-%d", portRange.GetMin(), portRange.GetMax()))
+	}
+	if ipRange, ok := rule.GetIpRangeOk(); ok {
+		values = append(values, "ipRange", *ipRange)
+	}
+	if remoteGroupID, ok := rule.GetRemoteSecurityGroupIdOk(); ok {
+		values = append(values, "remoteSecurityGroup", *remoteGroupID)
+	}
+	return values
+}
+
 // findMatchingRule returns a pointer to the item in wantedRules matching the given rule.
 func findMatchingRule(rule iaas.SecurityGroupRule, wantedRules []iaas.SecurityGroupRule) *iaas.SecurityGroupRule {
 	for i, wanted := range wantedRules {
@@ -261,8 +353,29 @@ func findMatchingRule(rule iaas.SecurityGroupRule, wantedRules []iaas.SecurityGr
 	return nil
 }
 
+// findDuplicateRule returns a pointer to the item in candidates which is an exact duplicate of the given rule, i.e.
+// which only differs in its ID and timestamps.
+func findDuplicateRule(rule iaas.SecurityGroupRule, candidates []iaas.SecurityGroupRule) *iaas.SecurityGroupRule {
+	for i, other := range candidates {
+		if cmp.Equal(rule, other, stackit.ProtocolComparison, stackit.MapStringAnyComparison, cmpopts.IgnoreFields(iaas.SecurityGroupRule{}, "Id", "CreatedAt", "UpdatedAt")) {
+			return &candidates[i]
+		}
+	}
+
+	return nil
+}
+
 // securityGroupRuleToCreatePayload transforms the given SecurityGroupRule to an equivalent CreateSecurityGroupRulePayload.
-func securityGroupRuleToCreatePayload(rule iaas.SecurityGroupRule) iaas.CreateSecurityGroupRulePayload {
+// Port ranges are only supported for the protocols in stackit.ProtocolsWithPortRange, an error is returned for rules
+// with a port range and any other (or no) protocol.
+func securityGroupRuleToCreatePayload(rule iaas.SecurityGroupRule) (iaas.CreateSecurityGroupRulePayload, error) {
+	if rule.HasPortRange() {
+		protocol := rule.GetProtocol()
+		if !stackit.ProtocolsWithPortRange.Has(protocol.GetName()) {
+			return iaas.CreateSecurityGroupRulePayload{}, fmt.Errorf("port ranges are only supported for the protocols %s, not for protocol %q", strings.Join(sets.List(stackit.ProtocolsWithPortRange), ", "), protocol.GetName())
+		}
+	}
+
 	payload := iaas.CreateSecurityGroupRulePayload{
 		Description:           rule.Description,
 		Direction:             rule.Direction,
@@ -277,22 +390,26 @@ func securityGroupRuleToCreatePayload(rule iaas.SecurityGroupRule) iaas.CreateSe
 		payload.Protocol = new(iaas.StringAsCreateProtocol(rule.Protocol.Name))
 	}
 
-	return payload
+	return payload, nil
 }
 
 func (c iaasClient) CreateServer(ctx context.Context, payload iaas.CreateServerPayload) (*iaas.Server, error) {
-	return c.Client.CreateServer(ctx, c.projectID, c.region).CreateServerPayload(payload).Execute()
+	ctx, withRequestID := captureRequestID(ctx)
+	server, err := retryCreate(ctx, c.retryConfig, c.Client.CreateServer(ctx, c.projectID, c.region).CreateServerPayload(payload).Execute)
+	return server, withRequestID(err)
 }
 
 func (c iaasClient) DeleteServer(ctx context.Context, serverId string) error {
-	return c.Client.DeleteServer(ctx, c.projectID, c.region, serverId).Execute()
+	ctx, withRequestID := captureRequestID(ctx)
+	return withRequestID(retryNoResult(ctx, c.retryConfig, c.Client.DeleteServer(ctx, c.projectID, c.region, serverId).Execute))
 }
 
 // GetServerByName finds the first server with the given name.
 func (c iaasClient) GetServerByName(ctx context.Context, name string) ([]iaas.Server, error) {
-	servers, err := c.Client.ListServers(ctx, c.projectID, c.region).Execute()
+	ctx, withRequestID := captureRequestID(ctx)
+	servers, err := retry(ctx, c.retryConfig, c.Client.ListServers(ctx, c.projectID, c.region).Execute)
 	if err != nil {
-		return nil, fmt.Errorf("error listing servers: %w", err)
+		return nil, fmt.Errorf("error listing servers: %w", withRequestID(err))
 	}
 
 	filteredServers := slices.DeleteFunc(servers.GetItems(), func(server iaas.Server) bool {
@@ -303,20 +420,83 @@ func (c iaasClient) GetServerByName(ctx context.Context, name string) ([]iaas.Se
 	return filteredServers, nil
 }
 
+// DeleteServersByLabels deletes all servers that match the given label selector, e.g. servers orphaned by the machine
+// controller manager. Deletion continues with the next server if deleting a server fails. An empty selector is rejected,
+// as it would match all servers of the project.
+func (c iaasClient) DeleteServersByLabels(ctx context.Context, selector stackit.LabelSelector) (int, error) {
+	if len(selector) == 0 {
+		return 0, errors.New("refusing to delete servers with an empty label selector")
+	}
+
+	listCtx, withRequestID := captureRequestID(ctx)
+	servers, err := retry(listCtx, c.retryConfig, c.Client.ListServers(listCtx, c.projectID, c.region).Execute)
+	if err != nil {
+		return 0, fmt.Errorf("error listing servers: %w", withRequestID(err))
+	}
+
+	var (
+		deleted int
+		errs    []error
+	)
+	for _, server := range servers.GetItems() {
+		if !selector.Matches(server.GetLabels()) {
+			continue
+		}
+		if err := c.DeleteServer(ctx, server.GetId()); err != nil {
+			if IsNotFound(err) {
+				continue
+			}
+			errs = append(errs, fmt.Errorf("error deleting server %s: %w", server.GetId(), err))
+			continue
+		}
+		deleted++
+	}
+
+	return deleted, errors.Join(errs...)
+}
+
+func (c iaasClient) CreateAffinityGroup(ctx context.Context, name, policy string) (*iaas.AffinityGroup, error) {
+	ctx, withRequestID := captureRequestID(ctx)
+	affinityGroup, err := retryCreate(ctx, c.retryConfig, c.Client.CreateAffinityGroup(ctx, c.projectID, c.region).CreateAffinityGroupPayload(iaas.CreateAffinityGroupPayload{Name: name, Policy: policy}).Execute)
+	return affinityGroup, withRequestID(err)
+}
+
+func (c iaasClient) DeleteAffinityGroup(ctx context.Context, affinityGroupId string) error {
+	ctx, withRequestID := captureRequestID(ctx)
+	return withRequestID(retryNoResult(ctx, c.retryConfig, c.Client.DeleteAffinityGroup(ctx, c.projectID, c.region, affinityGroupId).Execute))
+}
+
+// GetAffinityGroupByName returns all affinity groups with the given name.
+func (c iaasClient) GetAffinityGroupByName(ctx context.Context, name string) ([]iaas.AffinityGroup, error) {
+	ctx, withRequestID := captureRequestID(ctx)
+	affinityGroups, err := retry(ctx, c.retryConfig, c.Client.ListAffinityGroups(ctx, c.projectID, c.region).Execute)
+	if err != nil {
+		return nil, fmt.Errorf("error listing affinity groups: %w", withRequestID(err))
+	}
+
+	return slices.DeleteFunc(affinityGroups.GetItems(), func(affinityGroup iaas.AffinityGroup) bool {
+		return affinityGroup.GetName() != name
+	}), nil
+}
+
 func (c iaasClient) CreatePublicIp(ctx context.Context, payload iaas.CreatePublicIPPayload) (*iaas.PublicIp, error) {
-	return c.Client.CreatePublicIP(ctx, c.projectID, c.region).CreatePublicIPPayload(payload).Execute()
+	ctx, withRequestID := captureRequestID(ctx)
+	publicIP, err := retryCreate(ctx, c.retryConfig, c.Client.CreatePublicIP(ctx, c.projectID, c.region).CreatePublicIPPayload(payload).Execute)
+	return publicIP, withRequestID(err)
 }
 
 func (c iaasClient) DeletePublicIp(ctx context.Context, publicIpId string) error {
-	return c.Client.DeletePublicIP(ctx, c.projectID, c.region, publicIpId).Execute()
+	ctx, withRequestID := captureRequestID(ctx)
+	return withRequestID(retryNoResult(ctx, c.retryConfig, c.Client.DeletePublicIP(ctx, c.projectID, c.region, publicIpId).Execute))
 }
 
 // GetPublicIpByLabels finds the first public IP that matches the given label selector. Public IPs don't have a name,
 // so matching by label is our best option.
 func (c iaasClient) GetPublicIpByLabels(ctx context.Context, selector stackit.LabelSelector) ([]iaas.PublicIp, error) {
-	publicIPs, err := c.Client.ListPublicIPs(ctx, c.projectID, c.region).Execute()
+	ctx, withRequestID := captureRequestID(ctx)
+	publicIPs, err := retry(ctx, c.retryConfig, c.Client.ListPublicIPs(ctx, c.projectID, c.region).Execute)
 	if err != nil {
-		return nil, fmt.Errorf("error listing public IPs: %w", err)
+		return nil, fmt.Errorf("error listing public IPs: %w", withRequestID(err))
 	}
 
 	filteredIPs := slices.DeleteFunc(publicIPs.GetItems(), func(ip iaas.PublicIp) bool {
@@ -328,33 +508,69 @@ func (c iaasClient) GetPublicIpByLabels(ctx context.Context, selector stackit.La
 }
 
 func (c iaasClient) AddPublicIpToServer(ctx context.Context, serverId, publicIpId string) error {
-	return c.Client.AddPublicIpToServer(ctx, c.projectID, c.region, serverId, publicIpId).Execute()
+	ctx, withRequestID := captureRequestID(ctx)
+	return withRequestID(retryNoResult(ctx, c.retryConfig, c.Client.AddPublicIpToServer(ctx, c.projectID, c.region, serverId, publicIpId).Execute))
 }
 
 func (c iaasClient) GetKeypair(ctx context.Context, name string) (*iaas.Keypair, error) {
-	keypair, err := c.Client.GetKeyPair(ctx, name).Execute()
+	ctx, withRequestID := captureRequestID(ctx)
+	keypair, err := retry(ctx, c.retryConfig, c.Client.GetKeyPair(ctx, name).Execute)
 	if IsNotFound(err) {
 		return nil, nil
 	}
-	return keypair, err
+	return keypair, withRequestID(err)
 }
 
 func (c iaasClient) CreateKeypair(ctx context.Context, name, publicKey string) (*iaas.Keypair, error) {
-	return c.Client.CreateKeyPair(ctx).CreateKeyPairPayload(iaas.CreateKeyPairPayload{Name: &name, PublicKey: publicKey}).Execute()
+	ctx, withRequestID := captureRequestID(ctx)
+	keypair, err := retryCreate(ctx, c.retryConfig, c.Client.CreateKeyPair(ctx).CreateKeyPairPayload(iaas.CreateKeyPairPayload{Name: &name, PublicKey: publicKey}).Execute)
+	return keypair, withRequestID(err)
 }
 
 func (c iaasClient) DeleteKeypair(ctx context.Context, name string) error {
-	return c.Client.DeleteKeyPair(ctx, name).Execute()
+	ctx, withRequestID := captureRequestID(ctx)
+	return withRequestID(retryNoResult(ctx, c.retryConfig, c.Client.DeleteKeyPair(ctx, name).Execute))
 }
 
-func IsolatedNetworkToPartialUpdate(network iaas.CreateIsolatedNetworkPayload) iaas.PartialUpdateNetworkPayload {
-	return iaas.PartialUpdateNetworkPayload{
-		Dhcp:   network.Dhcp,
-		Labels: network.Labels,
-		Name:   &network.Name,
+func (c iaasClient) GetImageById(ctx context.Context, id string) (*iaas.Image, error) {
+	ctx, withRequestID := captureRequestID(ctx)
+	image, err := retry(ctx, c.retryConfig, c.Client.GetImage(ctx, c.projectID, c.region, id).Execute)
+	return image, withRequestID(err)
+}
+
+// IsolatedNetworkToPartialUpdate returns the update converging the given current network to the mutable attributes of
+// the desired network. The desired labels are merged into the labels of the current network, so that labels added by
+// others are kept.
+func IsolatedNetworkToPartialUpdate(current *iaas.Network, network iaas.CreateIsolatedNetworkPayload) iaas.PartialUpdateNetworkPayload {
+	update := iaas.PartialUpdateNetworkPayload{
+		Dhcp: network.Dhcp,
+		Name: &network.Name,
 		Ipv4: &iaas.UpdateNetworkIPv4Body{
 			Gateway:     network.Ipv4.CreateNetworkIPv4WithPrefix.Gateway,
 			Nameservers: network.Ipv4.CreateNetworkIPv4WithPrefix.Nameservers,
 		},
 	}
+	if len(network.Labels) > 0 {
+		labels := maps.Clone(current.GetLabels())
+		if labels == nil {
+			labels = make(map[string]any, len(network.Labels))
+		}
+		maps.Copy(labels, network.Labels)
+		update.Labels = labels
+	}
+	// the prefix of a network cannot be updated, only the nameservers and the gateway
+	if network.Ipv6 != nil {
+		switch {
+		case network.Ipv6.CreateNetworkIPv6WithPrefix != nil:
+			update.Ipv6 = &iaas.UpdateNetworkIPv6Body{
+				Gateway:     network.Ipv6.CreateNetworkIPv6WithPrefix.Gateway,
+				Nameservers: network.Ipv6.CreateNetworkIPv6WithPrefix.Nameservers,
+			}
+		case network.Ipv6.CreateNetworkIPv6WithPrefixLength != nil:
+			update.Ipv6 = &iaas.UpdateNetworkIPv6Body{
+				Nameservers: network.Ipv6.CreateNetworkIPv6WithPrefixLength.Nameservers,
+			}
+		}
+	}
+	return update
 }
@@ -0,0 +1,263 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	iaas "github.com/stackitcloud/stackit-sdk-go/services/iaas/v2api"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/stackit"
+)
+
+const (
+	resultSuccess = "success"
+	resultError   = "error"
+
+	// statusClassUnknown is the status class of requests which failed without a response of the API, e.g. because of
+	// a network error.
+	statusClassUnknown = "unknown"
+)
+
+var (
+	// IaaSRequestDuration is the histogram of the durations of STACKIT IaaS API calls, labeled by operation, result and
+	// HTTP status class.
+	IaaSRequestDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "stackit",
+			Subsystem: "iaas",
+			Name:      "request_duration_seconds",
+			Help:      "Duration of the STACKIT IaaS API calls in seconds.",
+			Buckets:   prometheus.ExponentialBuckets(0.05, 2, 10),
+		},
+		[]string{"operation", "result", "status_class"},
+	)
+
+	// IaaSRequestsTotal is the counter of the STACKIT IaaS API calls, labeled by operation, result and HTTP status class.
+	IaaSRequestsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "stackit",
+			Subsystem: "iaas",
+			Name:      "requests_total",
+			Help:      "Number of STACKIT IaaS API calls.",
+		},
+		[]string{"operation", "result", "status_class"},
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(IaaSRequestDuration, IaaSRequestsTotal)
+}
+
+// instrumentedIaaSClient records the IaaSRequestDuration and IaaSRequestsTotal metrics for the calls of the delegate.
+type instrumentedIaaSClient struct {
+	delegate IaaSClient
+}
+
+// NewInstrumentedIaaSClient returns an IaaSClient which records metrics for all calls of the given client. Calls
+// consisting of multiple requests, e.g. UpdateSecurityGroupRules, are recorded as a single operation.
+func NewInstrumentedIaaSClient(delegate IaaSClient) IaaSClient {
+	return &instrumentedIaaSClient{delegate: delegate}
+}
+
+// observe calls fn and records its duration and result for the given operation.
+func observe[T any](operation string, fn func() (T, error)) (T, error) {
+	start := time.Now()
+	result, err := fn()
+
+	labels := prometheus.Labels{"operation": operation, "result": resultSuccess, "status_class": "2xx"}
+	if err != nil {
+		labels["result"] = resultError
+		labels["status_class"] = statusClass(err)
+	}
+	IaaSRequestDuration.With(labels).Observe(time.Since(start).Seconds())
+	IaaSRequestsTotal.With(labels).Inc()
+
+	return result, err
+}
+
+// observeNoResult is like observe for calls which only return an error.
+func observeNoResult(operation string, fn func() error) error {
+	_, err := observe(operation, func() (struct{}, error) {
+		return struct{}{}, fn()
+	})
+	return err
+}
+
+// statusClass returns the HTTP status class (e.g. "4xx") of the given error of a failed request.
+func statusClass(err error) string {
+	statusCode := GetStatusCode(err)
+	if statusCode < 100 || statusCode > 599 {
+		return statusClassUnknown
+	}
+	return fmt.Sprintf("%dxx", statusCode/100)
+}
+
+func (c *instrumentedIaaSClient) ProjectID() string {
+	return c.delegate.ProjectID()
+}
+
+func (c *instrumentedIaaSClient) CreateIsolatedNetwork(ctx context.Context, payload iaas.CreateIsolatedNetworkPayload) (*iaas.Network, error) {
+	return observe("CreateIsolatedNetwork", func() (*iaas.Network, error) {
+		return c.delegate.CreateIsolatedNetwork(ctx, payload)
+	})
+}
+
+func (c *instrumentedIaaSClient) GetNetworkById(ctx context.Context, id string) (*iaas.Network, error) {
+	return observe("GetNetworkById", func() (*iaas.Network, error) {
+		return c.delegate.GetNetworkById(ctx, id)
+	})
+}
+
+func (c *instrumentedIaaSClient) GetNetworkByName(ctx context.Context, name string) ([]iaas.Network, error) {
+	return observe("GetNetworkByName", func() ([]iaas.Network, error) {
+		return c.delegate.GetNetworkByName(ctx, name)
+	})
+}
+
+func (c *instrumentedIaaSClient) UpdateNetwork(ctx context.Context, networkId string, payload iaas.PartialUpdateNetworkPayload) (*iaas.Network, error) {
+	return observe("UpdateNetwork", func() (*iaas.Network, error) {
+		return c.delegate.UpdateNetwork(ctx, networkId, payload)
+	})
+}
+
+func (c *instrumentedIaaSClient) DeleteNetwork(ctx context.Context, networkID string) error {
+	return observeNoResult("DeleteNetwork", func() error {
+		return c.delegate.DeleteNetwork(ctx, networkID)
+	})
+}
+
+func (c *instrumentedIaaSClient) CreateSecurityGroup(ctx context.Context, payload iaas.CreateSecurityGroupPayload) (*iaas.SecurityGroup, error) {
+	return observe("CreateSecurityGroup", func() (*iaas.SecurityGroup, error) {
+		return c.delegate.CreateSecurityGroup(ctx, payload)
+	})
+}
+
+func (c *instrumentedIaaSClient) DeleteSecurityGroup(ctx context.Context, securityGroupId string) error {
+	return observeNoResult("DeleteSecurityGroup", func() error {
+		return c.delegate.DeleteSecurityGroup(ctx, securityGroupId)
+	})
+}
+
+func (c *instrumentedIaaSClient) GetSecurityGroupByName(ctx context.Context, name string) ([]iaas.SecurityGroup, error) {
+	return observe("GetSecurityGroupByName", func() ([]iaas.SecurityGroup, error) {
+		return c.delegate.GetSecurityGroupByName(ctx, name)
+	})
+}
+
+func (c *instrumentedIaaSClient) GetSecurityGroupById(ctx context.Context, securityGroupId string) (*iaas.SecurityGroup, error) {
+	return observe("GetSecurityGroupById", func() (*iaas.SecurityGroup, error) {
+		return c.delegate.GetSecurityGroupById(ctx, securityGroupId)
+	})
+}
+
+func (c *instrumentedIaaSClient) CreateSecurityGroupRule(ctx context.Context, securityGroupId string, wantedRule iaas.SecurityGroupRule) (*iaas.SecurityGroupRule, error) {
+	return observe("CreateSecurityGroupRule", func() (*iaas.SecurityGroupRule, error) {
+		return c.delegate.CreateSecurityGroupRule(ctx, securityGroupId, wantedRule)
+	})
+}
+
+func (c *instrumentedIaaSClient) ReconcileSecurityGroupRules(ctx context.Context, log logr.Logger, securityGroup *iaas.SecurityGroup, wantedRules []iaas.SecurityGroupRule) error {
+	return observeNoResult("ReconcileSecurityGroupRules", func() error {
+		return c.delegate.ReconcileSecurityGroupRules(ctx, log, securityGroup, wantedRules)
+	})
+}
+
+func (c *instrumentedIaaSClient) UpdateSecurityGroupRules(ctx context.Context, group *iaas.SecurityGroup, desiredRules []iaas.SecurityGroupRule, deleteDuplicates bool, allowDelete func(rule *iaas.SecurityGroupRule) bool) (bool, error) {
+	return observe("UpdateSecurityGroupRules", func() (bool, error) {
+		return c.delegate.UpdateSecurityGroupRules(ctx, group, desiredRules, deleteDuplicates, allowDelete)
+	})
+}
+
+func (c *instrumentedIaaSClient) CreateServer(ctx context.Context, payload iaas.CreateServerPayload) (*iaas.Server, error) {
+	return observe("CreateServer", func() (*iaas.Server, error) {
+		return c.delegate.CreateServer(ctx, payload)
+	})
+}
+
+func (c *instrumentedIaaSClient) DeleteServer(ctx context.Context, serverId string) error {
+	return observeNoResult("DeleteServer", func() error {
+		return c.delegate.DeleteServer(ctx, serverId)
+	})
+}
+
+func (c *instrumentedIaaSClient) DeleteServersByLabels(ctx context.Context, selector stackit.LabelSelector) (int, error) {
+	return observe("DeleteServersByLabels", func() (int, error) {
+		return c.delegate.DeleteServersByLabels(ctx, selector)
+	})
+}
+
+func (c *instrumentedIaaSClient) GetServerByName(ctx context.Context, name string) ([]iaas.Server, error) {
+	return observe("GetServerByName", func() ([]iaas.Server, error) {
+		return c.delegate.GetServerByName(ctx, name)
+	})
+}
+
+func (c *instrumentedIaaSClient) CreateAffinityGroup(ctx context.Context, name, policy string) (*iaas.AffinityGroup, error) {
+	return observe("CreateAffinityGroup", func() (*iaas.AffinityGroup, error) {
+		return c.delegate.CreateAffinityGroup(ctx, name, policy)
+	})
+}
+
+func (c *instrumentedIaaSClient) DeleteAffinityGroup(ctx context.Context, affinityGroupId string) error {
+	return observeNoResult("DeleteAffinityGroup", func() error {
+		return c.delegate.DeleteAffinityGroup(ctx, affinityGroupId)
+	})
+}
+
+func (c *instrumentedIaaSClient) GetAffinityGroupByName(ctx context.Context, name string) ([]iaas.AffinityGroup, error) {
+	return observe("GetAffinityGroupByName", func() ([]iaas.AffinityGroup, error) {
+		return c.delegate.GetAffinityGroupByName(ctx, name)
+	})
+}
+
+func (c *instrumentedIaaSClient) CreatePublicIp(ctx context.Context, payload iaas.CreatePublicIPPayload) (*iaas.PublicIp, error) {
+	return observe("CreatePublicIp", func() (*iaas.PublicIp, error) {
+		return c.delegate.CreatePublicIp(ctx, payload)
+	})
+}
+
+func (c *instrumentedIaaSClient) DeletePublicIp(ctx context.Context, publicIpId string) error {
+	return observeNoResult("DeletePublicIp", func() error {
+		return c.delegate.DeletePublicIp(ctx, publicIpId)
+	})
+}
+
+func (c *instrumentedIaaSClient) GetPublicIpByLabels(ctx context.Context, selector stackit.LabelSelector) ([]iaas.PublicIp, error) {
+	return observe("GetPublicIpByLabels", func() ([]iaas.PublicIp, error) {
+		return c.delegate.GetPublicIpByLabels(ctx, selector)
+	})
+}
+
+func (c *instrumentedIaaSClient) AddPublicIpToServer(ctx context.Context, serverId, publicIpId string) error {
+	return observeNoResult("AddPublicIpToServer", func() error {
+		return c.delegate.AddPublicIpToServer(ctx, serverId, publicIpId)
+	})
+}
+
+func (c *instrumentedIaaSClient) GetKeypair(ctx context.Context, name string) (*iaas.Keypair, error) {
+	return observe("GetKeypair", func() (*iaas.Keypair, error) {
+		return c.delegate.GetKeypair(ctx, name)
+	})
+}
+
+func (c *instrumentedIaaSClient) CreateKeypair(ctx context.Context, name, publicKey string) (*iaas.Keypair, error) {
+	return observe("CreateKeypair", func() (*iaas.Keypair, error) {
+		return c.delegate.CreateKeypair(ctx, name, publicKey)
+	})
+}
+
+func (c *instrumentedIaaSClient) DeleteKeypair(ctx context.Context, name string) error {
+	return observeNoResult("DeleteKeypair", func() error {
+		return c.delegate.DeleteKeypair(ctx, name)
+	})
+}
+
+func (c *instrumentedIaaSClient) GetImageById(ctx context.Context, id string) (*iaas.Image, error) {
+	return observe("GetImageById", func() (*iaas.Image, error) {
+		return c.delegate.GetImageById(ctx, id)
+	})
+}
@@ -0,0 +1,87 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stackitcloud/stackit-sdk-go/core/oapierror"
+	iaas "github.com/stackitcloud/stackit-sdk-go/services/iaas/v2api"
+	"go.uber.org/mock/gomock"
+
+	mockclient "github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/stackit/client/mock"
+)
+
+var _ = Describe("InstrumentedIaaSClient", func() {
+	var (
+		ctx      context.Context
+		ctrl     *gomock.Controller
+		delegate *mockclient.MockIaaSClient
+		client   IaaSClient
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		ctrl = gomock.NewController(GinkgoT())
+		delegate = mockclient.NewMockIaaSClient(ctrl)
+		client = NewInstrumentedIaaSClient(delegate)
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	requests := func(operation, result, statusClass string) float64 {
+		GinkgoHelper()
+		metric := &dto.Metric{}
+		Expect(IaaSRequestsTotal.WithLabelValues(operation, result, statusClass).Write(metric)).To(Succeed())
+		return metric.GetCounter().GetValue()
+	}
+
+	observations := func(operation, result, statusClass string) uint64 {
+		GinkgoHelper()
+		observer, err := IaaSRequestDuration.GetMetricWithLabelValues(operation, result, statusClass)
+		Expect(err).NotTo(HaveOccurred())
+		metric := &dto.Metric{}
+		Expect(observer.(prometheus.Metric).Write(metric)).To(Succeed())
+		return metric.GetHistogram().GetSampleCount()
+	}
+
+	It("should record successful calls", func() {
+		before := requests("GetNetworkById", "success", "2xx")
+		beforeObservations := observations("GetNetworkById", "success", "2xx")
+		network := &iaas.Network{Id: "network-id"}
+		delegate.EXPECT().GetNetworkById(ctx, "network-id").Return(network, nil)
+
+		Expect(client.GetNetworkById(ctx, "network-id")).To(Equal(network))
+		Expect(requests("GetNetworkById", "success", "2xx")).To(Equal(before + 1))
+		Expect(observations("GetNetworkById", "success", "2xx")).To(Equal(beforeObservations + 1))
+	})
+
+	It("should record failed calls with the status class of the response", func() {
+		before := requests("DeleteNetwork", "error", "4xx")
+		delegate.EXPECT().DeleteNetwork(ctx, "network-id").Return(&oapierror.GenericOpenAPIError{StatusCode: http.StatusConflict})
+
+		Expect(client.DeleteNetwork(ctx, "network-id")).NotTo(Succeed())
+		Expect(requests("DeleteNetwork", "error", "4xx")).To(Equal(before + 1))
+	})
+
+	It("should record failed calls without a response", func() {
+		before := requests("GetKeypair", "error", "unknown")
+		delegate.EXPECT().GetKeypair(ctx, "key").Return(nil, errors.New("connection refused"))
+
+		_, err := client.GetKeypair(ctx, "key")
+		Expect(err).To(MatchError("connection refused"))
+		Expect(requests("GetKeypair", "error", "unknown")).To(Equal(before + 1))
+	})
+
+	It("should pass through the project ID", func() {
+		delegate.EXPECT().ProjectID().Return("project-id")
+
+		Expect(client.ProjectID()).To(Equal("project-id"))
+	})
+})
@@ -0,0 +1,441 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stackitcloud/stackit-sdk-go/core/oapierror"
+	iaas "github.com/stackitcloud/stackit-sdk-go/services/iaas/v2api"
+	"go.uber.org/mock/gomock"
+
+	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/stackit"
+	mock "github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/stackit/client/mock/iaas"
+)
+
+var _ = Describe("IaaSClient", func() {
+	Describe("#securityGroupRuleToCreatePayload", func() {
+		var rule iaas.SecurityGroupRule
+
+		BeforeEach(func() {
+			rule = iaas.SecurityGroupRule{
+				Direction:   stackit.DirectionIngress,
+				Ethertype:   new(stackit.EtherTypeIPv4),
+				Description: new("rule"),
+			}
+		})
+
+		DescribeTable("should keep the port range for protocols supporting it",
+			func(protocol string) {
+				rule.Protocol = &iaas.Protocol{Name: new(protocol)}
+				rule.PortRange = &iaas.PortRange{Min: 1000, Max: 2000}
+
+				payload, err := securityGroupRuleToCreatePayload(rule)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(payload.PortRange).To(Equal(&iaas.PortRange{Min: 1000, Max: 2000}))
+				Expect(payload.Protocol).To(Equal(new(iaas.StringAsCreateProtocol(new(protocol)))))
+			},
+			Entry("tcp", "tcp"),
+			Entry("udp", "udp"),
+			Entry("sctp", "sctp"),
+			Entry("dccp", "dccp"),
+			Entry("udplite", "udplite"),
+		)
+
+		DescribeTable("should reject the port range for protocols not supporting it",
+			func(protocol string) {
+				rule.Protocol = &iaas.Protocol{Name: new(protocol)}
+				rule.PortRange = &iaas.PortRange{Min: 1000, Max: 2000}
+
+				_, err := securityGroupRuleToCreatePayload(rule)
+				Expect(err).To(MatchError(ContainSubstring("port ranges are only supported for the protocols dccp, sctp, tcp, udp, udplite, not for protocol %q", protocol)))
+			},
+			Entry("icmp", "icmp"),
+			Entry("ipip", "ipip"),
+		)
+
+		It("should reject a port range without protocol", func() {
+			rule.PortRange = &iaas.PortRange{Min: 1000, Max: 2000}
+
+			_, err := securityGroupRuleToCreatePayload(rule)
+			Expect(err).To(MatchError(ContainSubstring(`not for protocol ""`)))
+		})
+
+		It("should allow rules without port range for any protocol", func() {
+			rule.Protocol = &iaas.Protocol{Name: new("icmp")}
+
+			payload, err := securityGroupRuleToCreatePayload(rule)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(payload.PortRange).To(BeNil())
+			Expect(payload.Direction).To(Equal(stackit.DirectionIngress))
+		})
+	})
+
+	Describe("#findDuplicateRule", func() {
+		var matched []iaas.SecurityGroupRule
+
+		BeforeEach(func() {
+			matched = []iaas.SecurityGroupRule{
+				{
+					Id:          new("rule-1"),
+					Direction:   stackit.DirectionIngress,
+					Ethertype:   new(stackit.EtherTypeIPv4),
+					Protocol:    &iaas.Protocol{Name: new(stackit.ProtocolTCP)},
+					PortRange:   &iaas.PortRange{Min: 30000, Max: 32767},
+					IpRange:     new("0.0.0.0/0"),
+					Description: new("node ports"),
+				},
+			}
+		})
+
+		It("should find an existing rule duplicating a matched rule", func() {
+			duplicate := matched[0]
+			duplicate.Id = new("rule-2")
+
+			Expect(findDuplicateRule(duplicate, matched)).To(Equal(&matched[0]))
+		})
+
+		It("should not treat rules with another description as duplicates", func() {
+			other := matched[0]
+			other.Id = new("rule-2")
+			other.Description = new("other")
+
+			Expect(findDuplicateRule(other, matched)).To(BeNil())
+		})
+
+		It("should not treat rules with another port range as duplicates", func() {
+			other := matched[0]
+			other.Id = new("rule-2")
+			other.PortRange = &iaas.PortRange{Min: 30000, Max: 30001}
+
+			Expect(findDuplicateRule(other, matched)).To(BeNil())
+		})
+	})
+
+	Describe("lookups by name", func() {
+		var (
+			ctx     context.Context
+			mockAPI *mock.MockDefaultAPI
+			client  IaaSClient
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			mockAPI = mock.NewMockDefaultAPI(gomock.NewController(GinkgoT()))
+			client = &iaasClient{Client: mockAPI, projectID: "test-project", region: "eu01"}
+		})
+
+		// The list endpoints of networks and security groups are not paginated, so a single request returns all items
+		// of the project.
+		It("#GetNetworkByName should return all networks with the name from a single list request", func() {
+			mockAPI.EXPECT().ListNetworks(gomock.Any(), "test-project", "eu01").Return(iaas.ApiListNetworksRequest{ApiService: mockAPI})
+			mockAPI.EXPECT().ListNetworksExecute(gomock.Any()).Return(&iaas.NetworkListResponse{Items: []iaas.Network{
+				{Id: "network-1", Name: "other"},
+				{Id: "network-2", Name: "shoot--foo--bar"},
+				{Id: "network-3", Name: "other"},
+				{Id: "network-4", Name: "shoot--foo--bar"},
+			}}, nil)
+
+			networks, err := client.GetNetworkByName(ctx, "shoot--foo--bar")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(networks).To(Equal([]iaas.Network{
+				{Id: "network-2", Name: "shoot--foo--bar"},
+				{Id: "network-4", Name: "shoot--foo--bar"},
+			}))
+		})
+
+		It("#GetSecurityGroupByName should return all security groups with the name from a single list request", func() {
+			mockAPI.EXPECT().ListSecurityGroups(gomock.Any(), "test-project", "eu01").Return(iaas.ApiListSecurityGroupsRequest{ApiService: mockAPI})
+			mockAPI.EXPECT().ListSecurityGroupsExecute(gomock.Any()).Return(&iaas.SecurityGroupListResponse{Items: []iaas.SecurityGroup{
+				{Id: new("group-1"), Name: "other"},
+				{Id: new("group-2"), Name: "other"},
+				{Id: new("group-3"), Name: "shoot--foo--bar"},
+			}}, nil)
+
+			groups, err := client.GetSecurityGroupByName(ctx, "shoot--foo--bar")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(groups).To(Equal([]iaas.SecurityGroup{{Id: new("group-3"), Name: "shoot--foo--bar"}}))
+		})
+	})
+
+	Describe("#UpdateSecurityGroupRules", func() {
+		var (
+			ctx     context.Context
+			mockAPI *mock.MockDefaultAPI
+			client  IaaSClient
+			desired iaas.SecurityGroupRule
+			group   *iaas.SecurityGroup
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			mockAPI = mock.NewMockDefaultAPI(gomock.NewController(GinkgoT()))
+			client = &iaasClient{Client: mockAPI, projectID: "test-project", region: "eu01"}
+
+			desired = iaas.SecurityGroupRule{
+				Direction:   stackit.DirectionIngress,
+				Ethertype:   new(stackit.EtherTypeIPv4),
+				IpRange:     new("10.0.0.0/8"),
+				Description: new("internal"),
+			}
+			rule := func(id string) iaas.SecurityGroupRule {
+				r := desired
+				r.Id = new(id)
+				return r
+			}
+			group = &iaas.SecurityGroup{Id: new("group"), Rules: []iaas.SecurityGroupRule{rule("rule-1"), rule("rule-2")}}
+		})
+
+		expectDeleteRule := func(id string) {
+			mockAPI.EXPECT().DeleteSecurityGroupRule(gomock.Any(), "test-project", "eu01", "group", id).Return(iaas.ApiDeleteSecurityGroupRuleRequest{ApiService: mockAPI})
+			mockAPI.EXPECT().DeleteSecurityGroupRuleExecute(gomock.Any()).Return(nil)
+		}
+
+		It("should delete duplicates of desired rules if requested", func() {
+			expectDeleteRule("rule-2")
+
+			modified, err := client.UpdateSecurityGroupRules(ctx, group, []iaas.SecurityGroupRule{desired}, true, func(*iaas.SecurityGroupRule) bool { return false })
+			Expect(err).NotTo(HaveOccurred())
+			Expect(modified).To(BeTrue())
+		})
+
+		It("should keep duplicates of desired rules unless they may be deleted", func() {
+			modified, err := client.UpdateSecurityGroupRules(ctx, group, []iaas.SecurityGroupRule{desired}, false, func(*iaas.SecurityGroupRule) bool { return false })
+			Expect(err).NotTo(HaveOccurred())
+			Expect(modified).To(BeFalse())
+		})
+
+		It("should delete duplicates of desired rules which may be deleted without deleting duplicates", func() {
+			expectDeleteRule("rule-2")
+
+			modified, err := client.UpdateSecurityGroupRules(ctx, group, []iaas.SecurityGroupRule{desired}, false, func(*iaas.SecurityGroupRule) bool { return true })
+			Expect(err).NotTo(HaveOccurred())
+			Expect(modified).To(BeTrue())
+		})
+
+		It("should not modify rules which only differ in their order", func() {
+			// security group rules have no priority in the STACKIT API, they only allow traffic
+			other := desired
+			other.IpRange = new("192.168.0.0/16")
+			existing := desired
+			existing.Id = new("rule-1")
+			existingOther := other
+			existingOther.Id = new("rule-2")
+			group.Rules = []iaas.SecurityGroupRule{existingOther, existing}
+
+			modified, err := client.UpdateSecurityGroupRules(ctx, group, []iaas.SecurityGroupRule{desired, other}, true, func(*iaas.SecurityGroupRule) bool { return true })
+			Expect(err).NotTo(HaveOccurred())
+			Expect(modified).To(BeFalse())
+		})
+	})
+
+	Describe("#IsolatedNetworkToPartialUpdate", func() {
+		It("should keep the DHCP setting and nameservers", func() {
+			update := IsolatedNetworkToPartialUpdate(nil, iaas.CreateIsolatedNetworkPayload{
+				Name: "network",
+				Dhcp: new(false),
+				Ipv4: &iaas.CreateNetworkIPv4{
+					CreateNetworkIPv4WithPrefix: &iaas.CreateNetworkIPv4WithPrefix{
+						Prefix:      "10.250.0.0/16",
+						Nameservers: []string{"1.1.1.1"},
+					},
+				},
+			})
+
+			Expect(update.Name).To(HaveValue(Equal("network")))
+			Expect(update.Dhcp).To(HaveValue(BeFalse()))
+			Expect(update.Ipv4.Nameservers).To(Equal([]string{"1.1.1.1"}))
+			Expect(update.Ipv6).To(BeNil())
+		})
+
+		It("should keep the IPv6 nameservers", func() {
+			update := IsolatedNetworkToPartialUpdate(nil, iaas.CreateIsolatedNetworkPayload{
+				Name: "network",
+				Ipv4: &iaas.CreateNetworkIPv4{
+					CreateNetworkIPv4WithPrefix: &iaas.CreateNetworkIPv4WithPrefix{Prefix: "10.250.0.0/16"},
+				},
+				Ipv6: &iaas.CreateNetworkIPv6{
+					CreateNetworkIPv6WithPrefixLength: &iaas.CreateNetworkIPv6WithPrefixLength{
+						PrefixLength: 64,
+						Nameservers:  []string{"2001:4860:4860::8888"},
+					},
+				},
+			})
+
+			Expect(update.Ipv6).NotTo(BeNil())
+			Expect(update.Ipv6.Nameservers).To(Equal([]string{"2001:4860:4860::8888"}))
+		})
+
+		It("should merge the desired labels into the labels of the current network", func() {
+			current := &iaas.Network{Id: "network", Labels: map[string]any{"foo": "bar", "kubernetes.io/cluster": "other"}}
+
+			update := IsolatedNetworkToPartialUpdate(current, iaas.CreateIsolatedNetworkPayload{
+				Name:   "network",
+				Labels: map[string]any{"kubernetes.io/cluster": "shoot--foo--bar"},
+				Ipv4: &iaas.CreateNetworkIPv4{
+					CreateNetworkIPv4WithPrefix: &iaas.CreateNetworkIPv4WithPrefix{Prefix: "10.250.0.0/16"},
+				},
+			})
+
+			Expect(update.Labels).To(Equal(map[string]any{"foo": "bar", "kubernetes.io/cluster": "shoot--foo--bar"}))
+			Expect(current.Labels).To(HaveKeyWithValue("kubernetes.io/cluster", "other"))
+		})
+
+		It("should not update the labels without desired labels", func() {
+			update := IsolatedNetworkToPartialUpdate(&iaas.Network{Id: "network", Labels: map[string]any{"foo": "bar"}}, iaas.CreateIsolatedNetworkPayload{
+				Name: "network",
+				Ipv4: &iaas.CreateNetworkIPv4{
+					CreateNetworkIPv4WithPrefix: &iaas.CreateNetworkIPv4WithPrefix{Prefix: "10.250.0.0/16"},
+				},
+			})
+
+			Expect(update.Labels).To(BeNil())
+		})
+	})
+
+	Describe("#DeleteServersByLabels", func() {
+		var (
+			ctx     context.Context
+			mockAPI *mock.MockDefaultAPI
+			client  IaaSClient
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			mockAPI = mock.NewMockDefaultAPI(gomock.NewController(GinkgoT()))
+			client = &iaasClient{Client: mockAPI, projectID: "test-project", region: "eu01"}
+		})
+
+		expectListServers := func(response *iaas.ServerListResponse, err error) {
+			mockAPI.EXPECT().ListServers(gomock.Any(), "test-project", "eu01").Return(iaas.ApiListServersRequest{ApiService: mockAPI})
+			mockAPI.EXPECT().ListServersExecute(gomock.Any()).Return(response, err)
+		}
+
+		expectDeleteServer := func(id string, err error) {
+			mockAPI.EXPECT().DeleteServer(gomock.Any(), "test-project", "eu01", id).Return(iaas.ApiDeleteServerRequest{ApiService: mockAPI})
+			mockAPI.EXPECT().DeleteServerExecute(gomock.Any()).Return(err)
+		}
+
+		It("should only delete matching servers and ignore servers which are already gone", func() {
+			expectListServers(&iaas.ServerListResponse{Items: []iaas.Server{
+				{Id: new("server-1"), Labels: map[string]any{"cluster": "shoot--foo--bar"}},
+				{Id: new("server-2"), Labels: map[string]any{"cluster": "shoot--foo--other"}},
+				{Id: new("server-3"), Labels: map[string]any{"cluster": "shoot--foo--bar", "role": "node"}},
+				{Id: new("server-4")},
+				{Id: new("server-5"), Labels: map[string]any{"cluster": "shoot--foo--bar"}},
+			}}, nil)
+			gomock.InOrder(
+				expectDeleteServer("server-1", nil),
+				expectDeleteServer("server-3", &oapierror.GenericOpenAPIError{StatusCode: http.StatusNotFound}),
+				expectDeleteServer("server-5", nil),
+			)
+
+			deleted, err := client.DeleteServersByLabels(ctx, stackit.LabelSelector{"cluster": "shoot--foo--bar"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deleted).To(Equal(2))
+		})
+
+		It("should continue deleting servers after a failure and return the errors", func() {
+			expectListServers(&iaas.ServerListResponse{Items: []iaas.Server{
+				{Id: new("server-1"), Labels: map[string]any{"cluster": "shoot--foo--bar"}},
+				{Id: new("server-2"), Labels: map[string]any{"cluster": "shoot--foo--bar"}},
+			}}, nil)
+			gomock.InOrder(
+				expectDeleteServer("server-1", &oapierror.GenericOpenAPIError{StatusCode: http.StatusConflict}),
+				expectDeleteServer("server-2", nil),
+			)
+
+			deleted, err := client.DeleteServersByLabels(ctx, stackit.LabelSelector{"cluster": "shoot--foo--bar"})
+			Expect(err).To(MatchError(ContainSubstring("error deleting server server-1")))
+			Expect(GetStatusCode(err)).To(Equal(http.StatusConflict))
+			Expect(deleted).To(Equal(1))
+		})
+
+		It("should fail if the servers cannot be listed", func() {
+			expectListServers(nil, &oapierror.GenericOpenAPIError{StatusCode: http.StatusForbidden})
+
+			deleted, err := client.DeleteServersByLabels(ctx, stackit.LabelSelector{"cluster": "shoot--foo--bar"})
+			Expect(err).To(MatchError(ContainSubstring("error listing servers")))
+			Expect(deleted).To(BeZero())
+		})
+
+		DescribeTable("should refuse to delete servers with an empty selector", func(selector stackit.LabelSelector) {
+			deleted, err := client.DeleteServersByLabels(ctx, selector)
+			Expect(err).To(MatchError(ContainSubstring("empty label selector")))
+			Expect(deleted).To(BeZero())
+		},
+			Entry("nil selector", nil),
+			Entry("empty selector", stackit.LabelSelector{}),
+		)
+	})
+
+	Describe("retries", func() {
+		var (
+			ctx     context.Context
+			mockAPI *mock.MockDefaultAPI
+			client  IaaSClient
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			mockAPI = mock.NewMockDefaultAPI(gomock.NewController(GinkgoT()))
+			c := &iaasClient{Client: mockAPI, projectID: "test-project", region: "eu01"}
+			WithRetryConfig(RetryConfig{MaxAttempts: 3, BaseDelay: time.Millisecond})(c)
+			client = c
+		})
+
+		It("should retry transient errors", func() {
+			mockAPI.EXPECT().GetNetwork(gomock.Any(), "test-project", "eu01", "network").Return(iaas.ApiGetNetworkRequest{ApiService: mockAPI})
+			gomock.InOrder(
+				mockAPI.EXPECT().GetNetworkExecute(gomock.Any()).Return(nil, &oapierror.GenericOpenAPIError{StatusCode: http.StatusTooManyRequests}),
+				mockAPI.EXPECT().GetNetworkExecute(gomock.Any()).Return(nil, &oapierror.GenericOpenAPIError{StatusCode: http.StatusBadGateway}),
+				mockAPI.EXPECT().GetNetworkExecute(gomock.Any()).Return(&iaas.Network{Id: "network"}, nil),
+			)
+
+			network, err := client.GetNetworkById(ctx, "network")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(network.Id).To(Equal("network"))
+		})
+
+		It("should give up after the maximum attempts", func() {
+			mockAPI.EXPECT().DeleteNetwork(gomock.Any(), "test-project", "eu01", "network").Return(iaas.ApiDeleteNetworkRequest{ApiService: mockAPI})
+			mockAPI.EXPECT().DeleteNetworkExecute(gomock.Any()).Return(&oapierror.GenericOpenAPIError{StatusCode: http.StatusServiceUnavailable}).Times(3)
+
+			err := client.DeleteNetwork(ctx, "network")
+			Expect(GetStatusCode(err)).To(Equal(http.StatusServiceUnavailable))
+		})
+
+		DescribeTable("should not retry client errors",
+			func(statusCode int) {
+				mockAPI.EXPECT().DeleteNetwork(gomock.Any(), "test-project", "eu01", "network").Return(iaas.ApiDeleteNetworkRequest{ApiService: mockAPI})
+				mockAPI.EXPECT().DeleteNetworkExecute(gomock.Any()).Return(&oapierror.GenericOpenAPIError{StatusCode: statusCode}).Times(1)
+
+				err := client.DeleteNetwork(ctx, "network")
+				Expect(GetStatusCode(err)).To(Equal(statusCode))
+			},
+			Entry("not found", http.StatusNotFound),
+			Entry("conflict", http.StatusConflict),
+		)
+
+		It("should only retry create requests rejected with 429", func() {
+			mockAPI.EXPECT().CreateIsolatedNetwork(gomock.Any(), "test-project", "eu01").Return(iaas.ApiCreateIsolatedNetworkRequest{ApiService: mockAPI})
+			gomock.InOrder(
+				mockAPI.EXPECT().CreateIsolatedNetworkExecute(gomock.Any()).Return(nil, &oapierror.GenericOpenAPIError{StatusCode: http.StatusTooManyRequests}),
+				mockAPI.EXPECT().CreateIsolatedNetworkExecute(gomock.Any()).Return(nil, &oapierror.GenericOpenAPIError{StatusCode: http.StatusBadGateway}),
+			)
+
+			_, err := client.CreateIsolatedNetwork(ctx, iaas.CreateIsolatedNetworkPayload{})
+			Expect(GetStatusCode(err)).To(Equal(http.StatusBadGateway))
+		})
+
+		It("should not retry without retry config", func() {
+			client = &iaasClient{Client: mockAPI, projectID: "test-project", region: "eu01"}
+			mockAPI.EXPECT().DeleteNetwork(gomock.Any(), "test-project", "eu01", "network").Return(iaas.ApiDeleteNetworkRequest{ApiService: mockAPI})
+			mockAPI.EXPECT().DeleteNetworkExecute(gomock.Any()).Return(&oapierror.GenericOpenAPIError{StatusCode: http.StatusServiceUnavailable}).Times(1)
+
+			Expect(client.DeleteNetwork(ctx, "network")).NotTo(Succeed())
+		})
+	})
+})
@@ -5,6 +5,7 @@ import (
 
 	sdkconfig "github.com/stackitcloud/stackit-sdk-go/core/config"
 	loadbalancer "github.com/stackitcloud/stackit-sdk-go/services/loadbalancer/v2api"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	stackitv1alpha1 "github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/apis/stackit/v1alpha1"
 	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/stackit"
@@ -26,8 +27,8 @@ type loadBalancingClient struct {
 	region    string
 }
 
-func NewLoadBalancingClient(_ context.Context, region string, endpoints stackitv1alpha1.APIEndpoints, credentials *stackit.Credentials, caBundle string) (LoadBalancingClient, error) {
-	options, err := clientOptions(endpoints, credentials, caBundle)
+func NewLoadBalancingClient(_ context.Context, region string, endpoints stackitv1alpha1.APIEndpoints, credentials *stackit.Credentials, caBundle string, requestTimeout *metav1.Duration) (LoadBalancingClient, error) {
+	options, err := clientOptions(endpoints, credentials, caBundle, requestTimeout)
 	if err != nil {
 		return nil, err
 	}
@@ -51,26 +52,34 @@ func (l loadBalancingClient) ProjectID() string {
 }
 
 func (l loadBalancingClient) CreateLoadBalancer(ctx context.Context, payload loadbalancer.CreateLoadBalancerPayload) (*loadbalancer.LoadBalancer, error) {
-	return l.Client.CreateLoadBalancer(ctx, l.projectID, l.region).CreateLoadBalancerPayload(payload).Execute()
+	ctx, withRequestID := captureRequestID(ctx)
+	lb, err := l.Client.CreateLoadBalancer(ctx, l.projectID, l.region).CreateLoadBalancerPayload(payload).Execute()
+	return lb, withRequestID(err)
 }
 
 func (l loadBalancingClient) GetLoadBalancer(ctx context.Context, name string) (*loadbalancer.LoadBalancer, error) {
-	return l.Client.GetLoadBalancer(ctx, l.projectID, l.region, name).Execute()
+	ctx, withRequestID := captureRequestID(ctx)
+	lb, err := l.Client.GetLoadBalancer(ctx, l.projectID, l.region, name).Execute()
+	return lb, withRequestID(err)
 }
 
 func (l loadBalancingClient) ListLoadBalancers(ctx context.Context) ([]loadbalancer.LoadBalancer, error) {
+	ctx, withRequestID := captureRequestID(ctx)
 	lbResponse, err := l.Client.ListLoadBalancers(ctx, l.projectID, l.region).Execute()
 	if err != nil {
-		return nil, err
+		return nil, withRequestID(err)
 	}
 	return lbResponse.GetLoadBalancers(), nil
 }
 
 func (l loadBalancingClient) UpdateLoadBalancer(ctx context.Context, name string, payload loadbalancer.UpdateLoadBalancerPayload) (*loadbalancer.LoadBalancer, error) {
-	return l.Client.UpdateLoadBalancer(ctx, l.projectID, l.region, name).UpdateLoadBalancerPayload(payload).Execute()
+	ctx, withRequestID := captureRequestID(ctx)
+	lb, err := l.Client.UpdateLoadBalancer(ctx, l.projectID, l.region, name).UpdateLoadBalancerPayload(payload).Execute()
+	return lb, withRequestID(err)
 }
 
 func (l loadBalancingClient) DeleteLoadBalancer(ctx context.Context, name string) error {
+	ctx, withRequestID := captureRequestID(ctx)
 	_, err := l.Client.DeleteLoadBalancer(ctx, l.projectID, l.region, name).Execute()
-	return err
+	return withRequestID(err)
 }
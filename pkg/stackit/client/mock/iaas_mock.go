@@ -57,6 +57,21 @@ func (mr *MockIaaSClientMockRecorder) AddPublicIpToServer(ctx, serverId, publicI
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddPublicIpToServer", reflect.TypeOf((*MockIaaSClient)(nil).AddPublicIpToServer), ctx, serverId, publicIpId)
 }
 
+// CreateAffinityGroup mocks base method.
+func (m *MockIaaSClient) CreateAffinityGroup(ctx context.Context, name, policy string) (*v2api.AffinityGroup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAffinityGroup", ctx, name, policy)
+	ret0, _ := ret[0].(*v2api.AffinityGroup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAffinityGroup indicates an expected call of CreateAffinityGroup.
+func (mr *MockIaaSClientMockRecorder) CreateAffinityGroup(ctx, name, policy any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAffinityGroup", reflect.TypeOf((*MockIaaSClient)(nil).CreateAffinityGroup), ctx, name, policy)
+}
+
 // CreateIsolatedNetwork mocks base method.
 func (m *MockIaaSClient) CreateIsolatedNetwork(ctx context.Context, payload v2api.CreateIsolatedNetworkPayload) (*v2api.Network, error) {
 	m.ctrl.T.Helper()
@@ -147,6 +162,20 @@ func (mr *MockIaaSClientMockRecorder) CreateServer(ctx, payload any) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateServer", reflect.TypeOf((*MockIaaSClient)(nil).CreateServer), ctx, payload)
 }
 
+// DeleteAffinityGroup mocks base method.
+func (m *MockIaaSClient) DeleteAffinityGroup(ctx context.Context, affinityGroupId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAffinityGroup", ctx, affinityGroupId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAffinityGroup indicates an expected call of DeleteAffinityGroup.
+func (mr *MockIaaSClientMockRecorder) DeleteAffinityGroup(ctx, affinityGroupId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAffinityGroup", reflect.TypeOf((*MockIaaSClient)(nil).DeleteAffinityGroup), ctx, affinityGroupId)
+}
+
 // DeleteKeypair mocks base method.
 func (m *MockIaaSClient) DeleteKeypair(ctx context.Context, name string) error {
 	m.ctrl.T.Helper()
@@ -217,6 +246,51 @@ func (mr *MockIaaSClientMockRecorder) DeleteServer(ctx, serverId any) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteServer", reflect.TypeOf((*MockIaaSClient)(nil).DeleteServer), ctx, serverId)
 }
 
+// DeleteServersByLabels mocks base method.
+func (m *MockIaaSClient) DeleteServersByLabels(ctx context.Context, selector stackit.LabelSelector) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteServersByLabels", ctx, selector)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteServersByLabels indicates an expected call of DeleteServersByLabels.
+func (mr *MockIaaSClientMockRecorder) DeleteServersByLabels(ctx, selector any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteServersByLabels", reflect.TypeOf((*MockIaaSClient)(nil).DeleteServersByLabels), ctx, selector)
+}
+
+// GetAffinityGroupByName mocks base method.
+func (m *MockIaaSClient) GetAffinityGroupByName(ctx context.Context, name string) ([]v2api.AffinityGroup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAffinityGroupByName", ctx, name)
+	ret0, _ := ret[0].([]v2api.AffinityGroup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAffinityGroupByName indicates an expected call of GetAffinityGroupByName.
+func (mr *MockIaaSClientMockRecorder) GetAffinityGroupByName(ctx, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAffinityGroupByName", reflect.TypeOf((*MockIaaSClient)(nil).GetAffinityGroupByName), ctx, name)
+}
+
+// GetImageById mocks base method.
+func (m *MockIaaSClient) GetImageById(ctx context.Context, id string) (*v2api.Image, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetImageById", ctx, id)
+	ret0, _ := ret[0].(*v2api.Image)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetImageById indicates an expected call of GetImageById.
+func (mr *MockIaaSClientMockRecorder) GetImageById(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetImageById", reflect.TypeOf((*MockIaaSClient)(nil).GetImageById), ctx, id)
+}
+
 // GetKeypair mocks base method.
 func (m *MockIaaSClient) GetKeypair(ctx context.Context, name string) (*v2api.Keypair, error) {
 	m.ctrl.T.Helper()
@@ -366,16 +440,16 @@ func (mr *MockIaaSClientMockRecorder) UpdateNetwork(ctx, networkId, payload any)
 }
 
 // UpdateSecurityGroupRules mocks base method.
-func (m *MockIaaSClient) UpdateSecurityGroupRules(ctx context.Context, group *v2api.SecurityGroup, desiredRules []v2api.SecurityGroupRule, allowDelete func(*v2api.SecurityGroupRule) bool) (bool, error) {
+func (m *MockIaaSClient) UpdateSecurityGroupRules(ctx context.Context, group *v2api.SecurityGroup, desiredRules []v2api.SecurityGroupRule, deleteDuplicates bool, allowDelete func(*v2api.SecurityGroupRule) bool) (bool, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateSecurityGroupRules", ctx, group, desiredRules, allowDelete)
+	ret := m.ctrl.Call(m, "UpdateSecurityGroupRules", ctx, group, desiredRules, deleteDuplicates, allowDelete)
 	ret0, _ := ret[0].(bool)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // UpdateSecurityGroupRules indicates an expected call of UpdateSecurityGroupRules.
-func (mr *MockIaaSClientMockRecorder) UpdateSecurityGroupRules(ctx, group, desiredRules, allowDelete any) *gomock.Call {
+func (mr *MockIaaSClientMockRecorder) UpdateSecurityGroupRules(ctx, group, desiredRules, deleteDuplicates, allowDelete any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSecurityGroupRules", reflect.TypeOf((*MockIaaSClient)(nil).UpdateSecurityGroupRules), ctx, group, desiredRules, allowDelete)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSecurityGroupRules", reflect.TypeOf((*MockIaaSClient)(nil).UpdateSecurityGroupRules), ctx, group, desiredRules, deleteDuplicates, allowDelete)
 }
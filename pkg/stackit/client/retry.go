@@ -0,0 +1,149 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// DefaultRetryConfig is the retry configuration of the clients created by the Factory.
+var DefaultRetryConfig = RetryConfig{
+	MaxAttempts: 3,
+	BaseDelay:   time.Second,
+	MaxDelay:    10 * time.Second,
+	Jitter:      0.1,
+}
+
+// RetryConfig configures the retries of failed STACKIT API requests. Only requests failing with 429, a 5xx status code
+// or a transient network error are retried, the delay between the attempts grows exponentially.
+type RetryConfig struct {
+	// MaxAttempts is the maximum number of attempts of a request including the first one. Values below 2 disable the
+	// retries.
+	MaxAttempts int
+	// BaseDelay is the delay before the first retry, it is doubled for every further retry.
+	BaseDelay time.Duration
+	// MaxDelay caps the delay between two attempts. No cap is applied if it is 0.
+	MaxDelay time.Duration
+	// Jitter is the maximum factor by which a delay is randomly increased, e.g. 0.1 adds up to 10% to each delay.
+	Jitter float64
+	// AttemptTimeout is the timeout of a single attempt. Attempts running into it are retried. No timeout is applied if
+	// it is 0.
+	AttemptTimeout time.Duration
+}
+
+// delay returns the delay before the n-th retry, starting at 0 for the first retry.
+func (c RetryConfig) delay(n int) time.Duration {
+	delay := c.BaseDelay
+	for range n {
+		if c.MaxDelay > 0 && delay >= c.MaxDelay {
+			break
+		}
+		delay *= 2
+	}
+	if c.Jitter > 0 {
+		delay = wait.Jitter(delay, c.Jitter)
+	}
+	if c.MaxDelay > 0 {
+		delay = min(delay, c.MaxDelay)
+	}
+	return delay
+}
+
+// Retry calls fn until it succeeds, fails with an error which is not retriable or the attempts of the given
+// configuration are exhausted. The error of the last attempt is returned. Waiting for the next attempt stops
+// immediately if the context is canceled. fn is only called once if config is nil.
+func Retry[T any](ctx context.Context, config *RetryConfig, fn func(context.Context) (T, error)) (T, error) {
+	return retryWhile(ctx, config, isRetriable, fn)
+}
+
+// RetryNoResult is like Retry for requests which only return an error.
+func RetryNoResult(ctx context.Context, config *RetryConfig, fn func(context.Context) error) error {
+	_, err := Retry(ctx, config, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, fn(ctx)
+	})
+	return err
+}
+
+// retry is like Retry for requests of the SDK clients, which are bound to a context already.
+func retry[T any](ctx context.Context, config *RetryConfig, fn func() (T, error)) (T, error) {
+	return retryWhile(ctx, config, isRetriable, func(context.Context) (T, error) {
+		return fn()
+	})
+}
+
+// retryNoResult is like retry for requests which only return an error.
+func retryNoResult(ctx context.Context, config *RetryConfig, fn func() error) error {
+	_, err := retry(ctx, config, func() (struct{}, error) {
+		return struct{}{}, fn()
+	})
+	return err
+}
+
+// retryCreate is like retry for requests creating a resource. As a create request which failed with a 5xx status code
+// or a network error might have created the resource anyway, it is only retried if the API rejected it with 429.
+func retryCreate[T any](ctx context.Context, config *RetryConfig, fn func() (T, error)) (T, error) {
+	return retryWhile(ctx, config, isRateLimited, func(context.Context) (T, error) {
+		return fn()
+	})
+}
+
+func retryWhile[T any](ctx context.Context, config *RetryConfig, retriable func(error) bool, fn func(context.Context) (T, error)) (T, error) {
+	attempt := func() (T, error) {
+		if config == nil || config.AttemptTimeout <= 0 {
+			return fn(ctx)
+		}
+		attemptCtx, cancel := context.WithTimeout(ctx, config.AttemptTimeout)
+		defer cancel()
+		return fn(attemptCtx)
+	}
+
+	result, err := attempt()
+	if config == nil {
+		return result, err
+	}
+
+	for n := 1; n < config.MaxAttempts && (retriable(err) || config.attemptTimedOut(ctx, err)); n++ {
+		timer := time.NewTimer(config.delay(n - 1))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return result, err
+		case <-timer.C:
+		}
+
+		result, err = attempt()
+	}
+
+	return result, err
+}
+
+// attemptTimedOut returns whether the given error is caused by the attempt timeout and not by the deadline of the
+// given parent context.
+func (c RetryConfig) attemptTimedOut(ctx context.Context, err error) bool {
+	return c.AttemptTimeout > 0 && errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil
+}
+
+// isRetriable returns whether a request failing with the given error might succeed when retrying it, i.e. if the API
+// returned 429 or a 5xx status code or if the request failed with a network error. Requests which ran into the deadline
+// or were canceled are not retried.
+func isRetriable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	if statusCode := GetStatusCode(err); statusCode != 0 {
+		return statusCode == http.StatusTooManyRequests || statusCode >= http.StatusInternalServerError
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// isRateLimited returns whether the API rejected a request with 429.
+func isRateLimited(err error) bool {
+	return GetStatusCode(err) == http.StatusTooManyRequests
+}
@@ -0,0 +1,148 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stackitcloud/stackit-sdk-go/core/oapierror"
+)
+
+var _ = Describe("Retry", func() {
+	DescribeTable("#isRetriable",
+		func(err error, expected bool) {
+			Expect(isRetriable(err)).To(Equal(expected))
+		},
+		Entry("no error", nil, false),
+		Entry("too many requests", &oapierror.GenericOpenAPIError{StatusCode: http.StatusTooManyRequests}, true),
+		Entry("internal server error", &oapierror.GenericOpenAPIError{StatusCode: http.StatusInternalServerError}, true),
+		Entry("service unavailable with request ID", &RequestIDError{Err: &oapierror.GenericOpenAPIError{StatusCode: http.StatusServiceUnavailable}, RequestID: "id"}, true),
+		Entry("not found", &oapierror.GenericOpenAPIError{StatusCode: http.StatusNotFound}, false),
+		Entry("conflict", &oapierror.GenericOpenAPIError{StatusCode: http.StatusConflict}, false),
+		Entry("network error", &net.OpError{Op: "dial", Err: fmt.Errorf("connection refused")}, true),
+		Entry("deadline exceeded", fmt.Errorf("request failed: %w", context.DeadlineExceeded), false),
+		Entry("canceled", context.Canceled, false),
+		Entry("other error", fmt.Errorf("foo"), false),
+	)
+
+	Describe("#delay", func() {
+		It("should grow exponentially up to the maximum delay", func() {
+			config := RetryConfig{BaseDelay: time.Second, MaxDelay: 5 * time.Second}
+			Expect(config.delay(0)).To(Equal(time.Second))
+			Expect(config.delay(1)).To(Equal(2 * time.Second))
+			Expect(config.delay(2)).To(Equal(4 * time.Second))
+			Expect(config.delay(3)).To(Equal(5 * time.Second))
+			Expect(config.delay(100)).To(Equal(5 * time.Second))
+		})
+
+		It("should add the jitter", func() {
+			config := RetryConfig{BaseDelay: time.Second, Jitter: 0.5}
+			Expect(config.delay(1)).To(BeNumerically("~", 2500*time.Millisecond, 500*time.Millisecond))
+		})
+	})
+
+	Describe("#retry", func() {
+		var (
+			ctx    context.Context
+			config *RetryConfig
+			calls  int
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			config = &RetryConfig{MaxAttempts: 3, BaseDelay: time.Millisecond}
+			calls = 0
+		})
+
+		failing := func(err error) func() (string, error) {
+			return func() (string, error) {
+				calls++
+				return "", err
+			}
+		}
+
+		It("should call the function once without config", func() {
+			_, err := retry(ctx, nil, failing(&oapierror.GenericOpenAPIError{StatusCode: http.StatusInternalServerError}))
+			Expect(err).To(HaveOccurred())
+			Expect(calls).To(Equal(1))
+		})
+
+		It("should stop once the function succeeds", func() {
+			result, err := retry(ctx, config, func() (string, error) {
+				calls++
+				if calls < 2 {
+					return "", &oapierror.GenericOpenAPIError{StatusCode: http.StatusTooManyRequests}
+				}
+				return "ok", nil
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal("ok"))
+			Expect(calls).To(Equal(2))
+		})
+
+		It("should return the last error after the maximum attempts", func() {
+			_, err := retry(ctx, config, failing(&oapierror.GenericOpenAPIError{StatusCode: http.StatusBadGateway}))
+			Expect(GetStatusCode(err)).To(Equal(http.StatusBadGateway))
+			Expect(calls).To(Equal(3))
+		})
+
+		It("should stop immediately if the context is canceled", func() {
+			config.BaseDelay = time.Hour
+			ctx, cancel := context.WithCancel(ctx)
+			cancel()
+
+			_, err := retry(ctx, config, failing(&oapierror.GenericOpenAPIError{StatusCode: http.StatusServiceUnavailable}))
+			Expect(GetStatusCode(err)).To(Equal(http.StatusServiceUnavailable))
+			Expect(calls).To(Equal(1))
+		})
+
+		It("should only retry create requests rejected with 429", func() {
+			_, err := retryCreate(ctx, config, failing(&oapierror.GenericOpenAPIError{StatusCode: http.StatusInternalServerError}))
+			Expect(GetStatusCode(err)).To(Equal(http.StatusInternalServerError))
+			Expect(calls).To(Equal(1))
+
+			calls = 0
+			_, err = retryCreate(ctx, config, failing(&oapierror.GenericOpenAPIError{StatusCode: http.StatusTooManyRequests}))
+			Expect(GetStatusCode(err)).To(Equal(http.StatusTooManyRequests))
+			Expect(calls).To(Equal(3))
+		})
+
+		It("should retry attempts running into the attempt timeout", func() {
+			config.AttemptTimeout = time.Millisecond
+			err := RetryNoResult(ctx, config, func(ctx context.Context) error {
+				calls++
+				<-ctx.Done()
+				return ctx.Err()
+			})
+			Expect(err).To(MatchError(context.DeadlineExceeded))
+			Expect(calls).To(Equal(3))
+		})
+
+		It("should not retry if the parent context ran into its deadline", func() {
+			config.AttemptTimeout = time.Hour
+			ctx, cancel := context.WithTimeout(ctx, time.Millisecond)
+			defer cancel()
+
+			err := RetryNoResult(ctx, config, func(ctx context.Context) error {
+				calls++
+				<-ctx.Done()
+				return ctx.Err()
+			})
+			Expect(err).To(MatchError(context.DeadlineExceeded))
+			Expect(calls).To(Equal(1))
+		})
+
+		It("should not retry errors which are not retriable", func() {
+			err := retryNoResult(ctx, config, func() error {
+				calls++
+				return &oapierror.GenericOpenAPIError{StatusCode: http.StatusNotFound}
+			})
+			Expect(IsNotFound(err)).To(BeTrue())
+			Expect(calls).To(Equal(1))
+		})
+	})
+})
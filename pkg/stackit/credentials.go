@@ -40,6 +40,8 @@ type Credentials struct {
 	ProjectID                     string
 	SaKeyJSON                     string
 	LoadBalancerAPIEmergencyToken string
+	// LoadBalancerAPIEmergencyCACert is the optional PEM encoded CA certificate of the emergency load balancer API.
+	LoadBalancerAPIEmergencyCACert string
 }
 
 // GetCredentialsFromSecretRef reads the secret given by the secret reference and returns the read Credentials
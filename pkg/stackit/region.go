@@ -8,7 +8,11 @@ import (
 // It handles the legacy RegionOne value from the OpenStack CloudProfile and returns eu01 instead.
 // TODO: Remove this once we migrated all Shoot specs from RegionOne to eu01.
 func DetermineRegion(cluster *extensionscontroller.Cluster) string {
-	region := cluster.Shoot.Spec.Region
+	return NormalizeRegion(cluster.Shoot.Spec.Region)
+}
+
+// NormalizeRegion returns the STACKIT region for the given region of a shoot, i.e. eu01 for the legacy RegionOne.
+func NormalizeRegion(region string) string {
 	if region == "RegionOne" {
 		return "eu01"
 	}
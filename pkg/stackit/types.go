@@ -2,6 +2,7 @@ package stackit
 
 import (
 	iaas "github.com/stackitcloud/stackit-sdk-go/services/iaas/v2api"
+	"k8s.io/apimachinery/pkg/util/sets"
 )
 
 // The SDK is lacking constants for well-known values of the security group rule fields.
@@ -19,6 +20,36 @@ const (
 
 	// PodIdentityWebhookName is a constant for the name of the Pod Identity Webhook. (stackit)
 	PodIdentityWebhookName = "stackit-pod-identity-webhook"
+
+	// AnnotationCredentialsRotationHistory is the annotation on the ControlPlane which records the observed checksums of
+	// the cloudprovider secret together with the time they were first observed.
+	AnnotationCredentialsRotationHistory = "stackit.provider.extensions.gardener.cloud/credentials-rotation-history"
+
+	// AnnotationValidateOnly is the annotation on a Worker which makes the worker controller only generate and validate
+	// the machine deployments and classes instead of applying them.
+	AnnotationValidateOnly = "stackit.provider.extensions.gardener.cloud/validate-only"
+
+	// AnnotationEffectiveFeatureGates is the annotation on the Infrastructure which records the feature gates and their
+	// source which were effective during the last reconciliation.
+	AnnotationEffectiveFeatureGates = "stackit.provider.extensions.gardener.cloud/effective-feature-gates"
+
+	// AnnotationRecreateMissingRouter is the annotation on the Infrastructure which makes the infrastructure controller
+	// create a managed router if the router configured in the InfrastructureConfig does not exist anymore.
+	AnnotationRecreateMissingRouter = "stackit.provider.extensions.gardener.cloud/recreate-missing-router"
+)
+
+// MaxDNSServers is the maximum number of DNS servers the STACKIT IaaS API accepts for the nameservers of a network. The
+// API rejects networks with more nameservers. The STACKIT SDK does not expose the limit, hence it has to be kept in sync
+// with the API. OpenStack networks are not limited.
+const MaxDNSServers = 3
+
+const (
+	// AdditionalSecurityGroupRuleDescriptionPrefix is the prefix of the descriptions of the security group rules created
+	// for the additionalSecurityGroupRules of the InfrastructureConfig. It marks the rules as managed by the extension, so
+	// that they are deleted once they are removed from the InfrastructureConfig.
+	AdditionalSecurityGroupRuleDescriptionPrefix = "gardener-additional: "
+	// MaxSecurityGroupRuleDescriptionLength is the maximum length of the description of a security group rule.
+	MaxSecurityGroupRuleDescriptionLength = 127
 )
 
 var (
@@ -26,4 +57,7 @@ var (
 	ProtocolTCP = iaas.Protocol{Name: new("tcp")}
 	// ProtocolUDP is a shortcut for specifying a security group rule's protocol.
 	ProtocolUDP = iaas.Protocol{Name: new("udp")}
+
+	// ProtocolsWithPortRange are the protocols which support port ranges in security group rules.
+	ProtocolsWithPortRange = sets.New("dccp", "sctp", "tcp", "udp", "udplite")
 )
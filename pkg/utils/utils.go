@@ -6,8 +6,11 @@ package utils
 
 import (
 	"fmt"
+	"net/netip"
+	"slices"
 	"strings"
 
+	extensionscontroller "github.com/gardener/gardener/extensions/pkg/controller"
 	utilsnet "k8s.io/utils/net"
 )
 
@@ -71,6 +74,118 @@ func ComputeEgressCIDRs(ips []string) []string {
 	return result
 }
 
+// AggregateCIDRs merges the given CIDRs into the smallest set of CIDRs covering exactly the same addresses.
+// Contiguous host CIDRs like 10.0.0.0/32 and 10.0.0.1/32 are merged into 10.0.0.0/31, non-contiguous ones are kept as is.
+// Invalid CIDRs are ignored. The result is sorted, with IPv4 CIDRs before IPv6 CIDRs.
+func AggregateCIDRs(cidrs []string) []string {
+	type addrRange struct {
+		first, last netip.Addr
+	}
+
+	var ranges []addrRange
+	for _, cidr := range cidrs {
+		prefix, err := netip.ParsePrefix(cidr)
+		if err != nil {
+			continue
+		}
+		prefix = prefix.Masked()
+		ranges = append(ranges, addrRange{first: prefix.Addr(), last: lastAddr(prefix)})
+	}
+	slices.SortFunc(ranges, func(a, b addrRange) int {
+		return a.first.Compare(b.first)
+	})
+
+	var merged []addrRange
+	for _, r := range ranges {
+		if n := len(merged); n > 0 {
+			current := &merged[n-1]
+			next := current.last.Next()
+			if current.first.Is4() == r.first.Is4() && (!next.IsValid() || r.first.Compare(next) <= 0) {
+				if r.last.Compare(current.last) > 0 {
+					current.last = r.last
+				}
+				continue
+			}
+		}
+		merged = append(merged, r)
+	}
+
+	var result []string
+	for _, r := range merged {
+		first := r.first
+		for {
+			// use the largest prefix which starts at first and doesn't exceed the range
+			bits := first.BitLen()
+			for bits > 0 {
+				candidate := netip.PrefixFrom(first, bits-1).Masked()
+				if candidate.Addr() != first || lastAddr(candidate).Compare(r.last) > 0 {
+					break
+				}
+				bits--
+			}
+			prefix := netip.PrefixFrom(first, bits)
+			result = append(result, prefix.String())
+
+			last := lastAddr(prefix)
+			if last == r.last {
+				break
+			}
+			first = last.Next()
+		}
+	}
+	return result
+}
+
+// lastAddr returns the last address of the given prefix.
+func lastAddr(prefix netip.Prefix) netip.Addr {
+	addr := prefix.Masked().Addr()
+	bytes := addr.As16()
+	hostBits := addr.BitLen() - prefix.Bits()
+	for i := 15; i >= 0 && hostBits > 0; i-- {
+		if hostBits >= 8 {
+			bytes[i] = 0xff
+			hostBits -= 8
+			continue
+		}
+		bytes[i] |= byte(1<<hostBits) - 1
+		hostBits = 0
+	}
+	last := netip.AddrFrom16(bytes)
+	if addr.Is4() {
+		return last.Unmap()
+	}
+	return last
+}
+
+// PodNetworkCIDRs returns all pod network CIDRs of the shoot, i.e. both the IPv4 and the IPv6 CIDR of dual-stack
+// shoots. Duplicates are removed and the IPv4 CIDRs always come first, so that the order does not depend on the order of
+// the shoot status. It fails if one of the CIDRs is invalid.
+func PodNetworkCIDRs(cluster *extensionscontroller.Cluster) ([]string, error) {
+	var cidrs []string
+	if networking := cluster.Shoot.Spec.Networking; networking != nil && networking.Pods != nil {
+		cidrs = append(cidrs, *networking.Pods)
+	}
+	if networking := cluster.Shoot.Status.Networking; networking != nil {
+		cidrs = append(cidrs, networking.Pods...)
+	}
+
+	var ipv4CIDRs, ipv6CIDRs []string
+	for _, cidr := range cidrs {
+		if slices.Contains(ipv4CIDRs, cidr) || slices.Contains(ipv6CIDRs, cidr) {
+			continue
+		}
+		if _, _, err := utilsnet.ParseCIDRSloppy(cidr); err != nil {
+			return nil, fmt.Errorf("invalid pod network CIDR %q: %w", cidr, err)
+		}
+		if utilsnet.IsIPv4CIDRString(cidr) {
+			ipv4CIDRs = append(ipv4CIDRs, cidr)
+		} else {
+			ipv6CIDRs = append(ipv6CIDRs, cidr)
+		}
+	}
+	return append(ipv4CIDRs, ipv6CIDRs...), nil
+}
+
 // BuildLabelKey constructs a label key from a custom domain and suffix.
 // If customDomain is empty, it defaults to "kubernetes.io".
 // Example: BuildLabelKey("ske.stackit.cloud", "cluster") returns "ske.stackit.cloud/cluster"
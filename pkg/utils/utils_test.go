@@ -5,6 +5,8 @@
 package utils_test
 
 import (
+	extensionscontroller "github.com/gardener/gardener/extensions/pkg/controller"
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
@@ -43,4 +45,42 @@ var _ = Describe("Utils", func() {
 		Entry("should be false as pointer value is different", new("different"), "test", false),
 		Entry("should be true as pointer value is equal", new("test"), "test", true),
 	)
+
+	DescribeTable("#AggregateCIDRs", func(cidrs, expected []string) {
+		Expect(utils.AggregateCIDRs(cidrs)).To(Equal(expected))
+	},
+		Entry("should return nil for no CIDRs", nil, nil),
+		Entry("should keep a single host CIDR", []string{"10.0.0.1/32"}, []string{"10.0.0.1/32"}),
+		Entry("should merge contiguous IPv4 host CIDRs", []string{"10.0.0.3/32", "10.0.0.1/32", "10.0.0.0/32", "10.0.0.2/32"}, []string{"10.0.0.0/30"}),
+		Entry("should not merge unaligned contiguous host CIDRs", []string{"10.0.0.1/32", "10.0.0.2/32"}, []string{"10.0.0.1/32", "10.0.0.2/32"}),
+		Entry("should split contiguous ranges into covering CIDRs", []string{"10.0.0.1/32", "10.0.0.2/32", "10.0.0.3/32", "10.0.0.4/32"}, []string{"10.0.0.1/32", "10.0.0.2/31", "10.0.0.4/32"}),
+		Entry("should keep non-contiguous host CIDRs", []string{"192.168.0.1/32", "10.0.0.5/32", "10.0.0.1/32"}, []string{"10.0.0.1/32", "10.0.0.5/32", "192.168.0.1/32"}),
+		Entry("should merge contiguous IPv6 host CIDRs", []string{"2001:db8::1/128", "2001:db8::/128", "10.0.0.1/32"}, []string{"10.0.0.1/32", "2001:db8::/127"}),
+		Entry("should remove duplicates and ignore invalid CIDRs", []string{"10.0.0.1/32", "10.0.0.1/32", "foo"}, []string{"10.0.0.1/32"}),
+	)
+
+	DescribeTable("#PodNetworkCIDRs", func(specPods *string, statusPods []string, expected []string) {
+		cluster := &extensionscontroller.Cluster{Shoot: &gardencorev1beta1.Shoot{}}
+		if specPods != nil {
+			cluster.Shoot.Spec.Networking = &gardencorev1beta1.Networking{Pods: specPods}
+		}
+		if statusPods != nil {
+			cluster.Shoot.Status.Networking = &gardencorev1beta1.NetworkingStatus{Pods: statusPods}
+		}
+		Expect(utils.PodNetworkCIDRs(cluster)).To(Equal(expected))
+	},
+		Entry("should return nil without pod networks", nil, nil, nil),
+		Entry("should return the pod network of the spec", new("10.96.0.0/11"), nil, []string{"10.96.0.0/11"}),
+		Entry("should return the pod networks of the status", nil, []string{"10.96.0.0/11"}, []string{"10.96.0.0/11"}),
+		Entry("should remove duplicates", new("10.96.0.0/11"), []string{"10.96.0.0/11", "2001:db8::/64"}, []string{"10.96.0.0/11", "2001:db8::/64"}),
+		Entry("should return the IPv4 pod network first", nil, []string{"2001:db8::/64", "10.96.0.0/11"}, []string{"10.96.0.0/11", "2001:db8::/64"}),
+	)
+
+	It("#PodNetworkCIDRs should fail for invalid pod networks", func() {
+		cluster := &extensionscontroller.Cluster{Shoot: &gardencorev1beta1.Shoot{}}
+		cluster.Shoot.Status.Networking = &gardencorev1beta1.NetworkingStatus{Pods: []string{"10.96.0.0/11", "invalid"}}
+
+		_, err := utils.PodNetworkCIDRs(cluster)
+		Expect(err).To(MatchError(ContainSubstring(`invalid pod network CIDR "invalid"`)))
+	})
 })
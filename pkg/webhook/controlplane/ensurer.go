@@ -22,7 +22,6 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	vpaautoscalingv1 "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/apis/autoscaling.k8s.io/v1"
 	kubeletconfigv1beta1 "k8s.io/kubelet/config/v1beta1"
-	"k8s.io/utils/ptr"
 
 	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/imagevector"
 	"github.com/stackitcloud/gardener-extension-provider-stackit/v2/pkg/apis/config"
@@ -86,7 +85,7 @@ func (e *ensurer) EnsureMachineControllerManagerDeployment(ctx context.Context,
 			return err
 		}
 
-		apiEndpoints := ptr.Deref(cloudProfileConfig.APIEndpoints, stackitv1alpha1.APIEndpoints{})
+		apiEndpoints := helper.APIEndpointsFromCluster(cluster, cloudProfileConfig, stackit.DetermineRegion(cluster))
 
 		if cluster.CloudProfile != nil && cluster.CloudProfile.Spec.CABundle != nil {
 			newObj.Spec.Template.Spec.Volumes = extensionswebhook.EnsureVolumeWithName(newObj.Spec.Template.Spec.Volumes, corev1.Volume{
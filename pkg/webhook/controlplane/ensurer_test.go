@@ -677,6 +677,52 @@ WantedBy=multi-user.target
 			}
 			Expect(deployment.Spec.Template.Spec.Containers).To(ConsistOf(expectedContainer))
 		})
+
+		It("should inject the IaaS endpoint of the InfrastructureConfig if it is allowed", func() {
+			eContext := gcontext.NewInternalGardenContext(
+				&extensionscontroller.Cluster{
+					CloudProfile: &gardencorev1beta1.CloudProfile{
+						Spec: gardencorev1beta1.CloudProfileSpec{
+							ProviderConfig: &runtime.RawExtension{
+								Raw: encode(&stackitv1alpha1.CloudProfileConfig{
+									APIEndpoints:         &stackitv1alpha1.APIEndpoints{IaaS: new("https://iaas.qa")},
+									AllowedIaaSEndpoints: []string{"https://iaas.staging"},
+								}),
+							},
+						},
+					},
+					Shoot: &gardencorev1beta1.Shoot{
+						ObjectMeta: metav1.ObjectMeta{
+							Annotations: map[string]string{
+								feature.ShootUseSTACKITMachineControllerManager: "true",
+							},
+						},
+						Spec: gardencorev1beta1.ShootSpec{
+							Kubernetes: gardencorev1beta1.Kubernetes{
+								Version: "1.27.1",
+							},
+							Provider: gardencorev1beta1.Provider{
+								InfrastructureConfig: &runtime.RawExtension{
+									Raw: encode(&stackitv1alpha1.InfrastructureConfig{
+										IaaSEndpoint: new("https://iaas.staging"),
+									}),
+								},
+							},
+						},
+					},
+				},
+			)
+
+			Expect(ensurer.EnsureMachineControllerManagerDeployment(context.TODO(), eContext, deployment, nil)).To(Succeed())
+			expectedContainer := machinecontrollermanager.ProviderSidecarContainer(shoot, deployment.Namespace, "provider-stackit", "foo:bar")
+			expectedContainer.Env = []corev1.EnvVar{
+				{
+					Name:  "STACKIT_IAAS_ENDPOINT",
+					Value: "https://iaas.staging",
+				},
+			}
+			Expect(deployment.Spec.Template.Spec.Containers).To(ConsistOf(expectedContainer))
+		})
 	})
 
 	Describe("#EnsureMachineControllerManagerVPA", func() {
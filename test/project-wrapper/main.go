@@ -11,6 +11,7 @@ import (
 	"os"
 	"os/exec"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
@@ -37,12 +38,22 @@ STACKIT_SERVICE_ACCOUNT_EMAIL: the e-mail address of the service account token
 BILLING_REFERENCE: a valid billing reference for the created portal project
 PROJECT_OWNER: string representing how is responsible for the created account
 PORTAL_FOLDER_ID: the folder in the portal overview in which the integration portal project will be created
+
+# Optional environment variables:
+
+CLEANUP_WAIT_TIMEOUT: if set (e.g. `15m`), the wrapper waits up to this duration for the extension to clean up the
+resources it created before deleting the portal project
+CLEANUP_WAIT_LABEL_KEYS: comma-separated label keys identifying the resources created by the extension, defaults to
+`cluster.stackit.cloud,kubernetes.io/cluster`
 */
 
 const (
 	readinessWaitSeconds = 10
+	cleanupPollInterval  = 10 * time.Second
 )
 
+var defaultCleanupWaitLabelKeys = []string{"cluster.stackit.cloud", "kubernetes.io/cluster"}
+
 func main() {
 	if err := checkRequiredEnvironmentVariables(); err != nil {
 		log.Println(err)
@@ -60,6 +71,11 @@ func run() error {
 	defer cancel()
 	var errs error
 
+	cleanupWaitTimeout, err := getCleanupWaitTimeout()
+	if err != nil {
+		return err
+	}
+
 	stackitClient, err := sdk.NewClient()
 	if err != nil {
 		return errors.Join(errs, err)
@@ -70,6 +86,14 @@ func run() error {
 		return errors.Join(errs, err)
 	}
 	defer func() {
+		if cleanupWaitTimeout > 0 {
+			log.Printf("Waiting up to %v for the cleanup of managed resources in project %s.\n", cleanupWaitTimeout, stackitProjectID)
+			waitErr := waitForManagedResourceCleanup(context.Background(), stackitClient, stackitProjectID, cleanupWaitTimeout, getCleanupWaitLabelKeys())
+			if waitErr != nil {
+				errs = errors.Join(errs, waitErr)
+			}
+		}
+
 		log.Printf("Deleting portal project %s.\n", stackitProjectID)
 		cleanupErr := deletePortalProject(context.Background(), stackitClient, stackitProjectID)
 		if cleanupErr != nil {
@@ -135,6 +159,41 @@ func checkRequiredEnvironmentVariables() error {
 	return nil
 }
 
+// getCleanupWaitTimeout parses the optional CLEANUP_WAIT_TIMEOUT environment variable. A timeout of 0 disables the wait
+// for the cleanup of managed resources.
+func getCleanupWaitTimeout() (time.Duration, error) {
+	value := os.Getenv("CLEANUP_WAIT_TIMEOUT")
+	if value == "" {
+		return 0, nil
+	}
+
+	timeout, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("error: invalid CLEANUP_WAIT_TIMEOUT '%s': %w", value, err)
+	}
+	if timeout < 0 {
+		return 0, fmt.Errorf("error: CLEANUP_WAIT_TIMEOUT '%s' must not be negative", value)
+	}
+	return timeout, nil
+}
+
+// getCleanupWaitLabelKeys returns the label keys of the optional CLEANUP_WAIT_LABEL_KEYS environment variable or the
+// default label keys of the extension.
+func getCleanupWaitLabelKeys() []string {
+	value := os.Getenv("CLEANUP_WAIT_LABEL_KEYS")
+	if value == "" {
+		return defaultCleanupWaitLabelKeys
+	}
+
+	var keys []string
+	for key := range strings.SplitSeq(value, ",") {
+		if key = strings.TrimSpace(key); key != "" {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
 // createPortalProject creates a new project in the STACKIT portal using the provided client.
 // It generates a random suffix for the project name and uses the provided context for any necessary operations.
 // Returns a string representing the ID of the newly created project, or an error if the project creation fails.
@@ -280,6 +339,42 @@ func waitForProjectReadiness(ctx context.Context, client *sdk.Client, stackitPro
 	return fmt.Errorf("timeout waiting for project '%s' to become active", stackitProjectID)
 }
 
+// waitForManagedResourceCleanup waits until no resources with one of the given label keys remain in the project, i.e.
+// until the extension has cleaned up all resources it created. Errors while listing the resources are retried.
+// If resources remain after the timeout, an error listing them is returned.
+func waitForManagedResourceCleanup(ctx context.Context, client *sdk.Client, stackitProjectID string, timeout time.Duration, labelKeys []string) error {
+	region := os.Getenv("STACKIT_REGION")
+
+	var (
+		remaining []string
+		lastErr   error
+	)
+	err := wait.PollUntilContextTimeout(ctx, cleanupPollInterval, timeout, true, func(ctx context.Context) (bool, error) {
+		resources, err := client.ListManagedResources(ctx, stackitProjectID, region, labelKeys)
+		if err != nil {
+			log.Printf("Error listing managed resources: %v", err)
+			lastErr = err
+			return false, nil
+		}
+
+		remaining, lastErr = resources, nil
+		if len(remaining) > 0 {
+			log.Printf("Waiting for the cleanup of %d managed resources: %s\n", len(remaining), strings.Join(remaining, ", "))
+			return false, nil
+		}
+		return true, nil
+	})
+	if err != nil {
+		if len(remaining) == 0 && lastErr != nil {
+			return fmt.Errorf("error waiting for the cleanup of managed resources in project '%s': %w", stackitProjectID, lastErr)
+		}
+		return fmt.Errorf("managed resources in project '%s' were not cleaned up within %v: %s", stackitProjectID, timeout, strings.Join(remaining, ", "))
+	}
+
+	log.Printf("All managed resources in project '%s' are cleaned up.\n", stackitProjectID)
+	return nil
+}
+
 // deletePortalProject deletes the given project from the STACKIT portal using the provided client.
 func deletePortalProject(ctx context.Context, client *sdk.Client, portalProjectID string) error {
 	if err := client.DeleteProject(ctx, portalProjectID); err != nil {
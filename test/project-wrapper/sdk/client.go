@@ -2,12 +2,17 @@ package sdk
 
 import (
 	"context"
+	"fmt"
 
+	iaas "github.com/stackitcloud/stackit-sdk-go/services/iaas/v2api"
+	loadbalancer "github.com/stackitcloud/stackit-sdk-go/services/loadbalancer/v2api"
 	resourcemanager "github.com/stackitcloud/stackit-sdk-go/services/resourcemanager/v0api"
 )
 
 type Client struct {
-	rmClient *resourcemanager.APIClient
+	rmClient   *resourcemanager.APIClient
+	iaasClient *iaas.APIClient
+	lbClient   *loadbalancer.APIClient
 }
 
 func NewClient() (*Client, error) {
@@ -15,9 +20,19 @@ func NewClient() (*Client, error) {
 	if err != nil {
 		return nil, err
 	}
+	iaasClient, err := iaas.NewAPIClient()
+	if err != nil {
+		return nil, err
+	}
+	lbClient, err := loadbalancer.NewAPIClient()
+	if err != nil {
+		return nil, err
+	}
 
 	return &Client{
-		rmClient: rmClient,
+		rmClient:   rmClient,
+		iaasClient: iaasClient,
+		lbClient:   lbClient,
 	}, nil
 }
 
@@ -57,3 +72,52 @@ func (c *Client) GetProject(ctx context.Context, projectID string) (*resourceman
 func (c *Client) DeleteProject(ctx context.Context, projectID string) error {
 	return c.rmClient.DefaultAPI.DeleteProject(ctx, projectID).Execute()
 }
+
+// ListManagedResources returns a description of all servers, security groups, public IPs and load balancers in the
+// project which have at least one of the given label keys, i.e. which were created by the extension and not yet
+// cleaned up.
+func (c *Client) ListManagedResources(ctx context.Context, projectID, region string, labelKeys []string) ([]string, error) {
+	var resources []string
+
+	servers, err := c.iaasClient.DefaultAPI.ListServers(ctx, projectID, region).Execute()
+	if err != nil {
+		return nil, fmt.Errorf("error listing servers: %w", err)
+	}
+	for _, server := range servers.GetItems() {
+		if hasLabelKey(server.GetLabels(), labelKeys) {
+			resources = append(resources, fmt.Sprintf("server %s", server.GetName()))
+		}
+	}
+
+	securityGroups, err := c.iaasClient.DefaultAPI.ListSecurityGroups(ctx, projectID, region).Execute()
+	if err != nil {
+		return nil, fmt.Errorf("error listing security groups: %w", err)
+	}
+	for _, securityGroup := range securityGroups.GetItems() {
+		if hasLabelKey(securityGroup.GetLabels(), labelKeys) {
+			resources = append(resources, fmt.Sprintf("security group %s", securityGroup.GetName()))
+		}
+	}
+
+	publicIPs, err := c.iaasClient.DefaultAPI.ListPublicIPs(ctx, projectID, region).Execute()
+	if err != nil {
+		return nil, fmt.Errorf("error listing public IPs: %w", err)
+	}
+	for _, publicIP := range publicIPs.GetItems() {
+		if hasLabelKey(publicIP.GetLabels(), labelKeys) {
+			resources = append(resources, fmt.Sprintf("public IP %s", publicIP.GetIp()))
+		}
+	}
+
+	loadBalancers, err := c.lbClient.DefaultAPI.ListLoadBalancers(ctx, projectID, region).Execute()
+	if err != nil {
+		return nil, fmt.Errorf("error listing load balancers: %w", err)
+	}
+	for _, loadBalancer := range loadBalancers.GetLoadBalancers() {
+		if hasLabelKey(loadBalancer.GetLabels(), labelKeys) {
+			resources = append(resources, fmt.Sprintf("load balancer %s", loadBalancer.GetName()))
+		}
+	}
+
+	return resources, nil
+}
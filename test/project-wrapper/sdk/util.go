@@ -21,3 +21,13 @@ func GetMembersForRoles(subject string, roles set.Set[string]) []authorization.M
 	}
 	return members
 }
+
+// hasLabelKey reports whether the labels contain at least one of the given keys.
+func hasLabelKey[V any](labels map[string]V, keys []string) bool {
+	for _, key := range keys {
+		if _, ok := labels[key]; ok {
+			return true
+		}
+	}
+	return false
+}